@@ -57,6 +57,7 @@ import (
 type avroSchemaType interface{}
 
 const (
+	avroSchemaArray   = `array`
 	avroSchemaBoolean = `boolean`
 	avroSchemaBytes   = `bytes`
 	avroSchemaDouble  = `double`
@@ -73,12 +74,21 @@ type avroLogicalType struct {
 	Scale       int            `json:"scale,omitempty"`
 }
 
+// avroArrayType is the schema of an avro array. Serializing it to JSON gives the
+// standard schema representation.
+type avroArrayType struct {
+	SchemaType avroSchemaType `json:"type"`
+	Items      avroSchemaType `json:"items"`
+}
+
 func avroUnionKey(t avroSchemaType) string {
 	switch s := t.(type) {
 	case string:
 		return s
 	case avroLogicalType:
 		return avroUnionKey(s.SchemaType) + `.` + s.LogicalType
+	case avroArrayType:
+		return avroSchemaArray
 	case *avroRecord:
 		return s.Name
 	default:
@@ -147,46 +157,81 @@ func columnDescToAvroSchema(colDesc *sqlbase.ColumnDescriptor) (*avroSchemaField
 		typ:      colDesc.Type,
 	}
 
+	// Make every field optional by unioning it with null, so that all schema
+	// evolutions for a table are considered "backward compatible" by avro. This
+	// means that the Avro type doesn't mirror the column's nullability, but it
+	// makes it much easier to work with long histories of table data afterward,
+	// especially for things like loading into analytics databases.
+	avroType, encodeFn, decodeFn, err := typeToAvroSchema(&colDesc.Type, true /* nullable */)
+	if err != nil {
+		return nil, errors.Wrapf(err, `column %s`, colDesc.Name)
+	}
+	schema.SchemaType = avroType
+	schema.encodeFn = func(d tree.Datum) (interface{}, error) {
+		encoded, err := encodeFn(d)
+		if err != nil {
+			return nil, errors.Wrapf(err, `column %s`, colDesc.Name)
+		}
+		return encoded, nil
+	}
+	schema.decodeFn = decodeFn
+	return schema, nil
+}
+
+// typeToAvroSchema returns the avro schema for values of the given SQL type,
+// along with functions that convert datums of that type to and from the native
+// Go representation used by the avro library. If nullable is true, the schema
+// is a union of null and the type, and the functions handle DNull.
+func typeToAvroSchema(
+	typ *types.T, nullable bool,
+) (
+	_ avroSchemaType,
+	_ func(tree.Datum) (interface{}, error),
+	_ func(interface{}) (tree.Datum, error),
+	_ error,
+) {
 	var avroType avroSchemaType
-	switch colDesc.Type.Family() {
+	var encodeFn func(tree.Datum) (interface{}, error)
+	var decodeFn func(interface{}) (tree.Datum, error)
+	switch typ.Family() {
 	case types.IntFamily:
 		avroType = avroSchemaLong
-		schema.encodeFn = func(d tree.Datum) (interface{}, error) {
+		encodeFn = func(d tree.Datum) (interface{}, error) {
 			return int64(*d.(*tree.DInt)), nil
 		}
-		schema.decodeFn = func(x interface{}) (tree.Datum, error) {
+		decodeFn = func(x interface{}) (tree.Datum, error) {
 			return tree.NewDInt(tree.DInt(x.(int64))), nil
 		}
 	case types.BoolFamily:
 		avroType = avroSchemaBoolean
-		schema.encodeFn = func(d tree.Datum) (interface{}, error) {
+		encodeFn = func(d tree.Datum) (interface{}, error) {
 			return bool(*d.(*tree.DBool)), nil
 		}
-		schema.decodeFn = func(x interface{}) (tree.Datum, error) {
+		decodeFn = func(x interface{}) (tree.Datum, error) {
 			return tree.MakeDBool(tree.DBool(x.(bool))), nil
 		}
 	case types.FloatFamily:
 		avroType = avroSchemaDouble
-		schema.encodeFn = func(d tree.Datum) (interface{}, error) {
+		encodeFn = func(d tree.Datum) (interface{}, error) {
 			return float64(*d.(*tree.DFloat)), nil
 		}
-		schema.decodeFn = func(x interface{}) (tree.Datum, error) {
+		decodeFn = func(x interface{}) (tree.Datum, error) {
 			return tree.NewDFloat(tree.DFloat(x.(float64))), nil
 		}
 	case types.StringFamily:
 		avroType = avroSchemaString
-		schema.encodeFn = func(d tree.Datum) (interface{}, error) {
+		encodeFn = func(d tree.Datum) (interface{}, error) {
 			return string(*d.(*tree.DString)), nil
 		}
-		schema.decodeFn = func(x interface{}) (tree.Datum, error) {
+		decodeFn = func(x interface{}) (tree.Datum, error) {
 			return tree.NewDString(x.(string)), nil
 		}
 	case types.BytesFamily:
 		avroType = avroSchemaBytes
-		schema.encodeFn = func(d tree.Datum) (interface{}, error) {
+		encodeFn = func(d tree.Datum) (interface{}, error) {
 			return []byte(*d.(*tree.DBytes)), nil
 		}
-		schema.decodeFn = func(x interface{}) (tree.Datum, error) {
+		decodeFn = func(x interface{}) (tree.Datum, error) {
 			return tree.NewDBytes(tree.DBytes(x.([]byte))), nil
 		}
 	case types.DateFamily:
@@ -194,16 +239,15 @@ func columnDescToAvroSchema(colDesc *sqlbase.ColumnDescriptor) (*avroSchemaField
 			SchemaType:  avroSchemaInt,
 			LogicalType: `date`,
 		}
-		schema.encodeFn = func(d tree.Datum) (interface{}, error) {
+		encodeFn = func(d tree.Datum) (interface{}, error) {
 			date := *d.(*tree.DDate)
 			if !date.IsFinite() {
-				return nil, errors.Errorf(
-					`column %s: infinite date not yet supported with avro`, colDesc.Name)
+				return nil, errors.New(`infinite date not yet supported with avro`)
 			}
 			// The avro library requires us to return this as a time.Time.
 			return date.ToTime()
 		}
-		schema.decodeFn = func(x interface{}) (tree.Datum, error) {
+		decodeFn = func(x interface{}) (tree.Datum, error) {
 			// The avro library hands this back as a time.Time.
 			return tree.NewDDateFromTime(x.(time.Time))
 		}
@@ -212,12 +256,12 @@ func columnDescToAvroSchema(colDesc *sqlbase.ColumnDescriptor) (*avroSchemaField
 			SchemaType:  avroSchemaLong,
 			LogicalType: `time-micros`,
 		}
-		schema.encodeFn = func(d tree.Datum) (interface{}, error) {
+		encodeFn = func(d tree.Datum) (interface{}, error) {
 			// The avro library requires us to return this as a time.Duration.
 			duration := time.Duration(*d.(*tree.DTime)) * time.Microsecond
 			return duration, nil
 		}
-		schema.decodeFn = func(x interface{}) (tree.Datum, error) {
+		decodeFn = func(x interface{}) (tree.Datum, error) {
 			// The avro library hands this back as a time.Duration.
 			micros := x.(time.Duration) / time.Microsecond
 			return tree.MakeDTime(timeofday.TimeOfDay(micros)), nil
@@ -227,10 +271,10 @@ func columnDescToAvroSchema(colDesc *sqlbase.ColumnDescriptor) (*avroSchemaField
 			SchemaType:  avroSchemaLong,
 			LogicalType: `timestamp-micros`,
 		}
-		schema.encodeFn = func(d tree.Datum) (interface{}, error) {
+		encodeFn = func(d tree.Datum) (interface{}, error) {
 			return d.(*tree.DTimestamp).Time, nil
 		}
-		schema.decodeFn = func(x interface{}) (tree.Datum, error) {
+		decodeFn = func(x interface{}) (tree.Datum, error) {
 			return tree.MakeDTimestamp(x.(time.Time), time.Microsecond), nil
 		}
 	case types.TimestampTZFamily:
@@ -238,102 +282,218 @@ func columnDescToAvroSchema(colDesc *sqlbase.ColumnDescriptor) (*avroSchemaField
 			SchemaType:  avroSchemaLong,
 			LogicalType: `timestamp-micros`,
 		}
-		schema.encodeFn = func(d tree.Datum) (interface{}, error) {
+		encodeFn = func(d tree.Datum) (interface{}, error) {
 			return d.(*tree.DTimestampTZ).Time, nil
 		}
-		schema.decodeFn = func(x interface{}) (tree.Datum, error) {
+		decodeFn = func(x interface{}) (tree.Datum, error) {
 			return tree.MakeDTimestampTZ(x.(time.Time), time.Microsecond), nil
 		}
 	case types.DecimalFamily:
-		if colDesc.Type.Precision() == 0 {
-			return nil, errors.Errorf(
-				`column %s: decimal with no precision not yet supported with avro`, colDesc.Name)
+		if typ.Precision() == 0 {
+			return nil, nil, nil, errors.New(`decimal with no precision not yet supported with avro`)
 		}
 		avroType = avroLogicalType{
 			SchemaType:  avroSchemaBytes,
 			LogicalType: `decimal`,
-			Precision:   int(colDesc.Type.Precision()),
-			Scale:       int(colDesc.Type.Width()),
+			Precision:   int(typ.Precision()),
+			Scale:       int(typ.Width()),
 		}
-		schema.encodeFn = func(d tree.Datum) (interface{}, error) {
+		encodeFn = func(d tree.Datum) (interface{}, error) {
 			dec := d.(*tree.DDecimal).Decimal
 			// TODO(dan): For the cases that the avro defined decimal format
 			// would not roundtrip, serialize the decimal as a string. Also
 			// support the unspecified precision/scale case in this branch. We
 			// can't currently do this without surgery to the avro library we're
 			// using and that's too scary leading up to 2.1.0.
-			rat, err := decimalToRat(dec, colDesc.Type.Width())
+			rat, err := decimalToRat(dec, typ.Width())
 			if err != nil {
 				return nil, err
 			}
 			return &rat, nil
 		}
-		schema.decodeFn = func(x interface{}) (tree.Datum, error) {
-			return &tree.DDecimal{Decimal: ratToDecimal(*x.(*big.Rat), colDesc.Type.Width())}, nil
+		decodeFn = func(x interface{}) (tree.Datum, error) {
+			return &tree.DDecimal{Decimal: ratToDecimal(*x.(*big.Rat), typ.Width())}, nil
 		}
 	case types.UuidFamily:
 		// Should be logical type of "uuid", but the avro library doesn't support
 		// that yet.
 		avroType = avroSchemaString
-		schema.encodeFn = func(d tree.Datum) (interface{}, error) {
+		encodeFn = func(d tree.Datum) (interface{}, error) {
 			return d.(*tree.DUuid).UUID.String(), nil
 		}
-		schema.decodeFn = func(x interface{}) (tree.Datum, error) {
+		decodeFn = func(x interface{}) (tree.Datum, error) {
 			return tree.ParseDUuidFromString(x.(string))
 		}
 	case types.INetFamily:
 		avroType = avroSchemaString
-		schema.encodeFn = func(d tree.Datum) (interface{}, error) {
+		encodeFn = func(d tree.Datum) (interface{}, error) {
 			return d.(*tree.DIPAddr).IPAddr.String(), nil
 		}
-		schema.decodeFn = func(x interface{}) (tree.Datum, error) {
+		decodeFn = func(x interface{}) (tree.Datum, error) {
 			return tree.ParseDIPAddrFromINetString(x.(string))
 		}
 	case types.JsonFamily:
 		avroType = avroSchemaString
-		schema.encodeFn = func(d tree.Datum) (interface{}, error) {
+		encodeFn = func(d tree.Datum) (interface{}, error) {
 			return d.(*tree.DJSON).JSON.String(), nil
 		}
-		schema.decodeFn = func(x interface{}) (tree.Datum, error) {
+		decodeFn = func(x interface{}) (tree.Datum, error) {
 			return tree.ParseDJSON(x.(string))
 		}
+	case types.ArrayFamily:
+		// The elements of SQL arrays can be NULL, so the items of the avro array
+		// are always nullable.
+		itemType, itemEncodeFn, itemDecodeFn, err := typeToAvroSchema(
+			typ.ArrayContents(), true /* nullable */)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		avroType = avroArrayType{
+			SchemaType: avroSchemaArray,
+			Items:      itemType,
+		}
+		encodeFn = func(d tree.Datum) (interface{}, error) {
+			datumArr := d.(*tree.DArray).Array
+			items := make([]interface{}, len(datumArr))
+			for i, elem := range datumArr {
+				encoded, err := itemEncodeFn(elem)
+				if err != nil {
+					return nil, err
+				}
+				items[i] = encoded
+			}
+			return items, nil
+		}
+		decodeFn = func(x interface{}) (tree.Datum, error) {
+			datumArr := tree.NewDArray(typ.ArrayContents())
+			for _, item := range x.([]interface{}) {
+				elem, err := itemDecodeFn(item)
+				if err != nil {
+					return nil, err
+				}
+				if err := datumArr.Append(elem); err != nil {
+					return nil, err
+				}
+			}
+			return datumArr, nil
+		}
 	default:
-		return nil, errors.Errorf(`column %s: type %s not yet supported with avro`,
-			colDesc.Name, colDesc.Type.SQLString())
+		return nil, nil, nil, errors.Errorf(`type %s not yet supported with avro`, typ.SQLString())
 	}
-	schema.SchemaType = avroType
 
-	// Make every field optional by unioning it with null, so that all schema
-	// evolutions for a table are considered "backward compatible" by avro. This
-	// means that the Avro type doesn't mirror the column's nullability, but it
-	// makes it much easier to work with long histories of table data afterward,
-	// especially for things like loading into analytics databases.
-	{
-		// The default for a union type is the default for the first element of
-		// the union.
-		schema.SchemaType = []avroSchemaType{avroSchemaNull, avroType}
-		encodeFn := schema.encodeFn
-		decodeFn := schema.decodeFn
-		unionKey := avroUnionKey(avroType)
-		schema.encodeFn = func(d tree.Datum) (interface{}, error) {
-			if d == tree.DNull {
-				return goavro.Union(avroSchemaNull, nil), nil
+	if !nullable {
+		return avroType, encodeFn, decodeFn, nil
+	}
+
+	// The default for a union type is the default for the first element of
+	// the union.
+	unionKey := avroUnionKey(avroType)
+	unionEncodeFn := func(d tree.Datum) (interface{}, error) {
+		if d == tree.DNull {
+			return goavro.Union(avroSchemaNull, nil), nil
+		}
+		encoded, err := encodeFn(d)
+		if err != nil {
+			return nil, err
+		}
+		return goavro.Union(unionKey, encoded), nil
+	}
+	unionDecodeFn := func(x interface{}) (tree.Datum, error) {
+		if x == nil {
+			return tree.DNull, nil
+		}
+		return decodeFn(x.(map[string]interface{})[unionKey])
+	}
+	return []avroSchemaType{avroSchemaNull, avroType}, unionEncodeFn, unionDecodeFn, nil
+}
+
+// ToAvroSchema returns the JSON representation of the avro schema used by
+// changefeeds for values of the given SQL type. If nullable is true, the
+// schema is a union of null and the type, as it is for every column of a
+// changefeed's avro records.
+func ToAvroSchema(typ *types.T, nullable bool) (string, error) {
+	avroType, _, _, err := typeToAvroSchema(typ, nullable)
+	if err != nil {
+		return "", err
+	}
+	j, err := json.Marshal(avroType)
+	if err != nil {
+		return "", err
+	}
+	return string(j), nil
+}
+
+// FromAvroSchema returns the SQL type corresponding to the given JSON avro
+// schema, along with whether the schema is nullable (a union with null). It is
+// the reverse of ToAvroSchema, but the mapping is lossy: UUID, INET and JSONB
+// values are all represented as avro strings and map back to STRING, and
+// TIMESTAMPTZ maps back to TIMESTAMP.
+func FromAvroSchema(schema string) (_ *types.T, nullable bool, _ error) {
+	var avroType interface{}
+	if err := json.Unmarshal([]byte(schema), &avroType); err != nil {
+		return nil, false, err
+	}
+	return avroSchemaToType(avroType)
+}
+
+// avroSchemaToType implements FromAvroSchema for an avro schema decoded from
+// JSON.
+func avroSchemaToType(avroType interface{}) (_ *types.T, nullable bool, _ error) {
+	switch t := avroType.(type) {
+	case string:
+		switch t {
+		case avroSchemaBoolean:
+			return types.Bool, false, nil
+		case avroSchemaBytes:
+			return types.Bytes, false, nil
+		case avroSchemaDouble:
+			return types.Float, false, nil
+		case avroSchemaInt:
+			return types.Int4, false, nil
+		case avroSchemaLong:
+			return types.Int, false, nil
+		case avroSchemaString:
+			return types.String, false, nil
+		}
+	case []interface{}:
+		if len(t) == 2 {
+			for i := range t {
+				if t[i] == avroSchemaNull {
+					typ, _, err := avroSchemaToType(t[1-i])
+					return typ, true, err
+				}
 			}
-			encoded, err := encodeFn(d)
+		}
+	case map[string]interface{}:
+		schemaType := t[`type`]
+		if schemaType == avroSchemaArray {
+			elemTyp, _, err := avroSchemaToType(t[`items`])
 			if err != nil {
-				return nil, err
+				return nil, false, err
 			}
-			return goavro.Union(unionKey, encoded), nil
+			return types.MakeArray(elemTyp), false, nil
 		}
-		schema.decodeFn = func(x interface{}) (tree.Datum, error) {
-			if x == nil {
-				return tree.DNull, nil
+		logicalType, ok := t[`logicalType`].(string)
+		if !ok {
+			return avroSchemaToType(schemaType)
+		}
+		switch {
+		case logicalType == `date` && schemaType == avroSchemaInt:
+			return types.Date, false, nil
+		case logicalType == `time-micros` && schemaType == avroSchemaLong:
+			return types.Time, false, nil
+		case logicalType == `timestamp-micros` && schemaType == avroSchemaLong:
+			return types.Timestamp, false, nil
+		case logicalType == `decimal` && schemaType == avroSchemaBytes:
+			precision, _ := t[`precision`].(float64)
+			scale, _ := t[`scale`].(float64)
+			if precision == 0 {
+				return nil, false, errors.New(`decimal with no precision not yet supported with avro`)
 			}
-			return decodeFn(x.(map[string]interface{})[unionKey])
+			return types.MakeDecimal(int32(precision), int32(scale)), false, nil
 		}
 	}
-
-	return schema, nil
+	return nil, false, errors.Errorf(`avro schema %v not supported`, avroType)
 }
 
 // indexToAvroSchema converts a column descriptor into its corresponding avro
@@ -160,6 +160,11 @@ func TestAvroSchema(t *testing.T) {
 			schema: `(a INT PRIMARY KEY, b DECIMAL (3,2), c DECIMAL (2, 1))`,
 			values: `(1, 1.23, 4.5)`,
 		},
+		{
+			name:   `ARRAYS`,
+			schema: `(a INT PRIMARY KEY, b INT[], c STRING[], d DECIMAL(3,2)[])`,
+			values: `(1, ARRAY[1, NULL, 3], ARRAY['a', 'b'], ARRAY[1.23]), (2, ARRAY[], NULL, ARRAY[NULL])`,
+		},
 	}
 	// Generate a test for each column type with a random datum of that type.
 	for _, typ := range types.OidToType {
@@ -416,6 +421,14 @@ func TestAvroSchema(t *testing.T) {
 			{sqlType: `JSONB`,
 				sql:  `'{"b": 1}'`,
 				avro: `{"string":"{\"b\": 1}"}`},
+
+			{sqlType: `INT[]`, sql: `NULL`, avro: `null`},
+			{sqlType: `INT[]`,
+				sql:  `ARRAY[1, NULL, 3]`,
+				avro: `{"array":[{"long":1},null,{"long":3}]}`},
+			{sqlType: `STRING[]`,
+				sql:  `ARRAY[]`,
+				avro: `{"array":[]}`},
 		}
 
 		for _, test := range goldens {
@@ -600,3 +613,51 @@ func TestDecimalRatRoundtrip(t *testing.T) {
 		}
 	})
 }
+
+func TestAvroTypeMapping(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	tests := []struct {
+		typ      *types.T
+		nullable bool
+		schema   string
+		// roundtrip is the type returned by FromAvroSchema, if it differs from
+		// typ.
+		roundtrip *types.T
+	}{
+		{typ: types.Int, schema: `"long"`},
+		{typ: types.Int, nullable: true, schema: `["null","long"]`},
+		{typ: types.Bool, nullable: true, schema: `["null","boolean"]`},
+		{typ: types.Date, schema: `{"type":"int","logicalType":"date"}`},
+		{typ: types.MakeDecimal(10, 3), nullable: true,
+			schema: `["null",{"type":"bytes","logicalType":"decimal","precision":10,"scale":3}]`},
+		{typ: types.TimestampTZ, schema: `{"type":"long","logicalType":"timestamp-micros"}`,
+			roundtrip: types.Timestamp},
+		{typ: types.Uuid, schema: `"string"`, roundtrip: types.String},
+		{typ: types.IntArray, nullable: true,
+			schema: `["null",{"type":"array","items":["null","long"]}]`},
+		{typ: types.MakeArray(types.MakeDecimal(4, 1)),
+			schema: `{"type":"array","items":["null",{"type":"bytes","logicalType":"decimal","precision":4,"scale":1}]}`},
+	}
+	for _, test := range tests {
+		schema, err := ToAvroSchema(test.typ, test.nullable)
+		require.NoError(t, err)
+		require.Equal(t, test.schema, schema, `SQL type %s`, test.typ.SQLString())
+
+		typ, nullable, err := FromAvroSchema(schema)
+		require.NoError(t, err)
+		expected := test.typ
+		if test.roundtrip != nil {
+			expected = test.roundtrip
+		}
+		require.True(t, expected.Identical(typ), `expected %s, got %s`, expected.SQLString(), typ.SQLString())
+		require.Equal(t, test.nullable, nullable)
+	}
+
+	_, err := ToAvroSchema(types.Decimal, true /* nullable */)
+	require.EqualError(t, err, `decimal with no precision not yet supported with avro`)
+	_, err = ToAvroSchema(types.Oid, true /* nullable */)
+	require.EqualError(t, err, `type OID not yet supported with avro`)
+	_, _, err = FromAvroSchema(`["null","long","string"]`)
+	require.EqualError(t, err, `avro schema [null long string] not supported`)
+}
@@ -60,6 +60,8 @@ const (
 	importOptionOversample = "oversample"
 	importOptionSkipFKs    = "skip_foreign_keys"
 
+	importOptionCoercionProfile = "coercion_profile"
+
 	importOptionDirectIngest = "experimental_direct_ingestion"
 
 	pgCopyDelimiter = "delimiter"
@@ -85,6 +87,8 @@ var importOptionExpectValues = map[string]sql.KVStringOptValidate{
 
 	importOptionSkipFKs: sql.KVStringOptRequireNoValue,
 
+	importOptionCoercionProfile: sql.KVStringOptRequireValue,
+
 	importOptionDirectIngest: sql.KVStringOptRequireNoValue,
 
 	pgMaxRowSize: sql.KVStringOptRequireValue,
@@ -370,6 +374,14 @@ func importPlanHook(
 			}
 		}
 
+		if override, ok := opts[importOptionCoercionProfile]; ok {
+			profile, err := types.CoercionProfileByName(override)
+			if err != nil {
+				return err
+			}
+			format.CoercionProfile = profile.Name
+		}
+
 		_, ingestDirectly := opts[importOptionDirectIngest]
 
 		var tableDetails []jobspb.ImportDetails_Table
@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding/csv"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
@@ -32,6 +33,7 @@ type csvInputReader struct {
 	batchSize    int
 	batch        csvRecord
 	opts         roachpb.CSVOptions
+	profile      *types.CoercionProfile
 	tableDesc    *sqlbase.TableDescriptor
 	expectedCols int
 }
@@ -41,12 +43,14 @@ var _ inputConverter = &csvInputReader{}
 func newCSVInputReader(
 	kvCh chan []roachpb.KeyValue,
 	opts roachpb.CSVOptions,
+	profile *types.CoercionProfile,
 	tableDesc *sqlbase.TableDescriptor,
 	evalCtx *tree.EvalContext,
 ) *csvInputReader {
 	return &csvInputReader{
 		evalCtx:      evalCtx,
 		opts:         opts,
+		profile:      profile,
 		kvCh:         kvCh,
 		expectedCols: len(tableDesc.VisibleColumns()),
 		tableDesc:    tableDesc,
@@ -166,6 +170,7 @@ func (c *csvInputReader) convertRecordWorker(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	conv.CoercionProfile = c.profile
 	if conv.EvalCtx.SessionData == nil {
 		panic("uninitialized session data")
 	}
@@ -179,7 +184,8 @@ func (c *csvInputReader) convertRecordWorker(ctx context.Context) error {
 					conv.Datums[i] = tree.DNull
 				} else {
 					var err error
-					conv.Datums[i], err = tree.ParseDatumStringAs(conv.VisibleColTypes[i], v, conv.EvalCtx)
+					conv.Datums[i], err = tree.ParseDatumStringAsWithProfile(
+						conv.VisibleColTypes[i], v, conv.EvalCtx, conv.CoercionProfile)
 					if err != nil {
 						return wrapRowErr(err, batch.file, rowNum, pgcode.Syntax,
 							"parse %q as %s", col.Name, col.Type.SQLString())
@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
 )
 
@@ -33,6 +34,7 @@ var _ inputConverter = &mysqloutfileReader{}
 func newMysqloutfileReader(
 	kvCh chan []roachpb.KeyValue,
 	opts roachpb.MySQLOutfileOptions,
+	profile *types.CoercionProfile,
 	tableDesc *sqlbase.TableDescriptor,
 	evalCtx *tree.EvalContext,
 ) (*mysqloutfileReader, error) {
@@ -40,6 +42,7 @@ func newMysqloutfileReader(
 	if err != nil {
 		return nil, err
 	}
+	conv.CoercionProfile = profile
 	return &mysqloutfileReader{
 		conv: *conv,
 		opts: opts,
@@ -100,7 +103,8 @@ func (d *mysqloutfileReader) readFile(
 		} else if !d.opts.HasEscape && string(field) == "NULL" {
 			row = append(row, tree.DNull)
 		} else {
-			datum, err := tree.ParseStringAs(d.conv.VisibleColTypes[len(row)], string(field), d.conv.EvalCtx)
+			datum, err := tree.ParseStringAsWithProfile(
+				d.conv.VisibleColTypes[len(row)], string(field), d.conv.EvalCtx, d.conv.CoercionProfile)
 			if err != nil {
 				col := d.conv.VisibleCols[len(row)]
 				return wrapRowErr(err, inputName, count, pgcode.Syntax,
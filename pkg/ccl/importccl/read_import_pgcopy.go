@@ -23,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
 	"github.com/cockroachdb/errors"
 )
@@ -41,6 +42,7 @@ var _ inputConverter = &pgCopyReader{}
 func newPgCopyReader(
 	kvCh chan []roachpb.KeyValue,
 	opts roachpb.PgCopyOptions,
+	profile *types.CoercionProfile,
 	tableDesc *sqlbase.TableDescriptor,
 	evalCtx *tree.EvalContext,
 ) (*pgCopyReader, error) {
@@ -48,6 +50,7 @@ func newPgCopyReader(
 	if err != nil {
 		return nil, err
 	}
+	conv.CoercionProfile = profile
 	return &pgCopyReader{
 		conv: *conv,
 		opts: opts,
@@ -285,7 +288,8 @@ func (d *pgCopyReader) readFile(
 			if s == nil {
 				d.conv.Datums[i] = tree.DNull
 			} else {
-				d.conv.Datums[i], err = tree.ParseDatumStringAs(d.conv.VisibleColTypes[i], *s, d.conv.EvalCtx)
+				d.conv.Datums[i], err = tree.ParseDatumStringAsWithProfile(
+					d.conv.VisibleColTypes[i], *s, d.conv.EvalCtx, d.conv.CoercionProfile)
 				if err != nil {
 					col := d.conv.VisibleCols[i]
 					return wrapRowErr(err, inputName, count, pgcode.Syntax,
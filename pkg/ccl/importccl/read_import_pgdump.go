@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -403,6 +404,7 @@ var _ inputConverter = &pgDumpReader{}
 func newPgDumpReader(
 	kvCh chan []roachpb.KeyValue,
 	opts roachpb.PgDumpOptions,
+	profile *types.CoercionProfile,
 	descs map[string]*sqlbase.TableDescriptor,
 	evalCtx *tree.EvalContext,
 ) (*pgDumpReader, error) {
@@ -413,6 +415,7 @@ func newPgDumpReader(
 			if err != nil {
 				return nil, err
 			}
+			conv.CoercionProfile = profile
 			converters[name] = conv
 		}
 	}
@@ -550,7 +553,8 @@ func (m *pgDumpReader) readFile(
 						if s == nil {
 							conv.Datums[i] = tree.DNull
 						} else {
-							conv.Datums[i], err = tree.ParseDatumStringAs(conv.VisibleColTypes[i], *s, conv.EvalCtx)
+							conv.Datums[i], err = tree.ParseDatumStringAsWithProfile(
+								conv.VisibleColTypes[i], *s, conv.EvalCtx, conv.CoercionProfile)
 							if err != nil {
 								col := conv.VisibleCols[i]
 								return wrapRowErr(err, inputName, count, pgcode.Syntax,
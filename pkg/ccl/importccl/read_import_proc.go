@@ -278,6 +278,14 @@ func (cp *readImportDataProcessor) doRun(ctx context.Context) error {
 		return errors.Errorf("%s only supports reading a single, pre-specified table", format.String())
 	}
 
+	profile := &types.DefaultCoercionProfile
+	if name := cp.spec.Format.CoercionProfile; name != "" {
+		var err error
+		if profile, err = types.CoercionProfileByName(name); err != nil {
+			return err
+		}
+	}
+
 	var conv inputConverter
 	var err error
 	switch cp.spec.Format.Format {
@@ -292,16 +300,16 @@ func (cp *readImportDataProcessor) doRun(ctx context.Context) error {
 		if isWorkload {
 			conv = newWorkloadReader(kvCh, singleTable, evalCtx)
 		} else {
-			conv = newCSVInputReader(kvCh, cp.spec.Format.Csv, singleTable, evalCtx)
+			conv = newCSVInputReader(kvCh, cp.spec.Format.Csv, profile, singleTable, evalCtx)
 		}
 	case roachpb.IOFileFormat_MysqlOutfile:
-		conv, err = newMysqloutfileReader(kvCh, cp.spec.Format.MysqlOut, singleTable, evalCtx)
+		conv, err = newMysqloutfileReader(kvCh, cp.spec.Format.MysqlOut, profile, singleTable, evalCtx)
 	case roachpb.IOFileFormat_Mysqldump:
 		conv, err = newMysqldumpReader(kvCh, cp.spec.Tables, evalCtx)
 	case roachpb.IOFileFormat_PgCopy:
-		conv, err = newPgCopyReader(kvCh, cp.spec.Format.PgCopy, singleTable, evalCtx)
+		conv, err = newPgCopyReader(kvCh, cp.spec.Format.PgCopy, profile, singleTable, evalCtx)
 	case roachpb.IOFileFormat_PgDump:
-		conv, err = newPgDumpReader(kvCh, cp.spec.Format.PgDump, cp.spec.Tables, evalCtx)
+		conv, err = newPgDumpReader(kvCh, cp.spec.Format.PgDump, profile, cp.spec.Tables, evalCtx)
 	default:
 		err = errors.Errorf("Requested IMPORT format (%d) not supported by this node", cp.spec.Format.Format)
 	}
@@ -0,0 +1,101 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/cmd/internal/issues"
+)
+
+const serverURLEnv = "TC_SERVER_URL"
+
+// bisectScriptTemplate is the script that bisects a failed test with git
+// bisect run. It exits with 125, which makes git bisect skip the commit, if the
+// test does not build, and otherwise with the status of the stress run.
+const bisectScriptTemplate = `#!/usr/bin/env bash
+#
+# Bisects the failure of %[1]s in %[2]s: %[3]s
+#
+# Run it from the root of a clean checkout with:
+#
+#   git bisect start %[4]s %[5]s
+#   git bisect run %[6]s
+#
+set -uo pipefail
+
+make testbuild PKG=%[2]s TAGS='%[7]s' || exit 125
+make stress PKG=%[2]s TESTS='%[8]s' TAGS='%[7]s' TESTTIMEOUT=5m STRESSFLAGS='-maxtime 10m -maxfails 1 -stderr' 2>&1 | tail -n 100
+`
+
+var unsafeFileNameRE = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// bisectScriptName returns the name of the bisect script of the given test.
+func bisectScriptName(testName string) string {
+	return "bisect-" + unsafeFileNameRE.ReplaceAllString(testName, "_") + ".sh"
+}
+
+// testsRegexp returns the -run regexp that only matches the given test, such
+// as ^TestX$/^sub$ for TestX/sub.
+func testsRegexp(testName string) string {
+	parts := strings.Split(testName, "/")
+	for i := range parts {
+		parts[i] = "^" + regexp.QuoteMeta(parts[i]) + "$"
+	}
+	return strings.Join(parts, "/")
+}
+
+// genBisectScript returns the bisect script of the given test. The good SHA is
+// a placeholder if it is not known.
+func genBisectScript(title, packageName, testName, badSHA, goodSHA string) string {
+	if goodSHA == "" {
+		goodSHA = "<good-sha>"
+	}
+	return fmt.Sprintf(bisectScriptTemplate, testName, "./"+issues.PackageDir(packageName),
+		title, badSHA, goodSHA, bisectScriptName(testName), os.Getenv("TAGS"), testsRegexp(testName))
+}
+
+// addBisectScript writes the bisect script of the given failed test to the
+// artifacts, and adds a section linking to it to the given body.
+func addBisectScript(body *issues.Body, title, packageName, testName string) {
+	badSHA := os.Getenv(shaEnv)
+	if badSHA == "" {
+		badSHA = "HEAD"
+	}
+	goodSHA, err := previousGreenSHA()
+	if err != nil {
+		log.Printf("unable to determine the good SHA of the bisect script: %s", err)
+	}
+	script := genBisectScript(title, packageName, testName, badSHA, goodSHA)
+	name := bisectScriptName(testName)
+	if err := ioutil.WriteFile(filepath.Join(artifactsDir, name), []byte(script), 0755); err != nil {
+		log.Printf("failed to create bisect script: %s", err)
+		return
+	}
+
+	// The script is linked from the artifacts tab of the build, if it is known.
+	var link string
+	if serverURL, buildID := os.Getenv(serverURLEnv), os.Getenv(buildIDEnv); serverURL != "" && buildID != "" {
+		options := url.Values{}
+		options.Add("buildId", buildID)
+		options.Add("tab", "artifacts")
+		link = strings.TrimSuffix(serverURL, "/") + "/viewLog.html?" + options.Encode()
+	}
+	body.AddLinkedSection("Bisect with "+name,
+		fmt.Sprintf("git bisect start %s %s\ngit bisect run %s", badSHA, goodSHA, name), link)
+}
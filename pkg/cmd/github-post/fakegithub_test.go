@@ -0,0 +1,278 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeGitHub is a fake of the parts of the GitHub API that are used to post
+// issues. It records the requests that search, create and comment on issues,
+// which are the ones that determine the issues filed by github-post. The
+// requests that look up assignees and milestones are served but not recorded,
+// since whether they are made depends on the git checkout running the test.
+type fakeGitHub struct {
+	mu struct {
+		sync.Mutex
+		// issues maps the titles of the open issues to their numbers.
+		issues     map[string]int
+		nextNumber int
+		requests   []string
+		bodies     []string
+	}
+}
+
+var searchPhraseRE = regexp.MustCompile(`^"([^"]*)"`)
+var commentPathRE = regexp.MustCompile(`^/repos/cockroachdb/cockroach/issues/(\d+)/comments$`)
+
+// reset forgets the recorded requests, and sets the open issues.
+func (g *fakeGitHub) reset(openIssues map[string]int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.mu.issues = make(map[string]int)
+	g.mu.nextNumber = 1000
+	for title, number := range openIssues {
+		g.mu.issues[title] = number
+	}
+	g.mu.requests = nil
+	g.mu.bodies = nil
+}
+
+// recorded returns the recorded requests, and the bodies of the created issues
+// and comments.
+func (g *fakeGitHub) recorded() (requests, bodies []string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.mu.requests, g.mu.bodies
+}
+
+func (g *fakeGitHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var reply interface{}
+	switch path := r.URL.Path; {
+	case r.Method == "GET" && path == "/rate_limit":
+		reset := time.Now().Add(time.Hour).Unix()
+		reply = map[string]interface{}{"resources": map[string]interface{}{
+			"core":   map[string]int64{"limit": 5000, "remaining": 4000, "reset": reset},
+			"search": map[string]int64{"limit": 30, "remaining": 30, "reset": reset},
+		}}
+
+	case r.Method == "GET" && path == "/repos/cockroachdb/cockroach/milestones":
+		reply = []interface{}{}
+
+	case r.Method == "GET" && path == "/repos/cockroachdb/cockroach/commits":
+		reply = []interface{}{map[string]interface{}{"author": map[string]string{"login": "hodor"}}}
+
+	case r.Method == "GET" && path == "/search/issues":
+		query := r.URL.Query().Get("q")
+		g.mu.requests = append(g.mu.requests, "GET "+path+" q="+query)
+		var items []interface{}
+		if m := searchPhraseRE.FindStringSubmatch(query); m != nil {
+			if number, ok := g.mu.issues[m[1]]; ok {
+				items = append(items, map[string]int{"number": number})
+			}
+		}
+		reply = map[string]interface{}{"total_count": len(items), "items": items}
+
+	case r.Method == "POST" && path == "/repos/cockroachdb/cockroach/issues":
+		var issue struct {
+			Title  string   `json:"title"`
+			Body   string   `json:"body"`
+			Labels []string `json:"labels"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&issue); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		g.mu.requests = append(g.mu.requests, fmt.Sprintf("POST %s title=%q labels=%s",
+			path, issue.Title, strings.Join(issue.Labels, ",")))
+		g.mu.bodies = append(g.mu.bodies, issue.Body)
+		g.mu.nextNumber++
+		g.mu.issues[issue.Title] = g.mu.nextNumber
+		reply = map[string]interface{}{"number": g.mu.nextNumber, "title": issue.Title}
+
+	case r.Method == "POST" && commentPathRE.MatchString(path):
+		var comment struct {
+			Body string `json:"body"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&comment); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		g.mu.requests = append(g.mu.requests, "POST "+path)
+		g.mu.bodies = append(g.mu.bodies, comment.Body)
+		reply = map[string]interface{}{"body": comment.Body}
+
+	default:
+		g.mu.requests = append(g.mu.requests, "unexpected "+r.Method+" "+r.URL.String())
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(reply); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// TestPostEndToEnd runs github-post on the recorded logs in testdata against a
+// fake GitHub server, and checks the requests that it makes to file issues.
+func TestPostEndToEnd(t *testing.T) {
+	var g fakeGitHub
+	server := httptest.NewServer(&g)
+	defer server.Close()
+
+	// The issues package reads its configuration from the environment when it
+	// posts the first issue, so it is set once for all the test cases.
+	for key, value := range map[string]string{
+		"GITHUB_API_TOKEN": "token",
+		"GITHUB_API_URL":   server.URL,
+		"BUILD_VCS_NUMBER": "abcd123",
+		"TC_BUILD_ID":      "8008135",
+		"TC_SERVER_URL":    "https://teamcity.example.com",
+	} {
+		if val, ok := os.LookupEnv(key); ok {
+			defer func(key, val string) {
+				if err := os.Setenv(key, val); err != nil {
+					t.Error(err)
+				}
+			}(key, val)
+		} else {
+			defer func(key string) {
+				if err := os.Unsetenv(key); err != nil {
+					t.Error(err)
+				}
+			}(key)
+		}
+		if err := os.Setenv(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	const searchSuffix = ` user:cockroachdb repo:cockroach is:open label:"O-robot" label:"C-test-failure"`
+	search := func(title string) string {
+		return `GET /search/issues q="` + title + `"` + searchSuffix
+	}
+	create := func(title string) string {
+		return fmt.Sprintf("POST /repos/cockroachdb/cockroach/issues title=%q labels=O-robot,C-test-failure", title)
+	}
+	comment := func(number int) string {
+		return "POST /repos/cockroachdb/cockroach/issues/" + strconv.Itoa(number) + "/comments"
+	}
+
+	testCases := []struct {
+		name       string
+		pkgEnv     string
+		fileName   string
+		openIssues map[string]int
+		// expRequests are the recorded requests, and expBodies are substrings of
+		// the bodies of the created issues and comments.
+		expRequests []string
+		expBodies   []string
+	}{
+		{
+			name:     "new issue",
+			pkgEnv:   "github.com/cockroachdb/cockroach/pkg/storage",
+			fileName: "stress-failure.json",
+			expRequests: []string{
+				search("storage: TestReplicateQueueRebalance failed under stress"),
+				create("storage: TestReplicateQueueRebalance failed under stress"),
+			},
+			expBodies: []string{"not balanced: [10 1 10 1 8]"},
+		},
+		{
+			name:     "existing issue",
+			pkgEnv:   "github.com/cockroachdb/cockroach/pkg/storage",
+			fileName: "stress-failure.json",
+			openIssues: map[string]int{
+				"storage: TestReplicateQueueRebalance failed under stress": 30,
+			},
+			expRequests: []string{
+				search("storage: TestReplicateQueueRebalance failed under stress"),
+				comment(30),
+			},
+			expBodies: []string{"not balanced: [10 1 10 1 8]"},
+		},
+		{
+			name:     "timeout and failure",
+			pkgEnv:   "github.com/cockroachdb/cockroach/pkg/kv",
+			fileName: "timeout-culprit-found.json",
+			expRequests: []string{
+				search("kv: TestTxnCoordSenderPipelining failed under stress"),
+				create("kv: TestTxnCoordSenderPipelining failed under stress"),
+				search("kv: TestAbortReadOnlyTransaction timed out under stress"),
+				create("kv: TestAbortReadOnlyTransaction timed out under stress"),
+			},
+			expBodies: []string{"injected failure", "| TestAbortReadOnlyTransaction | 3.99s |"},
+		},
+		{
+			name:     "package failure",
+			pkgEnv:   "github.com/cockroachdb/cockroach/pkg/storage",
+			fileName: "stress-unknown.json",
+			expRequests: []string{
+				search("storage: package failed under stress"),
+				create("storage: package failed under stress"),
+			},
+			expBodies: []string{"make: *** [bin/.submodules-initialized] Error 1"},
+		},
+	}
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			if err := os.Setenv("PKG", c.pkgEnv); err != nil {
+				t.Fatal(err)
+			}
+			g.reset(c.openIssues)
+
+			file, err := os.Open(filepath.Join("testdata", c.fileName))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+			var out bytes.Buffer
+			if err := run(context.Background(), file, &out); err != nil {
+				t.Fatal(err)
+			}
+			if out.Len() > 0 {
+				t.Fatalf("expected the issues to be posted, but they were printed:\n%s", out.String())
+			}
+
+			requests, bodies := g.recorded()
+			if !reflect.DeepEqual(requests, c.expRequests) {
+				t.Fatalf("got requests:\n%s\nexpected:\n%s",
+					strings.Join(requests, "\n"), strings.Join(c.expRequests, "\n"))
+			}
+			if len(bodies) != len(c.expBodies) {
+				t.Fatalf("got %d bodies, expected %d", len(bodies), len(c.expBodies))
+			}
+			for i := range bodies {
+				if !strings.Contains(bodies[i], c.expBodies[i]) {
+					t.Fatalf("expected body containing %s, but got:\n%s", c.expBodies[i], bodies[i])
+				}
+			}
+		})
+	}
+}
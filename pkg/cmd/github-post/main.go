@@ -19,12 +19,16 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
+	"math"
+	"net/http"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"sort"
 	"strconv"
@@ -32,24 +36,109 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/cmd/internal/issues"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/pkg/errors"
 )
 
 const (
 	pkgEnv = "PKG"
+	// unknownTestName is the test name of the issues that are not about a
+	// particular test.
+	unknownTestName = "(unknown)"
+)
+
+var (
+	minRateLimit = flag.Int("min-rate-limit", 0,
+		"the GitHub API rate limit budget below which the issues are printed instead of posted")
+	maxTestOutputKB = flag.Int("max-test-output-kb", 64,
+		"the KB kept from the start and from the end of the output of each failed test (0 keeps all of it)")
+	module = flag.String("module", "",
+		"the path of the module of the tested packages (detected from go.mod by default)")
+	parallelism = flag.Int("parallelism", 0,
+		"the number of runs that stress ran in parallel (detected from the stress flags by default)")
+	slackWebhook = flag.String("slack-webhook", "",
+		"a Slack incoming webhook URL that a message about each failure is posted to")
+	failuresFile = flag.String("failures-file", "",
+		"a file that each failure is appended to as a JSON object")
 )
 
 func main() {
-	ctx := context.Background()
+	flag.Parse()
+	setModulePath()
+	if err := run(context.Background(), os.Stdin, os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
 
-	f := func(ctx context.Context, title, packageName, testName, testMessage, authorEmail string) error {
-		log.Printf("filing issue with title: %s", title)
-		return issues.Post(ctx, title, packageName, testName, testMessage, authorEmail, nil)
+// setModulePath sets the module of the tested packages, which the issue titles
+// and the paths of the test sources are relative to, to --module or else to the
+// module of the go.mod file of the working directory. Without either, the
+// packages are assumed to be in the cockroach module.
+func setModulePath() {
+	path := *module
+	if path == "" {
+		var err error
+		if path, err = issues.DetectModulePath("."); err != nil {
+			log.Printf("unable to detect the module path, assuming %s: %s", issues.ModulePath(), err)
+			return
+		}
 	}
+	issues.SetModulePath(path)
+}
 
-	if err := listFailures(ctx, os.Stdin, f); err != nil {
-		log.Fatal(err)
+// run posts issues for the failures in the given test2json output. If the
+// GitHub API rate limit is below --min-rate-limit, the issues are written to
+// out instead. The failures are also posted to Slack and to a file, if
+// --slack-webhook and --failures-file are set.
+func run(ctx context.Context, input io.Reader, out io.Writer) error {
+	// Check the budget up front rather than running out of it halfway through
+	// posting the issues.
+	online := true
+	if *minRateLimit > 0 && issues.CanPost() {
+		if r, err := issues.RemainingRateLimit(ctx); err != nil {
+			log.Printf("%s; printing issues instead of posting them", err)
+			online = false
+		} else if r.Remaining < *minRateLimit {
+			log.Printf("GitHub API rate limit below --min-rate-limit=%d (%s); printing issues instead of posting them",
+				*minRateLimit, r)
+			online = false
+		}
+	}
+	sinks := fanOut{printSink{w: out}}
+	if online {
+		sinks = fanOut{githubSink{}}
 	}
+	if *slackWebhook != "" {
+		sinks = append(sinks, slackSink{webhookURL: *slackWebhook, client: http.DefaultClient})
+	}
+	if *failuresFile != "" {
+		file, err := os.OpenFile(*failuresFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		sinks = append(sinks, fileSink{w: file})
+	}
+
+	if err := listFailures(ctx, input, sinks); err != nil {
+		return err
+	}
+
+	if online && issues.CanPost() {
+		if r, err := issues.RemainingRateLimit(ctx); err != nil {
+			log.Print(err)
+		} else {
+			log.Printf("GitHub API rate limit after posting issues: %s", r)
+		}
+	}
+	return nil
+}
+
+// messageBody returns the body of an issue that consists of the given message.
+func messageBody(message string) *issues.Body {
+	var body issues.Body
+	body.AddSection("", message, false /* collapsed */)
+	return &body
 }
 
 // This struct is described in the test2json documentation.
@@ -62,11 +151,9 @@ type testEvent struct {
 	Elapsed float64   // seconds
 }
 
-func listFailures(
-	ctx context.Context,
-	input io.Reader,
-	f func(ctx context.Context, title, packageName, testName, testMessage, authorEmail string) error,
-) error {
+// listFailures posts the failures in the given test2json output to the given
+// sink.
+func listFailures(ctx context.Context, input io.Reader, s sink) error {
 	// Tests that took less than this are not even considered for slow test
 	// reporting. This is so that we protect against large number of
 	// programatically-generated subtests.
@@ -79,7 +166,78 @@ func listFailures(
 	if !ok {
 		return errors.Errorf("package name environment variable %s is not set", pkgEnv)
 	}
-	trimmedPkgName := strings.TrimPrefix(packageName, issues.CockroachPkgPrefix)
+	trimmedPkgName := issues.TrimPackageName(packageName)
+
+	// timeline records when the package started and finished, and when the
+	// first test failed or timed out.
+	var timeline runTimeline
+
+	runReport := testReport{
+		Package: packageName,
+		SHA:     os.Getenv(shaEnv),
+		BuildID: os.Getenv(buildIDEnv),
+		Created: timeutil.Now(),
+	}
+	// prevReport is the report of the previous run, which the failures are
+	// compared with, if it is known.
+	prevReport, err := readPreviousReport()
+	if err != nil {
+		log.Printf("unable to compare the failures with the previous run: %s", err)
+	} else if prevReport != nil && prevReport.Package != packageName {
+		log.Printf("ignoring the previous test report of %s, which is not the report of %s",
+			prevReport.Package, packageName)
+		prevReport = nil
+	}
+	// post posts a failure to the sink, and adds it to the test report.
+	post := func(class, title, testName string, body *issues.Body, authorEmail string) error {
+		// The message of the failure is the first section of its body, before
+		// the sections that are added here.
+		var message string
+		if sections := body.Sections(); len(sections) > 0 {
+			message = sections[0].Content
+		}
+		if tl := timeline.String(); tl != "" {
+			body.PrependSection("Run timeline (UTC)", tl, false /* collapsed */)
+		}
+		fingerprint := failureFingerprint(packageName, testName, class)
+		var status string
+		var failingRuns int
+		if prevReport != nil {
+			status, failingRuns = prevReport.failureStatus(fingerprint)
+			body.PrependSection("Status", statusNote(status, failingRuns), false /* collapsed */)
+		}
+		// Package failures happen before the tests run, and are rarely caused
+		// by the commits that touch the package.
+		if class != packageFailureClass {
+			addSuspectCommits(ctx, body, packageName)
+			if testName != unknownTestName {
+				addBisectScript(body, title, packageName, testName)
+			}
+		}
+		url, err := s.post(ctx, &failure{
+			Class:       class,
+			Title:       title,
+			Package:     packageName,
+			Test:        testName,
+			AuthorEmail: authorEmail,
+			Message:     message,
+			Body:        body,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to post issue")
+		}
+		runReport.Failures = append(runReport.Failures, reportedFailure{
+			Test:        testName,
+			Package:     packageName,
+			Class:       class,
+			Fingerprint: fingerprint,
+			Excerpt:     tailOutput(message, reportExcerptBytes),
+			IssueURL:    url,
+			Status:      status,
+			FailingRuns: failingRuns,
+		})
+		return nil
+	}
 
 	var packageOutput bytes.Buffer
 
@@ -94,6 +252,12 @@ func listFailures(
 	failures := make(map[string][]testEvent)
 	var slowPassingTests []testEvent
 	var slowFailingTests []testEvent
+	// passedTests are the tests that passed, which the failures of the previous
+	// run recovered if they were about.
+	passedTests := make(map[string]bool)
+	// durations summarizes the durations of all the tests, for the histogram
+	// of the slow tests report.
+	var durations runDurations
 
 	// init is true for the preamble of the input before the first "run" test
 	// event.
@@ -107,9 +271,22 @@ func listFailures(
 	// failing. In case the input comes from a stress run, this will be used to
 	// deduce the duration of a timed out test.
 	var elapsedTotalSec float64
+	// workers is the number of runs that stress ran in parallel with -p. Each
+	// run is a separate test process whose tests run one after the other, so
+	// the output of a single run accounts for all of elapsedTotalSec. Only if
+	// the output of several runs is interleaved, which is detected by a
+	// top-level test starting while another one is still running, do the
+	// tests take turns on that many workers, and account for elapsedTotalSec
+	// divided by workers.
+	workers := *parallelism
+	runningTests := make(map[string]bool)
+	var interleaved bool
 	// Will be set if the last test timed out.
 	var timedOutTestName string
 	var timedOutEvent testEvent
+	// timeoutStacks accumulates the output of the timed out test from the
+	// timeout message on, which is the dump of the goroutine stacks.
+	var timeoutStacks strings.Builder
 	var curTestStart time.Time
 	var lastTestName string
 	var lastEvent testEvent
@@ -121,12 +298,18 @@ func listFailures(
 			return err
 		}
 		lastEvent = te
+		timeline.observe(te)
 
 		if te.Test != "" {
 			init = false
 		}
-		if init && strings.Contains(te.Output, "-exec 'stress '") {
-			trustTimestamps = false
+		if init {
+			if m := stressExecRE.FindStringSubmatch(te.Output); m != nil {
+				trustTimestamps = false
+				if workers <= 0 {
+					workers = stressParallelism(m[1])
+				}
+			}
 		}
 		if timedOutTestName == "" && te.Elapsed > 0 {
 			// We don't count subtests as those are counted in the parent.
@@ -144,13 +327,23 @@ func listFailures(
 			switch te.Action {
 			case "run":
 				lastTestName = te.Test
+				if !strings.Contains(te.Test, "/") {
+					if len(runningTests) > 0 {
+						interleaved = true
+					}
+					runningTests[te.Test] = true
+				}
 				if trustTimestamps {
 					curTestStart = te.Time
 				}
 			case "output":
 				outstandingOutput[te.Test] = append(outstandingOutput[te.Test], te)
-				if strings.Contains(te.Output, timeoutMsg) {
+				if timedOutTestName != "" && timedOutTestName == te.Test {
+					timeoutStacks.WriteString(te.Output)
+				}
+				if timedOutTestName == "" && strings.Contains(te.Output, timeoutMsg) {
 					timedOutTestName = te.Test
+					timeoutStacks.WriteString(te.Output)
 
 					// Fill in the Elapsed field for a timeout event.
 					// As of go1.11, the Elapsed field is bogus for fail events for timed
@@ -188,19 +381,31 @@ func listFailures(
 							} else if matches[2] != "s" {
 								log.Fatalf("unexpected time unit in: %s", te.Output)
 							}
-							te.Elapsed = dur - elapsedTotalSec
+							runs := 1
+							if interleaved && workers > 1 {
+								runs = workers
+							}
+							te.Elapsed = dur - elapsedTotalSec/float64(runs)
 						}
 					}
 					timedOutEvent = te
+					timeline.timeout, timeline.timedOutTestName = te.Time, te.Test
 				}
 			case "pass", "skip":
 				if timedOutTestName != "" {
 					panic(fmt.Sprintf("detected test timeout but test seems to have passed (%+v)", te))
 				}
 				delete(outstandingOutput, te.Test)
-				if te.Elapsed > shortTestFilterSecs {
-					// We ignore subtests; their time contributes to the parent's.
-					if !strings.Contains(te.Test, "/") {
+				delete(runningTests, te.Test)
+				if te.Action == "pass" {
+					passedTests[te.Test] = true
+				}
+				// We ignore subtests; their time contributes to the parent's.
+				if !strings.Contains(te.Test, "/") {
+					if te.Action == "pass" {
+						durations.add(te.Elapsed)
+					}
+					if te.Elapsed > shortTestFilterSecs {
 						slowPassingTests = append(slowPassingTests, te)
 					}
 				}
@@ -211,6 +416,7 @@ func listFailures(
 				// to be a pass/fail event for it.
 				if !strings.Contains(te.Test, "/") || timedOutTestName == te.Test {
 					slowFailingTests = append(slowFailingTests, te)
+					durations.add(te.Elapsed)
 				}
 				// Move the test to the failures collection unless the test timed out.
 				// We have special reporting for timeouts below.
@@ -218,12 +424,19 @@ func listFailures(
 					failures[te.Test] = outstandingOutput[te.Test]
 				}
 				delete(outstandingOutput, te.Test)
+				delete(runningTests, te.Test)
+			case "pause":
+				// The parallel tests of a run are paused until its sequential tests
+				// are done, so they do not make its output interleaved.
+				delete(runningTests, te.Test)
 			}
 		} else if te.Action == "output" {
 			// Output was outside the context of a test. This consists mostly of the
 			// preamble and epilogue that Make outputs, but also any log messages that
 			// are printed by a test binary's main function.
 			packageOutput.WriteString(te.Output)
+		} else if te.Action == "pass" || te.Action == "fail" {
+			durations.packageSec = te.Elapsed
 		}
 	}
 
@@ -237,6 +450,7 @@ func listFailures(
 	if timedOutTestName != "" {
 		if _, ok := outstandingOutput[timedOutTestName]; ok {
 			slowFailingTests = append(slowFailingTests, timedOutEvent)
+			durations.add(timedOutEvent.Elapsed)
 			delete(outstandingOutput, timedOutTestName)
 		}
 	} else {
@@ -255,15 +469,22 @@ func listFailures(
 	if lastEvent.Action == "fail" && len(failures) == 0 && timedOutTestName == "" {
 		// If we couldn't find a failing Go test, assume that a failure occurred
 		// before running Go and post an issue about that.
-		const unknown = "(unknown)"
 		title := fmt.Sprintf("%s: package failed under stress", trimmedPkgName)
-		if err := f(
-			ctx, title, packageName, unknown, packageOutput.String(), "", /* authorEmail */
+		if err := post(
+			packageFailureClass, title, unknownTestName, messageBody(utcTimestamps(packageOutput.String())), "", /* authorEmail */
 		); err != nil {
-			return errors.Wrap(err, "failed to post issue")
+			return err
 		}
 	} else {
-		for test, testEvents := range failures {
+		// Consolidate the subtests in sorted order, so that the output of the
+		// parent test is deterministic.
+		var failedNames []string
+		for test := range failures {
+			failedNames = append(failedNames, test)
+		}
+		sort.Strings(failedNames)
+		for _, test := range failedNames {
+			testEvents := failures[test]
 			if split := strings.SplitN(test, "/", 2); len(split) == 2 {
 				parentTest, subTest := split[0], split[1]
 				log.Printf("consolidating failed subtest %q into parent test %q", subTest, parentTest)
@@ -289,10 +510,22 @@ func listFailures(
 			for _, testEvent := range testEvents {
 				outputs = append(outputs, testEvent.Output)
 			}
-			message := strings.Join(outputs, "")
+			message := utcTimestamps(elideOutput(strings.Join(outputs, ""), *maxTestOutputKB<<10))
+			body := messageBody(message)
+			if !hasTestOutput(testEvents) {
+				// Stress sometimes truncates the log of a failed run, which leaves
+				// the test without any output. Rather than filing an empty issue,
+				// say so and include the end of the package output, which is what
+				// is left of the log.
+				log.Printf("no output found for failed test %q", test)
+				body = messageBody(missingOutputMsg + message)
+				body.AddSection("Tail of the package output",
+					utcTimestamps(tailOutput(packageOutput.String(), missingOutputTailBytes)),
+					false /* collapsed */)
+			}
 			title := fmt.Sprintf("%s: %s failed under stress", trimmedPkgName, test)
-			if err := f(ctx, title, packageName, test, message, authorEmail); err != nil {
-				return errors.Wrap(err, "failed to post issue")
+			if err := post(testFailureClass, title, test, body, authorEmail); err != nil {
+				return err
 			}
 		}
 	}
@@ -305,7 +538,7 @@ func listFailures(
 		return slowFailingTests[i].Elapsed > slowFailingTests[j].Elapsed
 	})
 
-	report := genSlowTestsReport(slowPassingTests, slowFailingTests)
+	report := genSlowTestsReport(slowPassingTests, slowFailingTests, durations)
 	if err := writeSlowTestsReport(report); err != nil {
 		log.Printf("failed to create slow tests report: %s", err)
 	}
@@ -317,6 +550,12 @@ func listFailures(
 	// 2) Otherwise, we don't blame anybody in particular. We file a generic issue
 	// listing the package name containing the report of long-running tests.
 	if timedOutTestName != "" {
+		// The report is followed by the collapsed goroutine stacks, which are
+		// long and are only needed to debug the timeout.
+		var body issues.Body
+		body.AddMarkdown("", timeoutAncestorsNote(timedOutTestName)+report)
+		body.AddSection("Goroutine stacks at the timeout", utcTimestamps(timeoutStacks.String()),
+			true /* collapsed */)
 		slowest := slowFailingTests[0]
 		if len(slowPassingTests) > 0 && slowPassingTests[0].Elapsed > slowest.Elapsed {
 			slowest = slowPassingTests[0]
@@ -330,8 +569,8 @@ func listFailures(
 			}
 			title := fmt.Sprintf("%s: %s timed out under stress", trimmedPkgName, timedOutTestName)
 			log.Printf("timeout culprit found: %s\n", timedOutTestName)
-			if err := f(ctx, title, packageName, timedOutTestName, report, authorEmail); err != nil {
-				return errors.Wrap(err, "failed to post issue")
+			if err := post(timeoutClass, title, timedOutTestName, &body, authorEmail); err != nil {
+				return err
 			}
 		} else {
 			title := fmt.Sprintf("%s: package timed out under stress", trimmedPkgName)
@@ -340,45 +579,219 @@ func listFailures(
 			// TODO(andrei): Figure out how to assign to the on-call engineer. Maybe
 			// get their name from the Slack channel?
 			log.Printf("timeout culprit not found\n")
-			if err := f(
-				ctx, title, packageName, "(unknown)" /* testName */, report, "andreimatei1@gmail.com",
+			if err := post(
+				timeoutClass, title, unknownTestName, &body, "andreimatei1@gmail.com",
 			); err != nil {
-				return errors.Wrap(err, "failed to post issue")
+				return err
 			}
 		}
 	}
 
+	runReport.TimedOut = timedOutTestName != ""
+	runReport.addSlowTests(slowPassingTests, slowFailingTests)
+	if prevReport != nil {
+		runReport.addRecovered(prevReport, passedTests)
+		for _, f := range runReport.Recovered {
+			log.Printf("%s: %s (%s) after failing in %d runs", f.Status, f.Test, f.Class, f.FailingRuns)
+		}
+	}
+	if err := writeTestReport(&runReport); err != nil {
+		log.Printf("failed to create test report: %s", err)
+	}
 	return nil
 }
 
-func genSlowTestsReport(slowPassingTests, slowFailingTests []testEvent) string {
-	var b strings.Builder
-	b.WriteString("Slow failing tests:\n")
-	for i, te := range slowFailingTests {
-		if i == 20 {
-			break
+// missingOutputMsg starts the message of the issues about failed tests that
+// have no output, and missingOutputTailBytes is how much of the end of the
+// package output these issues include instead.
+const (
+	missingOutputMsg       = "test output missing/truncated\n"
+	missingOutputTailBytes = 4 << 10
+)
+
+// hasTestOutput returns true if the given events of a test have output other
+// than the lines that test2json frames the test with, such as "=== RUN".
+func hasTestOutput(events []testEvent) bool {
+	for _, te := range events {
+		line := strings.TrimSpace(te.Output)
+		if line == "" {
+			continue
 		}
-		fmt.Fprintf(&b, "%s - %.2fs\n", te.Test, te.Elapsed)
+		framing := false
+		for _, prefix := range []string{"=== RUN", "=== PAUSE", "=== CONT", "--- FAIL:"} {
+			if strings.HasPrefix(line, prefix) {
+				framing = true
+				break
+			}
+		}
+		if !framing {
+			return true
+		}
+	}
+	return false
+}
+
+// tailOutput returns the last keep bytes of the given output, starting at a
+// line boundary where possible.
+func tailOutput(output string, keep int) string {
+	if len(output) <= keep {
+		return output
 	}
-	if len(slowFailingTests) == 0 {
-		fmt.Fprint(&b, "<none>\n")
+	tail := output[len(output)-keep:]
+	if i := strings.IndexByte(tail, '\n'); i >= 0 && i+1 < len(tail) {
+		tail = tail[i+1:]
 	}
+	return tail
+}
 
-	b.WriteString("\nSlow passing tests:\n")
-	for i, te := range slowPassingTests {
-		if i == 20 {
-			break
+// elideOutput returns the output of a test, keeping the first and the last
+// keep bytes of it, and replacing the rest with a note of how much was elided,
+// so that a single runaway logger cannot consume the entire issue. The kept
+// parts are cut at line boundaries where possible. A keep of 0 keeps the whole
+// output.
+func elideOutput(output string, keep int) string {
+	if keep <= 0 || len(output) <= 2*keep {
+		return output
+	}
+	head := output[:keep]
+	if i := strings.LastIndexByte(head, '\n'); i >= 0 {
+		head = head[:i+1]
+	}
+	tail := tailOutput(output, keep)
+	elided := len(output) - len(head) - len(tail)
+	return fmt.Sprintf("%s\n[... %d bytes of output elided ...]\n\n%s", head, elided, tail)
+}
+
+// stressExecRE matches the go test invocations that run the tests under
+// stress, and captures the flags of stress.
+var stressExecRE = regexp.MustCompile(`-exec '(?:\S*/)?stress((?: [^']*)?)'`)
+
+// stressParallelismRE matches the -p flag of stress.
+var stressParallelismRE = regexp.MustCompile(`(?:^|\s)-p[ =](\d+)(?:\s|$)`)
+
+// stressParallelism returns the number of parallel runs set by the given stress
+// flags, or 1 if they do not set it.
+func stressParallelism(flags string) int {
+	if m := stressParallelismRE.FindStringSubmatch(flags); m != nil {
+		if p, err := strconv.Atoi(m[1]); err == nil && p > 0 {
+			return p
 		}
-		fmt.Fprintf(&b, "%s - %.2fs\n", te.Test, te.Elapsed)
 	}
-	if len(slowPassingTests) == 0 {
-		fmt.Fprint(&b, "<none>\n")
+	return 1
+}
+
+// timeoutAncestorsNote returns a note naming the ancestors of the given timed
+// out subtest, which never get a pass or fail event and so are missing from the
+// slow tests report. It returns an empty string for top-level tests.
+func timeoutAncestorsNote(timedOutTestName string) string {
+	parts := strings.Split(timedOutTestName, "/")
+	if len(parts) == 1 {
+		return ""
+	}
+	ancestors := make([]string, len(parts)-1)
+	for i := range ancestors {
+		ancestors[i] = "`" + strings.Join(parts[:i+1], "/") + "`"
 	}
+	return fmt.Sprintf("`%s` timed out; ancestors %s also affected, and are missing from the report.\n\n",
+		timedOutTestName, strings.Join(ancestors, ", "))
+}
+
+// runDurations summarizes the durations of the tests of a run.
+type runDurations struct {
+	// tests are the durations of the top-level tests, in seconds.
+	tests []float64
+	// packageSec is the wall time of the package, if it is known.
+	packageSec float64
+}
+
+func (d *runDurations) add(elapsedSec float64) {
+	// The duration of a timed out test is -1 if the timeout message could not
+	// be parsed.
+	if elapsedSec >= 0 {
+		d.tests = append(d.tests, elapsedSec)
+	}
+}
+
+// durationBuckets are the buckets of the histogram of test durations, by their
+// upper bound in seconds.
+var durationBuckets = []struct {
+	label string
+	max   float64
+}{
+	{"< 1s", 1},
+	{"1s - 10s", 10},
+	{"10s - 1m", 60},
+	{"1m - 5m", 5 * 60},
+	{">= 5m", math.Inf(1)},
+}
+
+// maxHistogramBar is the width of the bar of the largest bucket of the
+// histogram of test durations.
+const maxHistogramBar = 30
+
+// genSlowTestsReport returns a Markdown report of the slowest failing and
+// passing tests, with a histogram of the durations of all the tests and the
+// total time spent in tests and in the package.
+func genSlowTestsReport(
+	slowPassingTests, slowFailingTests []testEvent, durations runDurations,
+) string {
+	var b strings.Builder
+	writeTests := func(heading string, tests []testEvent) {
+		fmt.Fprintf(&b, "#### %s\n\n", heading)
+		if len(tests) == 0 {
+			b.WriteString("<none>\n")
+			return
+		}
+		b.WriteString("| Test | Duration |\n| --- | ---: |\n")
+		for i, te := range tests {
+			if i == 20 {
+				break
+			}
+			fmt.Fprintf(&b, "| %s | %.2fs |\n", markdownCell(te.Test), te.Elapsed)
+		}
+	}
+	writeTests("Slow failing tests", slowFailingTests)
+	b.WriteString("\n")
+	writeTests("Slow passing tests", slowPassingTests)
+
+	counts := make([]int, len(durationBuckets))
+	var maxCount int
+	var totalSec float64
+	for _, sec := range durations.tests {
+		totalSec += sec
+		for i := range durationBuckets {
+			if sec < durationBuckets[i].max {
+				counts[i]++
+				if counts[i] > maxCount {
+					maxCount = counts[i]
+				}
+				break
+			}
+		}
+	}
+	b.WriteString("\n#### Test durations\n\n| Duration | Tests | |\n| --- | ---: | --- |\n")
+	for i := range durationBuckets {
+		var bar string
+		if counts[i] > 0 {
+			bar = strings.Repeat("█", 1+(maxHistogramBar-1)*counts[i]/maxCount)
+		}
+		fmt.Fprintf(&b, "| %s | %d | %s |\n", durationBuckets[i].label, counts[i], bar)
+	}
+	fmt.Fprintf(&b, "\nTotal test time: %.2fs", totalSec)
+	if durations.packageSec > 0 {
+		fmt.Fprintf(&b, ", package wall time: %.2fs", durations.packageSec)
+	}
+	b.WriteString("\n")
 	return b.String()
 }
 
+// markdownCell escapes the given text for a cell of a Markdown table.
+func markdownCell(text string) string {
+	return strings.Replace(text, "|", "\\|", -1)
+}
+
 func writeSlowTestsReport(report string) error {
-	return ioutil.WriteFile("artifacts/slow-tests-report.txt", []byte(report), 0644)
+	return ioutil.WriteFile(filepath.Join(artifactsDir, "slow-tests-report.txt"), []byte(report), 0644)
 }
 
 func getAuthorEmail(ctx context.Context, packageName, testName string) (string, error) {
@@ -388,7 +801,7 @@ func getAuthorEmail(ctx context.Context, packageName, testName string) (string,
 	// commits in cockroachdb/cockroach for commits authored by the address.
 	subtests := strings.Split(testName, "/")
 	testName = subtests[0]
-	packageName = strings.TrimPrefix(packageName, "github.com/cockroachdb/cockroach/")
+	packageName = issues.PackageDir(packageName)
 	cmd := exec.Command(`/bin/bash`, `-c`,
 		fmt.Sprintf(`git grep -n "func %s" $(git rev-parse --show-toplevel)/%s/*_test.go`,
 			testName, packageName))
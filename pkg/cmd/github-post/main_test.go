@@ -11,19 +11,38 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
 	"strings"
 	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/cmd/internal/issues"
 )
 
+var flagRewriteGolden = flag.Bool("rewrite-golden", false, "rewrite the golden issue sets of the recorded logs")
+
 func TestListFailures(t *testing.T) {
 	type issue struct {
 		testName string
 		title    string
 		message  string
-		author   string
+		// extra, if set, is expected in a second section that follows the
+		// message, such as the goroutine stacks of timeout issues.
+		extra  string
+		author string
 	}
 	// Each test case expects a number of issues.
 	testCases := []struct {
@@ -95,13 +114,20 @@ func TestListFailures(t *testing.T) {
 				{
 					testName: "TestAbortReadOnlyTransaction",
 					title:    "kv: TestAbortReadOnlyTransaction timed out under stress",
-					message: `Slow failing tests:
-TestAbortReadOnlyTransaction - 3.99s
-TestTxnCoordSenderPipelining - 1.00s
+					message: `#### Slow failing tests
+
+| Test | Duration |
+| --- | ---: |
+| TestAbortReadOnlyTransaction | 3.99s |
+| TestTxnCoordSenderPipelining | 1.00s |
 
-Slow passing tests:
-TestAnchorKey - 1.01s
+#### Slow passing tests
+
+| Test | Duration |
+| --- | ---: |
+| TestAnchorKey | 1.01s |
 `,
+					extra:  "goroutine 38 [running]:",
 					author: "andrei@cockroachlabs.com",
 				},
 			},
@@ -117,12 +143,19 @@ TestAnchorKey - 1.01s
 				{
 					testName: "(unknown)",
 					title:    "kv: package timed out under stress",
-					message: `Slow failing tests:
-TestXXX/sub3 - 0.50s
+					message: `#### Slow failing tests
+
+| Test | Duration |
+| --- | ---: |
+| TestXXX/sub3 | 0.50s |
 
-Slow passing tests:
-TestXXA - 1.00s
+#### Slow passing tests
+
+| Test | Duration |
+| --- | ---: |
+| TestXXA | 1.00s |
 `,
+					extra:  "goroutine 16 [running]:",
 					author: "",
 				},
 			},
@@ -137,13 +170,20 @@ TestXXA - 1.00s
 				{
 					testName: "(unknown)",
 					title:    "kv: package timed out under stress",
-					message: `Slow failing tests:
-TestXXX/sub1 - 0.49s
+					message: `#### Slow failing tests
+
+| Test | Duration |
+| --- | ---: |
+| TestXXX/sub1 | 0.49s |
+
+#### Slow passing tests
 
-Slow passing tests:
-TestXXB - 1.01s
-TestXXA - 1.00s
+| Test | Duration |
+| --- | ---: |
+| TestXXB | 1.01s |
+| TestXXA | 1.00s |
 `,
+					extra:  "goroutine 38 [running]:",
 					author: "",
 				},
 			},
@@ -158,17 +198,58 @@ TestXXA - 1.00s
 				{
 					testName: "TestXXX/sub2",
 					title:    "kv: TestXXX/sub2 timed out under stress",
-					message: `Slow failing tests:
-TestXXX/sub2 - 2.99s
+					message: `#### Slow failing tests
+
+| Test | Duration |
+| --- | ---: |
+| TestXXX/sub2 | 2.99s |
+
+#### Slow passing tests
 
-Slow passing tests:
-TestXXB - 1.01s
-TestXXA - 1.00s
+| Test | Duration |
+| --- | ---: |
+| TestXXB | 1.01s |
+| TestXXA | 1.00s |
 `,
+					extra:  "goroutine 42 [running]:",
 					author: "",
 				},
 			},
 		},
+		{
+			// Like the above, except that stress ran two runs in parallel and their
+			// output is interleaved, so the tests that ran before the timed out one
+			// account for half their time.
+			pkgEnv:   "github.com/cockroachdb/cockroach/pkg/kv",
+			fileName: "stress-timeout-parallel.json",
+			expPkg:   "github.com/cockroachdb/cockroach/pkg/kv",
+			expIssues: []issue{
+				{
+					testName: "TestXXX/sub2",
+					title:    "kv: TestXXX/sub2 timed out under stress",
+					message:  "| TestXXX/sub2 | 4.00s |",
+					extra:    "goroutine 42 [running]:",
+					author:   "",
+				},
+			},
+		},
+		{
+			// Like the above, except that the output is that of a single one of the
+			// runs, whose tests ran one after the other, so the tests that ran before
+			// the timed out one account for all their time.
+			pkgEnv:   "github.com/cockroachdb/cockroach/pkg/kv",
+			fileName: "stress-timeout-parallel-single-run.json",
+			expPkg:   "github.com/cockroachdb/cockroach/pkg/kv",
+			expIssues: []issue{
+				{
+					testName: "TestXXX/sub2",
+					title:    "kv: TestXXX/sub2 timed out under stress",
+					message:  "| TestXXX/sub2 | 2.99s |",
+					extra:    "goroutine 42 [running]:",
+					author:   "",
+				},
+			},
+		},
 		{
 			// A panic in a test.
 			pkgEnv:   "github.com/cockroachdb/cockroach/pkg/kv",
@@ -183,6 +264,21 @@ TestXXA - 1.00s
 				},
 			},
 		},
+		{
+			// A failed test whose output was lost, because stress truncated the log.
+			pkgEnv:   "github.com/cockroachdb/cockroach/pkg/kv",
+			fileName: "stress-missing-output.json",
+			expPkg:   "github.com/cockroachdb/cockroach/pkg/kv",
+			expIssues: []issue{
+				{
+					testName: "TestXXX",
+					title:    "kv: TestXXX failed under stress",
+					message:  "test output missing/truncated",
+					extra:    "ERROR: exit status 1",
+					author:   "",
+				},
+			},
+		},
 		{
 			// A panic outside of a test (in this case, in a package init function).
 			pkgEnv:   "github.com/cockroachdb/cockroach/pkg/kv",
@@ -211,29 +307,42 @@ TestXXA - 1.00s
 			defer file.Close()
 			curIssue := 0
 
-			f := func(_ context.Context, title, packageName, testName, testMessage, author string) error {
+			f := sinkFunc(func(_ context.Context, f *failure) (string, error) {
 				if curIssue >= len(c.expIssues) {
-					t.Fatalf("unexpected issue filed. title: %s", title)
+					t.Fatalf("unexpected issue filed. title: %s", f.Title)
+				}
+				if exp := c.expPkg; exp != f.Package {
+					t.Fatalf("expected package %s, but got %s", exp, f.Package)
+				}
+				if exp := c.expIssues[curIssue].testName; exp != f.Test {
+					t.Fatalf("expected test name %s, but got %s", exp, f.Test)
 				}
-				if exp := c.expPkg; exp != packageName {
-					t.Fatalf("expected package %s, but got %s", exp, packageName)
+				if exp := c.expIssues[curIssue].author; exp != "" && exp != f.AuthorEmail {
+					t.Fatalf("expected author %s, but got %s", exp, f.AuthorEmail)
 				}
-				if exp := c.expIssues[curIssue].testName; exp != testName {
-					t.Fatalf("expected test name %s, but got %s", exp, testName)
+				if exp := c.expIssues[curIssue].title; exp != f.Title {
+					t.Fatalf("expected title %s, but got %s", exp, f.Title)
 				}
-				if exp := c.expIssues[curIssue].author; exp != "" && exp != author {
-					t.Fatalf("expected author %s, but got %s", exp, author)
+				if exp := c.expIssues[curIssue].message; !strings.Contains(f.Message, exp) {
+					t.Fatalf("expected message containing %s, but got:\n%s", exp, f.Message)
 				}
-				if exp := c.expIssues[curIssue].title; exp != title {
-					t.Fatalf("expected title %s, but got %s", exp, title)
+				// The message is followed by the extra section, if any, and may be
+				// preceded by the timeline of the run.
+				sections := f.Body.Sections()
+				if len(sections) > 0 && sections[0].Title == "Run timeline (UTC)" {
+					sections = sections[1:]
 				}
-				if exp := c.expIssues[curIssue].message; !strings.Contains(testMessage, exp) {
-					t.Fatalf("expected message containing %s, but got:\n%s", exp, testMessage)
+				if exp := c.expIssues[curIssue].extra; exp != "" {
+					if len(sections) != 2 || !strings.Contains(sections[1].Content, exp) {
+						t.Fatalf("expected a second section containing %s, but got:\n%+v", exp, sections[1:])
+					}
+				} else if len(sections) != 1 {
+					t.Fatalf("expected a single section, but got:\n%+v", sections)
 				}
 				// On next invocation, we'll check the next expected issue.
 				curIssue++
-				return nil
-			}
+				return "", nil
+			})
 			if err := listFailures(context.Background(), file, f); err != nil {
 				t.Fatal(err)
 			}
@@ -243,3 +352,469 @@ TestXXA - 1.00s
 		})
 	}
 }
+
+func TestElideOutput(t *testing.T) {
+	testCases := []struct {
+		output   string
+		keep     int
+		expected string
+	}{
+		{"short", 0, "short"},
+		{"short", 10, "short"},
+		{"aaaa\nbbbb\ncccc\ndddd\n", 10, "aaaa\nbbbb\ncccc\ndddd\n"},
+		{"aaaa\nbbbb\ncccc\ndddd\neeee\n", 10,
+			"aaaa\nbbbb\n\n[... 10 bytes of output elided ...]\n\neeee\n"},
+		{"aaaaaaaa\nbbbbbbbbbbbbbbbbbbbb\ncccccccc\n", 12,
+			"aaaaaaaa\n\n[... 21 bytes of output elided ...]\n\ncccccccc\n"},
+		{strings.Repeat("x", 30), 10,
+			strings.Repeat("x", 10) + "\n[... 10 bytes of output elided ...]\n\n" + strings.Repeat("x", 10)},
+	}
+	for _, c := range testCases {
+		if actual := elideOutput(c.output, c.keep); actual != c.expected {
+			t.Errorf("elideOutput(%q, %d): got %q, expected %q", c.output, c.keep, actual, c.expected)
+		}
+	}
+}
+
+// corpusPackages maps the recorded logs in testdata to the package that they
+// were recorded for, which github-post gets from the PKG environment variable.
+var corpusPackages = map[string]string{
+	"parallel-subtests.json":                  "github.com/cockroachdb/cockroach/pkg/testutils/lint",
+	"race.json":                               "github.com/cockroachdb/cockroach/pkg/sql/sem/builtins",
+	"stress-failure.json":                     "github.com/cockroachdb/cockroach/pkg/storage",
+	"stress-fatal.json":                       "github.com/cockroachdb/cockroach/pkg/storage",
+	"stress-init-panic.json":                  "github.com/cockroachdb/cockroach/pkg/kv",
+	"stress-missing-output.json":              "github.com/cockroachdb/cockroach/pkg/kv",
+	"stress-panic.json":                       "github.com/cockroachdb/cockroach/pkg/kv",
+	"stress-subtests.json":                    "github.com/cockroachdb/cockroach/pkg/util/json",
+	"stress-timeout-culprit-found.json":       "github.com/cockroachdb/cockroach/pkg/kv",
+	"stress-timeout-culprit-not-found.json":   "github.com/cockroachdb/cockroach/pkg/kv",
+	"stress-timeout-parallel.json":            "github.com/cockroachdb/cockroach/pkg/kv",
+	"stress-timeout-parallel-single-run.json": "github.com/cockroachdb/cockroach/pkg/kv",
+	"stress-unknown.json":                     "github.com/cockroachdb/cockroach/pkg/storage",
+	"timeout-culprit-found.json":              "github.com/cockroachdb/cockroach/pkg/kv",
+	"timeout-culprit-not-found.json":          "github.com/cockroachdb/cockroach/pkg/kv",
+}
+
+// TestListFailuresGolden replays the recorded logs in testdata through
+// listFailures, and compares the issues that it files with the golden issue
+// sets in the .golden files next to the logs, so that changes to the parser are
+// reviewed as diffs of the golden files. Run with -rewrite-golden to update
+// them. The authors of the issues are not part of the golden files, since they
+// are looked up in the git checkout running the test.
+func TestListFailuresGolden(t *testing.T) {
+	logs, err := filepath.Glob(filepath.Join("testdata", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, log := range logs {
+		names = append(names, filepath.Base(log))
+	}
+	var expectedNames []string
+	for name := range corpusPackages {
+		expectedNames = append(expectedNames, name)
+	}
+	sort.Strings(expectedNames)
+	if fmt.Sprint(names) != fmt.Sprint(expectedNames) {
+		t.Fatalf("the recorded logs %v do not match corpusPackages %v", names, expectedNames)
+	}
+
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			if err := os.Setenv("PKG", corpusPackages[name]); err != nil {
+				t.Fatal(err)
+			}
+			file, err := os.Open(filepath.Join("testdata", name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+
+			var b strings.Builder
+			f := sinkFunc(func(_ context.Context, f *failure) (string, error) {
+				fmt.Fprintf(&b, "issue: %s\npackage: %s\ntest: %s\n", f.Title, f.Package, f.Test)
+				for _, s := range f.Body.Sections() {
+					switch {
+					case s.Markdown:
+						fmt.Fprintf(&b, "%s\n", strings.TrimSpace("markdown: "+s.Title))
+					case s.Title == "":
+						b.WriteString("message:\n")
+					case s.Collapsed:
+						fmt.Fprintf(&b, "collapsed: %s\n", s.Title)
+					default:
+						fmt.Fprintf(&b, "section: %s\n", s.Title)
+					}
+					fmt.Fprintf(&b, "%s\n", strings.TrimSuffix(s.Content, "\n"))
+				}
+				b.WriteString("----\n")
+				return "", nil
+			})
+			if err := listFailures(context.Background(), file, f); err != nil {
+				t.Fatal(err)
+			}
+
+			goldenPath := filepath.Join("testdata", strings.TrimSuffix(name, ".json")+".golden")
+			if *flagRewriteGolden {
+				if err := ioutil.WriteFile(goldenPath, []byte(b.String()), 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			expected, err := ioutil.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual := b.String(); actual != string(expected) {
+				t.Fatalf("issues differ from %s (rerun with -rewrite-golden to update it):\n%s",
+					goldenPath, actual)
+			}
+		})
+	}
+}
+
+func TestTestReport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "github-post")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(old string) { artifactsDir = old }(artifactsDir)
+	artifactsDir = dir
+
+	const pkg = "github.com/cockroachdb/cockroach/pkg/kv"
+	if err := os.Setenv("PKG", pkg); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(filepath.Join("testdata", "timeout-culprit-found.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	issueNumber := 100
+	f := sinkFunc(func(context.Context, *failure) (string, error) {
+		issueNumber++
+		return fmt.Sprintf("https://github.com/cockroachdb/cockroach/issues/%d", issueNumber), nil
+	})
+	if err := listFailures(context.Background(), file, f); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "test-report.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var r testReport
+	if err := json.Unmarshal(data, &r); err != nil {
+		t.Fatal(err)
+	}
+	if r.Package != pkg || !r.TimedOut || r.Created.IsZero() {
+		t.Fatalf("unexpected metadata in report:\n%s", data)
+	}
+	if len(r.Failures) != 2 {
+		t.Fatalf("expected 2 failures, got:\n%s", data)
+	}
+	for i, exp := range []reportedFailure{
+		{
+			Test:        "TestTxnCoordSenderPipelining",
+			Package:     pkg,
+			Class:       testFailureClass,
+			Fingerprint: failureFingerprint(pkg, "TestTxnCoordSenderPipelining", testFailureClass),
+			IssueURL:    "https://github.com/cockroachdb/cockroach/issues/101",
+		},
+		{
+			Test:        "TestAbortReadOnlyTransaction",
+			Package:     pkg,
+			Class:       timeoutClass,
+			Fingerprint: failureFingerprint(pkg, "TestAbortReadOnlyTransaction", timeoutClass),
+			IssueURL:    "https://github.com/cockroachdb/cockroach/issues/102",
+		},
+	} {
+		actual := r.Failures[i]
+		if actual.Excerpt == "" {
+			t.Errorf("failure %d has no excerpt", i)
+		}
+		actual.Excerpt = ""
+		if actual != exp {
+			t.Errorf("failure %d: got %+v, expected %+v", i, actual, exp)
+		}
+	}
+	if exp := []reportedTest{{Test: "TestAnchorKey", ElapsedSec: 1.01}}; !reflect.DeepEqual(r.SlowPassingTests, exp) {
+		t.Errorf("got slow passing tests %+v, expected %+v", r.SlowPassingTests, exp)
+	}
+	if len(r.SlowFailingTests) != 2 || r.SlowFailingTests[0].Test != "TestAbortReadOnlyTransaction" {
+		t.Errorf("unexpected slow failing tests %+v", r.SlowFailingTests)
+	}
+
+	// Run again with the report as the previous one, which also has a failure
+	// of a test that passes and one of a test that does not run.
+	r.Failures[1].FailingRuns = 2
+	r.Failures = append(r.Failures,
+		reportedFailure{Test: "TestAnchorKey", Package: pkg, Class: testFailureClass,
+			Fingerprint: failureFingerprint(pkg, "TestAnchorKey", testFailureClass)},
+		reportedFailure{Test: "TestNotRun", Package: pkg, Class: testFailureClass,
+			Fingerprint: failureFingerprint(pkg, "TestNotRun", testFailureClass)},
+	)
+	// The failure of the first test is replaced with a new one.
+	r.Failures[0].Fingerprint = "000000000000"
+	data, err = json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prevPath := filepath.Join(dir, "previous-report.json")
+	if err := ioutil.WriteFile(prevPath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer func(old string) { *previousReportFile = old }(*previousReportFile)
+	*previousReportFile = prevPath
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	var statuses []string
+	f = sinkFunc(func(_ context.Context, f *failure) (string, error) {
+		statuses = append(statuses, f.Body.Sections()[0].Content)
+		return "", nil
+	})
+	if err := listFailures(context.Background(), file, f); err != nil {
+		t.Fatal(err)
+	}
+	if exp := []string{
+		"NEW: this failure did not happen in the previous run",
+		"STILL FAILING: this failure also happened in the previous 2 runs",
+	}; !reflect.DeepEqual(statuses, exp) {
+		t.Errorf("got statuses %q, expected %q", statuses, exp)
+	}
+	data, err = ioutil.ReadFile(filepath.Join(dir, "test-report.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r = testReport{}
+	if err := json.Unmarshal(data, &r); err != nil {
+		t.Fatal(err)
+	}
+	for i, exp := range []struct {
+		status string
+		runs   int
+	}{{newStatus, 1}, {stillFailingStatus, 3}} {
+		if actual := r.Failures[i]; actual.Status != exp.status || actual.FailingRuns != exp.runs {
+			t.Errorf("failure %d: got %s in %d runs, expected %s in %d runs",
+				i, actual.Status, actual.FailingRuns, exp.status, exp.runs)
+		}
+	}
+	if len(r.Recovered) != 1 || r.Recovered[0].Test != "TestAnchorKey" ||
+		r.Recovered[0].Status != recoveredStatus || r.Recovered[0].FailingRuns != 1 {
+		t.Errorf("unexpected recovered failures %+v", r.Recovered)
+	}
+}
+
+func TestGenSlowTestsReport(t *testing.T) {
+	slowFailingTests := []testEvent{{Test: "TestX/a|b", Elapsed: 75}}
+	durations := runDurations{tests: []float64{0.1, 0.2, 3, 75}, packageSec: 80}
+	const expected = `#### Slow failing tests
+
+| Test | Duration |
+| --- | ---: |
+| TestX/a\|b | 75.00s |
+
+#### Slow passing tests
+
+<none>
+
+#### Test durations
+
+| Duration | Tests | |
+| --- | ---: | --- |
+| < 1s | 2 | ` + "██████████████████████████████" + ` |
+| 1s - 10s | 1 | ` + "███████████████" + ` |
+| 10s - 1m | 0 |  |
+| 1m - 5m | 1 | ` + "███████████████" + ` |
+| >= 5m | 0 |  |
+
+Total test time: 78.30s, package wall time: 80.00s
+`
+	if actual := genSlowTestsReport(nil, slowFailingTests, durations); actual != expected {
+		t.Fatalf("got:\n%s\nexpected:\n%s", actual, expected)
+	}
+}
+
+func TestTimeoutAncestorsNote(t *testing.T) {
+	for name, expected := range map[string]string{
+		"TestX": "",
+		"TestX/sub": "`TestX/sub` timed out; ancestors `TestX` also affected, " +
+			"and are missing from the report.\n\n",
+		"TestX/sub/leaf": "`TestX/sub/leaf` timed out; ancestors `TestX`, `TestX/sub` also affected, " +
+			"and are missing from the report.\n\n",
+	} {
+		if actual := timeoutAncestorsNote(name); actual != expected {
+			t.Errorf("%s: got %q, expected %q", name, actual, expected)
+		}
+	}
+}
+
+func TestStressParallelism(t *testing.T) {
+	for flags, expected := range map[string]int{
+		" ":                               1,
+		" -maxruns 1 -maxfails 1 -stderr": 1,
+		" -p 4":                           4,
+		" -maxtime 20m -p=8 -stderr":      8,
+		" -pp 4":                          1,
+	} {
+		if actual := stressParallelism(flags); actual != expected {
+			t.Errorf("%q: got %d, expected %d", flags, actual, expected)
+		}
+	}
+}
+
+func TestSuspectCommits(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir, err := ioutil.TempDir("", "github-post")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	git := func(args ...string) string {
+		cmd := exec.Command("git", append([]string{
+			"-c", "user.name=Hodor", "-c", "user.email=hodor@example.com",
+		}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s: %s %s", strings.Join(args, " "), err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	commit := func(file, message string) {
+		path := filepath.Join(dir, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(message), 0644); err != nil {
+			t.Fatal(err)
+		}
+		git("add", file)
+		git("commit", "-q", "-m", message)
+	}
+	git("init", "-q")
+	commit("pkg/kv/kv.go", "kv: initial")
+	green := git("rev-parse", "HEAD")
+	commit("pkg/kv/txn.go", "kv: add txn")
+	commit("pkg/storage/store.go", "storage: add store")
+	commit("pkg/kv/kv.go", "kv: fix kv")
+
+	commits, err := suspectCommits(context.Background(), filepath.Join(dir, "pkg"), green, "HEAD",
+		"github.com/cockroachdb/cockroach/pkg/kv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	re := regexp.MustCompile(`^[0-9a-f]+ kv: fix kv \(Hodor <hodor@example.com>\)\n[0-9a-f]+ kv: add txn \(Hodor <hodor@example.com>\)$`)
+	if !re.MatchString(commits) {
+		t.Fatalf("unexpected suspect commits:\n%s", commits)
+	}
+}
+
+func TestBisectScript(t *testing.T) {
+	const pkg = "github.com/cockroachdb/cockroach/pkg/kv"
+	const test = "TestXXX/sub[1]"
+	if name, exp := bisectScriptName(test), "bisect-TestXXX_sub_1_.sh"; name != exp {
+		t.Errorf("got name %s, expected %s", name, exp)
+	}
+	if re, exp := testsRegexp(test), `^TestXXX$/^sub\[1\]$`; re != exp {
+		t.Errorf("got regexp %s, expected %s", re, exp)
+	}
+	script := genBisectScript("kv: TestXXX/sub[1] failed under stress", pkg, test, "abcd123", "")
+	for _, exp := range []string{
+		"#   git bisect start abcd123 <good-sha>\n#   git bisect run bisect-TestXXX_sub_1_.sh\n",
+		"make testbuild PKG=./pkg/kv TAGS='' || exit 125\n",
+		`make stress PKG=./pkg/kv TESTS='^TestXXX$/^sub\[1\]$'`,
+	} {
+		if !strings.Contains(script, exp) {
+			t.Errorf("expected script containing %s, got:\n%s", exp, script)
+		}
+	}
+
+	dir, err := ioutil.TempDir("", "github-post")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(old string) { artifactsDir = old }(artifactsDir)
+	artifactsDir = dir
+	var body issues.Body
+	addBisectScript(&body, "kv: TestXXX failed under stress", pkg, "TestXXX")
+	if _, err := os.Stat(filepath.Join(dir, "bisect-TestXXX.sh")); err != nil {
+		t.Fatal(err)
+	}
+	if sections := body.Sections(); len(sections) != 1 ||
+		!strings.HasSuffix(sections[0].Content, "git bisect run bisect-TestXXX.sh") {
+		t.Fatalf("unexpected sections %+v", sections)
+	}
+}
+
+func TestSinks(t *testing.T) {
+	var slackMessages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg struct {
+			Text string `json:"text"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		slackMessages = append(slackMessages, msg.Text)
+	}))
+	defer server.Close()
+
+	const issueURL = "https://github.com/cockroachdb/cockroach/issues/1234"
+	var printed, written bytes.Buffer
+	sinks := fanOut{
+		printSink{w: &printed},
+		sinkFunc(func(context.Context, *failure) (string, error) { return issueURL, nil }),
+		slackSink{webhookURL: server.URL, client: server.Client()},
+		fileSink{w: &written},
+	}
+	f := &failure{
+		Class:   testFailureClass,
+		Title:   "kv: TestXXX failed under stress",
+		Package: "github.com/cockroachdb/cockroach/pkg/kv",
+		Test:    "TestXXX",
+		Body:    messageBody("boom"),
+	}
+	if url, err := sinks.post(context.Background(), f); err != nil {
+		t.Fatal(err)
+	} else if url != issueURL {
+		t.Fatalf("got URL %s, expected %s", url, issueURL)
+	}
+
+	if exp := "=== ISSUE: kv: TestXXX failed under stress\n"; !strings.HasPrefix(printed.String(), exp) {
+		t.Errorf("expected printed issue starting with %q, got:\n%s", exp, printed.String())
+	}
+	if exp := []string{"<" + issueURL + "|kv: TestXXX failed under stress>"}; !reflect.DeepEqual(slackMessages, exp) {
+		t.Errorf("got Slack messages %q, expected %q", slackMessages, exp)
+	}
+	var decoded struct {
+		Class    string           `json:"class"`
+		Test     string           `json:"test"`
+		IssueURL string           `json:"issue_url"`
+		Sections []issues.Section `json:"sections"`
+	}
+	if err := json.Unmarshal(written.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Class != testFailureClass || decoded.Test != "TestXXX" || decoded.IssueURL != issueURL ||
+		len(decoded.Sections) != 1 || decoded.Sections[0].Content != "boom" {
+		t.Errorf("unexpected failure written: %s", written.String())
+	}
+}
+
+func TestUTCTimestamps(t *testing.T) {
+	const output = "I180711 20:06:50.862808 1 rand.go:75  Random seed: 1\n" +
+		"deadline 2018-09-08T10:18:02.418188667-04:00 exceeded at 2018-09-08 10:18:13.5 -0400 EDT\n"
+	const expected = "I180711 20:06:50.862808 1 rand.go:75  Random seed: 1\n" +
+		"deadline 2018-09-08T14:18:02.418188667Z exceeded at 2018-09-08 14:18:13.5 +0000 UTC\n"
+	if actual := utcTimestamps(output); actual != expected {
+		t.Fatalf("got:\n%s\nexpected:\n%s", actual, expected)
+	}
+}
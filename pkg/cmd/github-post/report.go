@@ -0,0 +1,197 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package main
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	shaEnv     = "BUILD_VCS_NUMBER"
+	buildIDEnv = "TC_BUILD_ID"
+)
+
+var previousReportFile = flag.String("previous-report", "",
+	"the test report of the previous run of the package, against which the failures are reported as new, still failing or recovered")
+
+// artifactsDir is the directory that the reports are written to.
+var artifactsDir = "artifacts"
+
+// These are the classes of the failures in the test report.
+const (
+	testFailureClass    = "test-failure"
+	packageFailureClass = "package-failure"
+	timeoutClass        = "timeout"
+)
+
+// These are the statuses of the failures in the test report, relative to the
+// previous report.
+const (
+	newStatus          = "NEW"
+	stillFailingStatus = "STILL FAILING"
+	recoveredStatus    = "RECOVERED"
+)
+
+// reportExcerptBytes is how much of the end of the message of a failure the
+// test report includes.
+const reportExcerptBytes = 1 << 10
+
+// testReport is the structured report of a run, which is written to
+// artifacts/test-report.json alongside the slow tests report, so that
+// dashboards can consume the results of runs without parsing their logs.
+type testReport struct {
+	Package string `json:"package"`
+	// SHA and BuildID identify the build, if they are known.
+	SHA      string    `json:"sha,omitempty"`
+	BuildID  string    `json:"build_id,omitempty"`
+	Created  time.Time `json:"created"`
+	TimedOut bool      `json:"timed_out"`
+	// Failures are the failures that issues were filed for, in the order that
+	// they were filed.
+	Failures         []reportedFailure `json:"failures"`
+	SlowPassingTests []reportedTest    `json:"slow_passing_tests"`
+	SlowFailingTests []reportedTest    `json:"slow_failing_tests"`
+	// Recovered are the failures of the previous report that did not happen
+	// again, if the previous report is known.
+	Recovered []reportedFailure `json:"recovered,omitempty"`
+}
+
+// reportedFailure is a failure in the test report.
+type reportedFailure struct {
+	Test    string `json:"test"`
+	Package string `json:"package"`
+	// Class is one of testFailureClass, packageFailureClass and timeoutClass.
+	Class string `json:"class"`
+	// Fingerprint identifies the failure across runs. See failureFingerprint.
+	Fingerprint string `json:"fingerprint"`
+	// Excerpt is the end of the message of the failure.
+	Excerpt string `json:"excerpt"`
+	// IssueURL is the URL of the issue or comment that was filed for the
+	// failure, if it was posted to GitHub.
+	IssueURL string `json:"issue_url,omitempty"`
+	// Status is one of newStatus, stillFailingStatus and recoveredStatus, if
+	// the previous report is known. FailingRuns is then the number of
+	// consecutive runs that the failure happened in, including this one unless
+	// it recovered.
+	Status      string `json:"status,omitempty"`
+	FailingRuns int    `json:"failing_runs,omitempty"`
+}
+
+// reportedTest is a slow test in the test report.
+type reportedTest struct {
+	Test       string  `json:"test"`
+	ElapsedSec float64 `json:"elapsed_sec"`
+}
+
+// failureFingerprint returns a short hash of the given package, test and class
+// of failure, which is the same for the failures that are filed as the same
+// issue.
+func failureFingerprint(packageName, testName, class string) string {
+	h := sha1.Sum([]byte(packageName + "\x00" + testName + "\x00" + class))
+	return hex.EncodeToString(h[:6])
+}
+
+// addSlowTests sets the slow tests of the report.
+func (r *testReport) addSlowTests(slowPassingTests, slowFailingTests []testEvent) {
+	convert := func(events []testEvent) []reportedTest {
+		tests := make([]reportedTest, len(events))
+		for i, te := range events {
+			tests[i] = reportedTest{Test: te.Test, ElapsedSec: te.Elapsed}
+		}
+		return tests
+	}
+	r.SlowPassingTests = convert(slowPassingTests)
+	r.SlowFailingTests = convert(slowFailingTests)
+}
+
+// readPreviousReport returns the test report of --previous-report, or nil if it
+// is not set.
+func readPreviousReport() (*testReport, error) {
+	if *previousReportFile == "" {
+		return nil, nil
+	}
+	data, err := ioutil.ReadFile(*previousReportFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the previous test report")
+	}
+	var r testReport
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the previous test report %s", *previousReportFile)
+	}
+	return &r, nil
+}
+
+// failureStatus returns the status of the failure with the given fingerprint,
+// given that r is the report of the previous run, and the number of
+// consecutive runs that it happened in, including the current one.
+func (r *testReport) failureStatus(fingerprint string) (string, int) {
+	for _, f := range r.Failures {
+		if f.Fingerprint == fingerprint {
+			// Reports written without a previous report don't count the runs.
+			runs := f.FailingRuns
+			if runs == 0 {
+				runs = 1
+			}
+			return stillFailingStatus, runs + 1
+		}
+	}
+	return newStatus, 1
+}
+
+// addRecovered sets the failures of the given previous report that recovered,
+// which are those that did not happen again and whose test passed. The
+// failures that are not about a particular test recover unless the run timed
+// out, since the run may not have got to them otherwise.
+func (r *testReport) addRecovered(prev *testReport, passedTests map[string]bool) {
+	failed := make(map[string]bool, len(r.Failures))
+	for _, f := range r.Failures {
+		failed[f.Fingerprint] = true
+	}
+	for _, f := range prev.Failures {
+		if failed[f.Fingerprint] {
+			continue
+		}
+		if f.Test == unknownTestName && r.TimedOut || f.Test != unknownTestName && !passedTests[f.Test] {
+			continue
+		}
+		f.Status = recoveredStatus
+		if f.FailingRuns == 0 {
+			f.FailingRuns = 1
+		}
+		r.Recovered = append(r.Recovered, f)
+	}
+}
+
+// statusNote describes the given status of a failure, which failed in the
+// given number of consecutive runs, in its issue.
+func statusNote(status string, runs int) string {
+	if status == stillFailingStatus {
+		return fmt.Sprintf("%s: this failure also happened in the previous %d runs", status, runs-1)
+	}
+	return fmt.Sprintf("%s: this failure did not happen in the previous run", status)
+}
+
+func writeTestReport(r *testReport) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(artifactsDir, "test-report.json"), data, 0644)
+}
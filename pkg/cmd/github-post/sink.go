@@ -0,0 +1,155 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/cmd/internal/issues"
+	"github.com/pkg/errors"
+)
+
+// failure is a failure of a run that an issue is filed for.
+type failure struct {
+	// Class is one of testFailureClass, packageFailureClass and timeoutClass.
+	Class       string `json:"class"`
+	Title       string `json:"title"`
+	Package     string `json:"package"`
+	Test        string `json:"test"`
+	AuthorEmail string `json:"author_email,omitempty"`
+	// Message is the main section of the body, such as the output of the
+	// failed test.
+	Message string `json:"message"`
+	// Body describes the failure. It is encoded as its sections.
+	Body *issues.Body `json:"-"`
+	// IssueURL is the URL of the issue filed by the sinks that precede a sink
+	// in a fanOut, if one of them filed one.
+	IssueURL string `json:"issue_url,omitempty"`
+}
+
+// A sink receives the failures of a run, such as to file issues for them.
+type sink interface {
+	// post reports the given failure, and returns the URL of the issue that it
+	// filed, if it filed one.
+	post(ctx context.Context, f *failure) (string, error)
+}
+
+// sinkFunc is a sink that calls the function.
+type sinkFunc func(ctx context.Context, f *failure) (string, error)
+
+func (fn sinkFunc) post(ctx context.Context, f *failure) (string, error) {
+	return fn(ctx, f)
+}
+
+// fanOut is a sink that posts the failures to each of its sinks in order. The
+// URL of the issue filed by a sink is passed on to the following ones, and is
+// returned.
+type fanOut []sink
+
+func (s fanOut) post(ctx context.Context, f *failure) (string, error) {
+	for _, sink := range s {
+		url, err := sink.post(ctx, f)
+		if err != nil {
+			return "", err
+		}
+		if url != "" && f.IssueURL == "" {
+			f.IssueURL = url
+		}
+	}
+	return f.IssueURL, nil
+}
+
+// githubSink files issues on GitHub.
+type githubSink struct{}
+
+func (githubSink) post(ctx context.Context, f *failure) (string, error) {
+	log.Printf("filing issue with title: %s", f.Title)
+	return issues.PostBody(ctx, f.Title, f.Package, f.Test, f.Body, f.AuthorEmail, nil)
+}
+
+// printSink writes the issues that are not posted to GitHub.
+type printSink struct {
+	w io.Writer
+}
+
+func (s printSink) post(_ context.Context, f *failure) (string, error) {
+	if _, err := fmt.Fprintf(s.w, "=== ISSUE: %s\npackage: %s\ntest: %s\nauthor: %s\n",
+		f.Title, f.Package, f.Test, f.AuthorEmail); err != nil {
+		return "", err
+	}
+	for _, section := range f.Body.Sections() {
+		if _, err := fmt.Fprintf(s.w, "\n%s\n%s\n", section.Title, section.Content); err != nil {
+			return "", err
+		}
+	}
+	return "", nil
+}
+
+// fileSink writes the failures to a file as JSON objects, one per line.
+type fileSink struct {
+	w io.Writer
+}
+
+func (s fileSink) post(_ context.Context, f *failure) (string, error) {
+	data, err := json.Marshal(struct {
+		failure
+		Sections []issues.Section `json:"sections"`
+	}{*f, f.Body.Sections()})
+	if err != nil {
+		return "", err
+	}
+	_, err = s.w.Write(append(data, '\n'))
+	return "", err
+}
+
+// slackSink posts a message about each failure to a Slack incoming webhook.
+type slackSink struct {
+	webhookURL string
+	client     *http.Client
+}
+
+func (s slackSink) post(ctx context.Context, f *failure) (string, error) {
+	text := f.Title
+	if f.IssueURL != "" {
+		text = fmt.Sprintf("<%s|%s>", f.IssueURL, f.Title)
+	} else if f.Message != "" {
+		text += "\n```" + tailOutput(strings.TrimSpace(f.Message), slackExcerptBytes) + "```"
+	}
+	data, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequest("POST", s.webhookURL, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to post to Slack")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("failed to post to Slack: %s", resp.Status)
+	}
+	return "", nil
+}
+
+// slackExcerptBytes is how much of the end of the message of a failure the
+// Slack messages include when there is no issue to link to.
+const slackExcerptBytes = 500
@@ -0,0 +1,97 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/cmd/internal/issues"
+	"github.com/pkg/errors"
+)
+
+// greenSHAEnv is the environment variable with the SHA of the last green build,
+// which takes precedence over --green-sha-file.
+const greenSHAEnv = "PREVIOUS_GREEN_SHA"
+
+var greenSHAFile = flag.String("green-sha-file", "",
+	"a file with the SHA of the last green build, since which the commits touching the package are listed in the issues")
+
+// maxSuspectCommits is the maximum number of suspect commits listed in an
+// issue.
+const maxSuspectCommits = 20
+
+// previousGreenSHA returns the SHA of the last green build, from greenSHAEnv or
+// else from --green-sha-file, or an empty string if neither is set.
+func previousGreenSHA() (string, error) {
+	if sha := os.Getenv(greenSHAEnv); sha != "" {
+		return sha, nil
+	}
+	if *greenSHAFile == "" {
+		return "", nil
+	}
+	data, err := ioutil.ReadFile(*greenSHAFile)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read the last green SHA")
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// suspectCommits returns the commits between the given SHAs that touch the
+// directory of the given package, one per line with their authors, newest
+// first. The git commands run in dir, or in the working directory if dir is
+// empty.
+func suspectCommits(ctx context.Context, dir, fromSHA, toSHA, packageName string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "log",
+		fmt.Sprintf("--max-count=%d", maxSuspectCommits), "--format=%h %s (%an <%ae>)",
+		fromSHA+".."+toSHA, "--", ":(top)"+issues.PackageDir(packageName))
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", errors.Errorf("couldn't list the commits in %s..%s: %s %s",
+			fromSHA, toSHA, err, string(out))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// addSuspectCommits adds a section listing the commits since the last green
+// build that touch the package to the given body, if the SHA of the last green
+// build is known.
+func addSuspectCommits(ctx context.Context, body *issues.Body, packageName string) {
+	fromSHA, err := previousGreenSHA()
+	if err != nil {
+		log.Printf("unable to list suspect commits: %s", err)
+		return
+	}
+	if fromSHA == "" {
+		return
+	}
+	toSHA := os.Getenv(shaEnv)
+	if toSHA == "" {
+		toSHA = "HEAD"
+	}
+	commits, err := suspectCommits(ctx, "" /* dir */, fromSHA, toSHA, packageName)
+	if err != nil {
+		log.Printf("unable to list suspect commits: %s", err)
+		return
+	}
+	if commits == "" {
+		commits = "<none>"
+	}
+	body.AddSection(fmt.Sprintf("Commits in %s..%s touching %s", fromSHA, toSHA,
+		issues.PackageDir(packageName)), commits, false /* collapsed */)
+}
@@ -0,0 +1,100 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// timestampLayouts are the layouts of the timestamps with a time zone that
+// utcTimestamps normalizes, by the regexp that matches them. The log lines of
+// cockroach are already in UTC, and the other timestamps that lack a time zone
+// cannot be normalized.
+var timestampLayouts = []struct {
+	re     *regexp.Regexp
+	layout string
+}{
+	{
+		re:     regexp.MustCompile(`\d{4}-\d\d-\d\dT\d\d:\d\d:\d\d(?:\.\d+)?(?:Z|[+-]\d\d:\d\d)`),
+		layout: time.RFC3339Nano,
+	},
+	{
+		// The format of time.Time.String.
+		re:     regexp.MustCompile(`\d{4}-\d\d-\d\d \d\d:\d\d:\d\d(?:\.\d+)? [+-]\d{4} [A-Z]+`),
+		layout: "2006-01-02 15:04:05.999999999 -0700 MST",
+	},
+}
+
+// utcTimestamps returns the given output with its timestamps converted to UTC,
+// so that they can be correlated with the logs of clusters and of CI.
+func utcTimestamps(output string) string {
+	for _, l := range timestampLayouts {
+		output = l.re.ReplaceAllStringFunc(output, func(s string) string {
+			t, err := time.Parse(l.layout, s)
+			if err != nil {
+				return s
+			}
+			return t.UTC().Format(l.layout)
+		})
+	}
+	return output
+}
+
+// runTimeline records when the main events of a run happened, according to the
+// Time fields of its test2json events.
+type runTimeline struct {
+	start, end       time.Time
+	firstFailure     time.Time
+	firstFailedTest  string
+	timeout          time.Time
+	timedOutTestName string
+}
+
+// observe records the given event in the timeline.
+func (tl *runTimeline) observe(te testEvent) {
+	if te.Time.IsZero() {
+		return
+	}
+	if tl.start.IsZero() {
+		tl.start = te.Time
+	}
+	tl.end = te.Time
+	// The timed out test fails after the timeout, if at all.
+	if te.Action == "fail" && te.Test != "" && te.Test != tl.timedOutTestName &&
+		tl.firstFailure.IsZero() {
+		tl.firstFailure = te.Time
+		tl.firstFailedTest = te.Test
+	}
+}
+
+// String renders the timeline in UTC, one event per line. It is empty if the
+// events have no Time fields.
+func (tl *runTimeline) String() string {
+	if tl.start.IsZero() {
+		return ""
+	}
+	var b strings.Builder
+	line := func(t time.Time, event string) {
+		fmt.Fprintf(&b, "%s %s\n", t.UTC().Format("2006-01-02 15:04:05.000"), event)
+	}
+	line(tl.start, "package started")
+	if !tl.firstFailure.IsZero() {
+		line(tl.firstFailure, "first failure: "+tl.firstFailedTest)
+	}
+	if !tl.timeout.IsZero() {
+		line(tl.timeout, "timeout: "+tl.timedOutTestName)
+	}
+	line(tl.end, "package finished")
+	return b.String()
+}
@@ -0,0 +1,100 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package issues
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/google/go-github/github"
+	"github.com/pkg/errors"
+)
+
+const (
+	// AttachLogThreshold is the length above which AttachLog uploads logs.
+	// Shorter logs fit in the body of an issue, and should be embedded in it.
+	AttachLogThreshold = githubIssueBodyMaximumLength
+	// maxAttachedLogLength is the maximum length of an attached log. Longer logs
+	// are trimmed like the messages embedded in issues. GitHub does not show
+	// the content of larger gists on their page.
+	maxAttachedLogLength = 1 << 20
+	// attachmentRetention is how long attachments are meant to be kept. Older
+	// gists can be deleted by a cleanup job.
+	attachmentRetention = 90 * 24 * time.Hour
+	// attachmentDescriptionPrefix starts the description of the gists created
+	// by AttachLog, so that a cleanup job can recognize them.
+	attachmentDescriptionPrefix = "cockroach issue attachment"
+)
+
+// An Attachment is a log that was uploaded to a secret gist, so that it can be
+// linked from an issue instead of being trimmed to fit in it.
+type Attachment struct {
+	// URL is the link to the gist.
+	URL string
+	// GistID identifies the gist, such as to delete it.
+	GistID string
+	// Trimmed is true if the log was trimmed to maxAttachedLogLength.
+	Trimmed bool
+	// Created is when the gist was created, and Expires is when it can be
+	// deleted. They are also recorded in the description of the gist.
+	Created time.Time
+	Expires time.Time
+}
+
+// attachmentDescription returns the description of the gist of an attachment
+// of the given name, which records the cleanup metadata of the attachment.
+func attachmentDescription(name string, created, expires time.Time) string {
+	return fmt.Sprintf("%s %s (created %s, can be deleted after %s)",
+		attachmentDescriptionPrefix, name,
+		created.UTC().Format(time.RFC3339), expires.UTC().Format(time.RFC3339))
+}
+
+// attachLog uploads the given log to a secret gist, in a file with the given
+// name. It returns false without uploading anything if the log does not exceed
+// AttachLogThreshold.
+func (p *poster) attachLog(ctx context.Context, name, log string) (Attachment, bool, error) {
+	if len(log) <= AttachLogThreshold {
+		return Attachment{}, false, nil
+	}
+	var a Attachment
+	if len(log) > maxAttachedLogLength {
+		log = trimMessage(log, maxAttachedLogLength)
+		a.Trimmed = true
+	}
+	a.Created = timeutil.Now()
+	a.Expires = a.Created.Add(attachmentRetention)
+	gist, _, err := p.createGist(ctx, &github.Gist{
+		Description: github.String(attachmentDescription(name, a.Created, a.Expires)),
+		Public:      github.Bool(false),
+		Files: map[github.GistFilename]github.GistFile{
+			github.GistFilename(name): {Content: &log},
+		},
+	})
+	if err != nil {
+		return Attachment{}, false, errors.Wrapf(err, "failed to create gist for %s", name)
+	}
+	a.URL = gist.GetHTMLURL()
+	a.GistID = gist.GetID()
+	return a, true, nil
+}
+
+// AttachLog uploads the given log to a secret gist, in a file with the given
+// name, such as "stress.log", and returns the attachment, which can be linked
+// from the body of an issue with Body.AddAttachment. Logs longer than 1 MiB are
+// trimmed.
+//
+// AttachLog returns false without uploading anything if the log does not exceed
+// AttachLogThreshold, in which case it should be embedded in the body instead.
+func AttachLog(ctx context.Context, name, log string) (Attachment, bool, error) {
+	return defaultPoster().attachLog(ctx, name, log)
+}
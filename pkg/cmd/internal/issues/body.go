@@ -0,0 +1,169 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package issues
+
+import (
+	"sort"
+	"strings"
+)
+
+// A Section is a part of the body of an issue, such as the output of a failed
+// test or the command that reproduces it. Its content is preformatted text,
+// which is rendered in a code block.
+type Section struct {
+	// Title is rendered before the content. Untitled sections only consist of
+	// their content.
+	Title string
+	// Content is the text of the section. It is trimmed when the body of the
+	// issue would otherwise exceed the maximum length.
+	Content string
+	// Collapsed sections are rendered in a <details> element, so that only
+	// their title is shown until they are expanded.
+	Collapsed bool
+	// URL, if set, is linked from the title, such as to the full log of which
+	// the content is an excerpt. Sections with a URL may have no content, in
+	// which case they only consist of the link.
+	URL string
+	// Markdown sections have Markdown content, which is rendered as is instead
+	// of in a code block.
+	Markdown bool
+}
+
+func (s *Section) render(content string) string {
+	var b strings.Builder
+	title := s.Title
+	if s.URL != "" {
+		title = "[" + title + "](" + s.URL + ")"
+		if s.Content == "" {
+			return title
+		}
+	}
+	if s.Markdown {
+		if title != "" {
+			b.WriteString(title)
+			b.WriteString(":\n\n")
+		}
+		b.WriteString(content)
+		return b.String()
+	}
+	if s.Collapsed {
+		b.WriteString("<details><summary>")
+		b.WriteString(title)
+		b.WriteString("</summary>\n\n")
+	} else if title != "" {
+		b.WriteString(title)
+		b.WriteString(":\n")
+	}
+	b.WriteString("```\n")
+	b.WriteString(content)
+	b.WriteString("\n```")
+	if s.Collapsed {
+		b.WriteString("\n</details>")
+	}
+	return b.String()
+}
+
+// Body is the body of an issue or comment, composed of sections. The zero
+// value is an empty body.
+//
+// When the body is rendered, the content of its sections is trimmed so that the
+// body does not exceed the maximum length accepted by GitHub. The available
+// length is divided evenly between the sections, and the length that is not
+// needed by the shorter sections is given to the longer ones.
+type Body struct {
+	sections []Section
+}
+
+// AddSection appends a section to the body.
+func (b *Body) AddSection(title, content string, collapsed bool) {
+	b.sections = append(b.sections, Section{Title: title, Content: content, Collapsed: collapsed})
+}
+
+// PrependSection inserts a section before the other sections of the body.
+func (b *Body) PrependSection(title, content string, collapsed bool) {
+	b.sections = append([]Section{{Title: title, Content: content, Collapsed: collapsed}}, b.sections...)
+}
+
+// AddMarkdown appends a section with the given Markdown content, which is
+// rendered as is rather than in a code block.
+func (b *Body) AddMarkdown(title, content string) {
+	b.sections = append(b.sections, Section{Title: title, Content: content, Markdown: true})
+}
+
+// AddAttachment appends a section linking to the given attachment, with the
+// given excerpt of the attached log as content. The excerpt may be empty. See
+// AttachLog.
+func (b *Body) AddAttachment(title, excerpt string, a Attachment) {
+	b.AddLinkedSection(title, excerpt, a.URL)
+}
+
+// AddLinkedSection appends a section whose title links to the given URL. The
+// section is a plain titled section if the URL is empty.
+func (b *Body) AddLinkedSection(title, content, url string) {
+	b.sections = append(b.sections, Section{Title: title, Content: content, URL: url})
+}
+
+// Sections returns the sections of the body.
+func (b *Body) Sections() []Section {
+	return b.sections
+}
+
+// String renders the body, trimmed to githubIssueBodyMaximumLength.
+func (b *Body) String() string {
+	return b.render(githubIssueBodyMaximumLength)
+}
+
+// sectionSeparator separates the rendered sections of a body.
+const sectionSeparator = "\n\n"
+
+// render renders the body, trimming the content of the sections so that the
+// result does not exceed maxLength, unless the sections do not fit even when
+// they are empty.
+func (b *Body) render(maxLength int) string {
+	if len(b.sections) == 0 {
+		return ""
+	}
+
+	// The overhead is the length of the body when all the sections are empty.
+	overhead := len(sectionSeparator) * (len(b.sections) - 1)
+	for i := range b.sections {
+		overhead += len(b.sections[i].render(""))
+	}
+	available := maxLength - overhead
+	if available < 0 {
+		available = 0
+	}
+
+	// Visit the sections by increasing length of their content, and give each
+	// one its share of the length that is still available.
+	order := make([]int, len(b.sections))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return len(b.sections[order[i]].Content) < len(b.sections[order[j]].Content)
+	})
+	limits := make([]int, len(b.sections))
+	for n, i := range order {
+		limit := available / (len(order) - n)
+		if l := len(b.sections[i].Content); l < limit {
+			limit = l
+		}
+		limits[i] = limit
+		available -= limit
+	}
+
+	rendered := make([]string, len(b.sections))
+	for i := range b.sections {
+		rendered[i] = b.sections[i].render(trimMessage(b.sections[i].Content, limits[i]))
+	}
+	return strings.Join(rendered, sectionSeparator)
+}
@@ -29,6 +29,7 @@ import (
 
 const (
 	githubAPITokenEnv    = "GITHUB_API_TOKEN"
+	githubAPIURLEnv      = "GITHUB_API_URL"
 	teamcityVCSNumberEnv = "BUILD_VCS_NUMBER"
 	teamcityBuildIDEnv   = "TC_BUILD_ID"
 	teamcityServerURLEnv = "TC_SERVER_URL"
@@ -36,7 +37,9 @@ const (
 	goFlagsEnv           = "GOFLAGS"
 	githubUser           = "cockroachdb"
 	githubRepo           = "cockroach"
-	// CockroachPkgPrefix is the crdb package prefix.
+	// CockroachPkgPrefix is the crdb package prefix. The titles of issues are
+	// generated with TrimPackageName, which trims the prefix of the module set
+	// with SetModulePath instead.
 	CockroachPkgPrefix = "github.com/cockroachdb/cockroach/pkg/"
 )
 
@@ -52,18 +55,14 @@ var (
 // is too long (maximum is 65536 characters)}]
 const githubIssueBodyMaximumLength = 5000
 
-// trimIssueRequestBody trims message such that the total size of an issue body
-// is less than githubIssueBodyMaximumLength. usedCharacters specifies the
-// number of characters that have already been used for the issue body (see
-// newIssueRequest below). message is usually the test failure message and
-// possibly includes stacktraces for all of the goroutines (which is what makes
-// the message very large).
+// trimMessage trims message such that its length does not exceed maxLength.
+// message is usually the test failure message and possibly includes
+// stacktraces for all of the goroutines (which is what makes the message very
+// large).
 //
 // TODO(peter): Rather than trimming the message like this, perhaps it can be
 // added as an attachment or some other expandable comment.
-func trimIssueRequestBody(message string, usedCharacters int) string {
-	maxLength := githubIssueBodyMaximumLength - usedCharacters
-
+func trimMessage(message string, maxLength int) string {
 	if m := stacktraceRE.FindStringIndex(message); m != nil {
 		// We want the top stack traces plus a few lines before.
 		{
@@ -201,6 +200,9 @@ type poster struct {
 	listMilestones func(ctx context.Context, owner string, repo string,
 		opt *github.MilestoneListOptions) ([]*github.Milestone, *github.Response, error)
 	getLatestTag func() (string, error)
+	createGist   func(ctx context.Context,
+		gist *github.Gist) (*github.Gist, *github.Response, error)
+	rateLimits func(ctx context.Context) (*github.RateLimits, *github.Response, error)
 }
 
 func newPoster() *poster {
@@ -213,6 +215,15 @@ func newPoster() *poster {
 	client := github.NewClient(oauth2.NewClient(ctx, oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: token},
 	)))
+	// The API URL can be overridden, such as to test commands against a fake
+	// GitHub server.
+	if apiURL, ok := os.LookupEnv(githubAPIURLEnv); ok {
+		u, err := url.Parse(strings.TrimSuffix(apiURL, "/") + "/")
+		if err != nil {
+			log.Fatalf("invalid GitHub API URL in environment variable %s: %s", githubAPIURLEnv, err)
+		}
+		client.BaseURL = u
+	}
 
 	return &poster{
 		createIssue:    client.Issues.Create,
@@ -221,6 +232,8 @@ func newPoster() *poster {
 		listCommits:    client.Repositories.ListCommits,
 		listMilestones: client.Issues.ListMilestones,
 		getLatestTag:   getLatestTag,
+		createGist:     client.Gists.Create,
+		rateLimits:     client.RateLimits,
 	}
 }
 
@@ -241,8 +254,7 @@ func (p *poster) init() {
 // DefaultStressFailureTitle provides the default title for stress failure
 // issues.
 func DefaultStressFailureTitle(packageName, testName string) string {
-	trimmedPkgName := strings.TrimPrefix(packageName, CockroachPkgPrefix)
-	return fmt.Sprintf("%s: %s failed under stress", trimmedPkgName, testName)
+	return fmt.Sprintf("%s: %s failed under stress", TrimPackageName(packageName), testName)
 }
 
 func (p *poster) post(
@@ -250,11 +262,33 @@ func (p *poster) post(
 	title, packageName, testName, message, authorEmail string,
 	extraLabels []string,
 ) error {
+	var body Body
+	body.AddSection("", message, false /* collapsed */)
+	_, err := p.postTemplate(ctx, templates[TestFailureTemplate], &IssueData{
+		Title:       title,
+		PackageName: packageName,
+		TestName:    testName,
+		AuthorEmail: authorEmail,
+		Body:        &body,
+		ExtraLabels: extraLabels,
+	})
+	return err
+}
+
+// postTemplate files the issue described by the given data with the given
+// template, or comments on the existing issue with its title. It returns the
+// URL of the created issue or comment.
+func (p *poster) postTemplate(ctx context.Context, tmpl *Template, data *IssueData) (string, error) {
+	if err := tmpl.Validate(data); err != nil {
+		return "", err
+	}
+
 	const bodyTemplate = `SHA: https://github.com/cockroachdb/cockroach/commits/%[1]s
 
 Parameters:%[2]s
 
-To repro, try:
+%[3]s%[4]s: %[5]s`
+	const reproTemplate = `To repro, try:
 
 ` + "```" + `
 # Don't forget to check out a clean suitable branch and experiment with the
@@ -264,28 +298,36 @@ To repro, try:
 ./scripts/gceworker.sh start && ./scripts/gceworker.sh mosh
 cd ~/go/src/github.com/cockroachdb/cockroach && \
 stdbuf -oL -eL \
-make stressrace TESTS=%[5]s PKG=%[4]s TESTTIMEOUT=5m STRESSFLAGS='-maxtime 20m -timeout 10m' 2>&1 | tee /tmp/stress.log
+make stressrace TESTS=%[2]s PKG=%[1]s TESTTIMEOUT=5m STRESSFLAGS='-maxtime 20m -timeout 10m' 2>&1 | tee /tmp/stress.log
 ` + "```" + `
 
-Failed test: %[3]s`
-	const messageTemplate = "\n\n```\n%s\n```"
+`
 
-	body := func(packageName, testName, message string) string {
-		body := fmt.Sprintf(bodyTemplate, p.sha, p.parameters(), p.teamcityURL(), packageName, testName) + messageTemplate
-		// We insert a raw "%s" above so we can figure out the length of the
-		// body so far, without the actual error text. We need this length so we
-		// can calculate the maximum amount of error text we can include in the
-		// issue without exceeding GitHub's limit. We replace that %s in the
-		// following Sprintf.
-		return fmt.Sprintf(body, trimIssueRequestBody(message, len(body)))
+	failure := data.Body
+	if failure == nil {
+		failure = &Body{}
+	}
+	body := func() string {
+		var repro string
+		if tmpl.Repro {
+			repro = fmt.Sprintf(reproTemplate, data.PackageName, data.TestName)
+		}
+		body := fmt.Sprintf(bodyTemplate, p.sha, p.parameters(), repro, tmpl.Heading, p.teamcityURL())
+		if len(failure.sections) == 0 {
+			return body
+		}
+		// The sections get the length that is left by the header, so that the
+		// issue does not exceed GitHub's limit.
+		body += sectionSeparator
+		return body + failure.render(githubIssueBodyMaximumLength-len(body))
 	}
 
-	newIssueRequest := func(packageName, testName, message, assignee string) *github.IssueRequest {
-		b := body(packageName, testName, message)
+	newIssueRequest := func(assignee string) *github.IssueRequest {
+		b := body()
 
-		labels := append(issueLabels, extraLabels...)
+		labels := tmpl.labels(data.ExtraLabels)
 		return &github.IssueRequest{
-			Title:     &title,
+			Title:     &data.Title,
 			Body:      &b,
 			Labels:    &labels,
 			Assignee:  &assignee,
@@ -293,55 +335,67 @@ Failed test: %[3]s`
 		}
 	}
 
-	newIssueComment := func(packageName, testName, message string) *github.IssueComment {
-		b := body(packageName, testName, message)
-		return &github.IssueComment{Body: &b}
-	}
-
-	assignee, err := getAssignee(ctx, authorEmail, p.listCommits)
+	assignee, err := getAssignee(ctx, data.AuthorEmail, p.listCommits)
 	if err != nil {
 		// if we *can't* assign anyone, sigh, feel free to hard-code me.
 		// -- tbg, 11/3/2017
 		assignee = "tbg"
-		message += fmt.Sprintf("\n\nFailed to find issue assignee: \n%s", err)
+		withError := Body{sections: append([]Section(nil), failure.sections...)}
+		withError.AddSection("Failed to find issue assignee", err.Error(), false /* collapsed */)
+		failure = &withError
 	}
 
-	issueRequest := newIssueRequest(packageName, testName, message, assignee)
+	issueRequest := newIssueRequest(assignee)
+	foundIssue, err := p.findExisting(ctx, *issueRequest.Title)
+	if err != nil {
+		return "", err
+	}
+
+	if foundIssue != 0 {
+		return p.comment(ctx, foundIssue, body())
+	}
+	issue, _, err := p.createIssue(ctx, githubUser, githubRepo, issueRequest)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create GitHub issue %s",
+			github.Stringify(issueRequest))
+	}
+	return issue.GetHTMLURL(), nil
+}
+
+// findExisting returns the number of the open issue filed by the robot that
+// matches the given phrase, or 0 if there is none.
+func (p *poster) findExisting(ctx context.Context, phrase string) (int, error) {
 	searchQuery := fmt.Sprintf(`"%s" user:%s repo:%s is:open`,
-		*issueRequest.Title, githubUser, githubRepo)
+		phrase, githubUser, githubRepo)
 	for _, label := range issueLabels {
 		searchQuery = searchQuery + fmt.Sprintf(` label:"%s"`, label)
 	}
 
-	var foundIssue *int
 	result, _, err := p.searchIssues(ctx, searchQuery, &github.SearchOptions{
 		ListOptions: github.ListOptions{
 			PerPage: 1,
 		},
 	})
 	if err != nil {
-		return errors.Wrapf(err, "failed to search GitHub with query %s",
+		return 0, errors.Wrapf(err, "failed to search GitHub with query %s",
 			github.Stringify(searchQuery))
 	}
 	if *result.Total > 0 {
-		foundIssue = result.Issues[0].Number
+		return result.Issues[0].GetNumber(), nil
 	}
+	return 0, nil
+}
 
-	if foundIssue == nil {
-		if _, _, err := p.createIssue(ctx, githubUser, githubRepo, issueRequest); err != nil {
-			return errors.Wrapf(err, "failed to create GitHub issue %s",
-				github.Stringify(issueRequest))
-		}
-	} else {
-		comment := newIssueComment(packageName, testName, message)
-		if _, _, err := p.createComment(
-			ctx, githubUser, githubRepo, *foundIssue, comment); err != nil {
-			return errors.Wrapf(err, "failed to update issue #%d with %s",
-				*foundIssue, github.Stringify(comment))
-		}
+// comment posts a comment with the given body to the issue with the given
+// number, and returns the URL of the comment.
+func (p *poster) comment(ctx context.Context, issueNumber int, body string) (string, error) {
+	comment := &github.IssueComment{Body: &body}
+	created, _, err := p.createComment(ctx, githubUser, githubRepo, issueNumber, comment)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to update issue #%d with %s",
+			issueNumber, github.Stringify(comment))
 	}
-
-	return nil
+	return created.GetHTMLURL(), nil
 }
 
 func (p *poster) teamcityURL() *url.URL {
@@ -398,6 +452,14 @@ var defaultP struct {
 	*poster
 }
 
+func defaultPoster() *poster {
+	defaultP.Do(func() {
+		defaultP.poster = newPoster()
+		defaultP.init()
+	})
+	return defaultP.poster
+}
+
 // Post either creates a new issue for a failed test, or posts a comment to an
 // existing open issue.
 func Post(
@@ -405,15 +467,67 @@ func Post(
 	title, packageName, testName, message, authorEmail string,
 	extraLabels []string,
 ) error {
-	defaultP.Do(func() {
-		defaultP.poster = newPoster()
-		defaultP.init()
+	var body Body
+	body.AddSection("", message, false /* collapsed */)
+	_, err := PostBody(ctx, title, packageName, testName, &body, authorEmail, extraLabels)
+	return err
+}
+
+// PostBody is like Post, but the failure is described by the sections of the
+// given body instead of a single message. The sections follow the SHA, the
+// parameters and the repro instructions of the build. See Body. It returns the
+// URL of the created issue or comment.
+func PostBody(
+	ctx context.Context,
+	title, packageName, testName string,
+	body *Body,
+	authorEmail string,
+	extraLabels []string,
+) (string, error) {
+	return PostTemplate(ctx, TestFailureTemplate, IssueData{
+		Title:       title,
+		PackageName: packageName,
+		TestName:    testName,
+		AuthorEmail: authorEmail,
+		Body:        body,
+		ExtraLabels: extraLabels,
 	})
-	err := defaultP.post(ctx, title, packageName, testName, message, authorEmail, extraLabels)
+}
+
+// PostTemplate is like PostBody, but the issue is filed with the registered
+// template of the given name, such as TimeoutTemplate, which determines its
+// labels and the contents of its body. It returns an error without posting
+// anything if the data lacks fields required by the template.
+func PostTemplate(ctx context.Context, templateName string, data IssueData) (string, error) {
+	tmpl, ok := LookupTemplate(templateName)
+	if !ok {
+		return "", errors.Errorf("unknown issue template %q; valid templates are: %s",
+			templateName, strings.Join(TemplateNames(), ", "))
+	}
+	p := defaultPoster()
+	url, err := p.postTemplate(ctx, tmpl, &data)
 	if !isInvalidAssignee(err) {
-		return err
+		return url, err
 	}
-	return defaultP.post(ctx, title, packageName, testName, message, "tobias.schottdorf@gmail.com", extraLabels)
+	data.AuthorEmail = "tobias.schottdorf@gmail.com"
+	return p.postTemplate(ctx, tmpl, &data)
+}
+
+// FindExisting returns the number of the open issue filed by the robot whose
+// title or body contains the given phrase, or 0 if there is none. The phrase is
+// usually the title of the issue, such as the one returned by
+// DefaultStressFailureTitle, or a fingerprint of the failure that the body of
+// the issue contains. Together with Comment, it lets callers update an existing
+// issue instead of filing a new one.
+func FindExisting(ctx context.Context, phrase string) (int, error) {
+	return defaultPoster().findExisting(ctx, phrase)
+}
+
+// Comment posts a comment with the given body to the issue with the given
+// number.
+func Comment(ctx context.Context, issueNumber int, body *Body) error {
+	_, err := defaultPoster().comment(ctx, issueNumber, body.String())
+	return err
 }
 
 // CanPost returns true if the github API token environment variable is set.
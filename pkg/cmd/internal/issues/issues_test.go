@@ -13,14 +13,18 @@ package issues
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
+	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-github/github"
 )
@@ -271,6 +275,271 @@ Failed test: %s`,
 	}
 }
 
+func TestBody(t *testing.T) {
+	var b Body
+	b.AddSection("", "short", false /* collapsed */)
+	b.AddSection("Excerpt", "excerpt", false /* collapsed */)
+	b.AddSection("Full log", "log", true /* collapsed */)
+	b.AddMarkdown("", "| a | b |")
+	b.AddMarkdown("Table", "| c |")
+	const expected = "```\nshort\n```\n\n" +
+		"Excerpt:\n```\nexcerpt\n```\n\n" +
+		"<details><summary>Full log</summary>\n\n```\nlog\n```\n</details>\n\n" +
+		"| a | b |\n\nTable:\n\n| c |"
+	if actual := b.String(); actual != expected {
+		t.Fatalf("got:\n%s\nexpected:\n%s", actual, expected)
+	}
+
+	// The length that is not needed by the short section is given to the long
+	// ones, which are trimmed evenly.
+	long := strings.Repeat("x", githubIssueBodyMaximumLength)
+	b = Body{}
+	b.AddSection("Short", "short", false /* collapsed */)
+	b.AddSection("Long", long, false /* collapsed */)
+	b.AddSection("Longer", long+long, true /* collapsed */)
+	rendered := b.String()
+	if length := len(rendered); length > githubIssueBodyMaximumLength || length < githubIssueBodyMaximumLength-2 {
+		t.Fatalf("body length %d, expected %d", length, githubIssueBodyMaximumLength)
+	}
+	if !strings.Contains(rendered, "Short:\n```\nshort\n```") {
+		t.Fatalf("expected the short section to be kept:\n%s", rendered)
+	}
+	if n := strings.Count(rendered, "x"); n < githubIssueBodyMaximumLength-200 {
+		t.Fatalf("expected the long sections to use the available length, got %d characters", n)
+	}
+}
+
+func TestFindExistingAndComment(t *testing.T) {
+	const (
+		phrase      = "storage: TestFoo failed under stress"
+		issueNumber = 30
+	)
+	ctx := context.Background()
+	for _, total := range []int{0, 1} {
+		p := &poster{}
+		p.searchIssues = func(_ context.Context, query string,
+			opt *github.SearchOptions) (*github.IssuesSearchResult, *github.Response, error) {
+			expected := `"` + phrase + `" user:cockroachdb repo:cockroach is:open label:"O-robot" label:"C-test-failure"`
+			if query != expected {
+				t.Fatalf("got query %s, expected %s", query, expected)
+			}
+			total := total
+			return &github.IssuesSearchResult{
+				Total:  &total,
+				Issues: []github.Issue{{Number: github.Int(issueNumber)}}[:total],
+			}, nil, nil
+		}
+		found, err := p.findExisting(ctx, phrase)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := issueNumber * total; found != expected {
+			t.Fatalf("found issue %d, expected %d", found, expected)
+		}
+	}
+
+	var b Body
+	b.AddSection("Excerpt", "boom", false /* collapsed */)
+	commentCount := 0
+	p := &poster{}
+	p.createComment = func(_ context.Context, owner string, repo string, number int,
+		comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+		commentCount++
+		if number != issueNumber {
+			t.Fatalf("got issue %d, expected %d", number, issueNumber)
+		}
+		if *comment.Body != b.String() {
+			t.Fatalf("got:\n%s\nexpected:\n%s", *comment.Body, b.String())
+		}
+		return nil, nil, nil
+	}
+	if _, err := p.comment(ctx, issueNumber, b.String()); err != nil {
+		t.Fatal(err)
+	}
+	if commentCount != 1 {
+		t.Fatalf("%d comments were posted, expected 1", commentCount)
+	}
+}
+
+func TestTemplates(t *testing.T) {
+	if names := TemplateNames(); !reflect.DeepEqual(names, []string{
+		BuildErrorTemplate, RaceTemplate, TestFailureTemplate, TimeoutTemplate,
+	}) {
+		t.Fatalf("unexpected templates %v", names)
+	}
+
+	var body Body
+	body.AddSection("", "build output", false /* collapsed */)
+	data := IssueData{
+		Title:       "storage: package failed to build",
+		PackageName: "github.com/cockroachdb/cockroach/pkg/storage",
+		Body:        &body,
+	}
+	tmpl, _ := LookupTemplate(BuildErrorTemplate)
+	if err := tmpl.Validate(&data); err != nil {
+		t.Fatal(err)
+	}
+	tmpl, _ = LookupTemplate(TestFailureTemplate)
+	if err := tmpl.Validate(&data); err == nil || !strings.HasSuffix(err.Error(), "lacks fields required by template test-failure: test") {
+		t.Fatalf("expected a missing test error, got %v", err)
+	}
+	if err := tmpl.Validate(&IssueData{}); err == nil || !strings.HasSuffix(err.Error(), "lacks fields required by template test-failure: title, package, test, body") {
+		t.Fatalf("expected a missing fields error, got %v", err)
+	}
+
+	p := &poster{sha: "abcd123", serverURL: "https://teamcity.example.com"}
+	p.searchIssues = func(_ context.Context, query string,
+		opt *github.SearchOptions) (*github.IssuesSearchResult, *github.Response, error) {
+		return &github.IssuesSearchResult{Total: github.Int(0)}, nil, nil
+	}
+	const issueURL = "https://github.com/cockroachdb/cockroach/issues/1234"
+	issueCount := 0
+	p.createIssue = func(_ context.Context, owner string, repo string,
+		issue *github.IssueRequest) (*github.Issue, *github.Response, error) {
+		issueCount++
+		if expected := []string{"O-robot", "C-test-failure", "A-build-system", "X-extra"}; !reflect.DeepEqual(*issue.Labels, expected) {
+			t.Fatalf("got labels %v, expected %v", *issue.Labels, expected)
+		}
+		if strings.Contains(*issue.Body, "To repro") || !strings.Contains(*issue.Body, "Failed build: https://") {
+			t.Fatalf("unexpected body:\n%s", *issue.Body)
+		}
+		return &github.Issue{HTMLURL: github.String(issueURL)}, nil, nil
+	}
+	data.ExtraLabels = []string{"X-extra"}
+	tmpl, _ = LookupTemplate(BuildErrorTemplate)
+	if url, err := p.postTemplate(context.Background(), tmpl, &data); err != nil {
+		t.Fatal(err)
+	} else if url != issueURL {
+		t.Fatalf("got URL %s, expected %s", url, issueURL)
+	}
+	if issueCount != 1 {
+		t.Fatalf("%d issues were posted, expected 1", issueCount)
+	}
+}
+
+func TestAttachLog(t *testing.T) {
+	const gistURL = "https://gist.github.com/cockroach-teamcity/abcdef"
+	var gists []*github.Gist
+	p := &poster{}
+	p.createGist = func(_ context.Context,
+		gist *github.Gist) (*github.Gist, *github.Response, error) {
+		gists = append(gists, gist)
+		return &github.Gist{ID: github.String("abcdef"), HTMLURL: github.String(gistURL)}, nil, nil
+	}
+	ctx := context.Background()
+
+	// Short logs are not attached.
+	if _, ok, err := p.attachLog(ctx, "stress.log", "short"); err != nil || ok || len(gists) != 0 {
+		t.Fatalf("expected the short log not to be attached (%v)", err)
+	}
+
+	for _, length := range []int{AttachLogThreshold + 1, maxAttachedLogLength + 1} {
+		gists = nil
+		log := strings.Repeat("x", length)
+		a, ok, err := p.attachLog(ctx, "stress.log", log)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok || len(gists) != 1 {
+			t.Fatalf("expected a gist to be created")
+		}
+		if a.URL != gistURL || a.GistID != "abcdef" || !a.Expires.Equal(a.Created.Add(attachmentRetention)) {
+			t.Fatalf("unexpected attachment %+v", a)
+		}
+		gist := gists[0]
+		if gist.GetPublic() || !strings.HasPrefix(gist.GetDescription(), attachmentDescriptionPrefix+" stress.log") {
+			t.Fatalf("unexpected gist %s", github.Stringify(gist))
+		}
+		file := gist.Files["stress.log"]
+		content := file.GetContent()
+		if trimmed := length > maxAttachedLogLength; a.Trimmed != trimmed || (content == log) == trimmed {
+			t.Fatalf("expected the log of length %d to be trimmed: %t", length, trimmed)
+		}
+		if len(content) > maxAttachedLogLength {
+			t.Fatalf("attached log length %d exceeds maximum %d", len(content), maxAttachedLogLength)
+		}
+
+		var b Body
+		b.AddAttachment("Full log", "", a)
+		b.AddAttachment("Excerpt", "x", a)
+		expected := "[Full log](" + gistURL + ")\n\n[Excerpt](" + gistURL + "):\n```\nx\n```"
+		if actual := b.String(); actual != expected {
+			t.Fatalf("got:\n%s\nexpected:\n%s", actual, expected)
+		}
+	}
+}
+
+func TestRemainingRateLimit(t *testing.T) {
+	reset := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	p := &poster{}
+	p.rateLimits = func(context.Context) (*github.RateLimits, *github.Response, error) {
+		return &github.RateLimits{
+			Core:   &github.Rate{Limit: 5000, Remaining: 42, Reset: github.Timestamp{Time: reset}},
+			Search: &github.Rate{Limit: 30, Remaining: 30},
+		}, nil, nil
+	}
+	r, err := p.remainingRateLimit(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := (RateLimit{Limit: 5000, Remaining: 42, Reset: reset}); r != expected {
+		t.Fatalf("got %+v, expected %+v", r, expected)
+	}
+	if expected := "42 of 5000 requests remaining until 2019-06-01T12:00:00Z"; r.String() != expected {
+		t.Fatalf("got %s, expected %s", r, expected)
+	}
+}
+
+func TestModulePath(t *testing.T) {
+	defer SetModulePath(ModulePath())
+
+	testCases := []struct {
+		module, pkg, expDir, expTrimmed string
+	}{
+		{CockroachModulePath, "github.com/cockroachdb/cockroach/pkg/storage", "pkg/storage", "storage"},
+		{CockroachModulePath, "github.com/example/fork/pkg/storage",
+			"github.com/example/fork/pkg/storage", "github.com/example/fork/pkg/storage"},
+		{"github.com/example/fork/", "github.com/example/fork/pkg/storage", "pkg/storage", "storage"},
+		{"example.com/tool", "example.com/tool/internal/x", "internal/x", "internal/x"},
+	}
+	for _, c := range testCases {
+		SetModulePath(c.module)
+		if dir := PackageDir(c.pkg); dir != c.expDir {
+			t.Errorf("%s in %s: expected directory %s, got %s", c.pkg, c.module, c.expDir, dir)
+		}
+		if trimmed := TrimPackageName(c.pkg); trimmed != c.expTrimmed {
+			t.Errorf("%s in %s: expected %s, got %s", c.pkg, c.module, c.expTrimmed, trimmed)
+		}
+	}
+	SetModulePath("example.com/tool")
+	if title, exp := DefaultStressFailureTitle("example.com/tool/cmd", "TestX"),
+		"cmd: TestX failed under stress"; title != exp {
+		t.Errorf("expected title %s, got %s", exp, title)
+	}
+
+	dir, err := ioutil.TempDir("", "issues-module")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if _, err := DetectModulePath(dir); err == nil {
+		t.Errorf("expected an error without a go.mod file")
+	}
+	goMod := "// A comment.\nmodule \"example.com/tool\"\n\nrequire example.com/dep v1.0.0\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0644); err != nil {
+		t.Fatal(err)
+	}
+	subdir := filepath.Join(dir, "cmd", "tool")
+	if err := os.MkdirAll(subdir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if path, err := DetectModulePath(subdir); err != nil {
+		t.Fatal(err)
+	} else if path != "example.com/tool" {
+		t.Errorf("expected module example.com/tool, got %s", path)
+	}
+}
+
 func TestGetAssignee(t *testing.T) {
 	listCommits := func(_ context.Context, owner string, repo string,
 		opts *github.CommitsListOptions) ([]*github.RepositoryCommit, *github.Response, error) {
@@ -0,0 +1,111 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package issues
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// CockroachModulePath is the path of the cockroach module, which is the module
+// of the failed packages unless SetModulePath is called.
+const CockroachModulePath = "github.com/cockroachdb/cockroach"
+
+// modulePath is the path of the module of the failed packages. It is not
+// synchronized, and is only meant to be set at startup.
+var modulePath = CockroachModulePath
+
+// SetModulePath sets the path of the module of the failed packages, such as
+// github.com/cockroachdb/cockroach, so that forks and other repositories can
+// file issues about their own packages.
+func SetModulePath(path string) {
+	modulePath = strings.TrimSuffix(path, "/")
+}
+
+// ModulePath returns the path of the module of the failed packages. See
+// SetModulePath.
+func ModulePath() string {
+	return modulePath
+}
+
+// PackageDir returns the directory of the given package relative to the root
+// of the module, such as pkg/storage for
+// github.com/cockroachdb/cockroach/pkg/storage. Packages of other modules are
+// returned unchanged.
+func PackageDir(packageName string) string {
+	return strings.TrimPrefix(packageName, modulePath+"/")
+}
+
+// TrimPackageName returns the short name of the given package that the titles
+// of issues start with, which is its directory relative to the root of the
+// module without the leading pkg/, such as storage for
+// github.com/cockroachdb/cockroach/pkg/storage.
+func TrimPackageName(packageName string) string {
+	dir := PackageDir(packageName)
+	if dir == packageName {
+		return packageName
+	}
+	return strings.TrimPrefix(dir, "pkg/")
+}
+
+// DetectModulePath returns the path of the module declared by the go.mod file
+// in the given directory or in the closest of its parents. It returns an error
+// if there is no such file, or if it does not declare a module.
+func DetectModulePath(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		path := filepath.Join(dir, "go.mod")
+		f, err := os.Open(path)
+		if err == nil {
+			defer f.Close()
+			return parseModulePath(path, f)
+		} else if !os.IsNotExist(err) {
+			return "", err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", errors.New("no go.mod file found")
+		}
+		dir = parent
+	}
+}
+
+// parseModulePath returns the path declared by the module directive of the
+// given go.mod file.
+func parseModulePath(path string, f *os.File) (string, error) {
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "module" {
+			continue
+		}
+		modPath := fields[1]
+		if strings.HasPrefix(modPath, `"`) {
+			var err error
+			if modPath, err = strconv.Unquote(modPath); err != nil {
+				return "", errors.Wrapf(err, "invalid module directive in %s", path)
+			}
+		}
+		return modPath, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", errors.Errorf("no module directive in %s", path)
+}
@@ -0,0 +1,66 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package issues
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// rateLimitWarningThreshold is the remaining number of requests below which
+// RemainingRateLimit logs a warning. Posting an issue takes a few requests.
+const rateLimitWarningThreshold = 100
+
+// RateLimit is the budget of requests to the GitHub API that is left to the
+// token used to post issues.
+type RateLimit struct {
+	// Limit is the number of requests per hour.
+	Limit int
+	// Remaining is the number of requests that are left until Reset.
+	Remaining int
+	// Reset is when the budget is reset to Limit.
+	Reset time.Time
+}
+
+func (r RateLimit) String() string {
+	return fmt.Sprintf("%d of %d requests remaining until %s",
+		r.Remaining, r.Limit, r.Reset.UTC().Format(time.RFC3339))
+}
+
+func (p *poster) remainingRateLimit(ctx context.Context) (RateLimit, error) {
+	limits, _, err := p.rateLimits(ctx)
+	if err != nil {
+		return RateLimit{}, errors.Wrap(err, "failed to get the GitHub API rate limit")
+	}
+	core := limits.GetCore()
+	if core == nil {
+		return RateLimit{}, errors.New("GitHub did not report the core API rate limit")
+	}
+	r := RateLimit{Limit: core.Limit, Remaining: core.Remaining, Reset: core.Reset.Time}
+	if r.Remaining < rateLimitWarningThreshold {
+		log.Printf("warning: the GitHub API rate limit is almost exhausted: %s", r)
+	}
+	return r, nil
+}
+
+// RemainingRateLimit returns the budget of requests to the core GitHub API,
+// which is used to post issues and comments, and logs a warning if it is
+// almost exhausted. Getting it does not count against the budget.
+//
+// Callers that post many issues can check the budget beforehand, so that they
+// do not run out of it halfway through.
+func RemainingRateLimit(ctx context.Context) (RateLimit, error) {
+	return defaultPoster().remainingRateLimit(ctx)
+}
@@ -0,0 +1,181 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package issues
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// A Field is a field of IssueData that a template can require.
+type Field string
+
+// These are the fields of IssueData.
+const (
+	FieldPackage Field = "package"
+	FieldTest    Field = "test"
+	FieldAuthor  Field = "author"
+	FieldBody    Field = "body"
+)
+
+// IssueData describes a failure, which is filed as an issue with a template.
+type IssueData struct {
+	// Title is the title of the issue. It is always required.
+	Title string
+	// PackageName is the package of the failure, such as
+	// github.com/cockroachdb/cockroach/pkg/storage.
+	PackageName string
+	// TestName is the name of the failed test.
+	TestName string
+	// AuthorEmail is the email of the author of the test, to whom the issue is
+	// assigned.
+	AuthorEmail string
+	// Body describes the failure, such as with the output of the test.
+	Body *Body
+	// ExtraLabels are added to the labels of the issue.
+	ExtraLabels []string
+}
+
+// isSet returns true if the given field of the data is set. A body is set if
+// one of its sections has content or a URL.
+func (d *IssueData) isSet(f Field) bool {
+	switch f {
+	case FieldPackage:
+		return d.PackageName != ""
+	case FieldTest:
+		return d.TestName != ""
+	case FieldAuthor:
+		return d.AuthorEmail != ""
+	case FieldBody:
+		if d.Body != nil {
+			for i := range d.Body.sections {
+				if d.Body.sections[i].Content != "" || d.Body.sections[i].URL != "" {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	panic(fmt.Sprintf("unknown field %q", f))
+}
+
+// A Template describes the issues filed for a class of failures.
+type Template struct {
+	// Name identifies the template, such as "test-failure".
+	Name string
+	// Heading precedes the link to the build in the body of the issues, such as
+	// "Failed test".
+	Heading string
+	// Labels are added to the labels of all the issues filed by the robot.
+	Labels []string
+	// Required are the fields of IssueData that must be set.
+	Required []Field
+	// Repro is true if the body of the issues includes the instructions to
+	// reproduce the failure under stress.
+	Repro bool
+}
+
+// These are the names of the built-in templates.
+const (
+	TestFailureTemplate = "test-failure"
+	TimeoutTemplate     = "timeout"
+	RaceTemplate        = "race"
+	BuildErrorTemplate  = "build-error"
+)
+
+var templates = map[string]*Template{}
+
+func init() {
+	for _, t := range []Template{
+		{
+			Name:     TestFailureTemplate,
+			Heading:  "Failed test",
+			Required: []Field{FieldPackage, FieldTest, FieldBody},
+			Repro:    true,
+		},
+		{
+			Name:     TimeoutTemplate,
+			Heading:  "Timed out build",
+			Required: []Field{FieldPackage, FieldBody},
+			Repro:    true,
+		},
+		{
+			Name:     RaceTemplate,
+			Heading:  "Race detected by build",
+			Required: []Field{FieldPackage, FieldTest, FieldBody},
+			Repro:    true,
+		},
+		{
+			Name:     BuildErrorTemplate,
+			Heading:  "Failed build",
+			Labels:   []string{"A-build-system"},
+			Required: []Field{FieldPackage, FieldBody},
+		},
+	} {
+		RegisterTemplate(t)
+	}
+}
+
+// RegisterTemplate registers a template, so that it can be used by
+// PostTemplate. It panics if a template with the same name is already
+// registered.
+func RegisterTemplate(t Template) {
+	if _, ok := templates[t.Name]; ok {
+		panic(fmt.Sprintf("template %q is already registered", t.Name))
+	}
+	templates[t.Name] = &t
+}
+
+// LookupTemplate returns the registered template with the given name.
+func LookupTemplate(name string) (*Template, bool) {
+	t, ok := templates[name]
+	return t, ok
+}
+
+// TemplateNames returns the sorted names of the registered templates.
+func TemplateNames() []string {
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate returns an error if the given data lacks a title or one of the
+// fields required by the template.
+func (t *Template) Validate(data *IssueData) error {
+	var missing []string
+	if data.Title == "" {
+		missing = append(missing, "title")
+	}
+	for _, f := range t.Required {
+		if !data.isSet(f) {
+			missing = append(missing, string(f))
+		}
+	}
+	if len(missing) > 0 {
+		return errors.Errorf("issue %q lacks fields required by template %s: %s",
+			data.Title, t.Name, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// labels returns the labels of the issues filed with the template.
+func (t *Template) labels(extraLabels []string) []string {
+	labels := make([]string, 0, len(issueLabels)+len(t.Labels)+len(extraLabels))
+	labels = append(labels, issueLabels...)
+	labels = append(labels, t.Labels...)
+	return append(labels, extraLabels...)
+}
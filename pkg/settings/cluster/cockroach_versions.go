@@ -39,6 +39,7 @@ const (
 	VersionQueryTxnTimestamp
 	VersionStickyBit
 	VersionParallelCommits
+	VersionExtendedTypes
 
 	// Add new versions here (step one of two).
 
@@ -477,6 +478,13 @@ var versionsSingleton = keyedVersions([]keyedVersion{
 		Key:     VersionParallelCommits,
 		Version: roachpb.Version{Major: 19, Minor: 1, Unstable: 4},
 	},
+	{
+		// VersionExtendedTypes gates the types and type modifiers that 19.1 nodes
+		// cannot decode, such as TIME(3) and PG_LSN, in descriptors. See
+		// types.T.MinimumClusterVersion.
+		Key:     VersionExtendedTypes,
+		Version: roachpb.Version{Major: 19, Minor: 1, Unstable: 5},
+	},
 
 	// Add new versions here (step two of two).
 
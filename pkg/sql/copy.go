@@ -79,6 +79,10 @@ type copyMachine struct {
 	// parsing. Is it not correctly initialized with timestamps, transactions and
 	// other things that statements more generally need.
 	parsingEvalCtx *tree.EvalContext
+
+	// coercionProfile is the profile used to parse the values of the input. It
+	// is the default profile, which only accepts the input syntax of SQL.
+	coercionProfile *types.CoercionProfile
 }
 
 // newCopyMachine creates a new copyMachine.
@@ -96,8 +100,9 @@ func newCopyMachine(
 		columns: n.Columns,
 		txnOpt:  txnOpt,
 		// The planner will be prepared before use.
-		p:            planner{execCfg: execCfg},
-		resetPlanner: resetPlanner,
+		p:               planner{execCfg: execCfg},
+		resetPlanner:    resetPlanner,
+		coercionProfile: &types.DefaultCoercionProfile,
 	}
 	c.resetPlanner(&c.p, nil /* txn */, time.Time{} /* txnTS */, time.Time{} /* stmtTS */)
 	c.parsingEvalCtx = c.p.EvalContext()
@@ -376,7 +381,10 @@ func (c *copyMachine) addRow(ctx context.Context, line []byte) error {
 				return err
 			}
 		}
-		d, err := tree.ParseStringAs(c.resultColumns[i].Typ, s, c.parsingEvalCtx)
+		// The values are not parsed with ParseDatumStringAsWithProfile, since
+		// BYTES values have already been unescaped by decodeCopy.
+		d, err := tree.ParseStringAsWithProfile(
+			c.resultColumns[i].Typ, s, c.parsingEvalCtx, c.coercionProfile)
 		if err != nil {
 			return err
 		}
@@ -25,6 +25,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/tests"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
@@ -310,6 +311,62 @@ func TestCopyError(t *testing.T) {
 	}
 }
 
+// TestCopyCoercionProfile verifies that COPY parses values with the default
+// coercion profile, which accepts the input syntax of SQL.
+func TestCopyCoercionProfile(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	params, _ := tests.CreateTestServerParams()
+	s, db, _ := serverutils.StartServer(t, params)
+	defer s.Stopper().Stop(context.TODO())
+
+	if _, err := db.Exec(`
+		CREATE DATABASE d;
+		SET DATABASE = d;
+		CREATE TABLE t (
+			o BOOL,
+			e DECIMAL
+		);
+	`); err != nil {
+		t.Fatal(err)
+	}
+
+	copyRow := func(o, e string) error {
+		txn, err := db.Begin()
+		if err != nil {
+			t.Fatal(err)
+		}
+		stmt, err := txn.Prepare(pq.CopyIn("t", "o", "e"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := stmt.Exec(o, e); err != nil {
+			t.Fatal(err)
+		}
+		if err := stmt.Close(); err != nil {
+			_ = txn.Rollback()
+			return err
+		}
+		return txn.Commit()
+	}
+
+	// Unlike with the strict profile, BOOL values can be spelled as in SQL and
+	// DECIMAL values can use exponent notation.
+	if err := copyRow("yes", "1.5e3"); err != nil {
+		t.Fatal(err)
+	}
+	var n int
+	if err := db.QueryRow("SELECT count(*) FROM t WHERE o AND e = 1500").Scan(&n); err != nil {
+		t.Fatal(err)
+	} else if n != 1 {
+		t.Fatalf("expected 1 row, got %d", n)
+	}
+
+	if err := copyRow("maybe", "1"); !testutils.IsError(err, `could not parse "maybe" as type bool`) {
+		t.Fatalf("expected error, got %v", err)
+	}
+}
+
 // TestCopyOne verifies that only one COPY can run at once.
 func TestCopyOne(t *testing.T) {
 	defer leaktest.AfterTest(t)()
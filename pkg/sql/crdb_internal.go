@@ -1239,7 +1239,7 @@ CREATE TABLE crdb_internal.table_columns (
 						tableName,
 						tree.NewDInt(tree.DInt(col.ID)),
 						tree.NewDString(col.Name),
-						tree.NewDString(col.Type.DebugString()),
+						tree.NewDString(col.Type.CompactDebugString()),
 						tree.MakeDBool(tree.DBool(col.Nullable)),
 						defStr,
 						tree.MakeDBool(tree.DBool(col.Hidden)),
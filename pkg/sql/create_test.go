@@ -23,8 +23,10 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/server"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/tests"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/sqlmigrations"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
@@ -476,6 +478,17 @@ func TestCreateStatementType(t *testing.T) {
 	}
 }
 
+// TestExtendedTypesVersion checks that the cluster version used by the types
+// package to gate the types that older nodes cannot decode is the one of the
+// corresponding version key.
+func TestExtendedTypesVersion(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	v := types.ClusterVersion(cluster.VersionByKey(cluster.VersionExtendedTypes))
+	if v != types.ExtendedTypesVersion {
+		t.Fatalf("expected %s, got %s", v, types.ExtendedTypesVersion)
+	}
+}
+
 // Test that the user's password cannot be set in insecure mode.
 func TestSetUserPasswordInsecure(t *testing.T) {
 	defer leaktest.AfterTest(t)()
@@ -199,6 +199,9 @@ func (dsp *DistSQLPlanner) SetSpanResolver(spanResolver distsqlplan.SpanResolver
 // distSQLExprCheckVisitor is a tree.Visitor that checks if expressions
 // contain things not supported by distSQL (like subqueries).
 type distSQLExprCheckVisitor struct {
+	// st is used to check that the types of the expressions can be decoded by
+	// the other nodes.
+	st  *cluster.Settings
 	err error
 }
 
@@ -222,6 +225,15 @@ func (v *distSQLExprCheckVisitor) VisitPre(expr tree.Expr) (recurse bool, newExp
 			v.err = newQueryNotSupportedErrorf("cast to %s is not supported by distsql", t.Type)
 			return false, expr
 		}
+		if err := sqlbase.CheckTypeIsSupported(v.st, t.Type); err != nil {
+			v.err = newQueryNotSupportedErrorf("cast to %s is not supported by distsql: %v", t.Type, err)
+			return false, expr
+		}
+	case *tree.AnnotateTypeExpr:
+		if err := sqlbase.CheckTypeIsSupported(v.st, t.Type); err != nil {
+			v.err = newQueryNotSupportedErrorf("type %s is not supported by distsql: %v", t.Type, err)
+			return false, expr
+		}
 	}
 	return true, expr
 }
@@ -234,7 +246,7 @@ func (dsp *DistSQLPlanner) checkExpr(expr tree.Expr) error {
 	if expr == nil {
 		return nil
 	}
-	v := distSQLExprCheckVisitor{}
+	v := distSQLExprCheckVisitor{st: dsp.st}
 	tree.WalkExprConst(&v, expr)
 	return v.err
 }
@@ -893,7 +893,7 @@ func MergeResultTypes(left, right []types.T) ([]types.T, error) {
 			merged[i] = *leftType
 		} else {
 			return nil, errors.Errorf(
-				"conflicting ColumnTypes: %s and %s", leftType.DebugString(), rightType.DebugString())
+				"conflicting ColumnTypes: %s and %s", leftType.CompactDebugString(), rightType.CompactDebugString())
 		}
 	}
 	return merged, nil
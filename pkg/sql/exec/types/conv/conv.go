@@ -21,33 +21,10 @@ import (
 	"github.com/pkg/errors"
 )
 
-// FromColumnType returns the T that corresponds to the input ColumnType.
+// FromColumnType returns the T that corresponds to the input ColumnType. See
+// semtypes.ExecType for more details.
 func FromColumnType(ct *semtypes.T) types.T {
-	switch ct.Family() {
-	case semtypes.BoolFamily:
-		return types.Bool
-	case semtypes.BytesFamily, semtypes.StringFamily:
-		return types.Bytes
-	case semtypes.DateFamily, semtypes.OidFamily:
-		return types.Int64
-	case semtypes.DecimalFamily:
-		return types.Decimal
-	case semtypes.IntFamily:
-		switch ct.Width() {
-		case 8:
-			return types.Int8
-		case 16:
-			return types.Int16
-		case 32:
-			return types.Int32
-		case 0, 64:
-			return types.Int64
-		}
-		panic(fmt.Sprintf("integer with unknown width %d", ct.Width()))
-	case semtypes.FloatFamily:
-		return types.Float64
-	}
-	return types.Unhandled
+	return semtypes.ExecType(ct)
 }
 
 // FromColumnTypes calls FromColumnType on each element of cts, returning the
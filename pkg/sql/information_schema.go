@@ -15,7 +15,7 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
-	"unicode/utf8"
+	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/security"
@@ -397,13 +397,13 @@ https://www.postgresql.org/docs/9.5/infoschema-columns.html`,
 					dStringPtrOrNull(column.DefaultExpr),                 // column_default
 					yesOrNoDatum(column.Nullable),                        // is_nullable
 					tree.NewDString(column.Type.InformationSchemaName()), // data_type
-					characterMaximumLength(&column.Type),                 // character_maximum_length
-					characterOctetLength(&column.Type),                   // character_octet_length
-					numericPrecision(&column.Type),                       // numeric_precision
-					numericPrecisionRadix(&column.Type),                  // numeric_precision_radix
-					numericScale(&column.Type),                           // numeric_scale
-					datetimePrecision(&column.Type),                      // datetime_precision
-					tree.DNull,                                           // interval_type
+					dIntFnOrNull(column.Type.CharacterMaximumLength),     // character_maximum_length
+					dIntFnOrNull(column.Type.CharacterOctetLength),       // character_octet_length
+					dIntFnOrNull(column.Type.NumericPrecision),           // numeric_precision
+					dIntFnOrNull(column.Type.NumericPrecisionRadix),      // numeric_precision_radix
+					dIntFnOrNull(column.Type.NumericScale),               // numeric_scale
+					dIntFnOrNull(column.Type.DatetimePrecision),          // datetime_precision
+					intervalType(&column.Type),                           // interval_type
 					tree.DNull,                                           // interval_precision
 					tree.DNull,                                           // character_set_catalog
 					tree.DNull,                                           // character_set_schema
@@ -478,96 +478,17 @@ CREATE TABLE information_schema.enabled_roles (
 	},
 }
 
-// characterMaximumLength returns the declared maximum length of
-// characters if the type is a character or bit string data
-// type. Returns false if the data type is not a character or bit
-// string, or if the string's length is not bounded.
-func characterMaximumLength(colType *types.T) tree.Datum {
-	return dIntFnOrNull(func() (int32, bool) {
-		switch colType.Family() {
-		case types.StringFamily, types.CollatedStringFamily, types.BitFamily:
-			if colType.Width() > 0 {
-				return colType.Width(), true
-			}
-		}
-		return 0, false
-	})
-}
-
-// characterOctetLength returns the maximum possible length in
-// octets of a datum if the T is a character string. Returns
-// false if the data type is not a character string, or if the
-// string's length is not bounded.
-func characterOctetLength(colType *types.T) tree.Datum {
-	return dIntFnOrNull(func() (int32, bool) {
-		switch colType.Family() {
-		case types.StringFamily, types.CollatedStringFamily:
-			if colType.Width() > 0 {
-				return colType.Width() * utf8.UTFMax, true
-			}
-		}
-		return 0, false
-	})
-}
-
-// numericPrecision returns the declared or implicit precision of numeric
-// data types. Returns false if the data type is not numeric, or if the precision
-// of the numeric type is not bounded.
-func numericPrecision(colType *types.T) tree.Datum {
-	return dIntFnOrNull(func() (int32, bool) {
-		switch colType.Family() {
-		case types.IntFamily:
-			return colType.Width(), true
-		case types.FloatFamily:
-			if colType.Width() == 32 {
-				return 24, true
-			}
-			return 53, true
-		case types.DecimalFamily:
-			if colType.Precision() > 0 {
-				return colType.Precision(), true
-			}
-		}
-		return 0, false
-	})
-}
-
-// numericPrecisionRadix returns the implicit precision radix of
-// numeric data types. Returns false if the data type is not numeric.
-func numericPrecisionRadix(colType *types.T) tree.Datum {
-	return dIntFnOrNull(func() (int32, bool) {
-		switch colType.Family() {
-		case types.IntFamily:
-			return 2, true
-		case types.FloatFamily:
-			return 2, true
-		case types.DecimalFamily:
-			return 10, true
-		}
-		return 0, false
-	})
-}
-
-// NumericScale returns the declared or implicit precision of exact numeric
-// data types. Returns false if the data type is not an exact numeric, or if the
-// scale of the exact numeric type is not bounded.
-func numericScale(colType *types.T) tree.Datum {
-	return dIntFnOrNull(func() (int32, bool) {
-		switch colType.Family() {
-		case types.IntFamily:
-			return 0, true
-		case types.DecimalFamily:
-			if colType.Precision() > 0 {
-				return colType.Width(), true
-			}
-		}
-		return 0, false
-	})
-}
-
-func datetimePrecision(colType *types.T) tree.Datum {
-	// We currently do not support a datetime precision.
-	return tree.DNull
+// intervalType returns the duration field qualifier of an INTERVAL type, such
+// as "DAY TO SECOND", or NULL if the type is not a qualified INTERVAL type.
+func intervalType(colType *types.T) tree.Datum {
+	if colType.Family() != types.IntervalFamily {
+		return tree.DNull
+	}
+	itm, err := colType.IntervalTypeMetadata()
+	if err != nil || itm.DurationField == (types.IntervalDurationField{}) {
+		return tree.DNull
+	}
+	return tree.NewDString(strings.TrimPrefix(colType.SQLString(), "INTERVAL "))
 }
 
 var informationSchemaConstraintColumnUsageTable = virtualSchemaTable{
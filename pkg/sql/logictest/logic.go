@@ -421,6 +421,13 @@ var logicTestConfigs = []testClusterConfig{
 		serverVersion:  roachpb.Version{Major: 1, Minor: 1},
 		disableUpgrade: true,
 	},
+	{name: "local-mixed-19.1-19.2", numNodes: 1,
+		overrideDistSQLMode: "off", overrideOptimizerMode: "off",
+		bootstrapVersion: cluster.ClusterVersion{
+			Version: roachpb.Version{Major: 19, Minor: 1},
+		},
+		disableUpgrade: true,
+	},
 	{name: "local-opt", numNodes: 1, overrideDistSQLMode: "off", overrideOptimizerMode: "on", overrideAutoStats: "false"},
 	{name: "local-vec", numNodes: 1, overrideOptimizerMode: "off", overrideExpVectorize: "on"},
 	{name: "fakedist", numNodes: 3, useFakeSpanResolver: true, overrideDistSQLMode: "on", overrideOptimizerMode: "off"},
@@ -354,10 +354,7 @@ func (b *Builder) buildColumnAccess(
 	}
 	childTyp := colAccess.Input.DataType()
 	colIdx := int(colAccess.Idx)
-	lbl := ""
-	if childTyp.TupleLabels() != nil {
-		lbl = childTyp.TupleLabels()[colIdx]
-	}
+	lbl := childTyp.TupleLabel(colIdx)
 	return tree.NewTypedColumnAccessExpr(input, lbl, colIdx), nil
 }
 
@@ -276,7 +276,7 @@ func (c *CustomFuncs) FoldColumnAccess(input opt.ScalarExpr, idx memo.TupleOrdin
 	if memo.CanExtractConstDatum(input) {
 		datum := memo.ExtractConstDatum(input)
 
-		colName := input.DataType().TupleLabels()[idx]
+		colName := input.DataType().TupleLabel(int(idx))
 		texpr := tree.NewTypedColumnAccessExpr(datum, colName, int(idx))
 		result, err := texpr.Eval(c.f.evalCtx)
 		if err == nil {
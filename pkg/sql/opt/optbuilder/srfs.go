@@ -135,10 +135,9 @@ func (b *Builder) finishBuildGeneratorFunction(
 	} else {
 		// Multi-column return type. Use the tuple labels in the SRF's return type
 		// as column aliases.
-		typ := f.ResolvedType()
-		for i := range typ.TupleContents() {
-			b.synthesizeColumn(outScope, typ.TupleLabels()[i], &typ.TupleContents()[i], nil, fn)
-		}
+		f.ResolvedType().ForEachTupleField(func(_ int, label string, typ *types.T) {
+			b.synthesizeColumn(outScope, label, typ, nil, fn)
+		})
 	}
 
 	return fn
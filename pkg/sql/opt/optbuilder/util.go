@@ -90,7 +90,7 @@ func (b *Builder) expandStar(
 				exprs[i] = tTuple.Exprs[i].(tree.TypedExpr)
 			} else {
 				// Can't de-tuplify: (Expr).* -> (Expr).a, (Expr).b, (Expr).c
-				exprs[i] = tree.NewTypedColumnAccessExpr(texpr, typ.TupleLabels()[i], i)
+				exprs[i] = tree.NewTypedColumnAccessExpr(texpr, typ.TupleLabel(i), i)
 			}
 		}
 
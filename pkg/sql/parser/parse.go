@@ -301,6 +301,13 @@ func ParseExpr(sql string) (tree.Expr, error) {
 
 // ParseType parses a column type.
 func ParseType(sql string) (*types.T, error) {
+	// Type names without type modifiers are resolved using the alias table that
+	// is shared with the type formatter. SERIAL types are left to the grammar,
+	// since they must be distinguishable from INT types (see isSerialType).
+	if typ, ok := types.LookupTypeName(sql); ok && !types.IsSerialTypeName(sql) {
+		return typ, nil
+	}
+
 	expr, err := ParseExpr(fmt.Sprintf("1::%s", sql))
 	if err != nil {
 		return nil, err
@@ -338,13 +345,13 @@ func newFloat(prec int64) (*types.T, error) {
 	if prec < 1 {
 		return nil, errFloatPrecAtLeast1
 	}
-	if prec <= 24 {
-		return types.Float4, nil
+	if prec > 54 {
+		return nil, errFloatPrecMax54
 	}
-	if prec <= 54 {
-		return types.Float, nil
+	if types.FloatWidthFromPrecision(int32(prec)) == 32 {
+		return types.Float4, nil
 	}
-	return nil, errFloatPrecMax54
+	return types.Float, nil
 }
 
 // newDecimal creates a type for DECIMAL with the given precision and scale.
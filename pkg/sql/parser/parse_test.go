@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	_ "github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
 	_ "github.com/cockroachdb/cockroach/pkg/util/log" // for flags
@@ -112,6 +113,7 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b UUID)`},
 		{`CREATE TABLE a (b INET)`},
 		{`CREATE TABLE a (b "char")`},
+		{`CREATE TABLE a (b PG_LSN, c MACADDR, d MACADDR8)`},
 		{`CREATE TABLE a (b INT8 NULL)`},
 		{`CREATE TABLE a (b INT8 CONSTRAINT maybe NULL)`},
 		{`CREATE TABLE a (b INT8 NOT NULL)`},
@@ -705,6 +707,10 @@ func TestParse(t *testing.T) {
 		{`SELECT 'foo'::TIMESTAMP(6)`},
 		{`SELECT 'foo'::TIMESTAMPTZ(6)`},
 		{`SELECT 'foo'::TIME(6)`},
+		{`SELECT 'foo'::INTERVAL(3)`},
+		{`SELECT 'foo'::INTERVAL YEAR`},
+		{`SELECT 'foo'::INTERVAL HOUR TO MINUTE`},
+		{`SELECT 'foo'::INTERVAL DAY TO SECOND(3)`},
 
 		{`SELECT '192.168.0.1'::INET`},
 		{`SELECT '192.168.0.1':::INET`},
@@ -2989,7 +2995,6 @@ func TestUnimplementedSyntax(t *testing.T) {
 
 		{`SELECT 123 AT TIME ZONE 'b'`, 32005, ``},
 
-		{`SELECT 'a'::INTERVAL SECOND`, 0, `interval with unit qualifier`},
 		{`SELECT 'a'::INTERVAL(123)`, 32564, ``},
 		{`SELECT 'a'::INTERVAL SECOND(123)`, 32564, `interval second`},
 		{`SELECT INTERVAL(3) 'a'`, 32564, ``},
@@ -3025,11 +3030,8 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`CREATE TABLE a(b CIRCLE)`, 21286, `circle`},
 		{`CREATE TABLE a(b LINE)`, 21286, `line`},
 		{`CREATE TABLE a(b LSEG)`, 21286, `lseg`},
-		{`CREATE TABLE a(b MACADDR)`, 0, `macaddr`},
-		{`CREATE TABLE a(b MACADDR8)`, 0, `macaddr8`},
 		{`CREATE TABLE a(b MONEY)`, 0, `money`},
 		{`CREATE TABLE a(b PATH)`, 21286, `path`},
-		{`CREATE TABLE a(b PG_LSN)`, 0, `pg_lsn`},
 		{`CREATE TABLE a(b POINT)`, 21286, `point`},
 		{`CREATE TABLE a(b POLYGON)`, 21286, `polygon`},
 		{`CREATE TABLE a(b TSQUERY)`, 7821, `tsquery`},
@@ -3182,6 +3184,62 @@ func TestParseOne(t *testing.T) {
 	}
 }
 
+// TestParseTypeAliases verifies that every type name in the alias table is
+// accepted by the grammar, and that ParseType resolves it to the same type as
+// the grammar does.
+func TestParseTypeAliases(t *testing.T) {
+	for _, name := range types.TypeNameAliases() {
+		expr, err := parser.ParseExpr("1::" + name)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		expected := expr.(*tree.CastExpr).Type
+		names := []string{name}
+		if !strings.Contains(name, `"`) {
+			// Unquoted type names are case-insensitive.
+			names = append(names, strings.ToUpper(name))
+		}
+		for _, s := range names {
+			typ, err := parser.ParseType(s)
+			if err != nil {
+				t.Errorf("%s: %v", s, err)
+				continue
+			}
+			if !typ.Identical(expected) {
+				t.Errorf("%s: expected %s, got %s", s, expected.DebugString(), typ.DebugString())
+			}
+		}
+	}
+}
+
+// TestParseBytesWidth verifies that BYTES types can have a maximum length,
+// whether or not types.BytesWidthEnabled is set.
+func TestParseBytesWidth(t *testing.T) {
+	testData := []struct {
+		sql      string
+		expected string
+	}{
+		{`CREATE TABLE a (b BYTES(10))`, `CREATE TABLE a (b BYTES(10))`},
+		{`CREATE TABLE a (b BYTEA(10), c BLOB(20))`, `CREATE TABLE a (b BYTES(10), c BYTES(20))`},
+		{`SELECT 'foo'::BYTES(3)`, `SELECT 'foo'::BYTES(3)`},
+	}
+	for _, d := range testData {
+		stmts, err := parser.Parse(d.sql)
+		if err != nil {
+			t.Fatalf("%s: %v", d.sql, err)
+		}
+		if s := stmts.String(); s != d.expected {
+			t.Errorf("%s: expected %s, but found %s", d.sql, d.expected, s)
+		}
+	}
+
+	if _, err := parser.Parse(`CREATE TABLE a (b BYTES(0))`); !testutils.IsError(err,
+		"length for type bytes must be at least 1") {
+		t.Errorf("expected error, got %v", err)
+	}
+}
+
 func BenchmarkParse(b *testing.B) {
 	testCases := []struct {
 		name, query string
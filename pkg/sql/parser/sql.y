@@ -413,8 +413,8 @@ func (u *sqlSymUnion) op() tree.Operator {
 func (u *sqlSymUnion) cmpOp() tree.ComparisonOperator {
     return u.val.(tree.ComparisonOperator)
 }
-func (u *sqlSymUnion) durationField() tree.DurationField {
-    return u.val.(tree.DurationField)
+func (u *sqlSymUnion) intervalTypeMetadata() types.IntervalTypeMetadata {
+    return u.val.(types.IntervalTypeMetadata)
 }
 func (u *sqlSymUnion) kvOption() tree.KVOption {
     return u.val.(tree.KVOption)
@@ -851,7 +851,7 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.Exprs> substr_list
 %type <tree.Exprs> trim_list
 %type <tree.Exprs> execute_param_clause
-%type <tree.DurationField> opt_interval interval_second interval_qualifier
+%type <types.IntervalTypeMetadata> opt_interval interval_second interval_qualifier
 %type <tree.Expr> overlay_placing
 
 %type <bool> opt_unique opt_cluster
@@ -918,6 +918,7 @@ func newNameFromStr(s string) *tree.Name {
 %type <*types.T> character_with_length character_without_length
 %type <*types.T> const_datetime const_interval
 %type <*types.T> bit_with_length bit_without_length
+%type <*types.T> bytes_with_length bytes_base
 %type <*types.T> character_base
 %type <*types.T> postgres_oid
 %type <*types.T> cast_target
@@ -6519,9 +6520,20 @@ simple_typename:
   const_typename
 | bit_with_length
 | character_with_length
+| bytes_with_length
 | const_interval
-| const_interval interval_qualifier { return unimplemented(sqllex, "interval with unit qualifier") }
-| const_interval '(' ICONST ')' { return unimplementedWithIssue(sqllex, 32564) }
+| const_interval interval_qualifier
+  {
+    $$.val = types.MakeInterval($2.intervalTypeMetadata())
+  }
+| const_interval '(' iconst32 ')'
+  {
+    prec := $3.int32()
+    if prec < 0 || prec > types.MaxTimePrecision {
+      return unimplementedWithIssue(sqllex, 32564)
+    }
+    $$.val = types.MakeInterval(types.IntervalTypeMetadata{Precision: prec, PrecisionIsSet: true})
+  }
 
 // We have a separate const_typename to allow defaulting fixed-length types
 // such as CHAR() and BIT() to an unspecified length. SQL9x requires that these
@@ -6541,18 +6553,7 @@ const_typename:
   {
     $$.val = types.Jsonb
   }
-| BLOB
-  {
-    $$.val = types.Bytes
-  }
-| BYTES
-  {
-    $$.val = types.Bytes
-  }
-| BYTEA
-  {
-    $$.val = types.Bytes
-  }
+| bytes_base
 | TEXT
   {
     $$.val = types.String
@@ -6819,6 +6820,31 @@ bit_without_length:
     $$.val = types.VarBit
   }
 
+// BYTES(n) columns are only accepted if types.BytesWidthEnabled is set.
+bytes_with_length:
+  bytes_base '(' iconst32 ')'
+  {
+    width := $3.int32()
+    if err := types.ValidateBytesWidth(width); err != nil {
+      return setErr(sqllex, err)
+    }
+    $$.val = types.MakeBytes(width)
+  }
+
+bytes_base:
+  BLOB
+  {
+    $$.val = types.Bytes
+  }
+| BYTES
+  {
+    $$.val = types.Bytes
+  }
+| BYTEA
+  {
+    $$.val = types.Bytes
+  }
+
 character_with_length:
   character_base '(' iconst32 ')'
   {
@@ -6877,9 +6903,10 @@ const_datetime:
 | TIME '(' iconst32 ')' opt_timezone
   {
     prec := $3.int32()
-    if prec != 6 {
-         return unimplementedWithIssue(sqllex, 32565)
+    if prec < 0 || prec > types.MaxTimePrecision {
+      return unimplementedWithIssue(sqllex, 32565)
     }
+    if $5.bool() { return unimplementedWithIssueDetail(sqllex, 26097, "type with precision") }
     $$.val = types.MakeTime(prec)
   }
 | TIMETZ                             { return unimplementedWithIssueDetail(sqllex, 26097, "type") }
@@ -6931,72 +6958,124 @@ const_interval:
 interval_qualifier:
   YEAR
   {
-    $$.val = tree.Year
+    $$.val = types.IntervalTypeMetadata{
+      DurationField: types.IntervalDurationField{DurationType: types.IntervalDurationType_YEAR},
+    }
   }
 | MONTH
   {
-    $$.val = tree.Month
+    $$.val = types.IntervalTypeMetadata{
+      DurationField: types.IntervalDurationField{DurationType: types.IntervalDurationType_MONTH},
+    }
   }
 | DAY
   {
-    $$.val = tree.Day
+    $$.val = types.IntervalTypeMetadata{
+      DurationField: types.IntervalDurationField{DurationType: types.IntervalDurationType_DAY},
+    }
   }
 | HOUR
   {
-    $$.val = tree.Hour
+    $$.val = types.IntervalTypeMetadata{
+      DurationField: types.IntervalDurationField{DurationType: types.IntervalDurationType_HOUR},
+    }
   }
 | MINUTE
   {
-    $$.val = tree.Minute
+    $$.val = types.IntervalTypeMetadata{
+      DurationField: types.IntervalDurationField{DurationType: types.IntervalDurationType_MINUTE},
+    }
   }
 | interval_second
   {
-    $$.val = $1.durationField()
+    $$.val = $1.intervalTypeMetadata()
   }
-// Like Postgres, we ignore the left duration field. See explanation:
+// Like Postgres, we ignore the left duration field when evaluating. See
+// explanation:
 // https://www.postgresql.org/message-id/20110510040219.GD5617%40tornado.gateway.2wire.net
+// It is still recorded in FromDurationType so that the type can be formatted
+// as it was written.
 | YEAR TO MONTH
   {
-    $$.val = tree.Month
+    $$.val = types.IntervalTypeMetadata{
+      DurationField: types.IntervalDurationField{
+        FromDurationType: types.IntervalDurationType_YEAR,
+        DurationType: types.IntervalDurationType_MONTH,
+      },
+    }
   }
 | DAY TO HOUR
   {
-    $$.val = tree.Hour
+    $$.val = types.IntervalTypeMetadata{
+      DurationField: types.IntervalDurationField{
+        FromDurationType: types.IntervalDurationType_DAY,
+        DurationType: types.IntervalDurationType_HOUR,
+      },
+    }
   }
 | DAY TO MINUTE
   {
-    $$.val = tree.Minute
+    $$.val = types.IntervalTypeMetadata{
+      DurationField: types.IntervalDurationField{
+        FromDurationType: types.IntervalDurationType_DAY,
+        DurationType: types.IntervalDurationType_MINUTE,
+      },
+    }
   }
 | DAY TO interval_second
   {
-    $$.val = $3.durationField()
+    ret := $3.intervalTypeMetadata()
+    ret.DurationField.FromDurationType = types.IntervalDurationType_DAY
+    $$.val = ret
   }
 | HOUR TO MINUTE
   {
-    $$.val = tree.Minute
+    $$.val = types.IntervalTypeMetadata{
+      DurationField: types.IntervalDurationField{
+        FromDurationType: types.IntervalDurationType_HOUR,
+        DurationType: types.IntervalDurationType_MINUTE,
+      },
+    }
   }
 | HOUR TO interval_second
   {
-    $$.val = $3.durationField()
+    ret := $3.intervalTypeMetadata()
+    ret.DurationField.FromDurationType = types.IntervalDurationType_HOUR
+    $$.val = ret
   }
 | MINUTE TO interval_second
   {
-    $$.val = $3.durationField()
+    ret := $3.intervalTypeMetadata()
+    ret.DurationField.FromDurationType = types.IntervalDurationType_MINUTE
+    $$.val = ret
   }
 
 opt_interval:
   interval_qualifier
 | /* EMPTY */
   {
-    $$.val = nil
+    $$.val = types.IntervalTypeMetadata{}
   }
 
 interval_second:
   SECOND
   {
-    $$.val = tree.Second
+    $$.val = types.IntervalTypeMetadata{
+      DurationField: types.IntervalDurationField{DurationType: types.IntervalDurationType_SECOND},
+    }
+  }
+| SECOND '(' iconst32 ')'
+  {
+    prec := $3.int32()
+    if prec < 0 || prec > types.MaxTimePrecision {
+      return unimplementedWithIssueDetail(sqllex, 32564, "interval second")
+    }
+    $$.val = types.IntervalTypeMetadata{
+      DurationField: types.IntervalDurationField{DurationType: types.IntervalDurationType_SECOND},
+      Precision: prec,
+      PrecisionIsSet: true,
+    }
   }
-| SECOND '(' ICONST ')' { return unimplementedWithIssueDetail(sqllex, 32564, "interval second") }
 
 // General expressions. This is the heart of the expression syntax.
 //
@@ -8630,13 +8709,7 @@ interval:
   {
     // We don't carry opt_interval information into the column type, so we need
     // to parse the interval directly.
-    var err error
-    var d tree.Datum
-    if $3.val == nil {
-      d, err = tree.ParseDInterval($2)
-    } else {
-      d, err = tree.ParseDIntervalWithField($2, $3.durationField())
-    }
+    d, err := tree.ParseDIntervalWithTypeMetadata($2, $3.intervalTypeMetadata())
     if err != nil { return setErr(sqllex, err) }
     $$.val = d
   }
@@ -271,7 +271,7 @@ CREATE TABLE pg_catalog.pg_am (
 	amcostestimate OID,
 	amoptions OID,
 	amhandler OID,
-	amtype CHAR
+	amtype "char"
 )`,
 	populate: func(_ context.Context, p *planner, _ *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
 		return addRow(
@@ -362,8 +362,8 @@ CREATE TABLE pg_catalog.pg_attribute (
 	attcacheoff INT,
 	atttypmod INT,
 	attbyval BOOL,
-	attstorage CHAR,
-	attalign CHAR,
+	attstorage "char",
+	attalign "char",
 	attnotnull BOOL,
 	atthasdef BOOL,
 	attisdropped BOOL,
@@ -380,23 +380,7 @@ CREATE TABLE pg_catalog.pg_attribute (
 			// addColumn adds adds either a table or a index column to the pg_attribute table.
 			addColumn := func(column *sqlbase.ColumnDescriptor, attRelID tree.Datum, colID sqlbase.ColumnID) error {
 				colTyp := &column.Type
-				attTypMod := int32(-1)
-				if width := colTyp.Width(); width != 0 {
-					switch colTyp.Family() {
-					case types.StringFamily:
-						// Postgres adds 4 to the attypmod for bounded string types, the
-						// var header size.
-						attTypMod = width + 4
-					case types.BitFamily:
-						attTypMod = width
-					case types.DecimalFamily:
-						// attTypMod is calculated by putting the precision in the upper
-						// bits and the scale in the lower bits of a 32-bit int, and adding
-						// 4 (the var header size). We mock this for clients' sake. See
-						// numeric.c.
-						attTypMod = ((colTyp.Precision() << 16) | width) + 4
-					}
-				}
+				attTypMod := colTyp.Typmod()
 				return addRow(
 					attRelID,                           // attrelid
 					tree.NewDName(column.Name),         // attname
@@ -495,9 +479,9 @@ CREATE TABLE pg_catalog.pg_class (
 	reltoastrelid OID,
 	relhasindex BOOL,
 	relisshared BOOL,
-	relpersistence CHAR,
+	relpersistence "char",
 	relistemp BOOL,
-	relkind CHAR,
+	relkind "char",
 	relnatts INT,
 	relchecks INT,
 	relhasoids BOOL,
@@ -602,7 +586,7 @@ https://www.postgresql.org/docs/9.5/catalog-pg-collation.html`,
 	schema: `
 CREATE TABLE pg_catalog.pg_collation (
   oid OID,
-  collname STRING,
+  collname NAME,
   collnamespace OID,
   collowner OID,
   collencoding INT,
@@ -679,7 +663,7 @@ CREATE TABLE pg_catalog.pg_constraint (
 	oid OID,
 	conname NAME,
 	connamespace OID,
-	contype STRING,
+	contype "char",
 	condeferrable BOOL,
 	condeferred BOOL,
 	convalidated BOOL,
@@ -687,9 +671,9 @@ CREATE TABLE pg_catalog.pg_constraint (
 	contypid OID,
 	conindid OID,
 	confrelid OID,
-	confupdtype STRING,
-	confdeltype STRING,
-	confmatchtype STRING,
+	confupdtype "char",
+	confdeltype "char",
+	confmatchtype "char",
 	conislocal BOOL,
 	coninhcount INT,
 	connoinherit BOOL,
@@ -959,7 +943,7 @@ CREATE TABLE pg_catalog.pg_depend (
   refclassid OID,
   refobjid OID,
   refobjsubid INT,
-  deptype CHAR
+  deptype "char"
 )`,
 	populate: func(ctx context.Context, p *planner, dbContext *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
 		vt := p.getVirtualTabler()
@@ -1483,7 +1467,7 @@ CREATE TABLE pg_catalog.pg_operator (
 	oprname NAME,
 	oprnamespace OID,
 	oprowner OID,
-	oprkind TEXT,
+	oprkind "char",
 	oprcanmerge BOOL,
 	oprcanhash BOOL,
 	oprleft OID,
@@ -1609,8 +1593,8 @@ CREATE TABLE pg_catalog.pg_proc (
 	proleakproof BOOL,
 	proisstrict BOOL,
 	proretset BOOL,
-	provolatile CHAR,
-	proparallel CHAR,
+	provolatile "char",
+	proparallel "char",
 	pronargs INT,
 	pronargdefaults INT,
 	prorettype OID,
@@ -1775,8 +1759,8 @@ CREATE TABLE pg_catalog.pg_rewrite (
 	oid OID,
 	rulename NAME,
 	ev_class OID,
-	ev_type TEXT,
-	ev_enabled TEXT,
+	ev_type "char",
+	ev_enabled "char",
 	is_instead BOOL,
 	ev_qual TEXT,
 	ev_action TEXT
@@ -2021,7 +2005,7 @@ CREATE TABLE pg_catalog.pg_trigger (
 	tgname NAME,
 	tgfoid OID,
 	tgtype INT,
-	tgenabled TEXT,
+	tgenabled "char",
 	tgisinternal BOOL,
 	tgconstrrelid OID,
 	tgconstrindid OID,
@@ -2041,48 +2025,6 @@ CREATE TABLE pg_catalog.pg_trigger (
 	},
 }
 
-var (
-	typTypeBase      = tree.NewDString("b")
-	typTypeComposite = tree.NewDString("c")
-	typTypeDomain    = tree.NewDString("d")
-	typTypeEnum      = tree.NewDString("e")
-	typTypePseudo    = tree.NewDString("p")
-	typTypeRange     = tree.NewDString("r")
-
-	// Avoid unused warning for constants.
-	_ = typTypeComposite
-	_ = typTypeDomain
-	_ = typTypeEnum
-	_ = typTypePseudo
-	_ = typTypeRange
-
-	// See https://www.postgresql.org/docs/9.6/static/catalog-pg-type.html#CATALOG-TYPCATEGORY-TABLE.
-	typCategoryArray       = tree.NewDString("A")
-	typCategoryBoolean     = tree.NewDString("B")
-	typCategoryComposite   = tree.NewDString("C")
-	typCategoryDateTime    = tree.NewDString("D")
-	typCategoryEnum        = tree.NewDString("E")
-	typCategoryGeometric   = tree.NewDString("G")
-	typCategoryNetworkAddr = tree.NewDString("I")
-	typCategoryNumeric     = tree.NewDString("N")
-	typCategoryPseudo      = tree.NewDString("P")
-	typCategoryRange       = tree.NewDString("R")
-	typCategoryString      = tree.NewDString("S")
-	typCategoryTimespan    = tree.NewDString("T")
-	typCategoryUserDefined = tree.NewDString("U")
-	typCategoryBitString   = tree.NewDString("V")
-	typCategoryUnknown     = tree.NewDString("X")
-
-	// Avoid unused warning for constants.
-	_ = typCategoryComposite
-	_ = typCategoryEnum
-	_ = typCategoryGeometric
-	_ = typCategoryRange
-	_ = typCategoryBitString
-
-	typDelim = tree.NewDString(",")
-)
-
 var pgCatalogTypeTable = virtualSchemaTable{
 	comment: `scalar types (incomplete)
 https://www.postgresql.org/docs/9.5/catalog-pg-type.html`,
@@ -2094,11 +2036,11 @@ CREATE TABLE pg_catalog.pg_type (
 	typowner OID,
 	typlen INT,
 	typbyval BOOL,
-	typtype CHAR,
-	typcategory CHAR,
+	typtype "char",
+	typcategory "char",
 	typispreferred BOOL,
 	typisdefined BOOL,
-	typdelim CHAR,
+	typdelim "char",
 	typrelid OID,
 	typelem OID,
 	typarray OID,
@@ -2109,8 +2051,8 @@ CREATE TABLE pg_catalog.pg_type (
 	typmodin OID,
 	typmodout OID,
 	typanalyze OID,
-	typalign CHAR,
-	typstorage CHAR,
+	typalign "char",
+	typstorage "char",
 	typnotnull BOOL,
 	typbasetype OID,
 	typtypmod INT,
@@ -2126,52 +2068,44 @@ CREATE TABLE pg_catalog.pg_type (
 			nspOid := h.NamespaceOid(db, pgCatalogName)
 
 			for o, typ := range types.OidToType {
-				cat := typCategory(typ)
-				typType := typTypeBase
+				info := typ.PGTypeInfo()
 				typElem := oidZero
 				typArray := oidZero
 				builtinPrefix := builtins.PGIOBuiltinPrefix(typ)
 				if typ.Family() == types.ArrayFamily {
 					switch typ.Oid() {
-					case oid.T_int2vector:
-						// IntVector needs a special case because it's a special snowflake
-						// type that behaves in some ways like a scalar type and in others
-						// like an array type.
-						typElem = tree.NewDOid(tree.DInt(oid.T_int2))
-						typArray = tree.NewDOid(tree.DInt(oid.T__int2vector))
-					case oid.T_oidvector:
-						// Same story as above for OidVector.
-						typElem = tree.NewDOid(tree.DInt(oid.T_oid))
-						typArray = tree.NewDOid(tree.DInt(oid.T__oidvector))
+					case oid.T_int2vector, oid.T_oidvector:
+						// IntVector and OidVector need a special case because they're
+						// special snowflake types that behave in some ways like scalar
+						// types and in others like array types.
 					case oid.T_anyarray:
 						// AnyArray does not use a prefix or element type.
 					default:
 						builtinPrefix = "array_"
-						typElem = tree.NewDOid(tree.DInt(typ.ArrayContents().Oid()))
 					}
-				} else {
-					typArray = tree.NewDOid(tree.DInt(types.MakeArray(typ).Oid()))
 				}
-				if cat == typCategoryPseudo {
-					typType = typTypePseudo
+				if info.Elem != 0 {
+					typElem = tree.NewDOid(tree.DInt(info.Elem))
+				}
+				if info.Array != 0 {
+					typArray = tree.NewDOid(tree.DInt(info.Array))
 				}
-				typname := typ.PGName()
 
 				if err := addRow(
-					tree.NewDOid(tree.DInt(o)), // oid
-					tree.NewDName(typname),     // typname
-					nspOid,                     // typnamespace
-					tree.DNull,                 // typowner
-					typLen(typ),                // typlen
-					typByVal(typ),              // typbyval
-					typType,                    // typtype
-					cat,                        // typcategory
-					tree.DBoolFalse,            // typispreferred
-					tree.DBoolTrue,             // typisdefined
-					typDelim,                   // typdelim
-					oidZero,                    // typrelid
-					typElem,                    // typelem
-					typArray,                   // typarray
+					tree.NewDOid(tree.DInt(o)),                   // oid
+					tree.NewDName(info.Name),                     // typname
+					nspOid,                                       // typnamespace
+					tree.DNull,                                   // typowner
+					tree.NewDInt(tree.DInt(info.Len)),            // typlen
+					tree.MakeDBool(tree.DBool(info.ByVal)),       // typbyval
+					tree.NewDString(string(info.Type)),           // typtype
+					tree.NewDString(string(info.Category)),       // typcategory
+					tree.MakeDBool(tree.DBool(info.IsPreferred)), // typispreferred
+					tree.DBoolTrue,                               // typisdefined
+					tree.NewDString(string(info.Delim)),          // typdelim
+					oidZero,                                      // typrelid
+					typElem,                                      // typelem
+					typArray,                                     // typarray
 
 					// regproc references
 					h.RegProc(builtinPrefix+"in"),   // typinput
@@ -2328,15 +2262,7 @@ func typOid(typ *types.T) tree.Datum {
 }
 
 func typLen(typ *types.T) *tree.DInt {
-	if sz, variable := tree.DatumTypeSize(typ); !variable {
-		return tree.NewDInt(tree.DInt(sz))
-	}
-	return negOneVal
-}
-
-func typByVal(typ *types.T) tree.Datum {
-	_, variable := tree.DatumTypeSize(typ)
-	return tree.MakeDBool(tree.DBool(!variable))
+	return tree.NewDInt(tree.DInt(typ.PGTypeInfo().Len))
 }
 
 // typColl returns the collation OID for a given type.
@@ -2347,47 +2273,30 @@ func typColl(typ *types.T, h oidHasher) tree.Datum {
 	case types.AnyFamily:
 		return oidZero
 	case types.StringFamily:
+		if !isCollatableStringKind(typ.StringKind()) {
+			return oidZero
+		}
 		return h.CollationOid(defaultCollationTag)
 	case types.CollatedStringFamily:
 		return h.CollationOid(typ.Locale())
 	}
 
-	if typ.Equivalent(types.StringArray) {
+	if typ.Equivalent(types.StringArray) && isCollatableStringKind(typ.ArrayContents().StringKind()) {
 		return h.CollationOid(defaultCollationTag)
 	}
 	return oidZero
 }
 
-// This mapping should be kept sync with PG's categorization.
-var datumToTypeCategory = map[types.Family]*tree.DString{
-	types.AnyFamily:         typCategoryPseudo,
-	types.BitFamily:         typCategoryBitString,
-	types.BoolFamily:        typCategoryBoolean,
-	types.BytesFamily:       typCategoryUserDefined,
-	types.DateFamily:        typCategoryDateTime,
-	types.TimeFamily:        typCategoryDateTime,
-	types.FloatFamily:       typCategoryNumeric,
-	types.IntFamily:         typCategoryNumeric,
-	types.IntervalFamily:    typCategoryTimespan,
-	types.JsonFamily:        typCategoryUserDefined,
-	types.DecimalFamily:     typCategoryNumeric,
-	types.StringFamily:      typCategoryString,
-	types.TimestampFamily:   typCategoryDateTime,
-	types.TimestampTZFamily: typCategoryDateTime,
-	types.ArrayFamily:       typCategoryArray,
-	types.TupleFamily:       typCategoryPseudo,
-	types.OidFamily:         typCategoryNumeric,
-	types.UuidFamily:        typCategoryUserDefined,
-	types.INetFamily:        typCategoryNetworkAddr,
-	types.UnknownFamily:     typCategoryUnknown,
-}
-
-func typCategory(typ *types.T) tree.Datum {
-	// Special case ARRAY of ANY.
-	if typ.Family() == types.ArrayFamily && typ.ArrayContents().Family() == types.AnyFamily {
-		return typCategoryPseudo
+// isCollatableStringKind returns false for the kinds of the types that are
+// represented as strings, but that are not collatable in Postgres because they
+// are not character string types.
+func isCollatableStringKind(kind types.StringKind) bool {
+	switch kind {
+	case types.JSONPathKind, types.RefCursorKind, types.PGLSNKind, types.MACAddrKind,
+		types.MACAddr8Kind:
+		return false
 	}
-	return datumToTypeCategory[typ.Family()]
+	return true
 }
 
 var pgCatalogViewsTable = virtualSchemaTable{
@@ -2397,7 +2306,7 @@ https://www.postgresql.org/docs/9.5/view-pg-views.html`,
 CREATE TABLE pg_catalog.pg_views (
 	schemaname NAME,
 	viewname NAME,
-	viewowner STRING,
+	viewowner NAME,
 	definition STRING
 )`,
 	populate: func(ctx context.Context, p *planner, dbContext *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
@@ -0,0 +1,136 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgwirebase
+
+import (
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
+)
+
+// TypeCodec declares how the values of a SQL type are exchanged in the pgwire
+// text and binary formats. A type that registers a codec with RegisterTypeCodec
+// is automatically handled by DecodeOidDatum and by the pgwire result writer,
+// without requiring new cases in their switch statements.
+//
+// The format codes a type supports are implied by the hooks that are set: a
+// codec supports FormatText if DecodeText and EncodeText are set, and
+// FormatBinary if DecodeBinary and EncodeBinary are set.
+type TypeCodec struct {
+	// Family is the type family of the values handled by the codec.
+	Family types.Family
+	// Oids are the type OIDs handled by the codec. Each OID can be handled by
+	// only one codec.
+	Oids []oid.Oid
+
+	// DecodeText decodes a value in the text format. If the ParseTimeContext is
+	// nil, reasonable defaults should be applied.
+	DecodeText func(ctx tree.ParseTimeContext, id oid.Oid, b []byte) (tree.Datum, error)
+	// DecodeBinary decodes a value in the binary format.
+	DecodeBinary func(ctx tree.ParseTimeContext, id oid.Oid, b []byte) (tree.Datum, error)
+	// EncodeText encodes a non-NULL value in the text format. The result does
+	// not include the length prefix.
+	EncodeText func(d tree.Datum, conv sessiondata.DataConversionConfig) ([]byte, error)
+	// EncodeBinary encodes a non-NULL value in the binary format, for a
+	// column having the given OID. The result does not include the length
+	// prefix.
+	EncodeBinary func(d tree.Datum, id oid.Oid, sessionLoc *time.Location) ([]byte, error)
+}
+
+// SupportsFormat returns true if the codec can both encode and decode values
+// in the given format.
+func (c *TypeCodec) SupportsFormat(code FormatCode) bool {
+	switch code {
+	case FormatText:
+		return c.DecodeText != nil && c.EncodeText != nil
+	case FormatBinary:
+		return c.DecodeBinary != nil && c.EncodeBinary != nil
+	}
+	return false
+}
+
+// decode decodes a value in the given format using the codec.
+func (c *TypeCodec) decode(
+	ctx tree.ParseTimeContext, id oid.Oid, code FormatCode, b []byte,
+) (tree.Datum, error) {
+	var fn func(tree.ParseTimeContext, oid.Oid, []byte) (tree.Datum, error)
+	switch code {
+	case FormatText:
+		fn = c.DecodeText
+	case FormatBinary:
+		fn = c.DecodeBinary
+	default:
+		return nil, errors.AssertionFailedf(
+			"unexpected format code: %d", errors.Safe(code))
+	}
+	if fn == nil {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"format code %s is not supported for type %s", errors.Safe(code), c.Family)
+	}
+	return fn(ctx, id, b)
+}
+
+// typeCodecs maps each OID to the codec registered for it.
+var typeCodecs = map[oid.Oid]*TypeCodec{}
+
+// RegisterTypeCodec registers the given codec for each of its OIDs. It must be
+// called during initialization, and panics if an OID already has a codec.
+func RegisterTypeCodec(c TypeCodec) {
+	if len(c.Oids) == 0 {
+		panic(errors.AssertionFailedf("codec for %s has no OIDs", c.Family))
+	}
+	codec := &c
+	for _, id := range c.Oids {
+		if _, ok := typeCodecs[id]; ok {
+			panic(errors.AssertionFailedf("duplicate codec for OID %d", id))
+		}
+		typeCodecs[id] = codec
+	}
+}
+
+// LookupTypeCodec returns the codec registered for the given OID, if any.
+func LookupTypeCodec(id oid.Oid) (*TypeCodec, bool) {
+	c, ok := typeCodecs[id]
+	return c, ok
+}
+
+func init() {
+	RegisterTypeCodec(TypeCodec{
+		Family: types.UuidFamily,
+		Oids:   []oid.Oid{oid.T_uuid},
+		DecodeText: func(_ tree.ParseTimeContext, _ oid.Oid, b []byte) (tree.Datum, error) {
+			d, err := tree.ParseDUuidFromString(string(b))
+			if err != nil {
+				return nil, pgerror.Newf(pgcode.Syntax, "could not parse string %q as uuid", b)
+			}
+			return d, nil
+		},
+		DecodeBinary: func(_ tree.ParseTimeContext, _ oid.Oid, b []byte) (tree.Datum, error) {
+			u, err := tree.ParseDUuidFromBytes(b)
+			if err != nil {
+				return nil, err
+			}
+			return u, nil
+		},
+		EncodeText: func(d tree.Datum, _ sessiondata.DataConversionConfig) ([]byte, error) {
+			return []byte(d.(*tree.DUuid).UUID.String()), nil
+		},
+		EncodeBinary: func(d tree.Datum, _ oid.Oid, _ *time.Location) ([]byte, error) {
+			return d.(*tree.DUuid).GetBytes(), nil
+		},
+	})
+}
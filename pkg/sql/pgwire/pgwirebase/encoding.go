@@ -16,6 +16,7 @@ import (
 	"encoding/binary"
 	"io"
 	"math"
+	"net"
 	"strconv"
 	"time"
 	"unicode/utf8"
@@ -208,10 +209,14 @@ func validateArrayDimensions(nDimensions int, nElements int) error {
 
 // DecodeOidDatum decodes bytes with specified Oid and format code into
 // a datum. If the ParseTimeContext is nil, reasonable defaults
-// will be applied.
+// will be applied. Types that have registered a TypeCodec are decoded by
+// their codec.
 func DecodeOidDatum(
 	ctx tree.ParseTimeContext, id oid.Oid, code FormatCode, b []byte,
 ) (tree.Datum, error) {
+	if c, ok := LookupTypeCodec(id); ok {
+		return c.decode(ctx, id, code, b)
+	}
 	switch code {
 	case FormatText:
 		switch id {
@@ -288,12 +293,6 @@ func DecodeOidDatum(
 				return nil, pgerror.Newf(pgcode.Syntax, "could not parse string %q as interval", b)
 			}
 			return d, nil
-		case oid.T_uuid:
-			d, err := tree.ParseDUuidFromString(string(b))
-			if err != nil {
-				return nil, pgerror.Newf(pgcode.Syntax, "could not parse string %q as uuid", b)
-			}
-			return d, nil
 		case oid.T_inet:
 			d, err := tree.ParseDIPAddrFromINetString(string(b))
 			if err != nil {
@@ -362,6 +361,17 @@ func DecodeOidDatum(
 				return nil, err
 			}
 			return tree.ParseDJSON(string(b))
+		case types.T_jsonpath:
+			if err := validateStringBytes(b); err != nil {
+				return nil, err
+			}
+			return tree.NewDString(string(b)), nil
+		case oid.T_pg_lsn, oid.T_macaddr, types.T_macaddr8:
+			s, err := types.OidToType[id].CanonicalizeString(string(b))
+			if err != nil {
+				return nil, err
+			}
+			return tree.NewDString(s), nil
 		}
 		if _, ok := types.ArrayOids[id]; ok {
 			// Arrays come in in their string form, so we parse them as such and later
@@ -534,12 +544,6 @@ func DecodeOidDatum(
 
 			duration := duration.MakeDuration(nanos, int64(days), int64(months))
 			return &tree.DInterval{Duration: duration}, nil
-		case oid.T_uuid:
-			u, err := tree.ParseDUuidFromBytes(b)
-			if err != nil {
-				return nil, err
-			}
-			return u, nil
 		case oid.T_inet:
 			ipAddr, err := pgBinaryToIPAddr(b)
 			if err != nil {
@@ -559,6 +563,33 @@ func DecodeOidDatum(
 				return nil, err
 			}
 			return tree.ParseDJSON(string(b))
+		case types.T_jsonpath:
+			if len(b) < 1 {
+				return nil, NewProtocolViolationErrorf("no data to decode")
+			}
+			if b[0] != 1 {
+				return nil, pgerror.Newf(pgcode.Syntax, "expected JSONPATH version 1")
+			}
+			// Skip over the version number.
+			b = b[1:]
+			if err := validateStringBytes(b); err != nil {
+				return nil, err
+			}
+			return tree.NewDString(string(b)), nil
+		case oid.T_pg_lsn:
+			if len(b) < 8 {
+				return nil, NewProtocolViolationErrorf("insufficient data: %d", len(b))
+			}
+			return tree.NewDString(types.FormatLSN(binary.BigEndian.Uint64(b))), nil
+		case oid.T_macaddr, types.T_macaddr8:
+			size := types.MACAddrSize
+			if id == types.T_macaddr8 {
+				size = types.MACAddr8Size
+			}
+			if len(b) < size {
+				return nil, NewProtocolViolationErrorf("insufficient data: %d", len(b))
+			}
+			return tree.NewDString(net.HardwareAddr(b[:size]).String()), nil
 		case oid.T_varbit, oid.T_bit:
 			if len(b) < 4 {
 				return nil, NewProtocolViolationErrorf("insufficient data: %d", len(b))
@@ -607,7 +638,7 @@ func DecodeOidDatum(
 
 	// Types with identical text/binary handling.
 	switch id {
-	case oid.T_text, oid.T_varchar, oid.T_bpchar:
+	case oid.T_text, oid.T_varchar, oid.T_bpchar, oid.T_refcursor:
 		if err := validateStringBytes(b); err != nil {
 			return nil, err
 		}
@@ -128,9 +128,6 @@ func (b *writeBuffer) writeTextDatum(
 		b.putInt32(int32(len(result)))
 		b.write([]byte(result))
 
-	case *tree.DUuid:
-		b.writeLengthPrefixedString(v.UUID.String())
-
 	case *tree.DIPAddr:
 		b.writeLengthPrefixedString(v.IPAddr.String())
 
@@ -182,6 +179,11 @@ func (b *writeBuffer) writeTextDatum(
 		b.writeLengthPrefixedDatum(v)
 
 	default:
+		if c, ok := pgwirebase.LookupTypeCodec(v.ResolvedType().Oid()); ok && c.EncodeText != nil {
+			enc, err := c.EncodeText(v, conv)
+			b.writeCodecResult(enc, err)
+			return
+		}
 		b.setError(errors.Errorf("unsupported type %T", d))
 	}
 }
@@ -354,10 +356,6 @@ func (b *writeBuffer) writeBinaryDatum(
 		b.putInt32(int32(len(*v)))
 		b.write([]byte(*v))
 
-	case *tree.DUuid:
-		b.putInt32(16)
-		b.write(v.GetBytes())
-
 	case *tree.DIPAddr:
 		// We calculate the Postgres binary format for an IPAddr. For the spec see,
 		// https://github.com/postgres/postgres/blob/81c5e46c490e2426db243eada186995da5bb0ba7/src/backend/utils/adt/network.c#L144
@@ -394,7 +392,36 @@ func (b *writeBuffer) writeBinaryDatum(
 		}
 
 	case *tree.DString:
-		b.writeLengthPrefixedString(string(*v))
+		switch Oid {
+		case types.T_jsonpath:
+			// Like JSONB, JSONPATH values are prefixed with a version number, and
+			// `1` is the only valid value.
+			b.putInt32(int32(len(*v) + 1))
+			b.writeByte(1)
+			b.writeString(string(*v))
+		case oid.T_pg_lsn:
+			lsn, err := types.ParseLSN(string(*v))
+			if err != nil {
+				b.setError(err)
+				return
+			}
+			b.putInt32(8)
+			b.putInt64(int64(lsn))
+		case oid.T_macaddr, types.T_macaddr8:
+			size := types.MACAddrSize
+			if Oid == types.T_macaddr8 {
+				size = types.MACAddr8Size
+			}
+			addr, err := types.ParseMACAddr(string(*v), size)
+			if err != nil {
+				b.setError(err)
+				return
+			}
+			b.putInt32(int32(len(addr)))
+			b.write(addr)
+		default:
+			b.writeLengthPrefixedString(string(*v))
+		}
 
 	case *tree.DCollatedString:
 		b.writeLengthPrefixedString(v.Contents)
@@ -467,10 +494,26 @@ func (b *writeBuffer) writeBinaryDatum(
 		b.putInt32(4)
 		b.putInt32(int32(v.DInt))
 	default:
+		if c, ok := pgwirebase.LookupTypeCodec(v.ResolvedType().Oid()); ok && c.EncodeBinary != nil {
+			enc, err := c.EncodeBinary(v, Oid, sessionLoc)
+			b.writeCodecResult(enc, err)
+			return
+		}
 		b.setError(errors.AssertionFailedf("unsupported type %T", d))
 	}
 }
 
+// writeCodecResult writes a value encoded by a pgwirebase.TypeCodec, along
+// with its length prefix, or records the encoding error.
+func (b *writeBuffer) writeCodecResult(enc []byte, err error) {
+	if err != nil {
+		b.setError(err)
+		return
+	}
+	b.putInt32(int32(len(enc)))
+	b.write(enc)
+}
+
 const (
 	pgTimeFormat              = "15:04:05.999999"
 	pgDateFormat              = "2006-01-02"
@@ -148,12 +148,12 @@ func (p *planner) ProjectSet(
 			} else {
 				// Prepare the result columns. Use the tuple labels in the SRF's
 				// return type as column labels.
-				for j := range typ.TupleContents() {
+				typ.ForEachTupleField(func(_ int, label string, fieldTyp *types.T) {
 					n.columns = append(n.columns, sqlbase.ResultColumn{
-						Name: typ.TupleLabels()[j],
-						Typ:  &typ.TupleContents()[j],
+						Name: label,
+						Typ:  fieldTyp,
 					})
-				}
+				})
 			}
 		} else {
 			// A simple non-generator expression.
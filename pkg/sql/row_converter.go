@@ -63,6 +63,11 @@ type RowConverter struct {
 	KvBatch  []roachpb.KeyValue
 	BatchCap int
 
+	// CoercionProfile is the profile used to parse the values of the input with
+	// tree.ParseDatumStringAsWithProfile. It is the default profile unless set
+	// by the caller.
+	CoercionProfile *types.CoercionProfile
+
 	tableDesc *sqlbase.ImmutableTableDescriptor
 
 	// The rest of these are derived from tableDesc, just cached here.
@@ -84,9 +89,10 @@ func NewRowConverter(
 ) (*RowConverter, error) {
 	immutDesc := sqlbase.NewImmutableTableDescriptor(*tableDesc)
 	c := &RowConverter{
-		tableDesc: immutDesc,
-		KvCh:      kvCh,
-		EvalCtx:   evalCtx,
+		tableDesc:       immutDesc,
+		KvCh:            kvCh,
+		EvalCtx:         evalCtx,
+		CoercionProfile: &types.DefaultCoercionProfile,
 	}
 
 	ri, err := row.MakeInserter(nil /* txn */, immutDesc, nil, /* fkTables */
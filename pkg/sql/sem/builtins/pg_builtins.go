@@ -57,19 +57,26 @@ func makeNotUsableFalseBuiltin() builtinDefinition {
 // programmatically determine whether or not this underscore is present, hence
 // the existence of this map.
 var typeBuiltinsHaveUnderscore = map[oid.Oid]struct{}{
-	types.Any.Oid():         {},
-	types.AnyArray.Oid():    {},
-	types.Date.Oid():        {},
-	types.Time.Oid():        {},
-	types.Decimal.Oid():     {},
-	types.Interval.Oid():    {},
-	types.Jsonb.Oid():       {},
-	types.Uuid.Oid():        {},
-	types.VarBit.Oid():      {},
-	oid.T_bit:               {},
-	types.Timestamp.Oid():   {},
-	types.TimestampTZ.Oid(): {},
-	types.AnyTuple.Oid():    {},
+	types.Any.Oid():          {},
+	types.AnyArray.Oid():     {},
+	types.Date.Oid():         {},
+	types.Time.Oid():         {},
+	types.Decimal.Oid():      {},
+	types.Interval.Oid():     {},
+	types.Jsonb.Oid():        {},
+	types.Jsonpath.Oid():     {},
+	types.PGLSN.Oid():        {},
+	types.MACAddr.Oid():      {},
+	types.MACAddr8.Oid():     {},
+	types.Uuid.Oid():         {},
+	types.VarBit.Oid():       {},
+	oid.T_bit:                {},
+	types.Timestamp.Oid():    {},
+	types.TimestampTZ.Oid():  {},
+	types.AnyTuple.Oid():     {},
+	types.Void.Oid():         {},
+	types.Trigger.Oid():      {},
+	types.EventTrigger.Oid(): {},
 }
 
 // PGIOBuiltinPrefix returns the string prefix to a type's IO functions. This
@@ -483,7 +483,11 @@ func (expr *StrVal) ResolveAsType(ctx *SemaContext, typ *types.T) (Datum, error)
 			expr.resString = DString(expr.s)
 			return NewDNameFromDString(&expr.resString), nil
 		}
-		expr.resString = DString(expr.s)
+		s, err := typ.CanonicalizeString(expr.s)
+		if err != nil {
+			return nil, err
+		}
+		expr.resString = DString(typ.TrimBlankPadding(s))
 		return &expr.resString, nil
 	case types.BytesFamily:
 		return ParseDByte(expr.s)
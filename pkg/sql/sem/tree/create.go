@@ -27,7 +27,6 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/errors"
-	"golang.org/x/text/language"
 )
 
 // CreateDatabase represents a CREATE DATABASE statement.
@@ -260,9 +259,9 @@ func NewColumnTableDef(
 		switch t := c.Qualification.(type) {
 		case ColumnCollation:
 			locale := string(t)
-			_, err := language.Parse(locale)
+			_, err := types.ParseLocale(locale)
 			if err != nil {
-				return nil, pgerror.Wrapf(err, pgcode.Syntax, "invalid locale %s", locale)
+				return nil, err
 			}
 			d.Type, err = processCollationOnType(name, d.Type, t)
 			if err != nil {
@@ -437,13 +436,7 @@ func (node *ColumnTableDef) Format(ctx *FmtCtx) {
 func (node *ColumnTableDef) columnTypeString() string {
 	if node.IsSerial {
 		// Map INT types to SERIAL keyword.
-		switch node.Type.Width() {
-		case 16:
-			return "SERIAL2"
-		case 32:
-			return "SERIAL4"
-		}
-		return "SERIAL8"
+		return types.SerialTypeName(node.Type.Width())
 	}
 	return node.Type.SQLString()
 }
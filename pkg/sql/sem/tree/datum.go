@@ -1814,6 +1814,20 @@ func MakeDTime(t timeofday.TimeOfDay) *DTime {
 	return &d
 }
 
+// RoundDTime rounds the DTime to the precision of the given TIME type, as when
+// the value is cast to the type or stored in a column of the type. It returns
+// the DTime unchanged if the type has the default microsecond precision.
+func RoundDTime(d *DTime, typ *types.T) *DTime {
+	if !typ.TimePrecisionIsSet() {
+		return d
+	}
+	rounded := timeofday.TimeOfDay(*d).Round(typ.Precision())
+	if rounded == timeofday.TimeOfDay(*d) {
+		return d
+	}
+	return MakeDTime(rounded)
+}
+
 // ParseDTime parses and returns the *DTime Datum value represented by the
 // provided string, or an error if parsing is unsuccessful.
 func ParseDTime(ctx ParseTimeContext, s string) (*DTime, error) {
@@ -2243,6 +2257,71 @@ func ParseDIntervalWithField(s string, field DurationField) (*DInterval, error)
 	return d, nil
 }
 
+// ParseDIntervalWithTypeMetadata is like ParseDIntervalWithField, but it takes
+// the DurationField from the qualifier in the given interval type metadata, and
+// also rounds the fractional seconds to the precision in the metadata. An
+// unqualified interval is parsed using the Second field.
+func ParseDIntervalWithTypeMetadata(
+	s string, itm types.IntervalTypeMetadata,
+) (*DInterval, error) {
+	d, err := ParseDIntervalWithField(s, intervalTypeDurationField(itm))
+	if err != nil {
+		return nil, err
+	}
+	roundDIntervalPrecision(d, itm)
+	return d, nil
+}
+
+// AdjustDInterval truncates the DInterval to the qualifier of the given
+// INTERVAL type, and rounds its fractional seconds to the precision of the
+// type, as when the value is cast to the type or stored in a column of the
+// type. For example, 1 day 02:03:04.5 becomes 1 day 02:00:00 in an INTERVAL
+// HOUR. It returns the DInterval unchanged if it already fits the type.
+func AdjustDInterval(d *DInterval, typ *types.T) (*DInterval, error) {
+	itm, err := typ.IntervalTypeMetadata()
+	if err != nil {
+		return nil, err
+	}
+	res := *d
+	truncateDInterval(&res, intervalTypeDurationField(itm))
+	roundDIntervalPrecision(&res, itm)
+	if res.Duration == d.Duration {
+		return d, nil
+	}
+	return &res, nil
+}
+
+// intervalTypeDurationField returns the DurationField that corresponds to the
+// qualifier in the given interval type metadata.
+func intervalTypeDurationField(itm types.IntervalTypeMetadata) DurationField {
+	switch itm.DurationField.DurationType {
+	case types.IntervalDurationType_YEAR:
+		return Year
+	case types.IntervalDurationType_MONTH:
+		return Month
+	case types.IntervalDurationType_DAY:
+		return Day
+	case types.IntervalDurationType_HOUR:
+		return Hour
+	case types.IntervalDurationType_MINUTE:
+		return Minute
+	}
+	return Second
+}
+
+// roundDIntervalPrecision rounds the fractional seconds of the DInterval to the
+// precision in the given interval type metadata, if it is set.
+func roundDIntervalPrecision(d *DInterval, itm types.IntervalTypeMetadata) {
+	if !itm.PrecisionIsSet || itm.Precision >= types.MaxTimePrecision {
+		return
+	}
+	unit := time.Second
+	for i := int32(0); i < itm.Precision; i++ {
+		unit /= 10
+	}
+	d.Duration.SetNanos(time.Duration(d.Duration.Nanos()).Round(unit).Nanoseconds())
+}
+
 func parseDInterval(s string, field DurationField) (*DInterval, error) {
 	// At this time the only supported interval formats are:
 	// - SQL standard.
@@ -3270,22 +3270,26 @@ func PerformCast(ctx *EvalContext, d Datum, t *types.T) (Datum, error) {
 		}
 		switch t.Family() {
 		case types.StringFamily:
-			if t.Oid() == oid.T_name {
-				return NewDName(s), nil
+			var err error
+			if s, err = t.CanonicalizeString(s); err != nil {
+				return nil, err
 			}
 
 			// If the string type specifies a limit we truncate to that limit:
 			//   'hello'::CHAR(2) -> 'he'
-			// This is true of all the string type variants.
-			if t.Width() > 0 && int(t.Width()) < len(s) {
-				s = s[:t.Width()]
+			// This is true of all the string type variants, including NAME and
+			// "char", which have implicit limits.
+			s = t.TruncateString(s)
+			// The trailing spaces of CHAR values are not significant:
+			//   'a  '::CHAR(3) -> 'a'
+			s = t.TrimBlankPadding(s)
+			if t.Oid() == oid.T_name {
+				return NewDName(s), nil
 			}
 			return NewDString(s), nil
 		case types.CollatedStringFamily:
-			// Ditto truncation like for TString.
-			if t.Width() > 0 && int(t.Width()) < len(s) {
-				s = s[:t.Width()]
-			}
+			// Ditto truncation and trimming like for TString.
+			s = t.TrimBlankPadding(t.TruncateString(s))
 			return NewDCollatedString(s, t.Locale(), &ctx.CollationEnv), nil
 		}
 
@@ -3342,19 +3346,27 @@ func PerformCast(ctx *EvalContext, d Datum, t *types.T) (Datum, error) {
 		}
 
 	case types.TimeFamily:
+		var res *DTime
+		var err error
 		switch d := d.(type) {
 		case *DString:
-			return ParseDTime(ctx, string(*d))
+			res, err = ParseDTime(ctx, string(*d))
 		case *DCollatedString:
-			return ParseDTime(ctx, d.Contents)
+			res, err = ParseDTime(ctx, d.Contents)
 		case *DTime:
-			return d, nil
+			res = d
 		case *DTimestamp:
-			return MakeDTime(timeofday.FromTime(d.Time)), nil
+			res = MakeDTime(timeofday.FromTime(d.Time))
 		case *DTimestampTZ:
-			return MakeDTime(timeofday.FromTime(d.Time)), nil
+			res = MakeDTime(timeofday.FromTime(d.Time))
 		case *DInterval:
-			return MakeDTime(timeofday.Min.Add(d.Duration)), nil
+			res = MakeDTime(timeofday.Min.Add(d.Duration))
+		}
+		if err != nil {
+			return nil, err
+		}
+		if res != nil {
+			return RoundDTime(res, t), nil
 		}
 
 	case types.TimestampFamily:
@@ -3407,17 +3419,22 @@ func PerformCast(ctx *EvalContext, d Datum, t *types.T) (Datum, error) {
 		}
 
 	case types.IntervalFamily:
+		itm, err := t.IntervalTypeMetadata()
+		if err != nil {
+			return nil, err
+		}
+		var res *DInterval
 		switch v := d.(type) {
 		case *DString:
-			return ParseDInterval(string(*v))
+			return ParseDIntervalWithTypeMetadata(string(*v), itm)
 		case *DCollatedString:
-			return ParseDInterval(v.Contents)
+			return ParseDIntervalWithTypeMetadata(v.Contents, itm)
 		case *DInt:
-			return &DInterval{Duration: duration.FromInt64(int64(*v))}, nil
+			res = &DInterval{Duration: duration.FromInt64(int64(*v))}
 		case *DFloat:
-			return &DInterval{Duration: duration.FromFloat64(float64(*v))}, nil
+			res = &DInterval{Duration: duration.FromFloat64(float64(*v))}
 		case *DTime:
-			return &DInterval{Duration: duration.MakeDuration(int64(*v)*1000, 0, 0)}, nil
+			res = &DInterval{Duration: duration.MakeDuration(int64(*v)*1000, 0, 0)}
 		case *DDecimal:
 			d := ctx.getTmpDec()
 			dnanos := v.Decimal
@@ -3435,9 +3452,12 @@ func PerformCast(ctx *EvalContext, d Datum, t *types.T) (Datum, error) {
 			if !ok {
 				return nil, errDecOutOfRange
 			}
-			return &DInterval{Duration: dv}, nil
+			res = &DInterval{Duration: dv}
 		case *DInterval:
-			return d, nil
+			res = v
+		}
+		if res != nil {
+			return AdjustDInterval(res, t)
 		}
 	case types.JsonFamily:
 		switch v := d.(type) {
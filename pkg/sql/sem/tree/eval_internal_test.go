@@ -14,6 +14,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 )
 
@@ -131,3 +132,33 @@ func TestEvalContextCopy(t *testing.T) {
 		t.Fatal("iVarContainerStacks are the same")
 	}
 }
+
+// TestCastCounterName checks that the telemetry counters of casts keep the
+// names of the types that labeled them before the cast matrix was introduced.
+func TestCastCounterName(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	testCases := []struct {
+		family   types.Family
+		target   bool
+		expected string
+	}{
+		{types.IntFamily, false, "int"},
+		{types.JsonFamily, false, "jsonb"},
+		{types.BitFamily, false, "varbit"},
+		{types.CollatedStringFamily, false, "collatedstring{*}"},
+		{types.TimestampTZFamily, false, "timestamptz"},
+		{types.TimestampTZFamily, true, "timestamp"},
+		{types.CollatedStringFamily, true, "string"},
+		{types.ArrayFamily, true, types.AnyArray.String()},
+		{types.VoidFamily, true, "void"},
+	}
+	for _, tc := range testCases {
+		counterTypes := castSourceCounterTypes
+		if tc.target {
+			counterTypes = castTargetCounterTypes
+		}
+		if actual := castCounterName(tc.family, counterTypes); actual != tc.expected {
+			t.Errorf("%s (target %t): expected %q, got %q", tc.family, tc.target, tc.expected, actual)
+		}
+	}
+}
@@ -15,7 +15,6 @@ import (
 	"fmt"
 	"strconv"
 
-	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/lex"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
@@ -1499,79 +1498,6 @@ func NewTypedCastExpr(expr TypedExpr, typ *types.T) (*CastExpr, error) {
 	return node, nil
 }
 
-type castInfo struct {
-	fromT   *types.T
-	counter telemetry.Counter
-}
-
-var (
-	bitArrayCastTypes = annotateCast(types.VarBit, []*types.T{types.Unknown, types.VarBit, types.Int, types.String, types.AnyCollatedString})
-	boolCastTypes     = annotateCast(types.Bool, []*types.T{types.Unknown, types.Bool, types.Int, types.Float, types.Decimal, types.String, types.AnyCollatedString})
-	intCastTypes      = annotateCast(types.Int, []*types.T{types.Unknown, types.Bool, types.Int, types.Float, types.Decimal, types.String, types.AnyCollatedString,
-		types.Timestamp, types.TimestampTZ, types.Date, types.Interval, types.Oid, types.VarBit})
-	floatCastTypes = annotateCast(types.Float, []*types.T{types.Unknown, types.Bool, types.Int, types.Float, types.Decimal, types.String, types.AnyCollatedString,
-		types.Timestamp, types.TimestampTZ, types.Date, types.Interval})
-	decimalCastTypes = annotateCast(types.Decimal, []*types.T{types.Unknown, types.Bool, types.Int, types.Float, types.Decimal, types.String, types.AnyCollatedString,
-		types.Timestamp, types.TimestampTZ, types.Date, types.Interval})
-	stringCastTypes = annotateCast(types.String, []*types.T{types.Unknown, types.Bool, types.Int, types.Float, types.Decimal, types.String, types.AnyCollatedString,
-		types.VarBit,
-		types.AnyArray, types.AnyTuple,
-		types.Bytes, types.Timestamp, types.TimestampTZ, types.Interval, types.Uuid, types.Date, types.Time, types.Oid, types.INet, types.Jsonb})
-	bytesCastTypes = annotateCast(types.Bytes, []*types.T{types.Unknown, types.String, types.AnyCollatedString, types.Bytes, types.Uuid})
-	dateCastTypes  = annotateCast(types.Date, []*types.T{types.Unknown, types.String, types.AnyCollatedString, types.Date, types.Timestamp, types.TimestampTZ, types.Int})
-	timeCastTypes  = annotateCast(types.Time, []*types.T{types.Unknown, types.String, types.AnyCollatedString, types.Time,
-		types.Timestamp, types.TimestampTZ, types.Interval})
-	timestampCastTypes = annotateCast(types.Timestamp, []*types.T{types.Unknown, types.String, types.AnyCollatedString, types.Date, types.Timestamp, types.TimestampTZ, types.Int})
-	intervalCastTypes  = annotateCast(types.Interval, []*types.T{types.Unknown, types.String, types.AnyCollatedString, types.Int, types.Time, types.Interval, types.Float, types.Decimal})
-	oidCastTypes       = annotateCast(types.Oid, []*types.T{types.Unknown, types.String, types.AnyCollatedString, types.Int, types.Oid})
-	uuidCastTypes      = annotateCast(types.Uuid, []*types.T{types.Unknown, types.String, types.AnyCollatedString, types.Bytes, types.Uuid})
-	inetCastTypes      = annotateCast(types.INet, []*types.T{types.Unknown, types.String, types.AnyCollatedString, types.INet})
-	arrayCastTypes     = annotateCast(types.AnyArray, []*types.T{types.Unknown, types.String})
-	jsonCastTypes      = annotateCast(types.Jsonb, []*types.T{types.Unknown, types.String, types.Jsonb})
-)
-
-// validCastTypes returns a set of types that can be cast into the provided type.
-func validCastTypes(t *types.T) []castInfo {
-	switch t.Family() {
-	case types.BitFamily:
-		return bitArrayCastTypes
-	case types.BoolFamily:
-		return boolCastTypes
-	case types.IntFamily:
-		return intCastTypes
-	case types.FloatFamily:
-		return floatCastTypes
-	case types.DecimalFamily:
-		return decimalCastTypes
-	case types.StringFamily, types.CollatedStringFamily:
-		return stringCastTypes
-	case types.BytesFamily:
-		return bytesCastTypes
-	case types.DateFamily:
-		return dateCastTypes
-	case types.TimeFamily:
-		return timeCastTypes
-	case types.TimestampFamily, types.TimestampTZFamily:
-		return timestampCastTypes
-	case types.IntervalFamily:
-		return intervalCastTypes
-	case types.JsonFamily:
-		return jsonCastTypes
-	case types.UuidFamily:
-		return uuidCastTypes
-	case types.INetFamily:
-		return inetCastTypes
-	case types.OidFamily:
-		return oidCastTypes
-	case types.ArrayFamily:
-		ret := make([]castInfo, len(arrayCastTypes))
-		copy(ret, arrayCastTypes)
-		return ret
-	default:
-		return nil
-	}
-}
-
 // ArraySubscripts represents a sequence of one or more array subscripts.
 type ArraySubscripts []*ArraySubscript
 
@@ -12,7 +12,9 @@ package tree
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 )
@@ -89,20 +91,80 @@ func init() {
 			o.counter = sqltelemetry.BinOpCounter(opName, lname, rname)
 		}
 	}
+
+	// Label the casts.
+	types.ForEachCast(func(from, to types.Family, _ types.CastContextKind) {
+		castCounters[castFamilies{from: from, to: to}] = sqltelemetry.CastOpCounter(
+			castCounterName(from, castSourceCounterTypes), castCounterName(to, castTargetCounterTypes))
+	})
+}
+
+// castCounters holds the telemetry counters for every cast in the cast matrix
+// defined by the types package, keyed by the source and target families. It is
+// populated in init.
+var castCounters = map[castFamilies]telemetry.Counter{}
+
+type castFamilies struct {
+	from, to types.Family
 }
 
-// annotateCast produces an array of cast types decorated with cast
-// type telemetry counters.
-func annotateCast(toType *types.T, fromTypes []*types.T) []castInfo {
-	ci := make([]castInfo, len(fromTypes))
-	for i, fromType := range fromTypes {
-		ci[i].fromT = fromType
+// castSourceCounterTypes and castTargetCounterTypes are the types whose names
+// label the source and target families of casts in the telemetry counters.
+// They predate the cast matrix, and must not change so that the telemetry
+// series of the casts are preserved. The targets differ from the sources in
+// that the casts to TIMESTAMPTZ are counted as casts to TIMESTAMP, and the
+// casts to collated strings as casts to STRING.
+var (
+	castSourceCounterTypes = map[types.Family]*types.T{
+		types.UnknownFamily:        types.Unknown,
+		types.BitFamily:            types.VarBit,
+		types.BoolFamily:           types.Bool,
+		types.IntFamily:            types.Int,
+		types.FloatFamily:          types.Float,
+		types.DecimalFamily:        types.Decimal,
+		types.StringFamily:         types.String,
+		types.CollatedStringFamily: types.AnyCollatedString,
+		types.ArrayFamily:          types.AnyArray,
+		types.TupleFamily:          types.AnyTuple,
+		types.BytesFamily:          types.Bytes,
+		types.TimestampFamily:      types.Timestamp,
+		types.TimestampTZFamily:    types.TimestampTZ,
+		types.IntervalFamily:       types.Interval,
+		types.UuidFamily:           types.Uuid,
+		types.DateFamily:           types.Date,
+		types.TimeFamily:           types.Time,
+		types.OidFamily:            types.Oid,
+		types.INetFamily:           types.INet,
+		types.JsonFamily:           types.Jsonb,
+	}
+	castTargetCounterTypes = map[types.Family]*types.T{
+		types.BitFamily:            types.VarBit,
+		types.BoolFamily:           types.Bool,
+		types.IntFamily:            types.Int,
+		types.FloatFamily:          types.Float,
+		types.DecimalFamily:        types.Decimal,
+		types.StringFamily:         types.String,
+		types.CollatedStringFamily: types.String,
+		types.BytesFamily:          types.Bytes,
+		types.DateFamily:           types.Date,
+		types.TimeFamily:           types.Time,
+		types.TimestampFamily:      types.Timestamp,
+		types.TimestampTZFamily:    types.Timestamp,
+		types.IntervalFamily:       types.Interval,
+		types.OidFamily:            types.Oid,
+		types.UuidFamily:           types.Uuid,
+		types.INetFamily:           types.INet,
+		types.ArrayFamily:          types.AnyArray,
+		types.JsonFamily:           types.Jsonb,
 	}
-	rname := toType.String()
+)
 
-	for i, fromType := range fromTypes {
-		lname := fromType.String()
-		ci[i].counter = sqltelemetry.CastOpCounter(lname, rname)
+// castCounterName returns the name used for the given type family in cast
+// telemetry counters: the name of its type in the given map, e.g. "varbit" for
+// BitFamily, or else the name of the family, e.g. "void" for VoidFamily.
+func castCounterName(f types.Family, counterTypes map[types.Family]*types.T) string {
+	if typ, ok := counterTypes[f]; ok {
+		return typ.String()
 	}
-	return ci
+	return strings.ToLower(strings.TrimSuffix(f.String(), "Family"))
 }
@@ -165,7 +165,7 @@ func (a ArgTypes) MatchAt(typ *types.T, i int) bool {
 	if typ.Family() == types.TupleFamily {
 		typ = types.AnyTuple
 	}
-	return i < len(a) && (typ.Family() == types.UnknownFamily || a[i].Typ.Equivalent(typ))
+	return i < len(a) && a[i].Typ.Matches(typ)
 }
 
 // MatchLen is part of the TypeList interface.
@@ -266,9 +266,9 @@ func (v VariadicType) Match(types []*types.T) bool {
 // MatchAt is part of the TypeList interface.
 func (v VariadicType) MatchAt(typ *types.T, i int) bool {
 	if i < len(v.FixedTypes) {
-		return typ.Family() == types.UnknownFamily || v.FixedTypes[i].Equivalent(typ)
+		return v.FixedTypes[i].Matches(typ)
 	}
-	return typ.Family() == types.UnknownFamily || v.VarType.Equivalent(typ)
+	return v.VarType.Matches(typ)
 }
 
 // MatchLen is part of the TypeList interface.
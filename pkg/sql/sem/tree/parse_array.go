@@ -31,12 +31,16 @@ var isQuoteChar = func(ch byte) bool {
 	return ch == '"'
 }
 
-var isControlChar = func(ch byte) bool {
-	return ch == '{' || ch == '}' || ch == ',' || ch == '"'
+// isControlChar returns true if the given character terminates an unquoted
+// array element.
+func (p *parseState) isControlChar(ch byte) bool {
+	return ch == '{' || ch == '}' || ch == p.delim || ch == '"'
 }
 
-var isElementChar = func(r rune) bool {
-	return r != '{' && r != '}' && r != ','
+// isElementChar returns true if the given character can start an unquoted
+// array element.
+func (p *parseState) isElementChar(r rune) bool {
+	return r != '{' && r != '}' && r != rune(p.delim)
 }
 
 // gobbleString advances the parser for the remainder of the current string
@@ -75,6 +79,8 @@ type parseState struct {
 	evalCtx *EvalContext
 	result  *DArray
 	t       *types.T
+	// delim is the character that separates the array elements.
+	delim byte
 }
 
 func (p *parseState) advance() {
@@ -102,7 +108,7 @@ func (p *parseState) parseQuotedString() (string, error) {
 }
 
 func (p *parseState) parseUnquotedString() (string, error) {
-	out, err := p.gobbleString(isControlChar)
+	out, err := p.gobbleString(p.isControlChar)
 	if err != nil {
 		return "", err
 	}
@@ -124,7 +130,7 @@ func (p *parseState) parseElement() error {
 		}
 		p.advance()
 	default:
-		if !isElementChar(r) {
+		if !p.isElementChar(r) {
 			return malformedError
 		}
 		next, err = p.parseUnquotedString()
@@ -151,6 +157,7 @@ func ParseDArrayFromString(evalCtx *EvalContext, s string, t *types.T) (*DArray,
 		evalCtx: evalCtx,
 		result:  NewDArray(t),
 		t:       t,
+		delim:   t.ArrayDelimiter(),
 	}
 
 	parser.eatWhitespace()
@@ -164,7 +171,7 @@ func ParseDArrayFromString(evalCtx *EvalContext, s string, t *types.T) (*DArray,
 			return nil, err
 		}
 		parser.eatWhitespace()
-		for parser.peek() == ',' {
+		for parser.peek() == rune(parser.delim) {
 			parser.advance()
 			parser.eatWhitespace()
 			if err := parser.parseElement(); err != nil {
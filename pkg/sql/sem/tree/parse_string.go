@@ -52,6 +52,73 @@ func ParseDatumStringAs(t *types.T, s string, evalCtx *EvalContext) (Datum, erro
 	}
 }
 
+// ParseStringAsWithProfile reads s as type t like ParseStringAs, except that
+// the values of the types covered by the given coercion profile are parsed
+// according to the profile first.
+func ParseStringAsWithProfile(
+	t *types.T, s string, evalCtx *EvalContext, p *types.CoercionProfile,
+) (Datum, error) {
+	if d, err := parseStringWithProfile(t, s, evalCtx, p); d != nil || err != nil {
+		return d, err
+	}
+	return ParseStringAs(t, s, evalCtx)
+}
+
+// ParseDatumStringAsWithProfile parses s as type t like ParseDatumStringAs,
+// except that the values of the types covered by the given coercion profile
+// are parsed according to the profile first.
+func ParseDatumStringAsWithProfile(
+	t *types.T, s string, evalCtx *EvalContext, p *types.CoercionProfile,
+) (Datum, error) {
+	if d, err := parseStringWithProfile(t, s, evalCtx, p); d != nil || err != nil {
+		return d, err
+	}
+	return ParseDatumStringAs(t, s, evalCtx)
+}
+
+// parseStringWithProfile parses s as type t according to the given coercion
+// profile. nil, nil is returned if the value is to be parsed with the input
+// syntax of SQL instead.
+func parseStringWithProfile(
+	t *types.T, s string, evalCtx *EvalContext, p *types.CoercionProfile,
+) (Datum, error) {
+	switch t.Family() {
+	case types.BoolFamily:
+		if !p.HasBoolSpellings() {
+			return nil, nil
+		}
+		b, ok := p.ParseBool(s)
+		if !ok {
+			return nil, makeParseError(s, t, nil)
+		}
+		return MakeDBool(DBool(b)), nil
+	case types.DecimalFamily:
+		return nil, p.CheckDecimal(s)
+	case types.DateFamily:
+		if tm, ok := p.ParseTime(t, s, time.UTC); ok {
+			return NewDDateFromTime(tm)
+		}
+	case types.TimestampFamily:
+		if tm, ok := p.ParseTime(t, s, time.UTC); ok {
+			return MakeDTimestamp(tm, timestampPrecision(t)), nil
+		}
+	case types.TimestampTZFamily:
+		if tm, ok := p.ParseTime(t, s, evalCtx.GetLocation()); ok {
+			return MakeDTimestampTZ(tm, timestampPrecision(t)), nil
+		}
+	}
+	return nil, nil
+}
+
+// timestampPrecision returns the precision to which the values of the given
+// TIMESTAMP or TIMESTAMPTZ type are rounded.
+func timestampPrecision(t *types.T) time.Duration {
+	if t.Precision() == 0 {
+		return time.Second
+	}
+	return time.Microsecond
+}
+
 // parseStringAs parses s as type t for simple types. Bytes, arrays, collated
 // strings are not handled. nil, nil is returned if t is not a supported type.
 func parseStringAs(t *types.T, s string, ctx ParseTimeContext) (Datum, error) {
@@ -71,23 +138,29 @@ func parseStringAs(t *types.T, s string, ctx ParseTimeContext) (Datum, error) {
 	case types.IntFamily:
 		return ParseDInt(s)
 	case types.IntervalFamily:
-		return ParseDInterval(s)
+		itm, err := t.IntervalTypeMetadata()
+		if err != nil {
+			return nil, err
+		}
+		return ParseDIntervalWithTypeMetadata(s, itm)
 	case types.JsonFamily:
 		return ParseDJSON(s)
 	case types.StringFamily:
-		return NewDString(s), nil
+		canonical, err := t.CanonicalizeString(s)
+		if err != nil {
+			return nil, err
+		}
+		return NewDString(t.TrimBlankPadding(canonical)), nil
 	case types.TimeFamily:
-		return ParseDTime(ctx, s)
-	case types.TimestampFamily:
-		if t.Precision() == 0 {
-			return ParseDTimestamp(ctx, s, time.Second)
+		d, err := ParseDTime(ctx, s)
+		if err != nil {
+			return nil, err
 		}
-		return ParseDTimestamp(ctx, s, time.Microsecond)
+		return RoundDTime(d, t), nil
+	case types.TimestampFamily:
+		return ParseDTimestamp(ctx, s, timestampPrecision(t))
 	case types.TimestampTZFamily:
-		if t.Precision() == 0 {
-			return ParseDTimestampTZ(ctx, s, time.Second)
-		}
-		return ParseDTimestampTZ(ctx, s, time.Microsecond)
+		return ParseDTimestampTZ(ctx, s, timestampPrecision(t))
 	case types.UuidFamily:
 		return ParseDUuidFromString(s)
 	default:
@@ -12,6 +12,7 @@ package tree
 
 import (
 	"bytes"
+	"strings"
 	"unicode/utf8"
 
 	"github.com/lib/pq/oid"
@@ -102,26 +103,27 @@ func (d *DArray) pgwireFormat(ctx *FmtCtx) {
 		return
 	}
 
+	delim := d.ParamTyp.ArrayDelimiter()
 	ctx.WriteByte('{')
-	comma := ""
-	for _, v := range d.Array {
-		ctx.WriteString(comma)
+	for i, v := range d.Array {
+		if i > 0 {
+			ctx.WriteByte(delim)
+		}
 		switch dv := UnwrapDatum(nil, v).(type) {
 		case dNull:
 			ctx.WriteString("NULL")
 		case *DString:
-			pgwireFormatStringInArray(&ctx.Buffer, string(*dv))
+			pgwireFormatStringInArray(&ctx.Buffer, string(*dv), delim)
 		case *DCollatedString:
-			pgwireFormatStringInArray(&ctx.Buffer, dv.Contents)
+			pgwireFormatStringInArray(&ctx.Buffer, dv.Contents, delim)
 			// Bytes cannot use the default case because they will be incorrectly
 			// double escaped.
 		case *DBytes:
 			ctx.FormatNode(dv)
 		default:
 			s := AsStringWithFlags(v, ctx.flags)
-			pgwireFormatStringInArray(&ctx.Buffer, s)
+			pgwireFormatStringInArray(&ctx.Buffer, s, delim)
 		}
-		comma = ","
 	}
 	ctx.WriteByte('}')
 }
@@ -144,8 +146,8 @@ func pgwireQuoteStringInTuple(in string) bool {
 	return in == "" || tupleQuoteSet.in(in)
 }
 
-func pgwireQuoteStringInArray(in string) bool {
-	if in == "" || arrayQuoteSet.in(in) {
+func pgwireQuoteStringInArray(in string, delim byte) bool {
+	if in == "" || arrayQuoteSet.in(in) || strings.IndexByte(in, delim) >= 0 {
 		return true
 	}
 	if len(in) == 4 &&
@@ -158,8 +160,8 @@ func pgwireQuoteStringInArray(in string) bool {
 	return false
 }
 
-func pgwireFormatStringInArray(buf *bytes.Buffer, in string) {
-	quote := pgwireQuoteStringInArray(in)
+func pgwireFormatStringInArray(buf *bytes.Buffer, in string, delim byte) {
+	quote := pgwireQuoteStringInArray(in, delim)
 	if quote {
 		buf.WriteByte('"')
 	}
@@ -129,15 +129,14 @@ func (p *PlaceholderTypesInfo) ValueType(idx PlaceholderIdx) (_ *types.T, ok boo
 // SetType assigns a known type to a placeholder.
 // Reports an error if another type was previously assigned.
 func (p *PlaceholderTypesInfo) SetType(idx PlaceholderIdx, typ *types.T) error {
-	if t := p.Types[idx]; t != nil {
-		if !typ.Equivalent(t) {
-			return pgerror.Newf(
-				pgcode.DatatypeMismatch,
-				"placeholder %s already has type %s, cannot assign %s", idx, t, typ)
-		}
-		return nil
+	t := p.Types[idx]
+	constrained, err := types.Constrain(t, typ)
+	if err != nil {
+		return pgerror.Newf(
+			pgcode.DatatypeMismatch,
+			"placeholder %s already has type %s, cannot assign %s", idx, t, typ)
 	}
-	p.Types[idx] = typ
+	p.Types[idx] = constrained
 	return nil
 }
 
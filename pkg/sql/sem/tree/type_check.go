@@ -27,7 +27,6 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
-	"golang.org/x/text/language"
 )
 
 // SemaContext defines the context in which to perform semantic analysis on an
@@ -292,6 +291,22 @@ func TypeCheckAndRequire(
 	return typedExpr, nil
 }
 
+// TypeCheckAndRequireFamily performs type checking on the provided expression
+// tree in the same way as TypeCheck, and ensures that the type of the resulting
+// TypedExpr is accepted by the constraint of its position.
+func TypeCheckAndRequireFamily(
+	expr Expr, ctx *SemaContext, constraint types.FamilyConstraint,
+) (TypedExpr, error) {
+	typedExpr, err := TypeCheck(expr, ctx, types.Any)
+	if err != nil {
+		return nil, err
+	}
+	if err := constraint.Check(typedExpr.ResolvedType()); err != nil {
+		return typedExpr, err
+	}
+	return typedExpr, nil
+}
+
 // TypeCheck implements the Expr interface.
 func (expr *AndExpr) TypeCheck(ctx *SemaContext, desired *types.T) (TypedExpr, error) {
 	leftTyped, err := typeCheckAndRequireBoolean(ctx, expr.Left, "AND argument")
@@ -420,6 +435,8 @@ func (expr *CaseExpr) TypeCheck(ctx *SemaContext, desired *types.T) (TypedExpr,
 	return expr, nil
 }
 
+// isCastDeepValid returns true if a value of type castFrom can be explicitly
+// cast to castTo, along with the telemetry counter for the cast.
 func isCastDeepValid(castFrom, castTo *types.T) (bool, telemetry.Counter) {
 	if castTo.Family() == types.ArrayFamily && castFrom.Family() == types.ArrayFamily {
 		ok, c := isCastDeepValid(castFrom.ArrayContents(), castTo.ArrayContents())
@@ -428,12 +445,10 @@ func isCastDeepValid(castFrom, castTo *types.T) (bool, telemetry.Counter) {
 		}
 		return ok, c
 	}
-	for _, t := range validCastTypes(castTo) {
-		if castFrom.Family() == t.fromT.Family() {
-			return true, t.counter
-		}
+	if !types.CanCastExplicit(castFrom, castTo) {
+		return false, nil
 	}
-	return false, nil
+	return true, castCounters[castFamilies{from: castFrom.Family(), to: castTo.Family()}]
 }
 
 func isEmptyArray(expr Expr) bool {
@@ -540,10 +555,8 @@ func (expr *AnnotateTypeExpr) TypeCheck(ctx *SemaContext, desired *types.T) (Typ
 
 // TypeCheck implements the Expr interface.
 func (expr *CollateExpr) TypeCheck(ctx *SemaContext, desired *types.T) (TypedExpr, error) {
-	_, err := language.Parse(expr.Locale)
-	if err != nil {
-		return nil, pgerror.Wrapf(err, pgcode.InvalidParameterValue,
-			"invalid locale %s", expr.Locale)
+	if _, err := types.ParseLocale(expr.Locale); err != nil {
+		return nil, err
 	}
 	subExpr, err := expr.Expr.TypeCheck(ctx, types.String)
 	if err != nil {
@@ -610,13 +623,7 @@ func (expr *ColumnAccessExpr) TypeCheck(ctx *SemaContext, desired *types.T) (Typ
 	}
 
 	// Go through all of the labels to find a match.
-	expr.ColIndex = -1
-	for i, label := range resolvedType.TupleLabels() {
-		if label == expr.ColName {
-			expr.ColIndex = i
-			break
-		}
-	}
+	expr.ColIndex = resolvedType.TupleLabelIndex(expr.ColName)
 	if expr.ColIndex < 0 {
 		return nil, pgerror.Newf(pgcode.DatatypeMismatch,
 			"could not identify column %q in %s",
@@ -1190,15 +1197,19 @@ func (expr *StrVal) TypeCheck(ctx *SemaContext, desired *types.T) (TypedExpr, er
 
 // TypeCheck implements the Expr interface.
 func (expr *Tuple) TypeCheck(ctx *SemaContext, desired *types.T) (TypedExpr, error) {
-	// Ensure the number of labels matches the number of expressions.
-	if len(expr.Labels) > 0 && len(expr.Labels) != len(expr.Exprs) {
-		return nil, pgerror.Newf(pgcode.Syntax,
-			"mismatch in tuple definition: %d expressions, %d labels",
-			len(expr.Exprs), len(expr.Labels),
-		)
+	// Copy the labels if there are any, and ensure that the number of labels
+	// matches the number of expressions and that there are no repeat labels.
+	var labels []string
+	if len(expr.Labels) > 0 {
+		labels = make([]string, len(expr.Labels))
+		for i := range expr.Labels {
+			labels[i] = lex.NormalizeName(expr.Labels[i])
+		}
+		if err := types.ValidateTupleLabels(len(expr.Exprs), labels); err != nil {
+			return nil, err
+		}
 	}
 
-	var labels []string
 	contents := make([]types.T, len(expr.Exprs))
 	for i, subExpr := range expr.Exprs {
 		desiredElem := types.Any
@@ -1212,24 +1223,6 @@ func (expr *Tuple) TypeCheck(ctx *SemaContext, desired *types.T) (TypedExpr, err
 		expr.Exprs[i] = typedExpr
 		contents[i] = *typedExpr.ResolvedType()
 	}
-	// Copy the labels if there are any.
-	if len(expr.Labels) > 0 {
-		// Ensure that there are no repeat labels.
-		for i := range expr.Labels {
-			for j := 0; j < i; j++ {
-				if expr.Labels[i] == expr.Labels[j] {
-					return nil, pgerror.Newf(pgcode.Syntax,
-						"found duplicate tuple label: %q", ErrNameStringP(&expr.Labels[i]),
-					)
-				}
-			}
-		}
-
-		labels = make([]string, len(expr.Labels))
-		for i := range expr.Labels {
-			labels[i] = lex.NormalizeName(expr.Labels[i])
-		}
-	}
 	expr.typ = types.MakeLabeledTuple(contents, labels)
 	return expr, nil
 }
@@ -2146,7 +2139,7 @@ func (v *placeholderAnnotationVisitor) VisitPre(expr Expr) (recurse bool, newExp
 
 			case typeFromAnnotation:
 				// Verify that the annotations are consistent.
-				if !t.Type.Equivalent(v.types[arg.Idx]) {
+				if _, err := types.Constrain(v.types[arg.Idx], t.Type); err != nil {
 					v.setErr(arg.Idx, pgerror.Newf(
 						pgcode.DatatypeMismatch,
 						"multiple conflicting type annotations around %s",
@@ -2156,7 +2149,7 @@ func (v *placeholderAnnotationVisitor) VisitPre(expr Expr) (recurse bool, newExp
 
 			case typeFromHint:
 				// Verify that the annotation is consistent with the type hint.
-				if prevType := v.types[arg.Idx]; !t.Type.Equivalent(prevType) {
+				if _, err := types.Constrain(v.types[arg.Idx], t.Type); err != nil {
 					v.setErr(arg.Idx, pgerror.Newf(
 						pgcode.DatatypeMismatch,
 						"type annotation around %s conflicts with specified type %s",
@@ -2179,7 +2172,7 @@ func (v *placeholderAnnotationVisitor) VisitPre(expr Expr) (recurse bool, newExp
 
 			case typeFromCast:
 				// Verify that the casts are consistent.
-				if !t.Type.Equivalent(v.types[arg.Idx]) {
+				if _, err := types.Constrain(v.types[arg.Idx], t.Type); err != nil {
 					v.state[arg.Idx] = conflictingCasts
 					v.types[arg.Idx] = nil
 				}
@@ -17,9 +17,11 @@ import (
 	"testing"
 
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/leanovate/gopter"
 	"github.com/leanovate/gopter/prop"
 )
@@ -211,3 +213,54 @@ func TestMarshalColumnValueRoundtrip(t *testing.T) {
 	)
 	properties.TestingRun(t)
 }
+
+// TestTypeRoundtrip verifies that the SQL string of every column type is a
+// fixed point of parsing and formatting, and that the protobuf encoding of
+// every column type is stable across an unmarshal/marshal cycle.
+func TestTypeRoundtrip(t *testing.T) {
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 10000
+	properties := gopter.NewProperties(parameters)
+
+	properties.Property("sql-string-fixed-point",
+		prop.ForAll(
+			func(typ *types.T) string {
+				sql := typ.SQLString()
+				parsed, err := parser.ParseType(sql)
+				if err != nil {
+					return fmt.Sprintf("error parsing %s: %v", sql, err)
+				}
+				if reparsed := parsed.SQLString(); reparsed != sql {
+					return fmt.Sprintf("%s was parsed as %s", sql, reparsed)
+				}
+				return ""
+			},
+			genColumnType(),
+		),
+	)
+	properties.Property("marshal-stable",
+		prop.ForAll(
+			func(typ *types.T) string {
+				data, err := protoutil.Marshal(typ)
+				if err != nil {
+					return "error marshaling: " + err.Error()
+				}
+				var decoded types.T
+				if err := protoutil.Unmarshal(data, &decoded); err != nil {
+					return "error unmarshaling: " + err.Error()
+				}
+				redata, err := protoutil.Marshal(&decoded)
+				if err != nil {
+					return "error marshaling: " + err.Error()
+				}
+				if !bytes.Equal(data, redata) {
+					return fmt.Sprintf("encoding of %s changed after roundtrip.\nbefore: %x\nafter:  %x",
+						typ.DebugString(), data, redata)
+				}
+				return ""
+			},
+			genColumnType(),
+		),
+	)
+	properties.TestingRun(t)
+}
@@ -11,8 +11,6 @@
 package sqlbase
 
 import (
-	"unicode/utf8"
-
 	"github.com/cockroachdb/apd"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
@@ -24,11 +22,14 @@ import (
 
 // LimitValueWidth checks that the width (for strings, byte arrays, and bit
 // strings) and scale (for decimals) of the value fits the specified column
-// type. In case of decimals, it can truncate fractional digits in the input
-// value in order to fit the target column. If the input value fits the target
-// column, it is returned unchanged. If the input value can be truncated to fit,
-// then a truncated copy is returned. Otherwise, an error is returned. This
-// method is used by INSERT and UPDATE.
+// type. In case of decimals, times and intervals, it can round fractional
+// digits in the input value in order to fit the target column, and intervals
+// are also truncated to the qualifier of the column type. The values of string
+// types that have a fixed format, such as PG_LSN, are validated and
+// canonicalized. If the input value fits the target column, it is returned
+// unchanged. If the input value can be truncated to fit, then a truncated copy
+// is returned. Otherwise, an error is returned. This method is used by INSERT
+// and UPDATE.
 func LimitValueWidth(typ *types.T, inVal tree.Datum, name *string) (outVal tree.Datum, err error) {
 	switch typ.Family() {
 	case types.StringFamily, types.CollatedStringFamily:
@@ -37,26 +38,43 @@ func LimitValueWidth(typ *types.T, inVal tree.Datum, name *string) (outVal tree.
 			sv = string(v)
 		} else if v, ok := inVal.(*tree.DCollatedString); ok {
 			sv = v.Contents
+		} else {
+			break
 		}
 
-		if typ.Width() > 0 && utf8.RuneCountInString(sv) > int(typ.Width()) {
+		// The values of the string types that have a fixed format (such as
+		// PG_LSN) are stored in their canonical form, which may not be the
+		// case of the values of other string types that are assigned to them.
+		canonical, err := typ.CanonicalizeString(sv)
+		if err != nil {
+			return nil, errors.Wrapf(err, "type %s (column %q)",
+				typ.ErrorFormat(), tree.ErrNameStringP(name))
+		}
+		// The trailing spaces of values of CHAR columns are not stored, as they
+		// are not significant.
+		trimmed := typ.TrimBlankPadding(canonical)
+		if !typ.StringFitsWidth(trimmed) {
 			return nil, pgerror.Newf(pgcode.StringDataRightTruncation,
 				"value too long for type %s (column %q)",
-				typ.SQLString(), tree.ErrNameStringP(name))
+				typ.ErrorFormat(), tree.ErrNameStringP(name))
+		}
+		if trimmed != sv {
+			if v, ok := inVal.(*tree.DCollatedString); ok {
+				return tree.NewDCollatedString(trimmed, v.Locale, &tree.CollationEnvironment{}), nil
+			}
+			return tree.NewDString(trimmed), nil
+		}
+	case types.BytesFamily:
+		if v, ok := tree.AsDBytes(inVal); ok && typ.Width() > 0 && len(v) > int(typ.Width()) {
+			return nil, pgerror.Newf(pgcode.StringDataRightTruncation,
+				"value too long for type %s (column %q)",
+				typ.ErrorFormat(), tree.ErrNameStringP(name))
 		}
 	case types.IntFamily:
 		if v, ok := tree.AsDInt(inVal); ok {
-			if typ.Width() == 32 || typ.Width() == 64 || typ.Width() == 16 {
-				// Width is defined in bits.
-				width := uint(typ.Width() - 1)
-
-				// We're performing bounds checks inline with Go's implementation of min and max ints in Math.go.
-				shifted := v >> width
-				if (v >= 0 && shifted > 0) || (v < 0 && shifted < -1) {
-					return nil, pgerror.Newf(pgcode.NumericValueOutOfRange,
-						"integer out of range for type %s (column %q)",
-						typ.Name(), tree.ErrNameStringP(name))
-				}
+			if err := typ.CheckBounds(int64(v)); err != nil {
+				return nil, pgerror.Newf(pgcode.NumericValueOutOfRange,
+					"%v (column %q)", err, tree.ErrNameStringP(name))
 			}
 		}
 	case types.BitFamily:
@@ -67,12 +85,12 @@ func LimitValueWidth(typ *types.T, inVal tree.Datum, name *string) (outVal tree.
 				case oid.T_varbit:
 					if bitLen > uint(typ.Width()) {
 						return nil, pgerror.Newf(pgcode.StringDataRightTruncation,
-							"bit string length %d too large for type %s", bitLen, typ.SQLString())
+							"bit string length %d too large for type %s", bitLen, typ.ErrorFormat())
 					}
 				default:
 					if bitLen != uint(typ.Width()) {
 						return nil, pgerror.Newf(pgcode.StringDataLengthMismatch,
-							"bit string length %d does not match type %s", bitLen, typ.SQLString())
+							"bit string length %d does not match type %s", bitLen, typ.ErrorFormat())
 					}
 				}
 			}
@@ -93,10 +111,18 @@ func LimitValueWidth(typ *types.T, inVal tree.Datum, name *string) (outVal tree.
 			err := tree.LimitDecimalWidth(&outDec.Decimal, int(typ.Precision()), int(typ.Scale()))
 			if err != nil {
 				return nil, errors.Wrapf(err, "type %s (column %q)",
-					typ.SQLString(), tree.ErrNameStringP(name))
+					typ.ErrorFormat(), tree.ErrNameStringP(name))
 			}
 			return &outDec, nil
 		}
+	case types.TimeFamily:
+		if v, ok := inVal.(*tree.DTime); ok {
+			return tree.RoundDTime(v, typ), nil
+		}
+	case types.IntervalFamily:
+		if v, ok := inVal.(*tree.DInterval); ok {
+			return tree.AdjustDInterval(v, typ)
+		}
 	case types.ArrayFamily:
 		if inArr, ok := inVal.(*tree.DArray); ok {
 			var outArr *tree.DArray
@@ -115,7 +141,7 @@ func LimitValueWidth(typ *types.T, inVal tree.Datum, name *string) (outVal tree.
 					}
 				}
 				if outArr != nil {
-					outArr.Array[i] = inElem
+					outArr.Array[i] = outElem
 				}
 			}
 			if outArr != nil {
@@ -322,17 +322,17 @@ func expandTupleStar(
 
 	columns = make(ResultColumns, len(typ.TupleContents()))
 	exprs = make([]tree.TypedExpr, len(typ.TupleContents()))
-	for i := range typ.TupleContents() {
-		columns[i].Typ = &typ.TupleContents()[i]
-		columns[i].Name = typ.TupleLabels()[i]
+	typ.ForEachTupleField(func(i int, label string, fieldTyp *types.T) {
+		columns[i].Typ = fieldTyp
+		columns[i].Name = label
 		if isTuple {
 			// De-tuplify: ((a,b,c)).* -> a, b, c
 			exprs[i] = tTuple.Exprs[i].(tree.TypedExpr)
 		} else {
 			// Can't de-tuplify: (Expr).* -> (Expr).a, (Expr).b, (Expr).c
-			exprs[i] = tree.NewTypedColumnAccessExpr(normalized, typ.TupleLabels()[i], i)
+			exprs[i] = tree.NewTypedColumnAccessExpr(normalized, label, i)
 		}
-	}
+	})
 	return columns, exprs, nil
 }
 
@@ -1306,6 +1306,17 @@ func (desc *TableDescriptor) validateCrossReferences(ctx context.Context, txn *c
 	return nil
 }
 
+// CheckTypeIsSupported returns an error if the given type cannot be used at the
+// active cluster version, because some nodes may not be able to decode it. It
+// is checked when table descriptors are validated, and before queries that use
+// the type are distributed. See types.T.MinimumClusterVersion.
+func CheckTypeIsSupported(st *cluster.Settings, typ *types.T) error {
+	if st == nil || !st.Version.IsInitialized() {
+		return nil
+	}
+	return typ.CheckSupportedAt(types.ClusterVersion(st.Version.Version().Version))
+}
+
 // ValidateTable validates that the table descriptor is well formed. Checks
 // include validating the table, column and index names, verifying that column
 // names and index names are unique and verifying that column IDs and index IDs
@@ -1390,6 +1401,10 @@ func (desc *TableDescriptor) ValidateTable(st *cluster.Settings) error {
 			return errors.AssertionFailedf("column %q invalid ID (%d) >= next column ID (%d)",
 				column.Name, errors.Safe(column.ID), errors.Safe(desc.NextColumnID))
 		}
+
+		if err := CheckTypeIsSupported(st, &column.Type); err != nil {
+			return err
+		}
 	}
 
 	for _, m := range desc.Mutations {
@@ -1806,7 +1821,8 @@ func fitColumnToFamily(desc *MutableTableDescriptor, col ColumnDescriptor) (int,
 
 // columnTypeIsIndexable returns whether the type t is valid as an indexed column.
 func columnTypeIsIndexable(t *types.T) bool {
-	return !MustBeValueEncoded(t.Family())
+	ok, _ := t.IsKeyEncodable()
+	return ok
 }
 
 // columnTypeIsInvertedIndexable returns whether the type t is valid to be indexed
@@ -1827,14 +1843,14 @@ func notIndexableError(cols []ColumnDescriptor, inverted bool) error {
 		if inverted {
 			msg += " with an inverted index"
 		}
-		typInfo = col.Type.DebugString()
+		typInfo = col.Type.CompactDebugString()
 		msg = fmt.Sprintf(msg, col.Name, col.Type.Name())
 	} else {
 		msg = "the following columns are not indexable due to their type: "
 		for i := range cols {
 			col := &cols[i]
 			msg += fmt.Sprintf("%s (type %s)", col.Name, col.Type.Name())
-			typInfo += col.Type.DebugString()
+			typInfo += col.Type.CompactDebugString()
 			if i != len(cols)-1 {
 				msg += ", "
 				typInfo += ","
@@ -21,7 +21,6 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/errors"
-	"golang.org/x/text/language"
 )
 
 // SanitizeVarFreeExpr verifies that an expression is valid, has the correct
@@ -69,10 +68,14 @@ func SanitizeVarFreeExpr(
 // ValidateColumnDefType returns an error if the type of a column definition is
 // not valid. It is checked when a column is created or altered.
 func ValidateColumnDefType(t *types.T) error {
+	if t.IsSignatureOnly() {
+		return pgerror.Newf(pgcode.InvalidTableDefinition,
+			"column cannot have pseudo-type %s", t.SQLString())
+	}
 	switch t.Family() {
 	case types.StringFamily, types.CollatedStringFamily:
 		if t.Family() == types.CollatedStringFamily {
-			if _, err := language.Parse(t.Locale()); err != nil {
+			if _, err := types.ParseLocale(t.Locale()); err != nil {
 				return pgerror.Newf(pgcode.Syntax, `invalid locale %s`, t.Locale())
 			}
 		}
@@ -97,7 +100,12 @@ func ValidateColumnDefType(t *types.T) error {
 		}
 		return ValidateColumnDefType(t.ArrayContents())
 
-	case types.BitFamily, types.IntFamily, types.FloatFamily, types.BoolFamily, types.BytesFamily, types.DateFamily,
+	case types.BytesFamily:
+		if err := types.CheckBytesWidthEnabled(t); err != nil {
+			return err
+		}
+
+	case types.BitFamily, types.IntFamily, types.FloatFamily, types.BoolFamily, types.DateFamily,
 		types.INetFamily, types.IntervalFamily, types.JsonFamily, types.OidFamily, types.TimeFamily,
 		types.TimestampFamily, types.TimestampTZFamily, types.UuidFamily:
 		// These types are OK.
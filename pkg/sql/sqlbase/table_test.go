@@ -1506,3 +1506,31 @@ func TestDecodeTableValue(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateColumnDefType(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		typ *types.T
+		err string
+	}{
+		{types.Int, ""},
+		{types.MakeArray(types.String), ""},
+		{types.Void, "column cannot have pseudo-type VOID"},
+		{types.Trigger, "column cannot have pseudo-type TRIGGER"},
+		{types.EventTrigger, "column cannot have pseudo-type EVENT_TRIGGER"},
+		{types.Unknown, "value type unknown cannot be used for table columns"},
+		{types.MakeBytes(10), `BYTES types cannot have a maximum length: BYTES\(10\)`},
+		{types.MakeArray(types.MakeBytes(10)), "BYTES types cannot have a maximum length"},
+	}
+	for _, tc := range testCases {
+		err := ValidateColumnDefType(tc.typ)
+		if tc.err == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", tc.typ.SQLString(), err)
+			}
+		} else if !testutils.IsError(err, tc.err) {
+			t.Errorf("%s: expected error %q, got %v", tc.typ.SQLString(), tc.err, err)
+		}
+	}
+}
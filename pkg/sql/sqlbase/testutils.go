@@ -16,6 +16,7 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"net"
 	"sort"
 	"time"
 	"unicode"
@@ -164,6 +165,18 @@ func RandDatumWithNullChance(rng *rand.Rand, typ *types.T, nullChance int) tree.
 		r := bitarray.Rand(rng, uint(width))
 		return &tree.DBitArray{BitArray: r}
 	case types.StringFamily:
+		switch typ.StringKind() {
+		case types.PGLSNKind:
+			return tree.NewDString(types.FormatLSN(rng.Uint64()))
+		case types.MACAddrKind, types.MACAddr8Kind:
+			size := types.MACAddrSize
+			if typ.StringKind() == types.MACAddr8Kind {
+				size = types.MACAddr8Size
+			}
+			addr := make(net.HardwareAddr, size)
+			_, _ = rng.Read(addr)
+			return tree.NewDString(addr.String())
+		}
 		// Generate a random ASCII string.
 		p := make([]byte, rng.Intn(10))
 		for i := range p {
@@ -196,7 +209,7 @@ func RandDatumWithNullChance(rng *rand.Rand, typ *types.T, nullChance int) tree.
 		return tree.NewDCollatedString(buf.String(), typ.Locale(), &tree.CollationEnvironment{})
 	case types.OidFamily:
 		return tree.NewDOid(tree.DInt(rng.Uint32()))
-	case types.UnknownFamily:
+	case types.UnknownFamily, types.VoidFamily, types.TriggerFamily:
 		return tree.DNull
 	case types.ArrayFamily:
 		contents := typ.ArrayContents()
@@ -344,8 +357,9 @@ func init() {
 			// Include these.
 			seedTypes = append(seedTypes, typ)
 		default:
-			// Only include scalar types.
-			if typ.Family() != types.ArrayFamily {
+			// Only include scalar types, other than the ones that can only be
+			// used in function signatures.
+			if typ.Family() != types.ArrayFamily && !typ.IsSignatureOnly() {
 				seedTypes = append(seedTypes, typ)
 			}
 		}
@@ -440,11 +454,12 @@ func RandColumnTypes(rng *rand.Rand, numCols int) []types.T {
 
 // RandSortingType returns a column type which can be key-encoded.
 func RandSortingType(rng *rand.Rand) *types.T {
-	typ := RandType(rng)
-	for MustBeValueEncoded(typ.Family()) {
-		typ = RandType(rng)
+	for {
+		typ := RandType(rng)
+		if ok, _ := typ.IsKeyEncodable(); ok {
+			return typ
+		}
 	}
-	return typ
 }
 
 // RandSortingTypes returns a slice of numCols random ColumnType values
@@ -770,8 +785,7 @@ func randIndexTableDefFromCols(
 
 	indexElemList := make(tree.IndexElemList, 0, len(cols))
 	for i := range cols {
-		semType := cols[i].Type.Family()
-		if MustBeValueEncoded(semType) {
+		if ok, _ := cols[i].Type.IsKeyEncodable(); !ok {
 			continue
 		}
 		indexElemList = append(indexElemList, tree.IndexElem{
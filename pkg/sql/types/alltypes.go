@@ -0,0 +1,57 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import "sort"
+
+// AllTypes returns all the concrete types that have an OID, including the
+// array types, sorted by OID. They are the types of OidToType, without the
+// wildcard and ambiguous types such as ANYELEMENT, RECORD and UNKNOWN. Each
+// type is the type of its OID that has no type modifiers, such as VARCHAR
+// rather than VARCHAR(n).
+//
+// AllTypes is intended for uses such as conformance tests, random query
+// generators and documentation generators, which then do not need to maintain
+// their own lists of types. Unlike Scalar, which only contains the canonical
+// type of each family, it contains all the types that are visible to clients.
+// The returned slice can be modified by the caller, but the types cannot.
+func AllTypes() []*T {
+	res := make([]*T, 0, len(OidToType))
+	for _, t := range OidToType {
+		if !t.IsAmbiguous() {
+			res = append(res, t)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Oid() < res[j].Oid() })
+	return res
+}
+
+// FamilyTypes returns the types of AllTypes that are in the given family,
+// sorted by OID. For example, the types of the IntFamily are INT2, INT4 and
+// INT8. It returns an empty slice for the families of the wildcard types, and
+// for the CollatedStringFamily, whose types are identified by their locales.
+func FamilyTypes(family Family) []*T {
+	var res []*T
+	for _, t := range AllTypes() {
+		if t.Family() == family {
+			res = append(res, t)
+		}
+	}
+	return res
+}
+
+// ForEachType calls the given function for each type of AllTypes, in the
+// same order.
+func ForEachType(fn func(t *T)) {
+	for _, t := range AllTypes() {
+		fn(t)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"testing"
+
+	"github.com/lib/pq/oid"
+)
+
+func TestAllTypes(t *testing.T) {
+	all := AllTypes()
+	oids := make(map[oid.Oid]bool)
+	for i, typ := range all {
+		if typ.IsAmbiguous() {
+			t.Errorf("unexpected ambiguous type %s", typ.DebugString())
+		}
+		if i > 0 && all[i-1].Oid() >= typ.Oid() {
+			t.Errorf("types are not sorted by OID: %d before %d", all[i-1].Oid(), typ.Oid())
+		}
+		oids[typ.Oid()] = true
+
+		// Every type is the type of its OID without type modifiers.
+		if fromOid, err := MakeTypeFromTypmod(typ.Oid(), -1); err != nil || !fromOid.Identical(typ) {
+			t.Errorf("%s: unexpected type for OID %d: %v (%v)", typ.SQLString(), typ.Oid(), fromOid, err)
+		}
+	}
+	expected := append([]*T{StringArray, OidVector}, Scalar...)
+	for _, typ := range expected {
+		if !oids[typ.Oid()] {
+			t.Errorf("expected AllTypes to contain %s", typ.SQLString())
+		}
+	}
+	for _, typ := range []*T{Any, AnyArray, AnyTuple, Unknown} {
+		if oids[typ.Oid()] {
+			t.Errorf("expected AllTypes not to contain %s", typ.SQLString())
+		}
+	}
+
+	var count int
+	ForEachType(func(typ *T) {
+		if !typ.Identical(all[count]) {
+			t.Errorf("expected %s, got %s", all[count].SQLString(), typ.SQLString())
+		}
+		count++
+	})
+	if count != len(all) {
+		t.Errorf("expected %d types, got %d", len(all), count)
+	}
+
+	ints := FamilyTypes(IntFamily)
+	if len(ints) != 3 || !ints[0].Identical(Int) || !ints[1].Identical(Int2) || !ints[2].Identical(Int4) {
+		t.Errorf("unexpected IntFamily types %v", ints)
+	}
+	if anys := FamilyTypes(AnyFamily); len(anys) != 0 {
+		t.Errorf("unexpected AnyFamily types %v", anys)
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/lib/pq/oid"
+)
+
+// MaxArrayDimensions is the maximum number of dimensions of the declared bounds
+// of an array type, which is the maximum number of dimensions of arrays in
+// Postgres (MAXDIM).
+const MaxArrayDimensions = 6
+
+// MakeDimensionedArray constructs a new instance of an ArrayFamily type with the
+// given element type and declared bounds, such as INT[3][4], which has the
+// bounds [3, 4]. A bound of -1 declares a dimension without a bound, as in
+// INT[][4]. The bounds are recorded in the ArrayDimensions field, and are only
+// enforced by CheckArrayBounds: like in Postgres, the values of the type are
+// otherwise those of the one-dimensional ARRAY type of the element type. An
+// array without bounds, or with a single dimension without a bound, is the same
+// as MakeArray.
+//
+// The bounds are kept for the compatibility tools that render and parse the
+// schemas of other databases. They are not kept when the type is marshaled and
+// unmarshaled, since previous versions of CRDB did not use them.
+func MakeDimensionedArray(typ *T, bounds []int32) (*T, error) {
+	if len(bounds) > MaxArrayDimensions {
+		return nil, pgerror.Newf(pgcode.ProgramLimitExceeded,
+			"number of array dimensions (%d) exceeds the maximum allowed (%d)", len(bounds), MaxArrayDimensions)
+	}
+	for _, bound := range bounds {
+		if bound < -1 {
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue, "invalid array bound %d", bound)
+		}
+	}
+	arr := MakeArray(typ)
+	if len(bounds) > 1 || (len(bounds) == 1 && bounds[0] != -1) {
+		arr.InternalType.ArrayDimensions = append([]int32(nil), bounds...)
+	}
+	return arr, nil
+}
+
+// ArrayBounds returns the declared bounds of an ArrayFamily type constructed by
+// MakeDimensionedArray, or nil if the type has no declared bounds.
+func (t *T) ArrayBounds() []int32 {
+	if t.Family() != ArrayFamily {
+		return nil
+	}
+	return t.InternalType.ArrayDimensions
+}
+
+// arrayBoundsString returns the SQL syntax of the given array bounds, such as
+// [3][4], or [] if there are none.
+func arrayBoundsString(bounds []int32) string {
+	if len(bounds) == 0 {
+		return "[]"
+	}
+	var buf strings.Builder
+	for _, bound := range bounds {
+		buf.WriteByte('[')
+		if bound >= 0 {
+			buf.WriteString(strconv.Itoa(int(bound)))
+		}
+		buf.WriteByte(']')
+	}
+	return buf.String()
+}
+
+// DimensionedSQLString is like SQLString, except that the declared bounds of
+// array types are rendered, as in INT8[3][4] or STRING[2] COLLATE de. SQLString
+// omits them, like the format_type function of Postgres.
+func (t *T) DimensionedSQLString() string {
+	s := t.SQLString()
+	if len(t.ArrayBounds()) == 0 {
+		return s
+	}
+	// The brackets of arrays of collated strings precede the COLLATE clause.
+	i := strings.LastIndex(s, "[]")
+	return s[:i] + arrayBoundsString(t.ArrayBounds()) + s[i+2:]
+}
+
+// DimensionedSQLStandardName returns the name of the type in the form returned
+// by SQLStandardNameWithTypmod, with the modifiers of the type and the declared
+// bounds of array types, such as:
+//
+//   character varying(255)[3][4]
+//
+// ParseDimensionedSQLStandardName parses the names back into the same types.
+func (t *T) DimensionedSQLStandardName() string {
+	if t.Family() != ArrayFamily || t.Oid() == oid.T_int2vector || t.Oid() == oid.T_oidvector {
+		return t.SQLStandardNameWithTypmod(true, int(t.Typmod()))
+	}
+	elem := t.ArrayContents()
+	return elem.SQLStandardNameWithTypmod(true, int(elem.Typmod())) + arrayBoundsString(t.ArrayBounds())
+}
+
+// ParseDimensionedSQLStandardName is like ParseSQLStandardName, except that the
+// array bounds are kept in the resulting type, as by MakeDimensionedArray.
+func ParseDimensionedSQLStandardName(s string) (*T, error) {
+	typ, bounds, err := parseSQLStandardName(s)
+	if err != nil {
+		return nil, err
+	}
+	if bounds == nil {
+		return typ, nil
+	}
+	return MakeDimensionedArray(typ, bounds)
+}
+
+// CheckArrayBounds enforces the declared bounds of this array type on a value,
+// given the length of each of its dimensions. It returns an error with the
+// ArraySubscript code if the value has a different number of dimensions than
+// the type, or if a dimension is longer than its bound. Arrays without declared
+// bounds accept all the values.
+//
+// Like Postgres, CRDB ignores the declared bounds by default. Compatibility
+// tools call CheckArrayBounds to emulate the strict handling of the bounds by
+// other databases.
+func (t *T) CheckArrayBounds(lengths []int) error {
+	bounds := t.ArrayBounds()
+	if len(bounds) == 0 {
+		return nil
+	}
+	if len(lengths) != len(bounds) {
+		return pgerror.Newf(pgcode.ArraySubscript,
+			"array has %d dimensions, but type %s has %d", len(lengths), t.DimensionedSQLString(), len(bounds))
+	}
+	for i, bound := range bounds {
+		if bound >= 0 && lengths[i] > int(bound) {
+			return pgerror.Newf(pgcode.ArraySubscript,
+				"array length %d exceeds the bound of dimension %d of type %s",
+				lengths[i], i+1, t.DimensionedSQLString())
+		}
+	}
+	return nil
+}
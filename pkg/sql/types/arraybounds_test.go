@@ -0,0 +1,115 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+func TestDimensionedArray(t *testing.T) {
+	mustMake := func(typ *T, bounds ...int32) *T {
+		arr, err := MakeDimensionedArray(typ, bounds)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return arr
+	}
+	testCases := []struct {
+		typ          *T
+		sqlString    string
+		standardName string
+	}{
+		{mustMake(Int, 3, 4), "INT8[3][4]", "bigint[3][4]"},
+		{mustMake(Int4, -1, 4), "INT4[][4]", "integer[][4]"},
+		{mustMake(Int, 3), "INT8[3]", "bigint[3]"},
+		{mustMake(MakeVarChar(255), 2, 2), "VARCHAR(255)[2][2]", "character varying(255)[2][2]"},
+		{mustMake(MakeCollatedString(String, "de"), 2), "STRING[2] COLLATE de", "text[2]"},
+		{mustMake(MakeDecimal(10, 2), -1, -1), "DECIMAL(10,2)[][]", "numeric(10,2)[][]"},
+		{mustMake(Int), "INT8[]", "bigint[]"},
+		{mustMake(Int, -1), "INT8[]", "bigint[]"},
+		{MakeArray(MakeVarChar(3)), "VARCHAR(3)[]", "character varying(3)[]"},
+		{MakeVarChar(3), "VARCHAR(3)", "character varying(3)"},
+	}
+	for _, tc := range testCases {
+		if s := tc.typ.DimensionedSQLString(); s != tc.sqlString {
+			t.Errorf("%s: expected SQL string %s, but got %s", tc.typ.DebugString(), tc.sqlString, s)
+		}
+		name := tc.typ.DimensionedSQLStandardName()
+		if name != tc.standardName {
+			t.Errorf("%s: expected standard name %s, but got %s", tc.typ.DebugString(), tc.standardName, name)
+		}
+		parsed, err := ParseDimensionedSQLStandardName(name)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if tc.typ.Family() == ArrayFamily && tc.typ.ArrayContents().Family() == CollatedStringFamily {
+			// The standard names of collated strings have no locale.
+			continue
+		}
+		if !parsed.Identical(tc.typ) {
+			t.Errorf("%s: expected %s, but got %s", name, tc.typ.DebugString(), parsed.DebugString())
+		}
+		// ParseSQLStandardName ignores the bounds.
+		if typ, err := ParseSQLStandardName(name); err != nil {
+			t.Errorf("%s: %v", name, err)
+		} else if typ.ArrayBounds() != nil {
+			t.Errorf("%s: expected no bounds, but got %v", name, typ.ArrayBounds())
+		}
+	}
+	if mustMake(Int, -1).ArrayBounds() != nil {
+		t.Error("expected a single dimension without a bound to have no bounds")
+	}
+	if !mustMake(Int, 3, 4).Equivalent(IntArray) {
+		t.Error("expected INT8[3][4] to be equivalent to INT8[]")
+	}
+
+	if _, err := MakeDimensionedArray(Int, []int32{1, 1, 1, 1, 1, 1, 1}); err == nil ||
+		pgerror.GetPGCode(err) != pgcode.ProgramLimitExceeded {
+		t.Errorf("expected too many dimensions to be rejected, but got %v", err)
+	}
+	if _, err := MakeDimensionedArray(Int, []int32{-2}); err == nil {
+		t.Error("expected a negative bound to be rejected")
+	}
+	if _, err := ParseDimensionedSQLStandardName("bigint[3x]"); err == nil {
+		t.Error("expected invalid bounds to be rejected")
+	}
+
+	boundsCases := []struct {
+		typ     *T
+		lengths []int
+		err     string
+	}{
+		{mustMake(Int, 3, 4), []int{3, 4}, ""},
+		{mustMake(Int, 3, 4), []int{2, 1}, ""},
+		{mustMake(Int, 3, 4), []int{3, 5}, "array length 5 exceeds the bound of dimension 2 of type INT8[3][4]"},
+		{mustMake(Int, 3, 4), []int{3}, "array has 1 dimensions, but type INT8[3][4] has 2"},
+		{mustMake(Int, -1, 2), []int{100, 2}, ""},
+		{IntArray, []int{100, 100}, ""},
+	}
+	for _, tc := range boundsCases {
+		err := tc.typ.CheckArrayBounds(tc.lengths)
+		if tc.err == "" {
+			if err != nil {
+				t.Errorf("%s %v: %v", tc.typ.DimensionedSQLString(), tc.lengths, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != tc.err {
+			t.Errorf("%s %v: expected error %q, but got %v", tc.typ.DimensionedSQLString(), tc.lengths, tc.err, err)
+		} else if code := pgerror.GetPGCode(err); code != pgcode.ArraySubscript {
+			t.Errorf("expected code %s, but got %s", pgcode.ArraySubscript, code)
+		}
+	}
+}
@@ -0,0 +1,335 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/errors"
+)
+
+// ArrowTypeID identifies one of the data types of the Apache Arrow columnar
+// format.
+type ArrowTypeID int
+
+// These constants mirror the members of the Type union of the Arrow format
+// specification (Schema.fbs) that are used by CockroachDB, with the integer and
+// floating point types split by width.
+const (
+	ArrowNull ArrowTypeID = iota
+	ArrowBool
+	ArrowInt8
+	ArrowInt16
+	ArrowInt32
+	ArrowInt64
+	ArrowUint32
+	ArrowFloat32
+	ArrowFloat64
+	ArrowDecimal128
+	ArrowUtf8
+	ArrowBinary
+	ArrowFixedSizeBinary
+	ArrowDate32
+	ArrowTime64
+	ArrowTimestamp
+	ArrowIntervalMonthDayNano
+	ArrowList
+	ArrowFixedSizeList
+	ArrowStruct
+)
+
+var arrowTypeIDNames = [...]string{
+	ArrowNull:                 "null",
+	ArrowBool:                 "bool",
+	ArrowInt8:                 "int8",
+	ArrowInt16:                "int16",
+	ArrowInt32:                "int32",
+	ArrowInt64:                "int64",
+	ArrowUint32:               "uint32",
+	ArrowFloat32:              "float32",
+	ArrowFloat64:              "float64",
+	ArrowDecimal128:           "decimal128",
+	ArrowUtf8:                 "utf8",
+	ArrowBinary:               "binary",
+	ArrowFixedSizeBinary:      "fixed_size_binary",
+	ArrowDate32:               "date32",
+	ArrowTime64:               "time64",
+	ArrowTimestamp:            "timestamp",
+	ArrowIntervalMonthDayNano: "month_day_nano_interval",
+	ArrowList:                 "list",
+	ArrowFixedSizeList:        "fixed_size_list",
+	ArrowStruct:               "struct",
+}
+
+// String returns the name of the type, as used by the Arrow libraries.
+func (a ArrowTypeID) String() string {
+	if a < 0 || int(a) >= len(arrowTypeIDNames) {
+		return "unknown"
+	}
+	return arrowTypeIDNames[a]
+}
+
+// ArrowTimeUnit is the unit of the values of the Arrow time and timestamp
+// types.
+type ArrowTimeUnit int
+
+// These constants mirror the TimeUnit enum of the Arrow format specification.
+const (
+	ArrowSecond ArrowTimeUnit = iota
+	ArrowMillisecond
+	ArrowMicrosecond
+	ArrowNanosecond
+)
+
+// These are the names of the canonical Arrow extension types that are used
+// for the types that have no Arrow data type of their own. They are stored in
+// the ARROW:extension:name metadata of the fields.
+const (
+	ArrowUUIDExtension = "arrow.uuid"
+	ArrowJSONExtension = "arrow.json"
+)
+
+// These are the maximum precision of Arrow decimal128 values, and the size of
+// the fixed_size_binary values that store UUIDs.
+const (
+	arrowMaxDecimal128Precision = 38
+	arrowUUIDByteWidth          = 16
+)
+
+// ArrowField describes a field of an Arrow schema: a column of a record batch,
+// the element of a list, or a member of a struct.
+type ArrowField struct {
+	Name     string
+	Type     ArrowTypeID
+	Nullable bool
+	// Extension is the name of the extension type of the field, if any.
+	Extension string
+
+	// ByteWidth is the length in bytes of an ArrowFixedSizeBinary value.
+	ByteWidth int32
+	// Precision and Scale are the parameters of an ArrowDecimal128 value.
+	Precision int32
+	Scale     int32
+	// Unit is the unit of an ArrowTime64 or ArrowTimestamp value.
+	Unit ArrowTimeUnit
+	// TimeZone is the time zone of an ArrowTimestamp value. Values with a time
+	// zone are instants in UTC, and values without one are local times.
+	TimeZone string
+	// ListSize is the number of elements of an ArrowFixedSizeList value.
+	ListSize int32
+	// Children are the element field of an ArrowList or ArrowFixedSizeList,
+	// or the member fields of an ArrowStruct.
+	Children []ArrowField
+}
+
+// ToArrowField returns the Arrow field that represents a column having the
+// given name and type, for use by columnar export paths and integrations with
+// external analytics tools:
+//
+//   - DECIMAL(p,s) uses decimal128 if p is at most 38. Other DECIMAL types are
+//     represented as utf8, like INTERVAL, BIT, INET and all the string types.
+//   - TIME, TIMESTAMP and TIMESTAMPTZ use microsecond units. Only TIMESTAMPTZ
+//     has a time zone, which is UTC.
+//   - UUID and JSONB use the arrow.uuid and arrow.json extension types.
+//   - Arrays use list fields, which have nullable elements.
+//   - Tuples use struct fields, named after the tuple labels or, like in
+//     Postgres, f1, f2, etc. if the tuple is not labeled.
+//
+// It returns an error for types that cannot be represented in Arrow.
+// FromArrowField performs the reverse conversion.
+func ToArrowField(name string, t *T, nullable bool) (ArrowField, error) {
+	f := ArrowField{Name: name, Nullable: nullable}
+
+	switch t.Family() {
+	case UnknownFamily:
+		f.Type = ArrowNull
+		f.Nullable = true
+
+	case BoolFamily:
+		f.Type = ArrowBool
+
+	case IntFamily:
+		switch t.Width() {
+		case 16:
+			f.Type = ArrowInt16
+		case 32:
+			f.Type = ArrowInt32
+		case 64:
+			f.Type = ArrowInt64
+		default:
+			return ArrowField{}, errors.AssertionFailedf("unknown int width: %d", t.Width())
+		}
+
+	case OidFamily:
+		f.Type = ArrowUint32
+
+	case FloatFamily:
+		if t.IsFloat4() {
+			f.Type = ArrowFloat32
+		} else {
+			f.Type = ArrowFloat64
+		}
+
+	case DecimalFamily:
+		if t.Precision() == 0 || t.Precision() > arrowMaxDecimal128Precision {
+			f.Type = ArrowUtf8
+		} else {
+			f.Type = ArrowDecimal128
+			f.Precision, f.Scale = t.Precision(), t.Scale()
+		}
+
+	case DateFamily:
+		f.Type = ArrowDate32
+
+	case TimeFamily:
+		f.Type, f.Unit = ArrowTime64, ArrowMicrosecond
+
+	case TimestampFamily:
+		f.Type, f.Unit = ArrowTimestamp, ArrowMicrosecond
+
+	case TimestampTZFamily:
+		f.Type, f.Unit, f.TimeZone = ArrowTimestamp, ArrowMicrosecond, "UTC"
+
+	case IntervalFamily:
+		f.Type = ArrowIntervalMonthDayNano
+
+	case StringFamily, CollatedStringFamily, BitFamily, INetFamily:
+		f.Type = ArrowUtf8
+
+	case BytesFamily:
+		f.Type = ArrowBinary
+
+	case UuidFamily:
+		f.Type, f.ByteWidth, f.Extension = ArrowFixedSizeBinary, arrowUUIDByteWidth, ArrowUUIDExtension
+
+	case JsonFamily:
+		f.Type, f.Extension = ArrowUtf8, ArrowJSONExtension
+
+	case ArrayFamily:
+		elem, err := ToArrowField("item", t.ArrayContents(), true /* nullable */)
+		if err != nil {
+			return ArrowField{}, err
+		}
+		f.Type, f.Children = ArrowList, []ArrowField{elem}
+
+	case TupleFamily:
+		contents := t.TupleContents()
+		f.Type, f.Children = ArrowStruct, make([]ArrowField, len(contents))
+		for i := range contents {
+			label := t.TupleLabel(i)
+			if label == "" {
+				label = fmt.Sprintf("f%d", i+1)
+			}
+			child, err := ToArrowField(label, &contents[i], true /* nullable */)
+			if err != nil {
+				return ArrowField{}, err
+			}
+			f.Children[i] = child
+		}
+
+	default:
+		return ArrowField{}, pgerror.Newf(pgcode.FeatureNotSupported,
+			"type %s cannot be represented in Arrow", t.SQLString())
+	}
+	return f, nil
+}
+
+// FromArrowField returns the type of a column that stores the values of the
+// given Arrow field, such as a field of the schema of an imported Arrow or
+// Parquet file. It is the reverse of ToArrowField, except for the types whose
+// values are represented as utf8, which result in STRING, OID, which results
+// in INT8, and unlabeled tuples, which result in tuples labeled with the names
+// of the struct fields. Arrow int8 fields result in INT2. Lists of lists result
+// in an error, since nested arrays are not supported.
+func FromArrowField(f ArrowField) (*T, error) {
+	switch f.Type {
+	case ArrowNull:
+		return Unknown, nil
+	case ArrowBool:
+		return Bool, nil
+	case ArrowInt8, ArrowInt16:
+		return Int2, nil
+	case ArrowInt32:
+		return Int4, nil
+	case ArrowInt64, ArrowUint32:
+		return Int, nil
+	case ArrowFloat32:
+		return Float4, nil
+	case ArrowFloat64:
+		return Float, nil
+	case ArrowDecimal128:
+		if f.Precision < 1 || f.Precision > arrowMaxDecimal128Precision || f.Scale < 0 || f.Scale > f.Precision {
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"invalid precision %d and scale %d of Arrow decimal128 field %s", f.Precision, f.Scale, f.Name)
+		}
+		return MakeDecimal(f.Precision, f.Scale), nil
+	case ArrowUtf8:
+		if f.Extension == ArrowJSONExtension {
+			return Jsonb, nil
+		}
+		return String, nil
+	case ArrowBinary:
+		return Bytes, nil
+	case ArrowFixedSizeBinary:
+		if f.Extension == ArrowUUIDExtension && f.ByteWidth == arrowUUIDByteWidth {
+			return Uuid, nil
+		}
+		return Bytes, nil
+	case ArrowDate32:
+		return Date, nil
+	case ArrowTime64:
+		return Time, nil
+	case ArrowTimestamp:
+		if f.TimeZone != "" {
+			return TimestampTZ, nil
+		}
+		return Timestamp, nil
+	case ArrowIntervalMonthDayNano:
+		return Interval, nil
+
+	case ArrowList, ArrowFixedSizeList:
+		if len(f.Children) != 1 {
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"Arrow %s field %s must have a single child", f.Type, f.Name)
+		}
+		elem, err := FromArrowField(f.Children[0])
+		if err != nil {
+			return nil, err
+		}
+		if err := CheckArrayElementType(elem); err != nil {
+			return nil, err
+		}
+		if elem.Family() == ArrayFamily {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"nested Arrow list field %s cannot be represented as an array", f.Name)
+		}
+		return MakeArray(elem), nil
+
+	case ArrowStruct:
+		contents := make([]T, len(f.Children))
+		labels := make([]string, len(f.Children))
+		for i := range f.Children {
+			typ, err := FromArrowField(f.Children[i])
+			if err != nil {
+				return nil, err
+			}
+			contents[i], labels[i] = *typ, f.Children[i].Name
+		}
+		if err := ValidateTupleLabels(len(contents), labels); err != nil {
+			return nil, err
+		}
+		return MakeLabeledTuple(contents, labels), nil
+	}
+	return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+		"Arrow %s field %s cannot be converted to a SQL type", f.Type, f.Name)
+}
@@ -0,0 +1,98 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestArrowField(t *testing.T) {
+	testCases := []struct {
+		typ      *T
+		expected ArrowField
+	}{
+		{Bool, ArrowField{Type: ArrowBool}},
+		{Int2, ArrowField{Type: ArrowInt16}},
+		{Int, ArrowField{Type: ArrowInt64}},
+		{Float4, ArrowField{Type: ArrowFloat32}},
+		{MakeDecimal(20, 4), ArrowField{Type: ArrowDecimal128, Precision: 20, Scale: 4}},
+		{Date, ArrowField{Type: ArrowDate32}},
+		{Time, ArrowField{Type: ArrowTime64, Unit: ArrowMicrosecond}},
+		{Timestamp, ArrowField{Type: ArrowTimestamp, Unit: ArrowMicrosecond}},
+		{TimestampTZ, ArrowField{Type: ArrowTimestamp, Unit: ArrowMicrosecond, TimeZone: "UTC"}},
+		{Interval, ArrowField{Type: ArrowIntervalMonthDayNano}},
+		{String, ArrowField{Type: ArrowUtf8}},
+		{Bytes, ArrowField{Type: ArrowBinary}},
+		{Uuid, ArrowField{Type: ArrowFixedSizeBinary, ByteWidth: 16, Extension: ArrowUUIDExtension}},
+		{Jsonb, ArrowField{Type: ArrowUtf8, Extension: ArrowJSONExtension}},
+		{StringArray, ArrowField{Type: ArrowList, Children: []ArrowField{
+			{Name: "item", Type: ArrowUtf8, Nullable: true}}}},
+		{MakeLabeledTuple([]T{*Int, *MakeArray(Date)}, []string{"a", "b"}), ArrowField{
+			Type: ArrowStruct, Children: []ArrowField{
+				{Name: "a", Type: ArrowInt64, Nullable: true},
+				{Name: "b", Type: ArrowList, Nullable: true, Children: []ArrowField{
+					{Name: "item", Type: ArrowDate32, Nullable: true}}},
+			}}},
+	}
+
+	for _, tc := range testCases {
+		actual, err := ToArrowField("", tc.typ, false /* nullable */)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.typ.SQLString(), err)
+			continue
+		}
+		if !reflect.DeepEqual(actual, tc.expected) {
+			t.Errorf("%s: expected %+v, got %+v", tc.typ.SQLString(), tc.expected, actual)
+		}
+		typ, err := FromArrowField(actual)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.typ.SQLString(), err)
+		} else if !typ.Identical(tc.typ) {
+			t.Errorf("%s: expected %s, got %s", tc.typ.SQLString(), tc.typ.DebugString(), typ.DebugString())
+		}
+	}
+
+	// Unlabeled tuples use the Postgres names of record fields.
+	f, err := ToArrowField("t", MakeTuple([]T{*Int, *String}), true /* nullable */)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Children[0].Name != "f1" || f.Children[1].Name != "f2" {
+		t.Errorf("unexpected struct fields %+v", f.Children)
+	}
+
+	// Decimals without a precision, or with a precision that is too large for
+	// decimal128, are represented as strings.
+	for _, typ := range []*T{Decimal, MakeDecimal(40, 2)} {
+		if f, err := ToArrowField("d", typ, true /* nullable */); err != nil || f.Type != ArrowUtf8 {
+			t.Errorf("%s: expected utf8 field, got %+v (%v)", typ.SQLString(), f, err)
+		}
+	}
+
+	// Fixed-size lists are read as arrays.
+	f = ArrowField{Type: ArrowFixedSizeList, ListSize: 3, Children: []ArrowField{{Type: ArrowFloat32}}}
+	if typ, err := FromArrowField(f); err != nil || !typ.Identical(MakeArray(Float4)) {
+		t.Errorf("expected FLOAT4[] for %+v, got %v (%v)", f, typ, err)
+	}
+
+	for _, f := range []ArrowField{
+		{Name: "a", Type: ArrowList, Children: []ArrowField{{Type: ArrowList, Children: []ArrowField{{Type: ArrowBool}}}}},
+		{Name: "b", Type: ArrowList},
+		{Name: "c", Type: ArrowDecimal128, Precision: 50},
+		{Name: "d", Type: ArrowStruct, Children: []ArrowField{{Name: "x", Type: ArrowBool}, {Name: "x", Type: ArrowBool}}},
+		{Name: "e", Type: ArrowTypeID(100)},
+	} {
+		if typ, err := FromArrowField(f); err == nil {
+			t.Errorf("%s: expected error, got %s", f.Name, typ.SQLString())
+		}
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"sync/atomic"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/envutil"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
+)
+
+// MaxBytesWidth is the maximum length in bytes of a BYTES type. It is the same
+// as the maximum length of the VARCHAR type in Postgres.
+const MaxBytesWidth = 10485760
+
+// bytesWidthEnabled is the compatibility flag that allows BYTES types to have
+// a maximum length, as in BYTES(n). Postgres has no such types, but other
+// systems such as SQL Server have them (e.g. VARBINARY(n)), so the flag eases
+// migrations from those systems. It is 1 if the flag is set.
+var bytesWidthEnabled = func() int32 {
+	if envutil.EnvOrDefaultBool("COCKROACH_ENABLE_BYTES_WIDTH", false) {
+		return 1
+	}
+	return 0
+}()
+
+// BytesWidthEnabled returns true if BYTES types can have a maximum length. It
+// is controlled by the COCKROACH_ENABLE_BYTES_WIDTH environment variable.
+func BytesWidthEnabled() bool {
+	return atomic.LoadInt32(&bytesWidthEnabled) == 1
+}
+
+// TestingSetBytesWidthEnabled sets the flag returned by BytesWidthEnabled, and
+// returns a function that restores its previous value.
+func TestingSetBytesWidthEnabled(enabled bool) func() {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	prev := atomic.SwapInt32(&bytesWidthEnabled, v)
+	return func() { atomic.StoreInt32(&bytesWidthEnabled, prev) }
+}
+
+// MakeBytes constructs a new instance of the BYTES type having the given
+// maximum length in bytes (0 = unspecified length). The width must be at most
+// MaxBytesWidth; use ValidateBytesWidth to check widths given by users.
+func MakeBytes(width int32) *T {
+	if width == 0 {
+		return Bytes
+	}
+	if width < 0 || width > MaxBytesWidth {
+		panic(errors.AssertionFailedf("invalid BYTES width: %d", width))
+	}
+	return &T{InternalType: InternalType{
+		Family: BytesFamily, Oid: oid.T_bytea, Width: width, Locale: &emptyLocale}}
+}
+
+// ValidateBytesWidth returns an error with the InvalidParameterValue code if a
+// BYTES type cannot have the given maximum length.
+func ValidateBytesWidth(width int32) error {
+	if width < 1 {
+		return pgerror.New(pgcode.InvalidParameterValue, "length for type bytes must be at least 1")
+	}
+	if width > MaxBytesWidth {
+		return pgerror.Newf(pgcode.InvalidParameterValue,
+			"length for type bytes cannot exceed %d", MaxBytesWidth)
+	}
+	return nil
+}
+
+// CheckBytesWidthEnabled returns an error with the FeatureNotSupported code if
+// the given type is a BYTES type that has a maximum length and
+// BytesWidthEnabled is false. The flag is only checked when columns are
+// created or altered, so the existing columns of such types remain usable if
+// it is unset.
+func CheckBytesWidthEnabled(t *T) error {
+	if t.Family() == BytesFamily && t.Width() > 0 && !BytesWidthEnabled() {
+		return errors.WithHint(
+			pgerror.Newf(pgcode.FeatureNotSupported,
+				"BYTES types cannot have a maximum length: %s", t.SQLString()),
+			"set the COCKROACH_ENABLE_BYTES_WIDTH environment variable to allow BYTES(n) columns")
+	}
+	return nil
+}
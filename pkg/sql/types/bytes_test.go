@@ -0,0 +1,69 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/lib/pq/oid"
+)
+
+func TestBytesWidth(t *testing.T) {
+	typ := MakeBytes(10)
+
+	// BYTES columns cannot have a maximum length by default.
+	if err := CheckBytesWidthEnabled(typ); pgerror.GetPGCode(err) != pgcode.FeatureNotSupported {
+		t.Errorf("expected code %s, got %v", pgcode.FeatureNotSupported, err)
+	}
+	if err := CheckBytesWidthEnabled(Bytes); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	func() {
+		defer TestingSetBytesWidthEnabled(true)()
+		if err := CheckBytesWidthEnabled(typ); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}()
+
+	if typ.SQLString() != "BYTES(10)" || typ.SQLStandardNameWithTypmod(true, int(typ.Typmod())) != "bytea(10)" {
+		t.Errorf("unexpected names %s and %s", typ.SQLString(), typ.SQLStandardNameWithTypmod(true, int(typ.Typmod())))
+	}
+	if MakeBytes(0) != Bytes || Bytes.Typmod() != -1 {
+		t.Error("expected BYTES without a maximum length")
+	}
+	if !typ.Equivalent(Bytes) || typ.Identical(Bytes) {
+		t.Error("expected BYTES(10) to be equivalent but not identical to BYTES")
+	}
+
+	fromTypmod, err := MakeTypeFromTypmod(oid.T_bytea, typ.Typmod())
+	if err != nil || !fromTypmod.Identical(typ) {
+		t.Errorf("expected %s, got %v (%v)", typ.DebugString(), fromTypmod, err)
+	}
+	if parsed, err := ParseSQLStandardName("bytea(10)[]"); err != nil || !parsed.Identical(MakeArray(typ)) {
+		t.Errorf("expected BYTES(10)[], got %v (%v)", parsed, err)
+	}
+	var unmarshaled T
+	if err := protoutil.Unmarshal(marshalOrFatal(t, typ), &unmarshaled); err != nil || !unmarshaled.Identical(typ) {
+		t.Errorf("expected %s, got %s (%v)", typ.DebugString(), unmarshaled.DebugString(), err)
+	}
+
+	for _, width := range []int32{0, -1, MaxBytesWidth + 1} {
+		if err := ValidateBytesWidth(width); pgerror.GetPGCode(err) != pgcode.InvalidParameterValue {
+			t.Errorf("%d: expected code %s, got %v", width, pgcode.InvalidParameterValue, err)
+		}
+	}
+	if _, err := MakeTypeFromTypmod(oid.T_bytea, varHeaderSize); err == nil {
+		t.Error("expected error for bytea typmod without a length")
+	}
+}
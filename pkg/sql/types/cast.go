@@ -0,0 +1,529 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/errors"
+)
+
+// CastContextKind describes the contexts in which a cast from one type to
+// another is allowed. It mirrors the castcontext column of the Postgres pg_cast
+// catalog table. The kinds are ordered so that a cast which is allowed in one
+// context is also allowed in every context that is less permissive:
+//
+//   CastImplicit   - allowed anywhere, without any explicit syntax
+//   CastAssignment - allowed when assigning to a column, and explicitly
+//   CastExplicit   - allowed only with explicit CAST or :: syntax
+//
+type CastContextKind int
+
+const (
+	// CastNotAllowed indicates that there is no cast between the two types.
+	CastNotAllowed CastContextKind = iota
+	// CastExplicit indicates that the cast requires explicit CAST or ::
+	// syntax (castcontext 'e' in Postgres).
+	CastExplicit
+	// CastAssignment indicates that the cast is also performed implicitly when
+	// assigning a value to a column, as in INSERT or UPDATE (castcontext 'a' in
+	// Postgres).
+	CastAssignment
+	// CastImplicit indicates that the cast is performed implicitly in any
+	// context (castcontext 'i' in Postgres).
+	CastImplicit
+)
+
+// String returns the name of the cast context.
+func (c CastContextKind) String() string {
+	switch c {
+	case CastNotAllowed:
+		return "none"
+	case CastExplicit:
+		return "explicit"
+	case CastAssignment:
+		return "assignment"
+	case CastImplicit:
+		return "implicit"
+	}
+	return "unknown"
+}
+
+// PgCastContext returns the single-letter code that Postgres uses for this
+// cast context in the castcontext column of pg_cast, or 0 for CastNotAllowed.
+func (c CastContextKind) PgCastContext() byte {
+	switch c {
+	case CastExplicit:
+		return 'e'
+	case CastAssignment:
+		return 'a'
+	case CastImplicit:
+		return 'i'
+	}
+	return 0
+}
+
+// Volatility describes whether a cast can be evaluated once, ahead of time, or
+// must be evaluated for each row. It mirrors the provolatile column of the
+// Postgres pg_proc catalog table for the functions that implement casts.
+type Volatility int
+
+const (
+	// VolatilityImmutable indicates that the result of the cast depends only
+	// on its input, so it can be folded into a constant and used in computed
+	// columns, indexes and partitioning expressions (provolatile 'i' in
+	// Postgres).
+	VolatilityImmutable Volatility = iota
+	// VolatilityStable indicates that the result of the cast also depends on
+	// the session, such as its time zone or the format of its output, but not
+	// on the time at which it is evaluated within a statement (provolatile 's'
+	// in Postgres).
+	VolatilityStable
+	// VolatilityVolatile indicates that the result of the cast can change
+	// every time it is evaluated (provolatile 'v' in Postgres).
+	VolatilityVolatile
+)
+
+// String returns the name of the volatility.
+func (v Volatility) String() string {
+	switch v {
+	case VolatilityImmutable:
+		return "immutable"
+	case VolatilityStable:
+		return "stable"
+	case VolatilityVolatile:
+		return "volatile"
+	}
+	return "unknown"
+}
+
+// PgProvolatile returns the single-letter code that Postgres uses for this
+// volatility in the provolatile column of pg_proc.
+func (v Volatility) PgProvolatile() byte {
+	switch v {
+	case VolatilityImmutable:
+		return 'i'
+	case VolatilityStable:
+		return 's'
+	}
+	return 'v'
+}
+
+// castInfo describes a cast between two type families in validCasts.
+type castInfo struct {
+	// ctx is the most permissive context in which the cast is allowed.
+	ctx CastContextKind
+	// volatility is the volatility of the cast. The zero value is
+	// VolatilityImmutable, which is the volatility of most casts.
+	volatility Volatility
+	// lossy is true if some values of the source family cannot be recovered
+	// from the result, or are rejected by the cast, for any types of the two
+	// families.
+	lossy bool
+}
+
+// validCasts is the matrix of casts supported by CockroachDB, indexed by the
+// family of the target type and then by the family of the source type. Casts
+// between array types are not listed; they are allowed in the same context as
+// the cast between their element types. The CollatedStringFamily target shares
+// the StringFamily entry.
+//
+// The contexts follow the Postgres pg_cast catalog for the analogous types:
+// numeric widening is implicit and narrowing is by assignment, any type can be
+// assigned to a string column, and parsing a string requires an explicit cast.
+//
+// Each cast is also annotated with its volatility and lossiness. Casts that
+// depend on the time zone of the session, like those between TIMESTAMP and
+// TIMESTAMPTZ, and casts that parse or format values according to the session
+// settings, are stable. Casts that narrow numeric values, that drop fields of
+// temporal values, and that parse strings, which rejects the strings that are
+// not valid values, are lossy.
+var validCasts = map[Family]map[Family]castInfo{
+	BitFamily: {
+		UnknownFamily:        {ctx: CastImplicit},
+		BitFamily:            {ctx: CastImplicit},
+		IntFamily:            {ctx: CastExplicit, lossy: true},
+		StringFamily:         {ctx: CastExplicit, lossy: true},
+		CollatedStringFamily: {ctx: CastExplicit, lossy: true},
+	},
+	BoolFamily: {
+		UnknownFamily:        {ctx: CastImplicit},
+		BoolFamily:           {ctx: CastImplicit},
+		IntFamily:            {ctx: CastExplicit, lossy: true},
+		FloatFamily:          {ctx: CastExplicit, lossy: true},
+		DecimalFamily:        {ctx: CastExplicit, lossy: true},
+		StringFamily:         {ctx: CastExplicit, lossy: true},
+		CollatedStringFamily: {ctx: CastExplicit, lossy: true},
+	},
+	IntFamily: {
+		UnknownFamily:        {ctx: CastImplicit},
+		BoolFamily:           {ctx: CastExplicit},
+		IntFamily:            {ctx: CastImplicit},
+		FloatFamily:          {ctx: CastAssignment, lossy: true},
+		DecimalFamily:        {ctx: CastAssignment, lossy: true},
+		StringFamily:         {ctx: CastExplicit, lossy: true},
+		CollatedStringFamily: {ctx: CastExplicit, lossy: true},
+		TimestampFamily:      {ctx: CastExplicit, lossy: true},
+		TimestampTZFamily:    {ctx: CastExplicit, lossy: true},
+		DateFamily:           {ctx: CastExplicit},
+		IntervalFamily:       {ctx: CastExplicit, lossy: true},
+		OidFamily:            {ctx: CastAssignment},
+		BitFamily:            {ctx: CastExplicit, lossy: true},
+	},
+	FloatFamily: {
+		UnknownFamily:        {ctx: CastImplicit},
+		BoolFamily:           {ctx: CastExplicit},
+		IntFamily:            {ctx: CastImplicit, lossy: true},
+		FloatFamily:          {ctx: CastImplicit},
+		DecimalFamily:        {ctx: CastImplicit, lossy: true},
+		StringFamily:         {ctx: CastExplicit, lossy: true},
+		CollatedStringFamily: {ctx: CastExplicit, lossy: true},
+		TimestampFamily:      {ctx: CastExplicit},
+		TimestampTZFamily:    {ctx: CastExplicit},
+		DateFamily:           {ctx: CastExplicit},
+		IntervalFamily:       {ctx: CastExplicit, lossy: true},
+	},
+	DecimalFamily: {
+		UnknownFamily:        {ctx: CastImplicit},
+		BoolFamily:           {ctx: CastExplicit},
+		IntFamily:            {ctx: CastImplicit},
+		FloatFamily:          {ctx: CastAssignment},
+		DecimalFamily:        {ctx: CastImplicit},
+		StringFamily:         {ctx: CastExplicit, lossy: true},
+		CollatedStringFamily: {ctx: CastExplicit, lossy: true},
+		TimestampFamily:      {ctx: CastExplicit},
+		TimestampTZFamily:    {ctx: CastExplicit},
+		DateFamily:           {ctx: CastExplicit},
+		IntervalFamily:       {ctx: CastExplicit, lossy: true},
+	},
+	StringFamily: {
+		UnknownFamily:        {ctx: CastImplicit},
+		BoolFamily:           {ctx: CastAssignment},
+		IntFamily:            {ctx: CastAssignment},
+		FloatFamily:          {ctx: CastAssignment},
+		DecimalFamily:        {ctx: CastAssignment},
+		StringFamily:         {ctx: CastImplicit},
+		CollatedStringFamily: {ctx: CastImplicit},
+		BitFamily:            {ctx: CastAssignment},
+		ArrayFamily:          {ctx: CastAssignment},
+		TupleFamily:          {ctx: CastAssignment},
+		BytesFamily:          {ctx: CastAssignment, volatility: VolatilityStable},
+		TimestampFamily:      {ctx: CastAssignment},
+		TimestampTZFamily:    {ctx: CastAssignment, volatility: VolatilityStable},
+		IntervalFamily:       {ctx: CastAssignment},
+		UuidFamily:           {ctx: CastAssignment},
+		DateFamily:           {ctx: CastAssignment},
+		TimeFamily:           {ctx: CastAssignment},
+		OidFamily:            {ctx: CastAssignment, volatility: VolatilityStable},
+		INetFamily:           {ctx: CastAssignment},
+		JsonFamily:           {ctx: CastAssignment},
+	},
+	BytesFamily: {
+		UnknownFamily:        {ctx: CastImplicit},
+		StringFamily:         {ctx: CastExplicit, lossy: true},
+		CollatedStringFamily: {ctx: CastExplicit, lossy: true},
+		BytesFamily:          {ctx: CastImplicit},
+		UuidFamily:           {ctx: CastExplicit},
+	},
+	DateFamily: {
+		UnknownFamily:        {ctx: CastImplicit},
+		StringFamily:         {ctx: CastExplicit, volatility: VolatilityStable, lossy: true},
+		CollatedStringFamily: {ctx: CastExplicit, volatility: VolatilityStable, lossy: true},
+		DateFamily:           {ctx: CastImplicit},
+		TimestampFamily:      {ctx: CastAssignment, lossy: true},
+		TimestampTZFamily:    {ctx: CastAssignment, volatility: VolatilityStable, lossy: true},
+		IntFamily:            {ctx: CastExplicit},
+	},
+	TimeFamily: {
+		UnknownFamily:        {ctx: CastImplicit},
+		StringFamily:         {ctx: CastExplicit, volatility: VolatilityStable, lossy: true},
+		CollatedStringFamily: {ctx: CastExplicit, volatility: VolatilityStable, lossy: true},
+		TimeFamily:           {ctx: CastImplicit},
+		TimestampFamily:      {ctx: CastAssignment, lossy: true},
+		TimestampTZFamily:    {ctx: CastAssignment, volatility: VolatilityStable, lossy: true},
+		IntervalFamily:       {ctx: CastAssignment, lossy: true},
+	},
+	TimestampFamily: {
+		UnknownFamily:        {ctx: CastImplicit},
+		StringFamily:         {ctx: CastExplicit, volatility: VolatilityStable, lossy: true},
+		CollatedStringFamily: {ctx: CastExplicit, volatility: VolatilityStable, lossy: true},
+		DateFamily:           {ctx: CastImplicit},
+		TimestampFamily:      {ctx: CastImplicit},
+		TimestampTZFamily:    {ctx: CastAssignment, volatility: VolatilityStable, lossy: true},
+		IntFamily:            {ctx: CastExplicit},
+	},
+	TimestampTZFamily: {
+		UnknownFamily:        {ctx: CastImplicit},
+		StringFamily:         {ctx: CastExplicit, volatility: VolatilityStable, lossy: true},
+		CollatedStringFamily: {ctx: CastExplicit, volatility: VolatilityStable, lossy: true},
+		DateFamily:           {ctx: CastImplicit, volatility: VolatilityStable},
+		TimestampFamily:      {ctx: CastImplicit, volatility: VolatilityStable, lossy: true},
+		TimestampTZFamily:    {ctx: CastImplicit},
+		IntFamily:            {ctx: CastExplicit},
+	},
+	IntervalFamily: {
+		UnknownFamily:        {ctx: CastImplicit},
+		StringFamily:         {ctx: CastExplicit, lossy: true},
+		CollatedStringFamily: {ctx: CastExplicit, lossy: true},
+		IntFamily:            {ctx: CastExplicit},
+		TimeFamily:           {ctx: CastImplicit},
+		IntervalFamily:       {ctx: CastImplicit},
+		FloatFamily:          {ctx: CastExplicit, lossy: true},
+		DecimalFamily:        {ctx: CastExplicit, lossy: true},
+	},
+	OidFamily: {
+		UnknownFamily:        {ctx: CastImplicit},
+		StringFamily:         {ctx: CastExplicit, volatility: VolatilityStable, lossy: true},
+		CollatedStringFamily: {ctx: CastExplicit, volatility: VolatilityStable, lossy: true},
+		IntFamily:            {ctx: CastImplicit, lossy: true},
+		OidFamily:            {ctx: CastImplicit},
+	},
+	UuidFamily: {
+		UnknownFamily:        {ctx: CastImplicit},
+		StringFamily:         {ctx: CastExplicit, lossy: true},
+		CollatedStringFamily: {ctx: CastExplicit, lossy: true},
+		BytesFamily:          {ctx: CastExplicit},
+		UuidFamily:           {ctx: CastImplicit},
+	},
+	INetFamily: {
+		UnknownFamily:        {ctx: CastImplicit},
+		StringFamily:         {ctx: CastExplicit, lossy: true},
+		CollatedStringFamily: {ctx: CastExplicit, lossy: true},
+		INetFamily:           {ctx: CastImplicit},
+	},
+	ArrayFamily: {
+		UnknownFamily: {ctx: CastImplicit},
+		StringFamily:  {ctx: CastExplicit, lossy: true},
+	},
+	JsonFamily: {
+		UnknownFamily: {ctx: CastImplicit},
+		StringFamily:  {ctx: CastExplicit, lossy: true},
+		JsonFamily:    {ctx: CastImplicit},
+	},
+}
+
+// castTargets returns the row of validCasts that applies to the given target
+// family.
+func castTargets(to Family) map[Family]castInfo {
+	if to == CollatedStringFamily {
+		to = StringFamily
+	}
+	return validCasts[to]
+}
+
+// CastContext returns the most permissive context in which a value of the
+// "from" type can be cast to the "to" type, or CastNotAllowed if there is no
+// such cast. Casts between array types are allowed in the same context as the
+// cast between their element types.
+func CastContext(from, to *T) CastContextKind {
+	if from.Family() == ArrayFamily && to.Family() == ArrayFamily {
+		return CastContext(from.ArrayContents(), to.ArrayContents())
+	}
+	return castTargets(to.Family())[from.Family()].ctx
+}
+
+// CastVolatility returns the volatility of the cast from the "from" type to the
+// "to" type, and false if there is no such cast. Casts between array types have
+// the volatility of the cast between their element types.
+//
+// Computed columns, indexes and partitioning expressions can only contain
+// immutable casts, and the optimizer only folds casts of constants that are
+// immutable.
+func CastVolatility(from, to *T) (Volatility, bool) {
+	if from.Family() == ArrayFamily && to.Family() == ArrayFamily {
+		return CastVolatility(from.ArrayContents(), to.ArrayContents())
+	}
+	info, ok := castTargets(to.Family())[from.Family()]
+	if !ok {
+		return VolatilityVolatile, false
+	}
+	return info.volatility, true
+}
+
+// IsImmutableCast returns true if there is a cast from the "from" type to the
+// "to" type, and its result only depends on its input.
+func IsImmutableCast(from, to *T) bool {
+	v, ok := CastVolatility(from, to)
+	return ok && v == VolatilityImmutable
+}
+
+// IsLossyCast returns true if the cast from the "from" type to the "to" type can
+// lose information, such as the cast from FLOAT8 to INT8, or can reject some of
+// its inputs, such as the cast from STRING to INT8. Casts between types of the
+// same family are lossy if the "to" type is narrower, as for INT8 to INT2,
+// DECIMAL(10,4) to DECIMAL(10,2), VARCHAR(20) to VARCHAR(10), TIME(6) to
+// TIME(3), and TIMESTAMP to TIMESTAMP(0). Casts from other families are also
+// lossy if the "to" type is narrower than the unconstrained type of its family,
+// as for INT8 to VARCHAR(1). Casts between array types are lossy if the cast
+// between their element types is. It returns false if there is no such cast.
+//
+// The optimizer can remove the casts that are not lossy from comparisons, and
+// schema changes can change the type of a column without rewriting it.
+func IsLossyCast(from, to *T) bool {
+	if from.Family() == ArrayFamily && to.Family() == ArrayFamily {
+		return IsLossyCast(from.ArrayContents(), to.ArrayContents())
+	}
+	if from.Family() != to.Family() {
+		info, ok := castTargets(to.Family())[from.Family()]
+		if !ok {
+			return false
+		}
+		if info.lossy {
+			return true
+		}
+		// The values are converted to the unconstrained type of the family of
+		// the "to" type, which is then narrowed to the "to" type.
+		if base := unconstrainedType(to); base != nil {
+			return IsLossyCast(base, to)
+		}
+		return false
+	}
+	switch to.Family() {
+	case IntFamily:
+		return to.Width() < from.Width()
+	case FloatFamily:
+		return to.Width() == 32 && from.Width() != 32
+	case DecimalFamily:
+		if to.Precision() == 0 {
+			return false
+		}
+		return from.Precision() == 0 || to.Scale() < from.Scale() ||
+			to.Precision()-to.Scale() < from.Precision()-from.Scale()
+	case StringFamily, CollatedStringFamily, BytesFamily, BitFamily:
+		if to.Family() == CollatedStringFamily && to.Locale() != from.Locale() {
+			return true
+		}
+		return to.Width() > 0 && (from.Width() == 0 || to.Width() < from.Width())
+	case TimeFamily:
+		return to.timePrecision() < from.timePrecision()
+	case TimestampFamily, TimestampTZFamily:
+		// The default precision of timestamps is -1, which keeps microseconds.
+		toPrec, fromPrec := to.Precision(), from.Precision()
+		if toPrec < 0 {
+			toPrec = MaxTimePrecision
+		}
+		if fromPrec < 0 {
+			fromPrec = MaxTimePrecision
+		}
+		return toPrec < fromPrec
+	case IntervalFamily:
+		if q := to.diffIntervalQualifier(); q != "none" && q != from.diffIntervalQualifier() {
+			return true
+		}
+		return to.timePrecision() < from.timePrecision()
+	case TupleFamily:
+		if len(to.TupleContents()) != len(from.TupleContents()) {
+			return true
+		}
+		for i := range to.TupleContents() {
+			if IsLossyCast(&from.TupleContents()[i], &to.TupleContents()[i]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// unconstrainedType returns the type of the family of the given type that has
+// no width, precision, scale nor qualifier, or nil if the values of the family
+// cannot be constrained. Collated strings keep the locale of the type.
+func unconstrainedType(t *T) *T {
+	switch t.Family() {
+	case IntFamily:
+		return Int
+	case FloatFamily:
+		return Float
+	case DecimalFamily:
+		return Decimal
+	case StringFamily:
+		return String
+	case CollatedStringFamily:
+		return MakeCollatedString(String, t.Locale())
+	case BytesFamily:
+		return Bytes
+	case BitFamily:
+		return VarBit
+	case TimeFamily:
+		return Time
+	case TimestampFamily:
+		return Timestamp
+	case TimestampTZFamily:
+		return TimestampTZ
+	case IntervalFamily:
+		return Interval
+	}
+	return nil
+}
+
+// CanCastImplicit returns true if a value of the "from" type can be cast to
+// the "to" type without explicit syntax.
+func CanCastImplicit(from, to *T) bool {
+	return CastContext(from, to) >= CastImplicit
+}
+
+// CanCastAssignment returns true if a value of the "from" type can be assigned
+// to a column of the "to" type.
+func CanCastAssignment(from, to *T) bool {
+	return CastContext(from, to) >= CastAssignment
+}
+
+// CanCastExplicit returns true if a value of the "from" type can be cast to the
+// "to" type using explicit CAST or :: syntax.
+func CanCastExplicit(from, to *T) bool {
+	return CastContext(from, to) >= CastExplicit
+}
+
+// CheckCast returns an error with the CannotCoerce code if a value of the
+// "from" type cannot be cast to the "to" type in the given context, as in:
+//
+//   cannot cast type DATE to INT8
+//
+// If the cast is allowed in a less permissive context, the error has a hint
+// that suggests an explicit cast.
+func CheckCast(from, to *T, ctx CastContextKind) error {
+	allowed := CastContext(from, to)
+	if allowed >= ctx && allowed != CastNotAllowed {
+		return nil
+	}
+	err := pgerror.Newf(pgcode.CannotCoerce, "cannot cast type %s to %s", from.SQLString(), to.SQLString())
+	if allowed != CastNotAllowed {
+		err = errors.WithHint(err, "use an explicit cast")
+	}
+	return err
+}
+
+// ForEachCast calls the given function for every cast between type families
+// that is present in the cast matrix, in a deterministic order. Casts between
+// array types, which depend on their element types, are not included. It is
+// intended for uses such as generating a compatibility report or registering
+// per-cast telemetry.
+func ForEachCast(fn func(from, to Family, ctx CastContextKind)) {
+	var targets []Family
+	for to := range validCasts {
+		targets = append(targets, to)
+	}
+	targets = append(targets, CollatedStringFamily)
+	sort.Slice(targets, func(i, j int) bool { return targets[i] < targets[j] })
+
+	for _, to := range targets {
+		row := castTargets(to)
+		var sources []Family
+		for from := range row {
+			sources = append(sources, from)
+		}
+		sort.Slice(sources, func(i, j int) bool { return sources[i] < sources[j] })
+		for _, from := range sources {
+			fn(from, to, row[from].ctx)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+func TestCastContext(t *testing.T) {
+	testCases := []struct {
+		from *T
+		to   *T
+		ctx  CastContextKind
+	}{
+		{Unknown, Int, CastImplicit},
+		{Int4, Int, CastImplicit},
+		{Int, Float, CastImplicit},
+		{Float, Int, CastAssignment},
+		{Int, String, CastAssignment},
+		{String, Int, CastExplicit},
+		{String, MakeCollatedString(String, "en"), CastImplicit},
+		{MakeCollatedString(String, "en"), Int, CastExplicit},
+		{Timestamp, TimestampTZ, CastImplicit},
+		{TimestampTZ, Timestamp, CastAssignment},
+		{Date, Timestamp, CastImplicit},
+		{IntArray, MakeArray(Float), CastImplicit},
+		{StringArray, IntArray, CastExplicit},
+		{IntArray, String, CastAssignment},
+		{String, IntArray, CastExplicit},
+		{MakeTuple([]T{*Int}), String, CastAssignment},
+		{MakeTuple([]T{*Int}), MakeTuple([]T{*Int}), CastNotAllowed},
+		{Uuid, Int, CastNotAllowed},
+		{Jsonb, Int, CastNotAllowed},
+		{Bool, Int, CastExplicit},
+	}
+
+	for _, tc := range testCases {
+		if actual := CastContext(tc.from, tc.to); actual != tc.ctx {
+			t.Errorf("%s -> %s: expected %s, got %s", tc.from.SQLString(), tc.to.SQLString(), tc.ctx, actual)
+		}
+		if actual := CanCastImplicit(tc.from, tc.to); actual != (tc.ctx == CastImplicit) {
+			t.Errorf("%s -> %s: unexpected CanCastImplicit %t", tc.from.SQLString(), tc.to.SQLString(), actual)
+		}
+		if actual := CanCastExplicit(tc.from, tc.to); actual != (tc.ctx != CastNotAllowed) {
+			t.Errorf("%s -> %s: unexpected CanCastExplicit %t", tc.from.SQLString(), tc.to.SQLString(), actual)
+		}
+		err := CheckCast(tc.from, tc.to, CastAssignment)
+		if tc.ctx >= CastAssignment {
+			if err != nil {
+				t.Errorf("%s -> %s: unexpected error: %v", tc.from.SQLString(), tc.to.SQLString(), err)
+			}
+		} else if code := pgerror.GetPGCode(err); code != pgcode.CannotCoerce {
+			t.Errorf("%s -> %s: expected code %s, got %s", tc.from.SQLString(), tc.to.SQLString(),
+				pgcode.CannotCoerce, code)
+		}
+	}
+
+	// Every family listed in the matrix is implicitly castable to itself, and
+	// the matrix contains no disallowed entries.
+	ForEachCast(func(from, to Family, ctx CastContextKind) {
+		if from == to && to != ArrayFamily && ctx != CastImplicit {
+			t.Errorf("%s -> %s: expected implicit cast, got %s", from, to, ctx)
+		}
+		if ctx == CastNotAllowed {
+			t.Errorf("%s -> %s: unexpected disallowed entry", from, to)
+		}
+	})
+}
+
+func TestCastVolatility(t *testing.T) {
+	testCases := []struct {
+		from, to   *T
+		volatility Volatility
+		ok         bool
+		lossy      bool
+	}{
+		{Int, Float, VolatilityImmutable, true, true},
+		{Int2, Int, VolatilityImmutable, true, false},
+		{Int, Int2, VolatilityImmutable, true, true},
+		{Float, Float4, VolatilityImmutable, true, true},
+		{Float4, Float, VolatilityImmutable, true, false},
+		{Int, Decimal, VolatilityImmutable, true, false},
+		{MakeDecimal(10, 4), MakeDecimal(10, 2), VolatilityImmutable, true, true},
+		{MakeDecimal(10, 2), MakeDecimal(12, 2), VolatilityImmutable, true, false},
+		{Decimal, MakeDecimal(10, 2), VolatilityImmutable, true, true},
+		{MakeVarChar(10), MakeVarChar(20), VolatilityImmutable, true, false},
+		{MakeVarChar(20), MakeVarChar(10), VolatilityImmutable, true, true},
+		{String, MakeVarChar(10), VolatilityImmutable, true, true},
+		{Int, String, VolatilityImmutable, true, false},
+		{String, Int, VolatilityImmutable, true, true},
+		{Bytes, String, VolatilityStable, true, false},
+		{String, Date, VolatilityStable, true, true},
+		{MakeCollatedString(String, "en"), Timestamp, VolatilityStable, true, true},
+		{Timestamp, TimestampTZ, VolatilityStable, true, true},
+		{TimestampTZ, String, VolatilityStable, true, false},
+		{Date, Timestamp, VolatilityImmutable, true, false},
+		{Timestamp, Date, VolatilityImmutable, true, true},
+		{Timestamp, MakeTimestamp(0), VolatilityImmutable, true, true},
+		{Timestamp, MakeTimestamp(6), VolatilityImmutable, true, false},
+		{MakeTime(6), MakeTime(3), VolatilityImmutable, true, true},
+		{MakeTime(3), Time, VolatilityImmutable, true, false},
+		{Interval, MakeInterval(IntervalTypeMetadata{
+			DurationField: IntervalDurationField{DurationType: IntervalDurationType_DAY},
+		}), VolatilityImmutable, true, true},
+		{IntArray, MakeArray(Float), VolatilityImmutable, true, true},
+		{StringArray, MakeArray(TimestampTZ), VolatilityStable, true, true},
+		{MakeArray(Int2), IntArray, VolatilityImmutable, true, false},
+		{Uuid, Int, VolatilityVolatile, false, false},
+		// Casts from other families are lossy if the target type is narrower
+		// than the unconstrained type of its family.
+		{Int, MakeVarChar(1), VolatilityImmutable, true, true},
+		{Bytes, MakeVarChar(10), VolatilityStable, true, true},
+		{Int, MakeDecimal(5, 2), VolatilityImmutable, true, true},
+		{Int, Float4, VolatilityImmutable, true, true},
+		{Time, MakeInterval(IntervalTypeMetadata{
+			DurationField: IntervalDurationField{DurationType: IntervalDurationType_HOUR},
+		}), VolatilityImmutable, true, true},
+		{Time, Interval, VolatilityImmutable, true, false},
+		{String, MakeCollatedString(String, "en"), VolatilityImmutable, true, false},
+		{MakeArray(Int), MakeArray(MakeVarChar(1)), VolatilityImmutable, true, true},
+	}
+	for _, tc := range testCases {
+		volatility, ok := CastVolatility(tc.from, tc.to)
+		if volatility != tc.volatility || ok != tc.ok {
+			t.Errorf("%s -> %s: expected %s, %t, got %s, %t", tc.from.SQLString(), tc.to.SQLString(),
+				tc.volatility, tc.ok, volatility, ok)
+		}
+		if actual := IsImmutableCast(tc.from, tc.to); actual != (tc.ok && tc.volatility == VolatilityImmutable) {
+			t.Errorf("%s -> %s: unexpected IsImmutableCast %t", tc.from.SQLString(), tc.to.SQLString(), actual)
+		}
+		if actual := IsLossyCast(tc.from, tc.to); actual != tc.lossy {
+			t.Errorf("%s -> %s: expected lossy %t, got %t", tc.from.SQLString(), tc.to.SQLString(), tc.lossy, actual)
+		}
+	}
+
+	// Every cast in the matrix has a volatility, and no cast between types of
+	// the same family is lossy.
+	for to, row := range validCasts {
+		for from, info := range row {
+			if info.volatility.PgProvolatile() == 'v' {
+				t.Errorf("%s -> %s: unexpected volatility %s", from, to, info.volatility)
+			}
+			if from == to && info.lossy {
+				t.Errorf("%s -> %s: unexpected lossy cast", from, to)
+			}
+		}
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/lib/pq/oid"
+)
+
+// ClusterVersion is a version of the cluster, such as 19.1-5. It has the same
+// components as roachpb.Version, and the two convert to one another, so that
+// the types package does not need to depend on roachpb.
+type ClusterVersion struct {
+	Major    int32
+	Minor    int32
+	Patch    int32
+	Unstable int32
+}
+
+// ExtendedTypesVersion is the cluster version at which the types and type
+// modifiers that the nodes running 19.1 cannot decode can be stored in
+// descriptors. It is the same as cluster.VersionExtendedTypes.
+var ExtendedTypesVersion = ClusterVersion{Major: 19, Minor: 1, Unstable: 5}
+
+// Less returns true if this version is older than the other version.
+func (v ClusterVersion) Less(other ClusterVersion) bool {
+	if v.Major != other.Major {
+		return v.Major < other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor < other.Minor
+	}
+	if v.Patch != other.Patch {
+		return v.Patch < other.Patch
+	}
+	return v.Unstable < other.Unstable
+}
+
+// String returns the version in the format of roachpb.Version.
+func (v ClusterVersion) String() string {
+	if v.Unstable == 0 {
+		return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+	}
+	return fmt.Sprintf("%d.%d-%d", v.Major, v.Minor, v.Unstable)
+}
+
+// familyMinimumVersions contains the families that can only be used once the
+// cluster has reached a minimum version. The families that are not listed
+// here can be used at any version.
+var familyMinimumVersions = map[Family]ClusterVersion{
+	VoidFamily:    ExtendedTypesVersion,
+	TriggerFamily: ExtendedTypesVersion,
+}
+
+// oidMinimumVersions contains the types of the other families that can only
+// be used once the cluster has reached a minimum version, by OID.
+var oidMinimumVersions = map[oid.Oid]ClusterVersion{
+	T_jsonpath:      ExtendedTypesVersion,
+	oid.T_refcursor: ExtendedTypesVersion,
+	oid.T_pg_lsn:    ExtendedTypesVersion,
+	oid.T_macaddr:   ExtendedTypesVersion,
+	T_macaddr8:      ExtendedTypesVersion,
+}
+
+// typeModifierFeature is a type modifier that can only be used once the
+// cluster has reached a minimum version.
+type typeModifierFeature struct {
+	name       string
+	minVersion ClusterVersion
+	// usedBy returns true if the given type uses the modifier. It is not called
+	// for the nested types of arrays and tuples, which are checked separately.
+	usedBy func(t *T) bool
+}
+
+var typeModifierFeatures = []typeModifierFeature{
+	{
+		name:       "TIME and INTERVAL precision",
+		minVersion: ExtendedTypesVersion,
+		usedBy: func(t *T) bool {
+			// TIME(6) could already be stored by previous versions, which did not
+			// set TimePrecisionIsSet for it (see upgradeType).
+			if t.Family() == TimeFamily && t.Precision() == 6 {
+				return false
+			}
+			return t.InternalType.TimePrecisionIsSet
+		},
+	},
+	{
+		name:       "INTERVAL qualifiers",
+		minVersion: ExtendedTypesVersion,
+		usedBy:     func(t *T) bool { return t.InternalType.IntervalDurationField != nil },
+	},
+	{
+		name:       "BYTES maximum length",
+		minVersion: ExtendedTypesVersion,
+		usedBy:     func(t *T) bool { return t.Family() == BytesFamily && t.Width() > 0 },
+	},
+	{
+		name:       "string widths measured in bytes",
+		minVersion: ExtendedTypesVersion,
+		usedBy:     func(t *T) bool { return t.WidthUnit() != StringWidthUnit_CHARACTERS },
+	},
+}
+
+// MinimumClusterVersion returns the oldest cluster version at which the type
+// can be stored in descriptors, which is the version at which every node of the
+// cluster can decode it. It takes into account the family and OID of the type,
+// its modifiers (such as TIME precision), and its nested types.
+func (t *T) MinimumClusterVersion() ClusterVersion {
+	var min ClusterVersion
+	t.forEachUnsupportedFeature(ClusterVersion{}, func(_ string, v ClusterVersion) bool {
+		if min.Less(v) {
+			min = v
+		}
+		return true
+	})
+	return min
+}
+
+// IsSupportedAt returns true if the type can be stored in descriptors at the
+// given cluster version. See MinimumClusterVersion.
+func (t *T) IsSupportedAt(v ClusterVersion) bool {
+	return !v.Less(t.MinimumClusterVersion())
+}
+
+// CheckSupportedAt returns an error with the FeatureNotSupported code if the
+// type cannot be stored in descriptors at the given cluster version. The error
+// names the first feature of the type that is not supported. See
+// MinimumClusterVersion.
+func (t *T) CheckSupportedAt(v ClusterVersion) error {
+	var err error
+	t.forEachUnsupportedFeature(v, func(feature string, min ClusterVersion) bool {
+		err = pgerror.Newf(pgcode.FeatureNotSupported,
+			"cluster version does not support %s (>= %s required) used by type %s",
+			feature, min, t.SQLString())
+		return false
+	})
+	return err
+}
+
+// forEachUnsupportedFeature calls fn with the name and minimum version of each
+// feature used by the type, or by its nested types, that is not supported at
+// the given cluster version, until fn returns false. It returns false if fn
+// did.
+func (t *T) forEachUnsupportedFeature(v ClusterVersion, fn func(string, ClusterVersion) bool) bool {
+	if min, ok := familyMinimumVersions[t.Family()]; ok && v.Less(min) {
+		if !fn(familyName(t.Family())+" types", min) {
+			return false
+		}
+	} else if min, ok := oidMinimumVersions[t.Oid()]; ok && v.Less(min) {
+		if !fn(t.PGName()+" types", min) {
+			return false
+		}
+	}
+	for i := range typeModifierFeatures {
+		f := &typeModifierFeatures[i]
+		if v.Less(f.minVersion) && f.usedBy(t) {
+			if !fn(f.name, f.minVersion) {
+				return false
+			}
+		}
+	}
+	switch t.Family() {
+	case ArrayFamily:
+		if t.ArrayContents() != nil {
+			return t.ArrayContents().forEachUnsupportedFeature(v, fn)
+		}
+	case TupleFamily:
+		for i := range t.TupleContents() {
+			if !t.TupleContents()[i].forEachUnsupportedFeature(v, fn) {
+				return false
+			}
+		}
+	}
+	return true
+}
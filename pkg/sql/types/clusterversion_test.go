@@ -0,0 +1,81 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+func TestMinimumClusterVersion(t *testing.T) {
+	v19_1 := ClusterVersion{Major: 19, Minor: 1}
+	if !v19_1.Less(ExtendedTypesVersion) || ExtendedTypesVersion.Less(v19_1) {
+		t.Errorf("expected %s to be older than %s", v19_1, ExtendedTypesVersion)
+	}
+	if s := ExtendedTypesVersion.String(); s != "19.1-5" {
+		t.Errorf("expected 19.1-5, got %s", s)
+	}
+
+	testData := []struct {
+		typ      *T
+		expected ClusterVersion
+		// feature is the feature named in the error for 19.1, if any.
+		feature string
+	}{
+		{Int, ClusterVersion{}, ""},
+		{MakeVarChar(10), ClusterVersion{}, ""},
+		{Timestamp, ClusterVersion{}, ""},
+		{MakeArray(MakeDecimal(10, 2)), ClusterVersion{}, ""},
+		{PGLSN, ExtendedTypesVersion, "pg_lsn types"},
+		{MakeTime(3), ExtendedTypesVersion, "TIME and INTERVAL precision"},
+		// TIME(6) can be decoded by 19.1 nodes.
+		{MakeTime(6), ClusterVersion{}, ""},
+		// TIMESTAMP(0) and TIMESTAMP(6) can be decoded by 19.1 nodes.
+		{MakeTimestamp(0), ClusterVersion{}, ""},
+		{MakeTimestampTZ(6), ClusterVersion{}, ""},
+		{MakeInterval(IntervalTypeMetadata{
+			DurationField: IntervalDurationField{DurationType: IntervalDurationType_HOUR},
+		}), ExtendedTypesVersion, "INTERVAL qualifiers"},
+		{MakeStringWithWidthUnit(MakeVarChar(10), StringWidthUnit_BYTES), ExtendedTypesVersion,
+			"string widths measured in bytes"},
+		{MakeArray(MACAddr8), ExtendedTypesVersion, "macaddr8 types"},
+		{MakeTuple([]T{*Int, *MakeTime(0)}), ExtendedTypesVersion, "TIME and INTERVAL precision"},
+	}
+	for _, tc := range testData {
+		if actual := tc.typ.MinimumClusterVersion(); actual != tc.expected {
+			t.Errorf("%s: expected minimum version %s, got %s", tc.typ.DebugString(), tc.expected, actual)
+		}
+		if !tc.typ.IsSupportedAt(ExtendedTypesVersion) {
+			t.Errorf("%s: expected support at %s", tc.typ.DebugString(), ExtendedTypesVersion)
+		}
+		if err := tc.typ.CheckSupportedAt(ExtendedTypesVersion); err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.typ.DebugString(), err)
+		}
+		err := tc.typ.CheckSupportedAt(v19_1)
+		if tc.feature == "" {
+			if err != nil || !tc.typ.IsSupportedAt(v19_1) {
+				t.Errorf("%s: expected support at %s, got %v", tc.typ.DebugString(), v19_1, err)
+			}
+			continue
+		}
+		if tc.typ.IsSupportedAt(v19_1) {
+			t.Errorf("%s: expected no support at %s", tc.typ.DebugString(), v19_1)
+		}
+		if code := pgerror.GetPGCode(err); code != pgcode.FeatureNotSupported {
+			t.Errorf("%s: expected a feature not supported error, got %v", tc.typ.DebugString(), err)
+		} else if !strings.Contains(err.Error(), tc.feature) {
+			t.Errorf("%s: expected the error to mention %q, got %v", tc.typ.DebugString(), tc.feature, err)
+		}
+	}
+}
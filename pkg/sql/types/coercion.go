@@ -0,0 +1,163 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// CoercionProfile describes how the values of some types are parsed from the
+// text formats of IMPORT and COPY, where they may be written differently than
+// in SQL. The values that are not covered by the profile are parsed with the
+// input syntax of SQL (see tree.ParseStringAsWithProfile).
+//
+// The zero value of CoercionProfile, like DefaultCoercionProfile, only accepts
+// the input syntax of SQL.
+type CoercionProfile struct {
+	// Name identifies the profile in the coercion_profile option of IMPORT.
+	Name string
+	// TrueSpellings and FalseSpellings are the spellings of the BOOL values,
+	// which are matched case-insensitively. If either is set, they replace the
+	// input syntax of SQL, which accepts true, yes, 1 and their prefixes.
+	TrueSpellings  []string
+	FalseSpellings []string
+	// DateLayouts are the layouts of DATE values, in the format of the time
+	// package, and TimestampLayouts those of TIMESTAMP and TIMESTAMPTZ values.
+	// They are tried in order before the input syntax of SQL.
+	DateLayouts      []string
+	TimestampLayouts []string
+	// DisallowDecimalExponents rejects the DECIMAL values written in exponent
+	// notation, such as 1.5e3, which are otherwise accepted.
+	DisallowDecimalExponents bool
+}
+
+var (
+	// DefaultCoercionProfile only accepts the input syntax of SQL. It is used
+	// by COPY, and by IMPORT unless the coercion_profile option is set.
+	DefaultCoercionProfile = CoercionProfile{Name: "default"}
+
+	// StrictCoercionProfile only accepts the canonical spellings of the BOOL
+	// values, and rejects DECIMAL values in exponent notation, so that values
+	// that are ambiguous or lossy for some producers are reported as errors.
+	StrictCoercionProfile = CoercionProfile{
+		Name:                     "strict",
+		TrueSpellings:            []string{"true"},
+		FalseSpellings:           []string{"false"},
+		DisallowDecimalExponents: true,
+	}
+
+	// MySQLCoercionProfile accepts the values written by the SELECT ... INTO
+	// OUTFILE statement of MySQL, which writes BOOL values as integers.
+	MySQLCoercionProfile = CoercionProfile{
+		Name:             "mysql",
+		TrueSpellings:    []string{"1", "true"},
+		FalseSpellings:   []string{"0", "false"},
+		DateLayouts:      []string{"2006-01-02"},
+		TimestampLayouts: []string{"2006-01-02 15:04:05", "2006-01-02 15:04:05.999999"},
+	}
+
+	// USCoercionProfile accepts dates in the month/day/year order that is
+	// common in spreadsheets exported in the US, such as 12/31/2019.
+	USCoercionProfile = CoercionProfile{
+		Name:             "us",
+		DateLayouts:      []string{"1/2/2006"},
+		TimestampLayouts: []string{"1/2/2006 15:04:05", "1/2/2006 3:04:05 PM", "1/2/2006 15:04"},
+	}
+)
+
+var coercionProfiles = map[string]*CoercionProfile{
+	DefaultCoercionProfile.Name: &DefaultCoercionProfile,
+	StrictCoercionProfile.Name:  &StrictCoercionProfile,
+	MySQLCoercionProfile.Name:   &MySQLCoercionProfile,
+	USCoercionProfile.Name:      &USCoercionProfile,
+}
+
+// CoercionProfileByName returns the coercion profile with the given name, which
+// is matched case-insensitively. It returns an error with the
+// InvalidParameterValue code if there is no such profile.
+func CoercionProfileByName(name string) (*CoercionProfile, error) {
+	if p, ok := coercionProfiles[strings.ToLower(name)]; ok {
+		return p, nil
+	}
+	return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+		"unknown coercion profile %q; valid profiles are: %s",
+		name, strings.Join(CoercionProfileNames(), ", "))
+}
+
+// CoercionProfileNames returns the sorted names of the coercion profiles.
+func CoercionProfileNames() []string {
+	names := make([]string, 0, len(coercionProfiles))
+	for name := range coercionProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// HasBoolSpellings returns true if the profile replaces the input syntax of
+// SQL for BOOL values.
+func (p *CoercionProfile) HasBoolSpellings() bool {
+	return len(p.TrueSpellings) > 0 || len(p.FalseSpellings) > 0
+}
+
+// ParseBool parses a BOOL value spelled as one of the spellings of the
+// profile. It returns false if the value has none of them. The value is
+// trimmed of surrounding spaces, like in SQL.
+func (p *CoercionProfile) ParseBool(s string) (val bool, ok bool) {
+	s = strings.TrimSpace(s)
+	for _, spelling := range p.TrueSpellings {
+		if strings.EqualFold(s, spelling) {
+			return true, true
+		}
+	}
+	for _, spelling := range p.FalseSpellings {
+		if strings.EqualFold(s, spelling) {
+			return false, true
+		}
+	}
+	return false, false
+}
+
+// ParseTime parses a value of the given DATE, TIMESTAMP or TIMESTAMPTZ type
+// with the layouts of the profile, in the given location. It returns false if
+// the value matches none of them, or if the type is not one of these types.
+func (p *CoercionProfile) ParseTime(t *T, s string, loc *time.Location) (time.Time, bool) {
+	var layouts []string
+	switch t.Family() {
+	case DateFamily:
+		layouts = p.DateLayouts
+	case TimestampFamily, TimestampTZFamily:
+		layouts = p.TimestampLayouts
+	}
+	s = strings.TrimSpace(s)
+	for _, layout := range layouts {
+		if tm, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return tm, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// CheckDecimal returns an error with the InvalidTextRepresentation code if the
+// profile does not accept the given DECIMAL value, before it is parsed with the
+// input syntax of SQL.
+func (p *CoercionProfile) CheckDecimal(s string) error {
+	if p.DisallowDecimalExponents && strings.ContainsAny(s, "eE") {
+		return pgerror.Newf(pgcode.InvalidTextRepresentation,
+			"decimal value %q cannot use exponent notation with coercion profile %s", s, p.Name)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+func TestCoercionProfile(t *testing.T) {
+	if names := CoercionProfileNames(); !reflect.DeepEqual(names, []string{"default", "mysql", "strict", "us"}) {
+		t.Errorf("unexpected coercion profiles %v", names)
+	}
+	if p, err := CoercionProfileByName("MySQL"); err != nil || p != &MySQLCoercionProfile {
+		t.Errorf("expected the mysql profile, got %v (%v)", p, err)
+	}
+	if _, err := CoercionProfileByName("oracle"); pgerror.GetPGCode(err) != pgcode.InvalidParameterValue {
+		t.Errorf("expected an invalid parameter error, got %v", err)
+	}
+
+	boolTestData := []struct {
+		profile *CoercionProfile
+		s       string
+		ok      bool
+		val     bool
+	}{
+		{&DefaultCoercionProfile, "true", false, false},
+		{&StrictCoercionProfile, "TRUE", true, true},
+		{&StrictCoercionProfile, " false ", true, false},
+		{&StrictCoercionProfile, "yes", false, false},
+		{&StrictCoercionProfile, "1", false, false},
+		{&MySQLCoercionProfile, "1", true, true},
+		{&MySQLCoercionProfile, "0", true, false},
+		{&MySQLCoercionProfile, "2", false, false},
+	}
+	for _, tc := range boolTestData {
+		if tc.profile.HasBoolSpellings() != (tc.profile != &DefaultCoercionProfile) {
+			t.Errorf("unexpected HasBoolSpellings for profile %s", tc.profile.Name)
+		}
+		val, ok := tc.profile.ParseBool(tc.s)
+		if ok != tc.ok || val != tc.val {
+			t.Errorf("%s: expected ParseBool(%q) = %t, %t, got %t, %t",
+				tc.profile.Name, tc.s, tc.val, tc.ok, val, ok)
+		}
+	}
+
+	timeTestData := []struct {
+		profile  *CoercionProfile
+		typ      *T
+		s        string
+		expected time.Time
+	}{
+		{&USCoercionProfile, Date, "12/31/2019", time.Date(2019, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{&USCoercionProfile, Timestamp, "1/2/2019 3:04:05 PM", time.Date(2019, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{&MySQLCoercionProfile, TimestampTZ, "2019-01-02 15:04:05.5", time.Date(2019, 1, 2, 15, 4, 5, 5e8, time.UTC)},
+		// The layouts of the profile only apply to the types they are for.
+		{&USCoercionProfile, Time, "12/31/2019", time.Time{}},
+		{&USCoercionProfile, Date, "2019-12-31", time.Time{}},
+		{&DefaultCoercionProfile, Date, "2019-12-31", time.Time{}},
+	}
+	for _, tc := range timeTestData {
+		tm, ok := tc.profile.ParseTime(tc.typ, tc.s, time.UTC)
+		if ok != !tc.expected.IsZero() || !tm.Equal(tc.expected) {
+			t.Errorf("%s: expected ParseTime(%s, %q) = %s, got %s (%t)",
+				tc.profile.Name, tc.typ.SQLString(), tc.s, tc.expected, tm, ok)
+		}
+	}
+
+	if err := DefaultCoercionProfile.CheckDecimal("1.5e3"); err != nil {
+		t.Errorf("expected the default profile to accept exponents, got %v", err)
+	}
+	if err := StrictCoercionProfile.CheckDecimal("1.5"); err != nil {
+		t.Errorf("expected the strict profile to accept 1.5, got %v", err)
+	}
+	if err := StrictCoercionProfile.CheckDecimal("1.5E3"); pgerror.GetPGCode(err) != pgcode.InvalidTextRepresentation {
+		t.Errorf("expected an invalid text representation error, got %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// Collator computes the collation keys of strings under a locale. Collation
+// keys compare bytewise in the order of the strings under the locale, and are
+// used as the key encoding of collated strings. A Collator does not need to be
+// safe for concurrent use.
+type Collator interface {
+	// AppendKey appends the collation key of s to buf and returns the extended
+	// slice.
+	AppendKey(buf []byte, s string) []byte
+}
+
+// CollationProvider creates the Collators of locales. It is called once for
+// each Collator that is used concurrently, so it must return a new Collator on
+// each call.
+type CollationProvider func(tag language.Tag) (Collator, error)
+
+// DefaultCollationProvider creates Collators that implement the Unicode
+// Collation Algorithm using the golang.org/x/text/collate package.
+func DefaultCollationProvider(tag language.Tag) (Collator, error) {
+	return &textCollator{c: collate.New(tag)}, nil
+}
+
+// textCollator is the Collator created by DefaultCollationProvider.
+type textCollator struct {
+	c   *collate.Collator
+	buf collate.Buffer
+}
+
+// AppendKey is part of the Collator interface.
+func (c *textCollator) AppendKey(buf []byte, s string) []byte {
+	buf = append(buf, c.c.KeyFromString(&c.buf, s)...)
+	c.buf.Reset()
+	return buf
+}
+
+// collators caches the Collators of each locale, since creating a Collator is
+// expensive. The Collators of a locale are pooled, since they are not safe for
+// concurrent use.
+var collators struct {
+	syncutil.Mutex
+	provider CollationProvider
+	pools    map[string]*sync.Pool
+}
+
+func init() {
+	collators.provider = DefaultCollationProvider
+}
+
+// SetCollationProvider replaces the provider of the Collators used by
+// CollationKey and DecodeCollationKey, and returns the previous provider. The
+// Collators created by the previous provider are discarded. It is meant to be
+// called during initialization, or by tests.
+func SetCollationProvider(provider CollationProvider) CollationProvider {
+	collators.Lock()
+	defer collators.Unlock()
+	prev := collators.provider
+	collators.provider = provider
+	collators.pools = nil
+	return prev
+}
+
+// collatorEntry is an entry of the pool of Collators of a locale.
+type collatorEntry struct {
+	Collator
+	// scratch is used by DecodeCollationKey to compute keys.
+	scratch []byte
+}
+
+// getCollator returns a Collator of the locale of the type, which must be put
+// back into the returned pool once it is no longer used. It returns an error if
+// the type is not a collated string type with a locale.
+func (t *T) getCollator() (*collatorEntry, *sync.Pool, error) {
+	tag, err := t.LocaleTag()
+	if err != nil {
+		return nil, nil, err
+	}
+	collators.Lock()
+	defer collators.Unlock()
+	if pool, ok := collators.pools[t.Locale()]; ok {
+		return pool.Get().(*collatorEntry), pool, nil
+	}
+
+	// Create the first Collator of the locale eagerly, so that errors are
+	// returned to the caller rather than by the pool.
+	provider := collators.provider
+	c, err := provider(tag)
+	if err != nil {
+		return nil, nil, pgerror.Wrapf(err, pgcode.InvalidParameterValue,
+			"cannot create collator for locale %s", t.Locale())
+	}
+	pool := &sync.Pool{New: func() interface{} {
+		c, err := provider(tag)
+		if err != nil {
+			panic(errors.NewAssertionErrorWithWrappedErrf(err,
+				"cannot create collator for locale %s", tag))
+		}
+		return &collatorEntry{Collator: c}
+	}}
+	if collators.pools == nil {
+		collators.pools = make(map[string]*sync.Pool)
+	}
+	collators.pools[t.Locale()] = pool
+	return &collatorEntry{Collator: c}, pool, nil
+}
+
+// CollationKey appends the collation key of s under the locale of this
+// collated string type to buf, and returns the extended slice. The Collators
+// of each locale are created by the CollationProvider and reused across calls.
+// It returns an error if the type is not in the CollatedStringFamily, or if
+// it is the wildcard collated string type, which has no locale.
+func (t *T) CollationKey(buf []byte, s string) ([]byte, error) {
+	c, pool, err := t.getCollator()
+	if err != nil {
+		return nil, err
+	}
+	defer pool.Put(c)
+	return c.AppendKey(buf, s), nil
+}
+
+// DecodeCollationKey is the inverse of CollationKey. Since different strings
+// can have the same collation key, such as strings that only differ in case
+// under a case-insensitive locale, collated strings are encoded by splitting
+// them into their collation key, which is stored in the key of the KV pair, and
+// their contents, which are stored in the value. DecodeCollationKey returns
+// the string that results from a key and the contents that were stored with
+// it, or an error with the DataCorrupted code if the key is not the collation
+// key of the contents.
+func (t *T) DecodeCollationKey(key []byte, contents string) (string, error) {
+	c, pool, err := t.getCollator()
+	if err != nil {
+		return "", err
+	}
+	defer pool.Put(c)
+	c.scratch = c.AppendKey(c.scratch[:0], contents)
+	if !bytes.Equal(c.scratch, key) {
+		return "", pgerror.Newf(pgcode.DataCorrupted,
+			"collation key %x does not match the contents %q of collated string type %s",
+			key, contents, t.SQLString())
+	}
+	return contents, nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/errors"
+	"golang.org/x/text/language"
+)
+
+// upperCollator is a Collator whose keys are the upper case form of strings.
+type upperCollator struct{}
+
+func (upperCollator) AppendKey(buf []byte, s string) []byte {
+	return append(buf, strings.ToUpper(s)...)
+}
+
+func TestCollationKey(t *testing.T) {
+	de := MakeCollatedString(String, "de")
+	// Under the default provider, "ä" sorts between "a" and "b" in German.
+	var keys [][]byte
+	for _, s := range []string{"a", "ä", "b"} {
+		key, err := de.CollationKey([]byte("prefix"), s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.HasPrefix(key, []byte("prefix")) {
+			t.Fatalf("expected key to be appended, got %x", key)
+		}
+		if decoded, err := de.DecodeCollationKey(key[len("prefix"):], s); err != nil || decoded != s {
+			t.Errorf("expected %q, got %q (%v)", s, decoded, err)
+		}
+		keys = append(keys, key)
+	}
+	if bytes.Compare(keys[0], keys[1]) >= 0 || bytes.Compare(keys[1], keys[2]) >= 0 {
+		t.Errorf("expected keys in collation order, got %x", keys)
+	}
+	if _, err := de.DecodeCollationKey(keys[0], "b"); pgerror.GetPGCode(err) != pgcode.DataCorrupted {
+		t.Errorf("expected code %s, got %v", pgcode.DataCorrupted, err)
+	}
+
+	for _, typ := range []*T{String, AnyCollatedString} {
+		if _, err := typ.CollationKey(nil, "a"); err == nil {
+			t.Errorf("%s: expected error", typ.SQLString())
+		}
+	}
+
+	// Plug in an alternate provider, which replaces the cached Collators.
+	prev := SetCollationProvider(func(tag language.Tag) (Collator, error) {
+		if tag == language.English {
+			return nil, errors.New("unsupported locale")
+		}
+		return upperCollator{}, nil
+	})
+	defer SetCollationProvider(prev)
+	if key, err := de.CollationKey(nil, "abc"); err != nil || string(key) != "ABC" {
+		t.Errorf("expected ABC, got %q (%v)", key, err)
+	}
+	if _, err := de.DecodeCollationKey([]byte("ABC"), "aBc"); err != nil {
+		t.Error(err)
+	}
+	_, err := MakeCollatedString(String, "en").CollationKey(nil, "a")
+	if code := pgerror.GetPGCode(err); code != pgcode.InvalidParameterValue {
+		t.Errorf("expected code %s, got %v", pgcode.InvalidParameterValue, err)
+	}
+}
@@ -0,0 +1,327 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"encoding/binary"
+
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
+)
+
+// The compact encoding of a type is an alternative to its protobuf encoding
+// for types that are sent between nodes, such as the types of the columns of
+// wide tables that flow through DistSQL. In the protobuf encoding, every
+// nested type is encoded in full, and prefixed with its length, even if the
+// same type is repeated in many fields of a tuple. The compact encoding instead
+// consists of a dictionary of the distinct types that occur in the type, in
+// which ARRAY and TUPLE types refer to their nested types by their index in
+// the dictionary.
+//
+// The encoding starts with a version byte and the number of dictionary
+// entries. Each entry starts with its kind, which is one of:
+//
+//   compactScalar: the length and protobuf encoding of a scalar type.
+//   compactArray:  the OID of the array type, and the index of its element type.
+//   compactTuple:  the OID of the tuple type, the number of fields and the
+//                  index of each field type, and the number of labels and the
+//                  length and bytes of each label.
+//
+// Entries only refer to the entries before them, and the last entry is the
+// encoded type. The numbers are unsigned varints.
+//
+// The compact encoding is only meant for data in flight: only nodes that run
+// the same version can decode it, so types that are persisted, such as the
+// types in descriptors, must use the protobuf encoding.
+const compactEncodingVersion = 1
+
+// These are the kinds of entries in the compact encoding.
+const (
+	compactScalar = iota
+	compactArray
+	compactTuple
+)
+
+// MarshalCompact appends the compact encoding of the type to b, and returns the
+// extended slice. Use UnmarshalCompact to decode it.
+func (t *T) MarshalCompact(b []byte) ([]byte, error) {
+	e := compactEncoder{refs: make(map[string]uint64)}
+	if _, err := e.encode(t); err != nil {
+		return nil, err
+	}
+	b = append(b, compactEncodingVersion)
+	b = appendUvarint(b, e.numEntries)
+	return append(b, e.entries...), nil
+}
+
+// compactEncoder builds the dictionary of the compact encoding of a type.
+type compactEncoder struct {
+	// entries is the encoding of the dictionary entries.
+	entries    []byte
+	numEntries uint64
+	// refs maps the encoding of each entry to its index, so that types that
+	// occur several times are only added once.
+	refs map[string]uint64
+	// scratch is used to build the encoding of an entry.
+	scratch []byte
+}
+
+// encode adds the given type and its nested types to the dictionary if they are
+// not already in it, and returns the index of the entry of the type.
+func (e *compactEncoder) encode(t *T) (uint64, error) {
+	switch t.Family() {
+	case ArrayFamily:
+		elem, err := e.encode(t.ArrayContents())
+		if err != nil {
+			return 0, err
+		}
+		entry := append(e.scratch[:0], compactArray)
+		entry = appendUvarint(entry, uint64(t.Oid()))
+		entry = appendUvarint(entry, elem)
+		return e.add(entry), nil
+
+	case TupleFamily:
+		contents := t.TupleContents()
+		fields := make([]uint64, len(contents))
+		for i := range contents {
+			ref, err := e.encode(&contents[i])
+			if err != nil {
+				return 0, err
+			}
+			fields[i] = ref
+		}
+		entry := append(e.scratch[:0], compactTuple)
+		entry = appendUvarint(entry, uint64(t.Oid()))
+		entry = appendUvarint(entry, uint64(len(fields)))
+		for _, ref := range fields {
+			entry = appendUvarint(entry, ref)
+		}
+		labels := t.TupleLabels()
+		entry = appendUvarint(entry, uint64(len(labels)))
+		for _, label := range labels {
+			entry = appendUvarint(entry, uint64(len(label)))
+			entry = append(entry, label...)
+		}
+		return e.add(entry), nil
+
+	default:
+		entry := append(e.scratch[:0], compactScalar)
+		entry = appendUvarint(entry, uint64(t.Size()))
+		entry, err := t.MarshalAppend(entry)
+		if err != nil {
+			return 0, err
+		}
+		return e.add(entry), nil
+	}
+}
+
+// add adds the given entry to the dictionary unless an identical entry is
+// already in it, and returns the index of the entry.
+func (e *compactEncoder) add(entry []byte) uint64 {
+	e.scratch = entry
+	if ref, ok := e.refs[string(entry)]; ok {
+		return ref
+	}
+	ref := e.numEntries
+	e.refs[string(entry)] = ref
+	e.entries = append(e.entries, entry...)
+	e.numEntries++
+	return ref
+}
+
+// UnmarshalCompact decodes a type from its compact encoding, which was created
+// by MarshalCompact. Like Unmarshal, it returns an UnmarshalLimitError if the
+// type exceeds MaxTypeNestingDepth or MaxTypeSize, where the size is that of
+// the type once its repeated nested types are expanded.
+func UnmarshalCompact(data []byte) (*T, error) {
+	if len(data) > MaxTypeSize {
+		return nil, &UnmarshalLimitError{Limit: "size in bytes", Max: MaxTypeSize}
+	}
+	d := compactDecoder{data: data}
+	if version := d.byte(); version != compactEncodingVersion {
+		if d.err != nil {
+			return nil, d.err
+		}
+		return nil, errors.Errorf("unknown compact type encoding version %d", version)
+	}
+	numEntries := d.uvarint()
+	if d.err == nil && (numEntries == 0 || numEntries > uint64(len(d.data))) {
+		d.fail("invalid number of entries %d", numEntries)
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	// For each entry, depths contains its nesting depth, and sizes the size of
+	// the entry once the entries it refers to are expanded.
+	entries := make([]*T, 0, numEntries)
+	depths := make([]int, 0, numEntries)
+	sizes := make([]int, 0, numEntries)
+	for uint64(len(entries)) < numEntries {
+		start := len(d.data)
+		var typ *T
+		depth, size := 0, 0
+		// ref decodes the index of an entry that the current entry refers to.
+		ref := func() int {
+			ref := d.uvarint()
+			if d.err == nil && ref >= uint64(len(entries)) {
+				d.fail("entry %d refers to entry %d", len(entries), ref)
+			}
+			if d.err != nil {
+				return 0
+			}
+			if depths[ref] >= depth {
+				depth = depths[ref] + 1
+			}
+			size += sizes[ref]
+			return int(ref)
+		}
+
+		switch kind := d.byte(); kind {
+		case compactScalar:
+			n := d.uvarint()
+			b := d.bytes(n)
+			if d.err != nil {
+				break
+			}
+			typ = &T{}
+			if err := typ.Unmarshal(b); err != nil {
+				return nil, err
+			}
+			if f := typ.Family(); f == ArrayFamily || f == TupleFamily {
+				d.fail("scalar entry %d has family %s", len(entries), f)
+			}
+
+		case compactArray:
+			o := oid.Oid(d.uvarint())
+			elem := ref()
+			if d.err != nil {
+				break
+			}
+			typ = &T{InternalType: InternalType{
+				Family: ArrayFamily, Oid: o, ArrayContents: entries[elem], Locale: &emptyLocale}}
+
+		case compactTuple:
+			o := oid.Oid(d.uvarint())
+			numFields := d.uvarint()
+			if d.err == nil && numFields > uint64(len(d.data)) {
+				d.fail("invalid number of fields %d", numFields)
+			}
+			if d.err != nil {
+				break
+			}
+			contents := make([]T, numFields)
+			for i := range contents {
+				field := ref()
+				if d.err != nil {
+					break
+				}
+				contents[i] = *entries[field]
+			}
+			var labels []string
+			if numLabels := d.uvarint(); numLabels > 0 && d.err == nil {
+				if numLabels != numFields {
+					d.fail("tuple entry %d has %d fields and %d labels", len(entries), numFields, numLabels)
+					break
+				}
+				labels = make([]string, numLabels)
+				for i := range labels {
+					labels[i] = string(d.bytes(d.uvarint()))
+				}
+			}
+			if d.err != nil {
+				break
+			}
+			typ = &T{InternalType: InternalType{
+				Family: TupleFamily, Oid: o, TupleContents: contents, TupleLabels: labels,
+				Locale: &emptyLocale}}
+
+		default:
+			d.fail("entry %d has unknown kind %d", len(entries), kind)
+		}
+		if d.err != nil {
+			return nil, d.err
+		}
+
+		if depth > MaxTypeNestingDepth {
+			return nil, &UnmarshalLimitError{Limit: "nesting depth", Max: MaxTypeNestingDepth}
+		}
+		size += start - len(d.data)
+		if size > MaxTypeSize {
+			return nil, &UnmarshalLimitError{Limit: "size in bytes", Max: MaxTypeSize}
+		}
+		entries = append(entries, typ)
+		depths = append(depths, depth)
+		sizes = append(sizes, size)
+	}
+	if len(d.data) != 0 {
+		return nil, errors.Errorf(
+			"invalid compact type encoding: %d bytes remain after the last entry", len(d.data))
+	}
+	return entries[len(entries)-1], nil
+}
+
+// compactDecoder decodes the values of the compact encoding of a type. Once an
+// error occurs, it is kept in err, and the methods return zero values.
+type compactDecoder struct {
+	data []byte
+	err  error
+}
+
+func (d *compactDecoder) fail(format string, args ...interface{}) {
+	d.err = errors.Errorf("invalid compact type encoding: "+format, args...)
+}
+
+func (d *compactDecoder) byte() byte {
+	if d.err != nil {
+		return 0
+	}
+	if len(d.data) == 0 {
+		d.fail("unexpected end of data")
+		return 0
+	}
+	b := d.data[0]
+	d.data = d.data[1:]
+	return b
+}
+
+func (d *compactDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.fail("invalid varint")
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *compactDecoder) bytes(n uint64) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n > uint64(len(d.data)) {
+		d.fail("unexpected end of data")
+		return nil
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
+
+// appendUvarint appends the unsigned varint encoding of v to b.
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	return append(b, buf[:n]...)
+}
@@ -0,0 +1,111 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+)
+
+func TestMarshalCompact(t *testing.T) {
+	labels := make([]string, 100)
+	contents := make([]T, len(labels))
+	for i := range contents {
+		contents[i] = *[]*T{Int, MakeVarChar(20), MakeArray(MakeDecimal(10, 2))}[i%3]
+		labels[i] = fmt.Sprintf("col%d", i)
+	}
+	wide := MakeLabeledTuple(contents, labels)
+	deep := MakeTuple([]T{*wide, *MakeCollatedString(String, "de")})
+	for i := 0; i < 6; i++ {
+		deep = MakeTuple([]T{*deep, *MakeArray(deep), *Int})
+	}
+	typs := append(AllTypes(), []*T{
+		MakeCollatedString(MakeVarChar(20), "en_US"),
+		MakeArray(MakeArray(Int)),
+		MakeTuple([]T{*Int2Vector, *OidVector}),
+		EmptyTuple,
+		wide,
+		deep,
+	}...)
+
+	prefix := []byte("prefix")
+	for _, typ := range typs {
+		data, err := typ.MarshalCompact(prefix[:len(prefix):len(prefix)])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.HasPrefix(data, prefix) {
+			t.Fatalf("%s: expected the encoding to be appended to the prefix", typ.DebugString())
+		}
+		actual, err := UnmarshalCompact(data[len(prefix):])
+		if err != nil {
+			t.Fatalf("%s: %v", typ.DebugString(), err)
+		}
+		if !actual.Identical(typ) {
+			t.Errorf("expected %s, got %s", typ.DebugString(), actual.DebugString())
+		}
+	}
+
+	// The repeated nested types are only encoded once.
+	for _, typ := range []*T{wide, deep} {
+		data, err := typ.MarshalCompact(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if size := typ.Size(); len(data) >= size/2 {
+			t.Errorf("expected the compact encoding to be much smaller than %d bytes, got %d bytes",
+				size, len(data))
+		}
+	}
+
+	// Truncated or corrupted encodings return errors.
+	data, err := deep.MarshalCompact(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < len(data); i++ {
+		if _, err := UnmarshalCompact(data[:i]); err == nil {
+			t.Errorf("expected an error for an encoding truncated to %d bytes", i)
+		}
+	}
+	for _, corrupt := range [][]byte{
+		{2, 1, compactScalar, 0},
+		{compactEncodingVersion, 1, compactArray, 0x7f, 4},
+		{compactEncodingVersion, 2, compactScalar, 2, 0x08, 0x01, compactTuple, 0x7f, 1, 0, 2},
+		{compactEncodingVersion, 1, 3},
+		append(append([]byte{compactEncodingVersion, 1, compactScalar}, data...), 0),
+	} {
+		if _, err := UnmarshalCompact(corrupt); err == nil {
+			t.Errorf("expected an error for %x", corrupt)
+		}
+	}
+
+	// The limits apply to the expanded type: each entry doubles its size.
+	var expanding []byte
+	numEntries := 40
+	expanding = append(expanding, compactEncodingVersion, byte(numEntries))
+	scalar, err := Int.MarshalAppend(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expanding = append(expanding, compactScalar, byte(len(scalar)))
+	expanding = append(expanding, scalar...)
+	for i := 1; i < numEntries; i++ {
+		expanding = append(expanding, compactTuple, 0x7f, 2, byte(i-1), byte(i-1), 0)
+	}
+	var limitErr *UnmarshalLimitError
+	if _, err := UnmarshalCompact(expanding); !errors.As(err, &limitErr) || limitErr.Limit != "size in bytes" {
+		t.Errorf("expected size in bytes limit error, got %v", err)
+	}
+}
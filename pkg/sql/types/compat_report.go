@@ -0,0 +1,224 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
+)
+
+// The compatibility reports are checked in, and TestCompatibilityReport fails
+// if they are not up to date.
+//go:generate go run ./compatgen -out testdata/compat_report.md
+//go:generate go run ./compatgen -format csv -out testdata/compat_report.csv
+
+// CompatibilityReportFormat is the format of the report written by
+// WriteCompatibilityReport.
+type CompatibilityReportFormat int
+
+const (
+	// CompatibilityReportMarkdown is a Markdown document containing the table of
+	// the Postgres types and the matrix of the casts between type families.
+	CompatibilityReportMarkdown CompatibilityReportFormat = iota
+	// CompatibilityReportCSV is a CSV file containing the table of the Postgres
+	// types, with a header row.
+	CompatibilityReportCSV
+)
+
+// pgTypmodSamples contains the Postgres types that accept a type modifier, and
+// for each of them a valid modifier, which is used to check whether the
+// CockroachDB type with the same OID supports it.
+var pgTypmodSamples = map[oid.Oid]int32{
+	oid.T_bpchar:      10 + varHeaderSize,
+	oid.T_varchar:     10 + varHeaderSize,
+	oid.T_numeric:     (10<<16 | 2) + varHeaderSize,
+	oid.T_bit:         8,
+	oid.T_varbit:      8,
+	oid.T_time:        3,
+	oid.T_timetz:      3,
+	oid.T_timestamp:   3,
+	oid.T_timestamptz: 3,
+	// INTERVAL(3) with the full range of fields.
+	oid.T_interval: 0x7FFF<<16 | 3,
+}
+
+// typeCompatibility is a row of the table of the Postgres types.
+type typeCompatibility struct {
+	pgName string
+	pgOid  oid.Oid
+	// typ is the CockroachDB type with the Postgres OID or, if there is none,
+	// with the Postgres name. It is nil if there is no such type.
+	typ *T
+	// typmod is "yes" or "no" depending on whether the CockroachDB type accepts
+	// the type modifiers of the Postgres type, or empty if the Postgres type
+	// does not accept any.
+	typmod string
+}
+
+func (c *typeCompatibility) cells() []string {
+	typ, oidMatch := "", ""
+	if c.typ != nil {
+		typ = reportTypeName(c.typ)
+		oidMatch = "yes"
+		if c.typ.Oid() != c.pgOid {
+			oidMatch = fmt.Sprintf("no (%d)", c.typ.Oid())
+		}
+	}
+	return []string{c.pgName, strconv.Itoa(int(c.pgOid)), typ, oidMatch, c.typmod}
+}
+
+// reportTypeName returns the name of the given type in the reports. The
+// SQLString of the wildcard tuple type is empty, so its String is used instead.
+func reportTypeName(t *T) string {
+	if name := t.SQLString(); name != "" && !strings.HasPrefix(name, "[") {
+		return name
+	}
+	return t.String()
+}
+
+var typeCompatibilityColumns = []string{
+	"Postgres type", "OID", "CockroachDB type", "OID match", "Type modifiers",
+}
+
+// typeCompatibilities returns the rows of the table of the Postgres types,
+// ordered by OID. The Postgres types are the ones known to the lib/pq driver.
+func typeCompatibilities() []typeCompatibility {
+	var res []typeCompatibility
+	for o, name := range oid.TypeName {
+		c := typeCompatibility{pgName: strings.ToLower(name), pgOid: o}
+		if typ, ok := TypeForOid(o); ok {
+			c.typ = typ
+		} else if typ, ok := TypeForPGTypeName(c.pgName); ok {
+			c.typ = typ
+		}
+		if typmod, ok := pgTypmodSamples[o]; ok {
+			c.typmod = "no"
+			if c.typ != nil {
+				if typ, err := MakeTypeFromTypmod(c.typ.Oid(), typmod); err == nil && typ.Typmod() == typmod {
+					c.typmod = "yes"
+				}
+			}
+		}
+		res = append(res, c)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].pgOid < res[j].pgOid })
+	return res
+}
+
+// WriteCompatibilityReport writes a report of the compatibility of the
+// CockroachDB types with the Postgres types, in the given format. For each
+// Postgres type, it reports whether there is a CockroachDB type with the same
+// name or OID, and whether it supports the same type modifiers. The Markdown
+// report also lists the CockroachDB types that have no Postgres counterpart,
+// and the cast matrix (see ForEachCast).
+func WriteCompatibilityReport(w io.Writer, format CompatibilityReportFormat) error {
+	switch format {
+	case CompatibilityReportMarkdown:
+		return writeCompatibilityMarkdown(w)
+	case CompatibilityReportCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(typeCompatibilityColumns); err != nil {
+			return err
+		}
+		for _, c := range typeCompatibilities() {
+			if err := cw.Write(c.cells()); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+	return errors.AssertionFailedf("unknown compatibility report format %d", format)
+}
+
+func writeCompatibilityMarkdown(w io.Writer) error {
+	var buf strings.Builder
+	writeRow := func(cells []string) {
+		buf.WriteString("|")
+		for _, cell := range cells {
+			buf.WriteString(" ")
+			buf.WriteString(cell)
+			buf.WriteString(" |")
+		}
+		buf.WriteString("\n")
+	}
+	writeHeader := func(columns []string) {
+		writeRow(columns)
+		separators := make([]string, len(columns))
+		for i := range separators {
+			separators[i] = "---"
+		}
+		writeRow(separators)
+	}
+
+	buf.WriteString("# Type compatibility with Postgres\n\n")
+	buf.WriteString("Generated by `go generate ./pkg/sql/types`. DO NOT EDIT.\n\n")
+
+	buf.WriteString("## Postgres types\n\n")
+	writeHeader(typeCompatibilityColumns)
+	pgOids := make(map[oid.Oid]bool, len(oid.TypeName))
+	for _, c := range typeCompatibilities() {
+		pgOids[c.pgOid] = true
+		writeRow(c.cells())
+	}
+
+	buf.WriteString("\n## CockroachDB types without a Postgres type\n\n")
+	writeHeader([]string{"CockroachDB type", "OID", "pg_type name"})
+	for _, typ := range AllTypes() {
+		if !pgOids[typ.Oid()] {
+			writeRow([]string{reportTypeName(typ), strconv.Itoa(int(typ.Oid())), typ.PGName()})
+		}
+	}
+
+	// The cast matrix has a row per source family and a column per target
+	// family. The cells contain the castcontext codes of pg_cast.
+	buf.WriteString("\n## Casts between type families\n\n")
+	buf.WriteString("Rows are source families and columns are target families: ")
+	buf.WriteString("i = implicit, a = assignment, e = explicit.\n\n")
+	casts := make(map[[2]Family]CastContextKind)
+	familySet := make(map[Family]bool)
+	ForEachCast(func(from, to Family, ctx CastContextKind) {
+		casts[[2]Family{from, to}] = ctx
+		familySet[from] = true
+		familySet[to] = true
+	})
+	families := make([]Family, 0, len(familySet))
+	for f := range familySet {
+		families = append(families, f)
+	}
+	sort.Slice(families, func(i, j int) bool { return families[i] < families[j] })
+	columns := []string{""}
+	for _, to := range families {
+		columns = append(columns, familyName(to))
+	}
+	writeHeader(columns)
+	for _, from := range families {
+		cells := []string{familyName(from)}
+		for _, to := range families {
+			cell := ""
+			if ctx, ok := casts[[2]Family{from, to}]; ok && ctx != CastNotAllowed {
+				cell = string(ctx.PgCastContext())
+			}
+			cells = append(cells, cell)
+		}
+		writeRow(cells)
+	}
+
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
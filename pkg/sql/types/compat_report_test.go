@@ -0,0 +1,62 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"bytes"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+// TestCompatibilityReport verifies that the compatibility reports in testdata
+// are up to date, and checks a few of the rows of the report.
+func TestCompatibilityReport(t *testing.T) {
+	for _, tc := range []struct {
+		file   string
+		format CompatibilityReportFormat
+	}{
+		{"testdata/compat_report.md", CompatibilityReportMarkdown},
+		{"testdata/compat_report.csv", CompatibilityReportCSV},
+	} {
+		var buf bytes.Buffer
+		if err := WriteCompatibilityReport(&buf, tc.format); err != nil {
+			t.Fatal(err)
+		}
+		expected, err := ioutil.ReadFile(tc.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(buf.Bytes(), expected) {
+			t.Errorf("%s is out of date; run go generate ./pkg/sql/types", tc.file)
+		}
+	}
+
+	rows := make(map[string][]string)
+	for _, c := range typeCompatibilities() {
+		rows[c.pgName] = c.cells()
+	}
+	testCases := []struct {
+		pgName string
+		cells  []string
+	}{
+		{"int8", []string{"int8", "20", "INT8", "yes", ""}},
+		{"varchar", []string{"varchar", "1043", "VARCHAR", "yes", "yes"}},
+		{"numeric", []string{"numeric", "1700", "DECIMAL", "yes", "yes"}},
+		{"record", []string{"record", "2249", "tuple", "yes", ""}},
+		{"interval", []string{"interval", "1186", "INTERVAL", "yes", "yes"}},
+	}
+	for _, tc := range testCases {
+		if cells := rows[tc.pgName]; !reflect.DeepEqual(cells, tc.cells) {
+			t.Errorf("%s: expected %v, got %v", tc.pgName, tc.cells, cells)
+		}
+	}
+}
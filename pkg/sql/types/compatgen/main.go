@@ -0,0 +1,54 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// compatgen writes the report of the compatibility of the SQL types with the
+// Postgres types. See types.WriteCompatibilityReport.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+func main() {
+	format := flag.String("format", "markdown", "format of the report: markdown or csv")
+	out := flag.String("out", "", "file to write the report to (default: stdout)")
+	flag.Parse()
+
+	var f types.CompatibilityReportFormat
+	switch *format {
+	case "markdown":
+		f = types.CompatibilityReportMarkdown
+	case "csv":
+		f = types.CompatibilityReportCSV
+	default:
+		fmt.Fprintf(os.Stderr, "unknown format %q\n", *format)
+		os.Exit(2)
+	}
+
+	var buf bytes.Buffer
+	if err := types.WriteCompatibilityReport(&buf, f); err != nil {
+		fmt.Fprintln(os.Stderr, "error writing report:", err)
+		os.Exit(1)
+	}
+	if *out == "" {
+		_, _ = os.Stdout.Write(buf.Bytes())
+		return
+	}
+	if err := ioutil.WriteFile(*out, buf.Bytes(), 0644); err != nil {
+		fmt.Fprintln(os.Stderr, "error writing report:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// FamilyConstraint describes an expression position that accepts only values
+// of some type families, such as the predicate of a partial index, which must
+// be a boolean, or the expiration expression of a row-level TTL, which must be
+// a timestamp. It is used by semantic analysis to validate the resolved type of
+// the expression with an error message that names the position.
+type FamilyConstraint struct {
+	// Context describes the expression position in error messages, such as
+	// "index predicate".
+	Context string
+	// Families are the families accepted by the position.
+	Families []Family
+}
+
+// MakeFamilyConstraint returns a constraint for the expression position
+// described by context, which accepts the given families.
+func MakeFamilyConstraint(context string, families ...Family) FamilyConstraint {
+	return FamilyConstraint{Context: context, Families: families}
+}
+
+// Accepts returns true if an expression of the given type can appear in the
+// position. NULL (UnknownFamily) is accepted by every position, as it is by
+// tree.TypeCheckAndRequire.
+func (c FamilyConstraint) Accepts(t *T) bool {
+	if t.Family() == UnknownFamily {
+		return true
+	}
+	for _, f := range c.Families {
+		if t.Family() == f {
+			return true
+		}
+	}
+	return false
+}
+
+// Check returns an error with the DatatypeMismatch code if an expression of the
+// given type cannot appear in the position, for example:
+//
+//   index predicate must be type bool, not type int
+//   TTL expiration expression must be one of types timestamp, timestamptz, not type string
+//
+func (c FamilyConstraint) Check(t *T) error {
+	if c.Accepts(t) {
+		return nil
+	}
+	if len(c.Families) == 1 {
+		return pgerror.Newf(pgcode.DatatypeMismatch, "%s must be type %s, not type %s",
+			c.Context, familyName(c.Families[0]), t)
+	}
+	names := make([]string, len(c.Families))
+	for i, f := range c.Families {
+		names[i] = familyName(f)
+	}
+	return pgerror.Newf(pgcode.DatatypeMismatch, "%s must be one of types %s, not type %s",
+		c.Context, strings.Join(names, ", "), t)
+}
+
+// familyName returns the name of a family in error messages, which is the name
+// of its default type (e.g. "int" for IntFamily) for most families.
+func familyName(f Family) string {
+	switch f {
+	case AnyFamily:
+		return "any"
+	case ArrayFamily:
+		return "array"
+	case TupleFamily:
+		return "tuple"
+	case CollatedStringFamily:
+		return "collated string"
+	}
+	if typ, ok := OidToType[familyToOid[f]]; ok {
+		return typ.Name()
+	}
+	return strings.ToLower(strings.TrimSuffix(f.String(), "Family"))
+}
@@ -0,0 +1,68 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+func TestFamilyConstraint(t *testing.T) {
+	predicate := MakeFamilyConstraint("index predicate", BoolFamily)
+	expiration := MakeFamilyConstraint(
+		"TTL expiration expression", TimestampFamily, TimestampTZFamily)
+	elements := MakeFamilyConstraint("element", ArrayFamily, TupleFamily, CollatedStringFamily)
+
+	testCases := []struct {
+		constraint FamilyConstraint
+		typ        *T
+		err        string
+	}{
+		{predicate, Bool, ""},
+		{predicate, Unknown, ""},
+		{predicate, Int, "index predicate must be type bool, not type int"},
+		{predicate, MakeArray(Bool), "index predicate must be type bool, not type bool[]"},
+		{expiration, Timestamp, ""},
+		{expiration, TimestampTZ, ""},
+		{expiration, String, "TTL expiration expression must be one of types " +
+			"timestamp, timestamptz, not type string"},
+		{elements, MakeArray(Int), ""},
+		{elements, EmptyTuple, ""},
+		{elements, MakeCollatedString(String, "en"), ""},
+		{elements, Date, "element must be one of types array, tuple, collated string, not type date"},
+	}
+	for _, tc := range testCases {
+		err := tc.constraint.Check(tc.typ)
+		if accepts := tc.constraint.Accepts(tc.typ); accepts != (err == nil) {
+			t.Errorf("%s: Accepts returned %t, but Check returned %v", tc.typ.SQLString(), accepts, err)
+		}
+		if tc.err == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", tc.typ.SQLString(), err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != tc.err {
+			t.Errorf("%s: expected error %q, got %v", tc.typ.SQLString(), tc.err, err)
+		} else if code := pgerror.GetPGCode(err); code != pgcode.DatatypeMismatch {
+			t.Errorf("%s: expected code %s, got %s", tc.typ.SQLString(), pgcode.DatatypeMismatch, code)
+		}
+	}
+
+	// Every family has a name.
+	for _, typ := range Scalar {
+		if name := familyName(typ.Family()); name == "" {
+			t.Errorf("%s: expected a family name", typ.Family())
+		}
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TypeAttribute is an attribute of a type that Diff compares.
+type TypeAttribute string
+
+// These are the attributes of types that Diff compares.
+const (
+	// FamilyAttribute is the family of the type.
+	FamilyAttribute TypeAttribute = "family"
+	// NameAttribute is the name of the type within its family, such as
+	// VARCHAR or STRING, which is determined by its Oid. It is not compared
+	// for the INT and FLOAT types, whose names are determined by their widths,
+	// nor for array types, whose names are determined by their elements.
+	NameAttribute TypeAttribute = "type"
+	// WidthAttribute is the width of string, bit and INT types.
+	WidthAttribute TypeAttribute = "width"
+	// WidthUnitAttribute is the unit in which the width of a string type is
+	// measured.
+	WidthUnitAttribute TypeAttribute = "width unit"
+	// PrecisionAttribute is the precision of DECIMAL, TIME, TIMESTAMP and
+	// INTERVAL types.
+	PrecisionAttribute TypeAttribute = "precision"
+	// ScaleAttribute is the scale of DECIMAL types.
+	ScaleAttribute TypeAttribute = "scale"
+	// LocaleAttribute is the locale of collated string types.
+	LocaleAttribute TypeAttribute = "locale"
+	// QualifierAttribute is the qualifier of INTERVAL types, such as DAY TO
+	// SECOND.
+	QualifierAttribute TypeAttribute = "qualifier"
+	// FieldsAttribute is the number of fields of tuple types.
+	FieldsAttribute TypeAttribute = "number of fields"
+	// LabelsAttribute is the labels of the fields of tuple types.
+	LabelsAttribute TypeAttribute = "labels"
+	// BoundsAttribute is the declared bounds of array types (see
+	// MakeDimensionedArray).
+	BoundsAttribute TypeAttribute = "array bounds"
+)
+
+// TypeDifference describes an attribute in which two types differ.
+type TypeDifference struct {
+	// Path is the position of the nested type that differs within the compared
+	// types, such as "element type" or "field 2 of element type". It is empty
+	// if the compared types themselves differ.
+	Path string
+	// Attribute is the attribute that differs.
+	Attribute TypeAttribute
+	// Old and New are the values of the attribute in the first and second
+	// compared types.
+	Old, New string
+}
+
+// String returns a description of the difference, such as:
+//
+//   width changed from 10 to 20
+//   element type: locale changed from de to fr
+//
+func (d TypeDifference) String() string {
+	s := fmt.Sprintf("%s changed from %s to %s", d.Attribute, d.Old, d.New)
+	if d.Path != "" {
+		s = d.Path + ": " + s
+	}
+	return s
+}
+
+// Diff returns the attributes in which the two given types differ, in the order
+// of a depth-first traversal of the types. It returns nil if the types have
+// the same attributes, which Identical types do. If the families of the types
+// differ, that is the only difference that is returned, since the other
+// attributes are not comparable. The elements of arrays and the fields of
+// tuples are compared if the types have the same family, and the same number
+// of fields.
+//
+// Schema changes use Diff to determine whether a change of the type of a
+// column requires the column to be rewritten, and to explain why in errors.
+func Diff(a, b *T) []TypeDifference {
+	return appendDiff(nil, "", a, b)
+}
+
+// appendDiff appends the differences between the types at the given path to
+// diffs, and returns the extended slice.
+func appendDiff(diffs []TypeDifference, path string, a, b *T) []TypeDifference {
+	add := func(attr TypeAttribute, old, new string) {
+		if old != new {
+			diffs = append(diffs, TypeDifference{Path: path, Attribute: attr, Old: old, New: new})
+		}
+	}
+	if a.Family() != b.Family() {
+		add(FamilyAttribute, familyName(a.Family()), familyName(b.Family()))
+		return diffs
+	}
+	switch a.Family() {
+	case IntFamily, FloatFamily:
+	case ArrayFamily:
+		// The vector types, such as INT2VECTOR, are arrays with the same
+		// elements as other array types.
+		if a.ArrayContents().Oid() == b.ArrayContents().Oid() && a.Oid() != b.Oid() {
+			add(NameAttribute, strings.ToUpper(a.Name()), strings.ToUpper(b.Name()))
+		}
+	default:
+		if a.Oid() != b.Oid() {
+			add(NameAttribute, strings.ToUpper(a.Name()), strings.ToUpper(b.Name()))
+		}
+	}
+
+	switch a.Family() {
+	case IntFamily, FloatFamily, StringFamily, CollatedStringFamily, BytesFamily, BitFamily:
+		add(WidthAttribute, formatDiffWidth(a.Width()), formatDiffWidth(b.Width()))
+		add(WidthUnitAttribute, a.WidthUnit().String(), b.WidthUnit().String())
+		if a.Family() == CollatedStringFamily {
+			add(LocaleAttribute, a.Locale(), b.Locale())
+		}
+	case DecimalFamily:
+		add(PrecisionAttribute, formatDiffWidth(a.Precision()), formatDiffWidth(b.Precision()))
+		add(ScaleAttribute, strconv.Itoa(int(a.Scale())), strconv.Itoa(int(b.Scale())))
+	case TimestampFamily, TimestampTZFamily:
+		add(PrecisionAttribute, formatDiffPrecision(a.Precision() >= 0, a.Precision()),
+			formatDiffPrecision(b.Precision() >= 0, b.Precision()))
+	case TimeFamily:
+		add(PrecisionAttribute, formatDiffPrecision(a.TimePrecisionIsSet(), a.Precision()),
+			formatDiffPrecision(b.TimePrecisionIsSet(), b.Precision()))
+	case IntervalFamily:
+		add(QualifierAttribute, a.diffIntervalQualifier(), b.diffIntervalQualifier())
+		add(PrecisionAttribute, formatDiffPrecision(a.TimePrecisionIsSet(), a.Precision()),
+			formatDiffPrecision(b.TimePrecisionIsSet(), b.Precision()))
+	case ArrayFamily:
+		add(BoundsAttribute, arrayBoundsString(a.ArrayBounds()), arrayBoundsString(b.ArrayBounds()))
+		diffs = appendDiff(diffs, joinDiffPath("element type", path), a.ArrayContents(), b.ArrayContents())
+	case TupleFamily:
+		if len(a.TupleContents()) != len(b.TupleContents()) {
+			add(FieldsAttribute, strconv.Itoa(len(a.TupleContents())), strconv.Itoa(len(b.TupleContents())))
+			break
+		}
+		add(LabelsAttribute, formatDiffLabels(a.TupleLabels()), formatDiffLabels(b.TupleLabels()))
+		for i := range a.TupleContents() {
+			fieldPath := joinDiffPath(fmt.Sprintf("field %d", i+1), path)
+			diffs = appendDiff(diffs, fieldPath, &a.TupleContents()[i], &b.TupleContents()[i])
+		}
+	}
+	return diffs
+}
+
+// diffIntervalQualifier returns the qualifier of an INTERVAL type, such as DAY
+// TO SECOND, or "none".
+func (t *T) diffIntervalQualifier() string {
+	itm, err := t.IntervalTypeMetadata()
+	if err != nil {
+		panic(err)
+	}
+	itm.PrecisionIsSet = false
+	if q := strings.TrimSpace(formatInterval("", itm, strings.ToUpper)); q != "" {
+		return q
+	}
+	return "none"
+}
+
+// joinDiffPath returns the path of a nested type of the type at the given path.
+func joinDiffPath(elem, path string) string {
+	if path == "" {
+		return elem
+	}
+	return elem + " of " + path
+}
+
+// formatDiffWidth formats a width, or a DECIMAL precision, for Diff, where 0
+// means that there is none.
+func formatDiffWidth(width int32) string {
+	if width == 0 {
+		return "none"
+	}
+	return strconv.Itoa(int(width))
+}
+
+// formatDiffPrecision formats the precision of a temporal type for Diff.
+func formatDiffPrecision(isSet bool, precision int32) string {
+	if !isSet {
+		return "default"
+	}
+	return strconv.Itoa(int(precision))
+}
+
+// formatDiffLabels formats the labels of a tuple type for Diff.
+func formatDiffLabels(labels []string) string {
+	if labels == nil {
+		return "none"
+	}
+	return "(" + strings.Join(labels, ", ") + ")"
+}
@@ -0,0 +1,81 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	dayToSecond := MakeInterval(IntervalTypeMetadata{
+		DurationField: IntervalDurationField{
+			FromDurationType: IntervalDurationType_DAY, DurationType: IntervalDurationType_SECOND},
+	})
+	bounded, err := MakeDimensionedArray(Int, []int32{3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		a, b     *T
+		expected []string
+	}{
+		{Int, Int, nil},
+		{MakeVarChar(10), MakeVarChar(10), nil},
+		{MakeVarChar(10), MakeVarChar(20), []string{"width changed from 10 to 20"}},
+		{MakeVarChar(10), String, []string{
+			"type changed from VARCHAR to STRING", "width changed from 10 to none"}},
+		{MakeVarChar(10), MakeStringWithWidthUnit(MakeVarChar(10), StringWidthUnit_BYTES), []string{
+			"width unit changed from CHARACTERS to BYTES"}},
+		{Int2, Int, []string{"width changed from 16 to 64"}},
+		{Int, String, []string{"family changed from int to string"}},
+		{MakeDecimal(10, 2), MakeDecimal(12, 2), []string{"precision changed from 10 to 12"}},
+		{MakeDecimal(10, 2), Decimal, []string{
+			"precision changed from 10 to none", "scale changed from 2 to 0"}},
+		{MakeCollatedString(String, "de"), MakeCollatedString(String, "fr"), []string{
+			"locale changed from de to fr"}},
+		{MakeTime(3), Time, []string{"precision changed from 3 to default"}},
+		{Timestamp, MakeTimestamp(0), []string{"precision changed from default to 0"}},
+		{Interval, dayToSecond, []string{"qualifier changed from none to DAY TO SECOND"}},
+		{MakeArray(MakeCollatedString(String, "de")), MakeArray(MakeCollatedString(String, "fr")), []string{
+			"element type: locale changed from de to fr"}},
+		{IntArray, StringArray, []string{"element type: family changed from int to string"}},
+		{IntArray, bounded, []string{"array bounds changed from [] to [3]"}},
+		{Int2Vector, MakeArray(Int2), []string{"type changed from INT2VECTOR to INT2[]"}},
+		{MakeTuple([]T{*Int, *MakeVarChar(3)}), MakeTuple([]T{*Int4, *MakeVarChar(5)}), []string{
+			"field 1: width changed from 64 to 32", "field 2: width changed from 3 to 5"}},
+		{MakeTuple([]T{*Int}), MakeTuple([]T{*Int, *Int}), []string{"number of fields changed from 1 to 2"}},
+		{MakeTuple([]T{*Int}), MakeLabeledTuple([]T{*Int}, []string{"a"}), []string{
+			"labels changed from none to (a)"}},
+		{MakeArray(MakeTuple([]T{*MakeBit(3)})), MakeArray(MakeTuple([]T{*MakeBit(4)})), []string{
+			"field 1 of element type: width changed from 3 to 4"}},
+	}
+	for _, tc := range testCases {
+		var diffs []string
+		for _, d := range Diff(tc.a, tc.b) {
+			diffs = append(diffs, d.String())
+		}
+		if !reflect.DeepEqual(diffs, tc.expected) {
+			t.Errorf("%s, %s: expected %q, but got %q", tc.a.SQLString(), tc.b.SQLString(), tc.expected, diffs)
+		}
+	}
+
+	// Every pair of types that are not identical has a difference.
+	typs := AllTypes()
+	for _, a := range typs {
+		for _, b := range typs {
+			if diffs := Diff(a, b); a.Identical(b) != (len(diffs) == 0) {
+				t.Errorf("%s, %s: identical %t, but got differences %v",
+					a.DebugString(), b.DebugString(), a.Identical(b), diffs)
+			}
+		}
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// EquivalenceClassID identifies a class of equivalent types (see Equivalent).
+// The function overload resolver and the type checker use the IDs of the types
+// of arguments to cache resolution results, since comparing two IDs is much
+// cheaper than comparing two types.
+//
+// The IDs of the composite classes are assigned on first use, so they are only
+// meaningful within a process, and must never be persisted or sent to other
+// nodes.
+type EquivalenceClassID uint32
+
+// NoEquivalenceClass is returned by EquivalenceClassID instead of the ID of a
+// composite class when too many of them were assigned. It is not the ID of any
+// class, so results keyed by it must not be cached.
+const NoEquivalenceClass EquivalenceClassID = 0
+
+// firstCompositeClassID is the first ID that is assigned to a composite class.
+// The IDs of the other classes are derived from the families of their types,
+// which are all smaller.
+const firstCompositeClassID EquivalenceClassID = 256
+
+// maxCompositeClasses is the maximum number of composite classes that are
+// assigned an ID. The composite classes include those of tuples, which are
+// created by users, so their number is bounded to prevent the registry from
+// growing without limit.
+const maxCompositeClasses = 10000
+
+// compositeClasses assigns the IDs of the composite classes, by their keys.
+var compositeClasses struct {
+	syncutil.Mutex
+	ids map[string]EquivalenceClassID
+}
+
+// EquivalenceClassID returns the ID of the class of types that are equivalent
+// to this type. Two types that are not wildcards have the same ID if and only
+// if they are Equivalent, so the ID is determined by the family of the type
+// and by the modifiers that matter to equivalence:
+//
+//   arrays            : the class of the element type
+//   tuples            : the classes of the element types
+//   collated strings  : the locale
+//
+// The other modifiers, such as the width, the precision and the Oid, are
+// ignored, so INT2 and INT8 are in the same class, and so are OID and
+// REGCLASS.
+//
+// Each wildcard type, such as ANYELEMENT, RECORD or a collated string with the
+// wildcard locale, has its own class, rather than the classes of the types that
+// it matches. Resolution results that are cached by class are therefore only
+// reused for arguments of the same classes.
+//
+// The classes of the types without such modifiers have fixed IDs. The other
+// classes are assigned an ID the first time that it is requested. It returns
+// NoEquivalenceClass once maxCompositeClasses IDs have been assigned.
+func (t *T) EquivalenceClassID() EquivalenceClassID {
+	switch t.Family() {
+	case ArrayFamily, TupleFamily:
+	case CollatedStringFamily:
+		if t.Locale() == "" {
+			return familyClassID(CollatedStringFamily)
+		}
+	default:
+		return familyClassID(t.Family())
+	}
+	if IsWildcardTupleType(t) {
+		return familyClassID(TupleFamily)
+	}
+
+	var key strings.Builder
+	key.WriteString(strconv.Itoa(int(t.Family())))
+	switch t.Family() {
+	case CollatedStringFamily:
+		key.WriteByte(':')
+		key.WriteString(t.Locale())
+	case ArrayFamily:
+		if !appendClassID(&key, t.ArrayContents()) {
+			return NoEquivalenceClass
+		}
+	case TupleFamily:
+		for i := range t.TupleContents() {
+			if !appendClassID(&key, &t.TupleContents()[i]) {
+				return NoEquivalenceClass
+			}
+		}
+	}
+
+	compositeClasses.Lock()
+	defer compositeClasses.Unlock()
+	if id, ok := compositeClasses.ids[key.String()]; ok {
+		return id
+	}
+	if len(compositeClasses.ids) >= maxCompositeClasses {
+		return NoEquivalenceClass
+	}
+	if compositeClasses.ids == nil {
+		compositeClasses.ids = make(map[string]EquivalenceClassID)
+	}
+	id := firstCompositeClassID + EquivalenceClassID(len(compositeClasses.ids))
+	compositeClasses.ids[key.String()] = id
+	return id
+}
+
+// familyClassID returns the fixed ID of the class of the types of the given
+// family that have no modifiers that matter to equivalence.
+func familyClassID(f Family) EquivalenceClassID {
+	return EquivalenceClassID(f) + 1
+}
+
+// appendClassID appends the class ID of the given element type to the key of
+// a composite class. It returns false if the element type has no class ID.
+func appendClassID(key *strings.Builder, elem *T) bool {
+	id := elem.EquivalenceClassID()
+	if id == NoEquivalenceClass {
+		return false
+	}
+	key.WriteByte(',')
+	key.WriteString(strconv.FormatUint(uint64(id), 10))
+	return true
+}
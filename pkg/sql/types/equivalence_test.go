@@ -0,0 +1,60 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"testing"
+)
+
+func TestEquivalenceClassID(t *testing.T) {
+	typs := append(AllTypes(), []*T{
+		MakeVarChar(20),
+		MakeCollatedString(String, "de"),
+		MakeCollatedString(MakeVarChar(3), "de"),
+		MakeCollatedString(String, "en_US"),
+		MakeArray(MakeCollatedString(String, "de")),
+		MakeArray(MakeArray(Int)),
+		MakeArray(MakeArray(Int2)),
+		MakeArray(MakeArray(String)),
+		MakeTuple([]T{*Int, *String}),
+		MakeLabeledTuple([]T{*Int2, *MakeVarChar(3)}, []string{"a", "b"}),
+		MakeTuple([]T{*String, *Int}),
+		MakeTuple([]T{*Int}),
+		MakeTuple(nil),
+		MakeTuple([]T{*MakeTuple([]T{*Float4}), *MakeArray(MakeBit(3))}),
+		MakeTuple([]T{*MakeTuple([]T{*Float}), *MakeArray(VarBit)}),
+	}...)
+	for _, a := range typs {
+		if a.EquivalenceClassID() == NoEquivalenceClass {
+			t.Fatalf("%s has no equivalence class", a.DebugString())
+		}
+		if a.EquivalenceClassID() != a.EquivalenceClassID() {
+			t.Errorf("%s has different equivalence classes", a.DebugString())
+		}
+		for _, b := range typs {
+			if equivalent, same := a.Equivalent(b), a.EquivalenceClassID() == b.EquivalenceClassID(); equivalent != same {
+				t.Errorf("%s and %s: expected the same class %t, but got %t",
+					a.DebugString(), b.DebugString(), equivalent, same)
+			}
+		}
+	}
+
+	// The wildcards have their own classes.
+	wildcards := []*T{Any, AnyArray, AnyTuple, AnyCollatedString}
+	for i, a := range wildcards {
+		for j, b := range append(wildcards, typs...) {
+			if i != j && a.EquivalenceClassID() == b.EquivalenceClassID() {
+				t.Errorf("%s and %s have the same class %d",
+					a.DebugString(), b.DebugString(), a.EquivalenceClassID())
+			}
+		}
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"fmt"
+
+	exectypes "github.com/cockroachdb/cockroach/pkg/sql/exec/types"
+)
+
+// ExecType returns the physical type used to store the values of the given
+// type in the columnar batches of the vectorized execution engine. It returns
+// exectypes.Unhandled if the vectorized engine does not support the type.
+//
+// Keeping this mapping next to the type definitions ensures that it is updated
+// when a type family is added or changed.
+func ExecType(t *T) exectypes.T {
+	switch t.Family() {
+	case BoolFamily:
+		return exectypes.Bool
+	case BytesFamily, StringFamily:
+		return exectypes.Bytes
+	case DateFamily, OidFamily:
+		return exectypes.Int64
+	case DecimalFamily:
+		return exectypes.Decimal
+	case IntFamily:
+		switch t.Width() {
+		case 16:
+			return exectypes.Int16
+		case 32:
+			return exectypes.Int32
+		case 0, 64:
+			return exectypes.Int64
+		}
+		panic(fmt.Sprintf("integer with unknown width %d", t.Width()))
+	case FloatFamily:
+		// FLOAT4 values are stored with 64 bits of precision during execution,
+		// like the float datum.
+		return exectypes.Float64
+	}
+	return exectypes.Unhandled
+}
@@ -0,0 +1,48 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"testing"
+
+	exectypes "github.com/cockroachdb/cockroach/pkg/sql/exec/types"
+)
+
+func TestExecType(t *testing.T) {
+	testCases := []struct {
+		typ      *T
+		expected exectypes.T
+	}{
+		{Bool, exectypes.Bool},
+		{Bytes, exectypes.Bytes},
+		{String, exectypes.Bytes},
+		{MakeVarChar(10), exectypes.Bytes},
+		{Date, exectypes.Int64},
+		{Oid, exectypes.Int64},
+		{Decimal, exectypes.Decimal},
+		{MakeDecimal(10, 2), exectypes.Decimal},
+		{Int2, exectypes.Int16},
+		{Int4, exectypes.Int32},
+		{Int, exectypes.Int64},
+		{Float4, exectypes.Float64},
+		{Float, exectypes.Float64},
+		{MakeCollatedString(String, "en"), exectypes.Unhandled},
+		{Timestamp, exectypes.Unhandled},
+		{Uuid, exectypes.Unhandled},
+		{IntArray, exectypes.Unhandled},
+		{AnyTuple, exectypes.Unhandled},
+	}
+	for _, tc := range testCases {
+		if actual := ExecType(tc.typ); actual != tc.expected {
+			t.Errorf("%s: expected %s, got %s", tc.typ.SQLString(), tc.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
+)
+
+// MaxFloat4Precision is the largest precision, in binary digits, of a FLOAT(p)
+// type that is stored as a FLOAT4. Types with a larger precision are stored as
+// a FLOAT8.
+const MaxFloat4Precision = 24
+
+// Float8Precision is the precision, in binary digits, of a FLOAT8 type.
+const Float8Precision = 53
+
+// IsFloat4 returns true if this is a FLOAT4 (REAL) type.
+//
+// The distinction between FLOAT4 and FLOAT8 is based on the width of the type.
+// Previous versions also stored a precision for some FLOAT types; that is
+// converted to a width when the type is unmarshaled, and is otherwise ignored.
+func (t *T) IsFloat4() bool {
+	return t.Family() == FloatFamily && t.Width() == 32
+}
+
+// IsFloat8 returns true if this is a FLOAT8 (DOUBLE PRECISION) type. See
+// IsFloat4 for more details.
+func (t *T) IsFloat8() bool {
+	return t.Family() == FloatFamily && t.Width() != 32
+}
+
+// RoundFloat returns the given value rounded to the precision of this FLOAT
+// type. FLOAT4 values are rounded to the nearest 32-bit float, while FLOAT8
+// values are returned unchanged.
+func (t *T) RoundFloat(f float64) float64 {
+	if t.IsFloat4() {
+		return float64(float32(f))
+	}
+	return f
+}
+
+// FloatWidthFromPrecision returns the width, in bits, of the FLOAT type having
+// the given precision in binary digits, as in FLOAT(p): 32 for precisions up
+// to MaxFloat4Precision, and 64 otherwise. It is also used to upgrade FLOAT
+// types written by versions prior to 2.1, which stored a precision instead of
+// a width.
+func FloatWidthFromPrecision(precision int32) int32 {
+	if precision >= 1 && precision <= MaxFloat4Precision {
+		return 32
+	}
+	return 64
+}
+
+// UpgradeFromPre21 converts a FLOAT type that may have been serialized by a
+// version prior to 2.1 into the modern representation, in which FLOAT4 and
+// FLOAT8 are distinguished by their width, and the Precision field is always
+// 0. Older versions used the following encodings:
+//
+//   VisibleType = REAL                    -> FLOAT4
+//   VisibleType = DOUBLE PRECISION        -> FLOAT8
+//   VisibleType = NONE, Width = 32 or 64  -> FLOAT4 or FLOAT8 (2.1 and later)
+//   VisibleType = NONE, Width = 0         -> FLOAT8
+//   VisibleType = NONE, Precision = p > 0 -> FLOAT4 if p <= 24, else FLOAT8
+//
+// UpgradeFromPre21 is applied when a type is unmarshaled, so it is normally
+// not necessary to call it directly. It has no effect on types that are
+// already in the modern representation, or that are not in the FloatFamily.
+func (t *T) UpgradeFromPre21() error {
+	if t.Family() != FloatFamily {
+		return nil
+	}
+
+	switch t.InternalType.VisibleType {
+	case visibleREAL:
+		t.InternalType.Width = 32
+	case visibleDOUBLE:
+		t.InternalType.Width = 64
+	case visibleNONE:
+		switch t.Width() {
+		case 32, 64:
+		default:
+			t.InternalType.Width = FloatWidthFromPrecision(t.Precision())
+		}
+	default:
+		return errors.AssertionFailedf("unexpected visible type: %d", t.InternalType.VisibleType)
+	}
+
+	if t.InternalType.Width == 32 {
+		t.InternalType.Oid = oid.T_float4
+	} else {
+		t.InternalType.Oid = oid.T_float8
+	}
+	t.InternalType.VisibleType = visibleNONE
+	// Precision should always be set to 0 going forward.
+	t.InternalType.Precision = 0
+	return nil
+}
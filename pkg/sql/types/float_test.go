@@ -0,0 +1,141 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+)
+
+func TestFloatWidth(t *testing.T) {
+	if !Float4.IsFloat4() || Float4.IsFloat8() {
+		t.Error("expected FLOAT4 to be a FLOAT4")
+	}
+	if Float.IsFloat4() || !Float.IsFloat8() {
+		t.Error("expected FLOAT8 to be a FLOAT8")
+	}
+	if Int4.IsFloat4() || Int.IsFloat8() {
+		t.Error("expected INT types not to be FLOAT types")
+	}
+
+	if f := Float4.RoundFloat(0.1); f != float64(float32(0.1)) {
+		t.Errorf("expected FLOAT4 rounding, got %v", f)
+	}
+	if f := Float.RoundFloat(0.1); f != 0.1 {
+		t.Errorf("expected no FLOAT8 rounding, got %v", f)
+	}
+
+	for _, tc := range []struct {
+		precision int32
+		expected  int32
+	}{
+		{0, 64}, {1, 32}, {24, 32}, {25, 64}, {53, 64},
+	} {
+		if actual := FloatWidthFromPrecision(tc.precision); actual != tc.expected {
+			t.Errorf("precision %d: expected width %d, got %d", tc.precision, tc.expected, actual)
+		}
+	}
+
+	// A FLOAT type written by a version prior to 2.1 is upgraded using its
+	// precision.
+	for _, tc := range []struct {
+		precision int32
+		expected  *T
+	}{
+		{20, Float4}, {40, Float},
+	} {
+		data, err := protoutil.Marshal(&InternalType{Family: FloatFamily, Precision: tc.precision})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var typ T
+		if err := protoutil.Unmarshal(data, &typ); err != nil {
+			t.Fatal(err)
+		}
+		if !typ.Identical(tc.expected) || typ.IsFloat4() != tc.expected.IsFloat4() {
+			t.Errorf("precision %d: expected %s, got %s",
+				tc.precision, tc.expected.DebugString(), typ.DebugString())
+		}
+	}
+}
+
+func TestUpgradeFromPre21(t *testing.T) {
+	floatFamily := FloatFamily
+	testCases := []struct {
+		from     InternalType
+		expected *T
+	}{
+		// VisibleType = REAL or DOUBLE PRECISION, with or without a width.
+		{InternalType{Family: FloatFamily, VisibleType: visibleREAL}, Float4},
+		{InternalType{Family: FloatFamily, VisibleType: visibleREAL, Width: 64}, Float4},
+		{InternalType{Family: FloatFamily, VisibleType: visibleDOUBLE}, Float},
+		{InternalType{Family: FloatFamily, VisibleType: visibleDOUBLE, Precision: 10}, Float},
+
+		// VisibleType = NONE, with a width (2.1 and later).
+		{InternalType{Family: FloatFamily, Width: 32}, Float4},
+		{InternalType{Family: FloatFamily, Width: 64}, Float},
+		{InternalType{Family: FloatFamily, Width: 32, Precision: 40}, Float4},
+
+		// VisibleType = NONE, without a width or precision.
+		{InternalType{Family: FloatFamily}, Float},
+
+		// VisibleType = NONE, with a precision.
+		{InternalType{Family: FloatFamily, Precision: 1}, Float4},
+		{InternalType{Family: FloatFamily, Precision: 24}, Float4},
+		{InternalType{Family: FloatFamily, Precision: 25}, Float},
+		{InternalType{Family: FloatFamily, Precision: 53}, Float},
+
+		// Arrays of FLOAT types, as stored before ArrayContents was added.
+		{InternalType{Family: ArrayFamily, ArrayElemType: &floatFamily, Precision: 20}, MakeArray(Float4)},
+		{InternalType{Family: ArrayFamily, ArrayElemType: &floatFamily, Precision: 40}, MakeArray(Float)},
+		{InternalType{
+			Family: ArrayFamily, ArrayElemType: &floatFamily, VisibleType: visibleREAL,
+		}, MakeArray(Float4)},
+	}
+
+	for _, tc := range testCases {
+		// Check that the upgrade is applied when the type is unmarshaled.
+		data, err := protoutil.Marshal(&tc.from)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var actual T
+		if err := protoutil.Unmarshal(data, &actual); err != nil {
+			t.Fatal(err)
+		}
+		if !actual.Identical(tc.expected) {
+			t.Errorf("%s: expected %s, got %s",
+				tc.from.String(), tc.expected.DebugString(), actual.DebugString())
+		}
+
+		// Check that calling the upgrade directly is equivalent, and that
+		// upgrading again has no effect.
+		if tc.from.Family == FloatFamily {
+			typ := T{InternalType: tc.from}
+			for i := 0; i < 2; i++ {
+				if err := typ.UpgradeFromPre21(); err != nil {
+					t.Fatal(err)
+				}
+				if typ.Width() != tc.expected.Width() || typ.Oid() != tc.expected.Oid() ||
+					typ.Precision() != 0 || typ.InternalType.VisibleType != visibleNONE {
+					t.Errorf("%s: expected %s, got %s",
+						tc.from.String(), tc.expected.DebugString(), typ.DebugString())
+				}
+			}
+		}
+	}
+
+	typ := T{InternalType: InternalType{Family: FloatFamily, VisibleType: visibleVARCHAR}}
+	if err := typ.UpgradeFromPre21(); err == nil {
+		t.Error("expected error for invalid visible type")
+	}
+}
@@ -0,0 +1,190 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/cockroachdb/apd"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
+	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
+)
+
+var (
+	goBoolType      = reflect.TypeOf(false)
+	goInt16Type     = reflect.TypeOf(int16(0))
+	goInt32Type     = reflect.TypeOf(int32(0))
+	goInt64Type     = reflect.TypeOf(int64(0))
+	goFloat32Type   = reflect.TypeOf(float32(0))
+	goFloat64Type   = reflect.TypeOf(float64(0))
+	goStringType    = reflect.TypeOf("")
+	goBytesType     = reflect.TypeOf([]byte(nil))
+	goTimeType      = reflect.TypeOf(time.Time{})
+	goDecimalType   = reflect.TypeOf(apd.Decimal{})
+	goTimeOfDayType = reflect.TypeOf(timeofday.TimeOfDay(0))
+	goDurationType  = reflect.TypeOf(duration.Duration{})
+	goUUIDType      = reflect.TypeOf(uuid.UUID{})
+	goIPAddrType    = reflect.TypeOf(ipaddr.IPAddr{})
+	goBitArrayType  = reflect.TypeOf(bitarray.BitArray{})
+	goOidType       = reflect.TypeOf(oid.Oid(0))
+	goJSONType      = reflect.TypeOf(json.RawMessage(nil))
+)
+
+// GoType returns the canonical Go representation of the non-NULL values of
+// the given type. Client libraries and internal helpers that scan SQL values
+// into Go variables use it so that they agree on the binding of each type:
+//
+//   BOOL                           bool
+//   INT2, INT4, INT8               int16, int32, int64
+//   FLOAT4, FLOAT8                 float32, float64
+//   DECIMAL                        apd.Decimal
+//   STRING, collated STRING        string
+//   BYTES                          []byte
+//   DATE, TIMESTAMP, TIMESTAMPTZ   time.Time
+//   TIME                           timeofday.TimeOfDay
+//   INTERVAL                       duration.Duration
+//   UUID                           uuid.UUID
+//   INET                           ipaddr.IPAddr
+//   BIT, VARBIT                    bitarray.BitArray
+//   OID                            oid.Oid
+//   JSONB                          json.RawMessage
+//   T[]                            a slice of the Go type of T
+//
+// Nullable values are represented by a pointer to the canonical type. GoType
+// returns an error for types that have no canonical Go representation, such
+// as tuples and wildcard types.
+func GoType(t *T) (reflect.Type, error) {
+	switch t.Family() {
+	case BoolFamily:
+		return goBoolType, nil
+	case IntFamily:
+		switch t.Width() {
+		case 16:
+			return goInt16Type, nil
+		case 32:
+			return goInt32Type, nil
+		case 64:
+			return goInt64Type, nil
+		}
+		return nil, errors.AssertionFailedf("unknown int width: %d", t.Width())
+	case FloatFamily:
+		if t.IsFloat4() {
+			return goFloat32Type, nil
+		}
+		return goFloat64Type, nil
+	case DecimalFamily:
+		return goDecimalType, nil
+	case StringFamily, CollatedStringFamily:
+		return goStringType, nil
+	case BytesFamily:
+		return goBytesType, nil
+	case DateFamily, TimestampFamily, TimestampTZFamily:
+		return goTimeType, nil
+	case TimeFamily:
+		return goTimeOfDayType, nil
+	case IntervalFamily:
+		return goDurationType, nil
+	case UuidFamily:
+		return goUUIDType, nil
+	case INetFamily:
+		return goIPAddrType, nil
+	case BitFamily:
+		return goBitArrayType, nil
+	case OidFamily:
+		return goOidType, nil
+	case JsonFamily:
+		return goJSONType, nil
+	case ArrayFamily:
+		elem, err := GoType(t.ArrayContents())
+		if err != nil {
+			return nil, err
+		}
+		return reflect.SliceOf(elem), nil
+	}
+	return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+		"type %s has no Go representation", t.SQLString())
+}
+
+// FromGoType returns the SQL type whose canonical Go representation, as
+// defined by GoType, is the given Go type. Pointer types are mapped to the SQL
+// type of the type they point to. Since several SQL types can share a Go
+// representation, FromGoType picks the most general of them: time.Time maps
+// to TIMESTAMPTZ, string to STRING and bitarray.BitArray to VARBIT. In
+// addition, the int type maps to INT8. FromGoType returns an error for Go
+// types that do not correspond to any SQL type.
+func FromGoType(typ reflect.Type) (*T, error) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	// Check for the named types first, since some of them have the same kind
+	// as the basic types below.
+	switch typ {
+	case goTimeType:
+		return TimestampTZ, nil
+	case goDecimalType:
+		return Decimal, nil
+	case goTimeOfDayType:
+		return Time, nil
+	case goDurationType:
+		return Interval, nil
+	case goUUIDType:
+		return Uuid, nil
+	case goIPAddrType:
+		return INet, nil
+	case goBitArrayType:
+		return VarBit, nil
+	case goOidType:
+		return Oid, nil
+	case goJSONType:
+		return Jsonb, nil
+	case goBytesType:
+		return Bytes, nil
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		return Bool, nil
+	case reflect.Int16:
+		return Int2, nil
+	case reflect.Int32:
+		return Int4, nil
+	case reflect.Int, reflect.Int64:
+		return Int, nil
+	case reflect.Float32:
+		return Float4, nil
+	case reflect.Float64:
+		return Float, nil
+	case reflect.String:
+		return String, nil
+	case reflect.Slice:
+		elem, err := FromGoType(typ.Elem())
+		if err != nil {
+			return nil, err
+		}
+		if elem.Family() == ArrayFamily {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"Go type %s maps to a nested array, which is not supported", typ)
+		}
+		return MakeArray(elem), nil
+	}
+	return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+		"Go type %s has no corresponding SQL type", typ)
+}
@@ -0,0 +1,94 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/apd"
+	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
+	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/lib/pq/oid"
+)
+
+func TestGoType(t *testing.T) {
+	testCases := []struct {
+		typ *T
+		// expected is a value of the expected Go type.
+		expected interface{}
+		// fromGo is the type returned by FromGoType for the Go type, if it is
+		// different from typ.
+		fromGo *T
+	}{
+		{Bool, false, nil},
+		{Int2, int16(0), nil},
+		{Int4, int32(0), nil},
+		{Int, int64(0), nil},
+		{Float4, float32(0), nil},
+		{Float, float64(0), nil},
+		{MakeDecimal(10, 2), apd.Decimal{}, Decimal},
+		{MakeVarChar(10), "", String},
+		{MakeCollatedString(String, "en"), "", String},
+		{Bytes, []byte(nil), nil},
+		{Date, time.Time{}, TimestampTZ},
+		{Timestamp, time.Time{}, TimestampTZ},
+		{TimestampTZ, time.Time{}, nil},
+		{Time, timeofday.TimeOfDay(0), nil},
+		{Interval, duration.Duration{}, nil},
+		{Uuid, uuid.UUID{}, nil},
+		{INet, ipaddr.IPAddr{}, nil},
+		{MakeBit(3), bitarray.BitArray{}, VarBit},
+		{Oid, oid.Oid(0), nil},
+		{Jsonb, json.RawMessage(nil), nil},
+		{IntArray, []int64(nil), nil},
+		{MakeArray(Uuid), []uuid.UUID(nil), nil},
+	}
+
+	for _, tc := range testCases {
+		goTyp, err := GoType(tc.typ)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.typ.SQLString(), err)
+			continue
+		}
+		if goTyp != reflect.TypeOf(tc.expected) {
+			t.Errorf("%s: expected %T, got %s", tc.typ.SQLString(), tc.expected, goTyp)
+		}
+
+		expected := tc.fromGo
+		if expected == nil {
+			expected = tc.typ
+		}
+		for _, g := range []reflect.Type{goTyp, reflect.PtrTo(goTyp)} {
+			typ, err := FromGoType(g)
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", g, err)
+			} else if !typ.Identical(expected) {
+				t.Errorf("%s: expected %s, got %s", g, expected.DebugString(), typ.DebugString())
+			}
+		}
+	}
+
+	if _, err := GoType(AnyTuple); err == nil {
+		t.Error("expected error for tuple type")
+	}
+	if _, err := FromGoType(reflect.TypeOf(uint64(0))); err == nil {
+		t.Error("expected error for uint64")
+	}
+	if _, err := FromGoType(reflect.TypeOf([][]int64(nil))); err == nil {
+		t.Error("expected error for nested slice")
+	}
+}
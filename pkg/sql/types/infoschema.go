@@ -0,0 +1,113 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import "unicode/utf8"
+
+// The methods in this file compute the values of the columns of the
+// information_schema.columns table that describe the type of a column. Each of
+// them returns false if the column is NULL for the type.
+
+// CharacterMaximumLength returns the declared maximum length of the character
+// and bit string types, which is the character_maximum_length column. It
+// returns false for the other types, and for strings of unbounded length.
+func (t *T) CharacterMaximumLength() (int32, bool) {
+	switch t.Family() {
+	case StringFamily, CollatedStringFamily, BitFamily:
+		if t.Width() > 0 {
+			return t.Width(), true
+		}
+	}
+	return 0, false
+}
+
+// CharacterOctetLength returns the maximum length in bytes of the values of the
+// character string types, which is the character_octet_length column. A width
+// measured in characters is multiplied by the maximum size of a UTF-8 encoded
+// character. It returns false for the other types, and for strings of
+// unbounded length.
+func (t *T) CharacterOctetLength() (int32, bool) {
+	switch t.Family() {
+	case StringFamily, CollatedStringFamily:
+		if t.Width() > 0 {
+			if t.WidthUnit() == StringWidthUnit_BYTES {
+				return t.Width(), true
+			}
+			return t.Width() * utf8.UTFMax, true
+		}
+	}
+	return 0, false
+}
+
+// NumericPrecision returns the declared or implicit precision of the numeric
+// types, which is the numeric_precision column. It is measured in the radix
+// returned by NumericPrecisionRadix. It returns false for the other types, and
+// for DECIMAL types of unbounded precision.
+func (t *T) NumericPrecision() (int32, bool) {
+	switch t.Family() {
+	case IntFamily:
+		return t.Width(), true
+	case FloatFamily:
+		if t.IsFloat4() {
+			return MaxFloat4Precision, true
+		}
+		return Float8Precision, true
+	case DecimalFamily:
+		if t.Precision() > 0 {
+			return t.Precision(), true
+		}
+	}
+	return 0, false
+}
+
+// NumericPrecisionRadix returns the radix of the precision and scale of the
+// numeric types, which is the numeric_precision_radix column: 2 for the binary
+// INT and FLOAT types, and 10 for DECIMAL. It returns false for the other
+// types.
+func (t *T) NumericPrecisionRadix() (int32, bool) {
+	switch t.Family() {
+	case IntFamily, FloatFamily:
+		return 2, true
+	case DecimalFamily:
+		return 10, true
+	}
+	return 0, false
+}
+
+// NumericScale returns the declared or implicit scale of the exact numeric
+// types, which is the numeric_scale column. It returns false for the other
+// types, and for DECIMAL types of unbounded precision.
+func (t *T) NumericScale() (int32, bool) {
+	switch t.Family() {
+	case IntFamily:
+		return 0, true
+	case DecimalFamily:
+		if t.Precision() > 0 {
+			return t.Width(), true
+		}
+	}
+	return 0, false
+}
+
+// DatetimePrecision returns the number of fractional digits of the seconds of
+// the TIME and INTERVAL types, which is the datetime_precision column. It is
+// DefaultTimePrecision if the type has no declared precision. It returns false
+// for the other types.
+func (t *T) DatetimePrecision() (int32, bool) {
+	switch t.Family() {
+	case TimeFamily, IntervalFamily:
+		if t.TimePrecisionIsSet() {
+			return t.Precision(), true
+		}
+		return DefaultTimePrecision, true
+	}
+	return 0, false
+}
@@ -0,0 +1,63 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"testing"
+)
+
+func TestInformationSchemaColumns(t *testing.T) {
+	// A value of -1 means that the column is NULL.
+	testData := []struct {
+		typ                                        *T
+		charMaxLength, octetLength                 int32
+		precision, radix, scale, datetimePrecision int32
+	}{
+		{Int, -1, -1, 64, 2, 0, -1},
+		{Int2, -1, -1, 16, 2, 0, -1},
+		{Float4, -1, -1, 24, 2, -1, -1},
+		{Float, -1, -1, 53, 2, -1, -1},
+		{Decimal, -1, -1, -1, 10, -1, -1},
+		{MakeDecimal(10, 2), -1, -1, 10, 10, 2, -1},
+		{String, -1, -1, -1, -1, -1, -1},
+		{MakeVarChar(10), 10, 40, -1, -1, -1, -1},
+		{MakeStringWithWidthUnit(MakeVarChar(10), StringWidthUnit_BYTES), 10, 10, -1, -1, -1, -1},
+		{MakeCollatedString(MakeChar(3), "en"), 3, 12, -1, -1, -1, -1},
+		{MakeBit(8), 8, -1, -1, -1, -1, -1},
+		{Time, -1, -1, -1, -1, -1, DefaultTimePrecision},
+		{MakeTime(3), -1, -1, -1, -1, -1, 3},
+		{Interval, -1, -1, -1, -1, -1, DefaultTimePrecision},
+		{Timestamp, -1, -1, -1, -1, -1, -1},
+		{MakeArray(Int), -1, -1, -1, -1, -1, -1},
+	}
+	for _, tc := range testData {
+		for _, c := range []struct {
+			name     string
+			fn       func() (int32, bool)
+			expected int32
+		}{
+			{"character_maximum_length", tc.typ.CharacterMaximumLength, tc.charMaxLength},
+			{"character_octet_length", tc.typ.CharacterOctetLength, tc.octetLength},
+			{"numeric_precision", tc.typ.NumericPrecision, tc.precision},
+			{"numeric_precision_radix", tc.typ.NumericPrecisionRadix, tc.radix},
+			{"numeric_scale", tc.typ.NumericScale, tc.scale},
+			{"datetime_precision", tc.typ.DatetimePrecision, tc.datetimePrecision},
+		} {
+			actual, ok := c.fn()
+			if !ok {
+				actual = -1
+			}
+			if actual != c.expected {
+				t.Errorf("%s: expected %s %d, got %d", tc.typ.DebugString(), c.name, c.expected, actual)
+			}
+		}
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"math"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/errors"
+)
+
+// IntBounds returns the minimum and maximum values that can be represented by
+// an INT type having the given width in bits (16, 32 or 64). A width of 0,
+// which was used by previous versions for INT8, is treated as 64.
+func IntBounds(width int32) (min, max int64, err error) {
+	switch width {
+	case 16:
+		return math.MinInt16, math.MaxInt16, nil
+	case 32:
+		return math.MinInt32, math.MaxInt32, nil
+	case 0, 64:
+		return math.MinInt64, math.MaxInt64, nil
+	}
+	return 0, 0, errors.AssertionFailedf("invalid width %d for IntFamily type", width)
+}
+
+// CheckBounds returns an error with the NumericValueOutOfRange code if the
+// given value cannot be represented by this INT type, as in:
+//
+//   integer out of range for type int2
+//
+// It is an error to call CheckBounds on a type that is not in the IntFamily.
+func (t *T) CheckBounds(v int64) error {
+	if t.Family() != IntFamily {
+		return errors.AssertionFailedf("cannot check integer bounds of type %s", t.SQLString())
+	}
+	min, max, err := IntBounds(t.Width())
+	if err != nil {
+		return err
+	}
+	if v < min || v > max {
+		// Postgres names integer types by their width in bytes (e.g. int2).
+		bytes := t.Width() / 8
+		return pgerror.Newf(pgcode.NumericValueOutOfRange,
+			"integer out of range for type int%d", bytes)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+func TestCheckBounds(t *testing.T) {
+	testCases := []struct {
+		typ      *T
+		v        int64
+		expected string
+	}{
+		{Int2, math.MaxInt16, ""},
+		{Int2, math.MinInt16, ""},
+		{Int2, math.MaxInt16 + 1, "integer out of range for type int2"},
+		{Int2, math.MinInt16 - 1, "integer out of range for type int2"},
+		{Int4, math.MaxInt32, ""},
+		{Int4, math.MaxInt32 + 1, "integer out of range for type int4"},
+		{Int4, math.MinInt32 - 1, "integer out of range for type int4"},
+		{Int, math.MaxInt64, ""},
+		{Int, math.MinInt64, ""},
+	}
+	for _, tc := range testCases {
+		err := tc.typ.CheckBounds(tc.v)
+		if tc.expected == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error for %d: %v", tc.typ.SQLString(), tc.v, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != tc.expected {
+			t.Errorf("%s: expected error %q for %d, got %v", tc.typ.SQLString(), tc.expected, tc.v, err)
+		} else if code := pgerror.GetPGCode(err); code != pgcode.NumericValueOutOfRange {
+			t.Errorf("%s: expected code %s, got %s", tc.typ.SQLString(), pgcode.NumericValueOutOfRange, code)
+		}
+	}
+
+	if _, _, err := IntBounds(8); err == nil {
+		t.Error("expected error for invalid width")
+	}
+	if err := Float.CheckBounds(0); err == nil {
+		t.Error("expected error for non-integer type")
+	}
+}
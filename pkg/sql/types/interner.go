@@ -0,0 +1,83 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import "github.com/cockroachdb/cockroach/pkg/util/syncutil"
+
+// defaultInternerSize is the maximum number of distinct strings interned by
+// DefaultInterner. Locales are few, but tuple labels are chosen by users, so
+// the interner is bounded to prevent it from growing without limit.
+const defaultInternerSize = 10000
+
+// DefaultInterner is the Interner that Unmarshal uses to intern the locales
+// and tuple labels of the types that it decodes, so that the many column types
+// of descriptors that share a locale such as "en-US" also share its memory.
+var DefaultInterner = NewInterner(defaultInternerSize)
+
+// An Interner deduplicates the locales and tuple labels of types, so that equal
+// strings share the same memory. The interner is safe for concurrent use by
+// multiple goroutines. It is also safe to use through a nil reference, where
+// it does not intern any string.
+type Interner struct {
+	mu struct {
+		syncutil.Mutex
+		strs map[string]*string
+	}
+	size int
+}
+
+// NewInterner creates a new Interner that interns at most the given number of
+// distinct strings. Once it is full, other strings are returned unchanged.
+func NewInterner(size int) *Interner {
+	in := &Interner{size: size}
+	in.mu.strs = make(map[string]*string)
+	return in
+}
+
+// Intern returns a string that is equal to s, and that shares its memory with
+// all the other strings equal to s that were interned.
+func (in *Interner) Intern(s string) string {
+	return *in.internPtr(s)
+}
+
+// internPtr returns a pointer to a string that is equal to s. The pointer
+// is shared by all the strings equal to s that were interned, and must not
+// be written through.
+func (in *Interner) internPtr(s string) *string {
+	if s == "" {
+		return &emptyLocale
+	}
+	if in == nil {
+		return &s
+	}
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	if p, ok := in.mu.strs[s]; ok {
+		return p
+	}
+	if len(in.mu.strs) < in.size {
+		in.mu.strs[s] = &s
+	}
+	return &s
+}
+
+// InternType replaces the locale and the tuple labels of the given type with
+// interned strings. The types nested in the type are not changed; Unmarshal
+// interns them as it decodes them.
+func (in *Interner) InternType(t *T) {
+	it := &t.InternalType
+	if it.Locale != nil {
+		it.Locale = in.internPtr(*it.Locale)
+	}
+	for i := range it.TupleLabels {
+		it.TupleLabels[i] = in.Intern(it.TupleLabels[i])
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+)
+
+func TestInterner(t *testing.T) {
+	// Types decoded by Unmarshal share the memory of their locales.
+	var a, b T
+	data := marshalOrFatal(t, MakeCollatedString(String, "en-US"))
+	if err := protoutil.Unmarshal(data, &a); err != nil {
+		t.Fatal(err)
+	}
+	if err := protoutil.Unmarshal(data, &b); err != nil {
+		t.Fatal(err)
+	}
+	if a.InternalType.Locale != b.InternalType.Locale {
+		t.Errorf("expected locales of decoded types to be interned")
+	}
+
+	in := NewInterner(2)
+	typ := MakeLabeledTuple([]T{*Int, *MakeCollatedString(String, "de")}, []string{"a", "b"})
+	in.InternType(typ)
+	in.InternType(&typ.TupleContents()[1])
+	if len(in.mu.strs) != 2 {
+		t.Errorf("expected 2 interned strings, got %d", len(in.mu.strs))
+	}
+	if in.internPtr("a") != in.internPtr("a") {
+		t.Errorf("expected interned strings to share memory")
+	}
+	if in.internPtr("de") == in.internPtr("de") || in.Intern("de") != "de" {
+		t.Errorf("expected strings not to be interned once the interner is full")
+	}
+
+	// A nil interner does not intern anything.
+	var nilInterner *Interner
+	if nilInterner.Intern("x") != "x" {
+		t.Errorf("expected nil interner to return strings unchanged")
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
+)
+
+// IntervalTypeMetadata contains the attributes that can be specified for an
+// INTERVAL type, beyond its family:
+//
+//   INTERVAL                  - no attributes
+//   INTERVAL(3)               - Precision=3
+//   INTERVAL HOUR             - DurationField={DurationType: HOUR}
+//   INTERVAL DAY TO SECOND(3) - DurationField={DurationType: SECOND,
+//                                              FromDurationType: DAY},
+//                               Precision=3
+//
+type IntervalTypeMetadata struct {
+	// DurationField is the duration field qualifier of the type. It is empty if
+	// the type has no qualifier.
+	DurationField IntervalDurationField
+	// Precision is the maximum number of fractional second digits. It is only
+	// meaningful if PrecisionIsSet is true.
+	Precision int32
+	// PrecisionIsSet is true if the precision was explicitly specified.
+	PrecisionIsSet bool
+}
+
+// MakeInterval constructs a new instance of an INTERVAL type (oid =
+// T_interval) having the given qualifier and precision. A precision can only
+// be specified if the qualifier is empty or ends in SECOND.
+func MakeInterval(itm IntervalTypeMetadata) *T {
+	df := itm.DurationField
+	if itm.PrecisionIsSet {
+		if itm.Precision < 0 || itm.Precision > MaxTimePrecision {
+			panic(errors.AssertionFailedf("precision %d is not currently supported", itm.Precision))
+		}
+		switch df.DurationType {
+		case IntervalDurationType_UNSET, IntervalDurationType_SECOND:
+		default:
+			panic(errors.AssertionFailedf(
+				"interval %s cannot have precision", df.DurationType))
+		}
+	} else if itm.Precision != 0 {
+		panic(errors.AssertionFailedf("precision %d is set without PrecisionIsSet", itm.Precision))
+	}
+	if !df.isValid() {
+		panic(errors.AssertionFailedf(
+			"invalid interval qualifier %s TO %s", df.FromDurationType, df.DurationType))
+	}
+
+	t := &T{InternalType: InternalType{
+		Family:             IntervalFamily,
+		Oid:                oid.T_interval,
+		Precision:          itm.Precision,
+		TimePrecisionIsSet: itm.PrecisionIsSet,
+		Locale:             &emptyLocale,
+	}}
+	if df != (IntervalDurationField{}) {
+		t.InternalType.IntervalDurationField = &df
+	}
+	return t
+}
+
+// IntervalTypeMetadata returns the qualifier and precision of an INTERVAL
+// type. It returns an error if this is not an INTERVAL type.
+func (t *T) IntervalTypeMetadata() (IntervalTypeMetadata, error) {
+	if t.Family() != IntervalFamily {
+		return IntervalTypeMetadata{}, errors.AssertionFailedf(
+			"cannot call IntervalTypeMetadata on non-interval type %s", t.CompactDebugString())
+	}
+	itm := IntervalTypeMetadata{
+		Precision:      t.InternalType.Precision,
+		PrecisionIsSet: t.InternalType.TimePrecisionIsSet,
+	}
+	if t.InternalType.IntervalDurationField != nil {
+		itm.DurationField = *t.InternalType.IntervalDurationField
+	}
+	return itm, nil
+}
+
+// isValid returns true if the qualifier is one of the forms accepted by the
+// SQL standard: either a single unit, or one of YEAR TO MONTH, DAY TO HOUR,
+// DAY TO MINUTE, DAY TO SECOND, HOUR TO MINUTE, HOUR TO SECOND or MINUTE TO
+// SECOND.
+func (m *IntervalDurationField) isValid() bool {
+	switch m.FromDurationType {
+	case IntervalDurationType_UNSET:
+		return true
+	case IntervalDurationType_YEAR:
+		return m.DurationType == IntervalDurationType_MONTH
+	case IntervalDurationType_DAY:
+		return m.DurationType >= IntervalDurationType_HOUR &&
+			m.DurationType <= IntervalDurationType_SECOND
+	case IntervalDurationType_HOUR:
+		return m.DurationType >= IntervalDurationType_MINUTE &&
+			m.DurationType <= IntervalDurationType_SECOND
+	case IntervalDurationType_MINUTE:
+		return m.DurationType == IntervalDurationType_SECOND
+	}
+	return false
+}
+
+// intervalTypeSQL returns the SQL string for an INTERVAL type, such as
+// "INTERVAL", "INTERVAL(3)" or "INTERVAL DAY TO SECOND(3)".
+func (t *T) intervalTypeSQL() string {
+	itm, err := t.IntervalTypeMetadata()
+	if err != nil {
+		panic(err)
+	}
+	return formatInterval("INTERVAL", itm, strings.ToUpper)
+}
+
+// formatInterval appends the qualifier and precision in itm to the given
+// interval type name. The qualifier keywords are passed through the given
+// case mapping function.
+func formatInterval(name string, itm IntervalTypeMetadata, toCase func(string) string) string {
+	var buf strings.Builder
+	buf.WriteString(name)
+	df := itm.DurationField
+	if df.FromDurationType != IntervalDurationType_UNSET {
+		buf.WriteByte(' ')
+		buf.WriteString(toCase(df.FromDurationType.String()))
+		buf.WriteString(toCase(" to"))
+	}
+	if df.DurationType != IntervalDurationType_UNSET {
+		buf.WriteByte(' ')
+		buf.WriteString(toCase(df.DurationType.String()))
+	}
+	if itm.PrecisionIsSet {
+		buf.WriteString(fmt.Sprintf("(%d)", itm.Precision))
+	}
+	return buf.String()
+}
+
+// These constants mirror the interval typmod encoding used by Postgres (see
+// src/include/utils/timestamp.h and src/include/utils/datetime.h). The typmod
+// of an INTERVAL type stores a bitmask of the units allowed by its qualifier
+// in the upper 16 bits and its precision in the lower 16 bits.
+const (
+	intervalMaskMonth  = 1 << 1
+	intervalMaskYear   = 1 << 2
+	intervalMaskDay    = 1 << 3
+	intervalMaskHour   = 1 << 10
+	intervalMaskMinute = 1 << 11
+	intervalMaskSecond = 1 << 12
+
+	intervalFullRange     = 0x7FFF
+	intervalFullPrecision = 0xFFFF
+)
+
+// intervalUnitMasks maps each IntervalDurationType to its Postgres unit mask.
+var intervalUnitMasks = map[IntervalDurationType]int32{
+	IntervalDurationType_YEAR:   intervalMaskYear,
+	IntervalDurationType_MONTH:  intervalMaskMonth,
+	IntervalDurationType_DAY:    intervalMaskDay,
+	IntervalDurationType_HOUR:   intervalMaskHour,
+	IntervalDurationType_MINUTE: intervalMaskMinute,
+	IntervalDurationType_SECOND: intervalMaskSecond,
+}
+
+// rangeMask returns the Postgres bitmask of all the units that are allowed by
+// the qualifier, from FromDurationType down to DurationType inclusive.
+func (m *IntervalDurationField) rangeMask() int32 {
+	if m.DurationType == IntervalDurationType_UNSET {
+		return intervalFullRange
+	}
+	from := m.FromDurationType
+	if from == IntervalDurationType_UNSET {
+		from = m.DurationType
+	}
+	var mask int32
+	for u := from; u <= m.DurationType; u++ {
+		mask |= intervalUnitMasks[u]
+	}
+	return mask
+}
+
+// IntervalTypmod returns the Postgres type modifier (typmod) for an INTERVAL
+// type, as reported by the atttypmod column of pg_attribute. It returns -1 if
+// the type has neither a qualifier nor a precision, and panics if this is not
+// an INTERVAL type.
+func (t *T) IntervalTypmod() int32 {
+	itm, err := t.IntervalTypeMetadata()
+	if err != nil {
+		panic(err)
+	}
+	if itm.DurationField == (IntervalDurationField{}) && !itm.PrecisionIsSet {
+		return -1
+	}
+	precision := int32(intervalFullPrecision)
+	if itm.PrecisionIsSet {
+		precision = itm.Precision
+	}
+	return (itm.DurationField.rangeMask() << 16) | precision
+}
+
+// MakeIntervalFromTypmod constructs an INTERVAL type from a Postgres type
+// modifier (typmod), such as one returned by IntervalTypmod. A negative typmod
+// results in the unqualified INTERVAL type. It returns an error with the
+// InvalidParameterValue code if the typmod is not valid.
+func MakeIntervalFromTypmod(typmod int32) (*T, error) {
+	if typmod < 0 {
+		return Interval, nil
+	}
+	var itm IntervalTypeMetadata
+	if precision := typmod & 0xFFFF; precision != intervalFullPrecision {
+		if precision > MaxTimePrecision {
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"invalid interval typmod %d: precision %d out of range", typmod, precision)
+		}
+		itm.Precision = precision
+		itm.PrecisionIsSet = true
+	}
+	if mask := (typmod >> 16) & intervalFullRange; mask != intervalFullRange {
+		// Find the most and least significant units in the mask.
+		for u := IntervalDurationType_YEAR; u <= IntervalDurationType_SECOND; u++ {
+			if mask&intervalUnitMasks[u] == 0 {
+				continue
+			}
+			if itm.DurationField.DurationType == IntervalDurationType_UNSET {
+				itm.DurationField.FromDurationType = u
+			}
+			itm.DurationField.DurationType = u
+		}
+		df := &itm.DurationField
+		if df.FromDurationType == df.DurationType {
+			df.FromDurationType = IntervalDurationType_UNSET
+		}
+		if df.DurationType == IntervalDurationType_UNSET || !df.isValid() || df.rangeMask() != mask {
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"invalid interval typmod %d: unknown range %d", typmod, mask)
+		}
+		if itm.PrecisionIsSet && df.DurationType != IntervalDurationType_SECOND {
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"invalid interval typmod %d: precision without seconds", typmod)
+		}
+	}
+	return MakeInterval(itm), nil
+}
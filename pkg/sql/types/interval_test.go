@@ -0,0 +1,90 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+func TestIntervalQualifiers(t *testing.T) {
+	qualified := func(from, to IntervalDurationType) IntervalDurationField {
+		return IntervalDurationField{FromDurationType: from, DurationType: to}
+	}
+	testCases := []struct {
+		itm       IntervalTypeMetadata
+		sqlString string
+		typmod    int32
+		pgName    string
+	}{
+		{IntervalTypeMetadata{}, "INTERVAL", -1, "interval"},
+		{IntervalTypeMetadata{Precision: 0, PrecisionIsSet: true},
+			"INTERVAL(0)", 0x7FFF0000, "interval(0)"},
+		{IntervalTypeMetadata{Precision: 3, PrecisionIsSet: true},
+			"INTERVAL(3)", 0x7FFF0003, "interval(3)"},
+		{IntervalTypeMetadata{DurationField: qualified(IntervalDurationType_UNSET, IntervalDurationType_YEAR)},
+			"INTERVAL YEAR", 0x0004FFFF, "interval year"},
+		{IntervalTypeMetadata{DurationField: qualified(IntervalDurationType_YEAR, IntervalDurationType_MONTH)},
+			"INTERVAL YEAR TO MONTH", 0x0006FFFF, "interval year to month"},
+		{IntervalTypeMetadata{DurationField: qualified(IntervalDurationType_DAY, IntervalDurationType_HOUR)},
+			"INTERVAL DAY TO HOUR", 0x0408FFFF, "interval day to hour"},
+		{IntervalTypeMetadata{DurationField: qualified(IntervalDurationType_HOUR, IntervalDurationType_MINUTE)},
+			"INTERVAL HOUR TO MINUTE", 0x0C00FFFF, "interval hour to minute"},
+		{IntervalTypeMetadata{DurationField: qualified(IntervalDurationType_UNSET, IntervalDurationType_SECOND)},
+			"INTERVAL SECOND", 0x1000FFFF, "interval second"},
+		{IntervalTypeMetadata{
+			DurationField: qualified(IntervalDurationType_UNSET, IntervalDurationType_SECOND),
+			Precision:     2, PrecisionIsSet: true},
+			"INTERVAL SECOND(2)", 0x10000002, "interval second(2)"},
+		{IntervalTypeMetadata{
+			DurationField: qualified(IntervalDurationType_DAY, IntervalDurationType_SECOND),
+			Precision:     6, PrecisionIsSet: true},
+			"INTERVAL DAY TO SECOND(6)", 0x1C080006, "interval day to second(6)"},
+		{IntervalTypeMetadata{DurationField: qualified(IntervalDurationType_MINUTE, IntervalDurationType_SECOND)},
+			"INTERVAL MINUTE TO SECOND", 0x1800FFFF, "interval minute to second"},
+	}
+
+	for _, tc := range testCases {
+		typ := MakeInterval(tc.itm)
+		if actual := typ.SQLString(); actual != tc.sqlString {
+			t.Errorf("expected SQL string %q, got %q", tc.sqlString, actual)
+		}
+		if actual := typ.IntervalTypmod(); actual != tc.typmod {
+			t.Errorf("%s: expected typmod 0x%X, got 0x%X", tc.sqlString, tc.typmod, actual)
+		}
+		if actual := typ.SQLStandardNameWithTypmod(true, int(tc.typmod)); actual != tc.pgName {
+			t.Errorf("expected standard name %q, got %q", tc.pgName, actual)
+		}
+		fromTypmod, err := MakeIntervalFromTypmod(tc.typmod)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !fromTypmod.Identical(typ) {
+			t.Errorf("expected <%v>, got <%v>", typ.DebugString(), fromTypmod.DebugString())
+		}
+		if !typ.Equivalent(Interval) {
+			t.Errorf("expected %s to be equivalent to INTERVAL", tc.sqlString)
+		}
+	}
+
+	// Invalid typmods.
+	for _, typmod := range []int32{
+		0x7FFF0007, // precision out of range
+		0x000CFFFF, // YEAR and DAY without MONTH
+		0x04000003, // precision without SECOND
+	} {
+		if _, err := MakeIntervalFromTypmod(typmod); pgerror.GetPGCode(err) != pgcode.InvalidParameterValue {
+			t.Errorf("expected InvalidParameterValue error for typmod 0x%X, got %v", typmod, err)
+		}
+	}
+}
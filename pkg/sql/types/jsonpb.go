@@ -0,0 +1,67 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"bytes"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gogo/protobuf/jsonpb"
+)
+
+var _ jsonpb.JSONPBMarshaler = (*T)(nil)
+var _ jsonpb.JSONPBUnmarshaler = (*T)(nil)
+
+// MarshalJSONPB serializes a type into its JSON representation, which is the
+// JSON representation of its InternalType. T itself has no proto fields, so
+// without this method jsonpb would marshal every type (including the elements
+// of arrays and tuples embedded in other messages, such as the columns of
+// descriptors in the debug endpoints) as an empty object.
+//
+// Like Marshal, the type is first downgraded to the format of the previous
+// version of CRDB, so that the JSON representation has the same fields as the
+// binary representation. Enums are marshaled by name unless the marshaler sets
+// EnumsAsInts, and the Oid field is marshaled as a number.
+//
+// MarshalJSONPB is part of the jsonpb.JSONPBMarshaler interface.
+func (t *T) MarshalJSONPB(m *jsonpb.Marshaler) ([]byte, error) {
+	temp := *t
+	if err := temp.downgradeType(); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := m.Marshal(&buf, &temp.InternalType); err != nil {
+		return nil, errors.Wrapf(err, "error marshaling type %s to JSON", t.SQLString())
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSONPB deserializes a type from the JSON representation produced by
+// MarshalJSONPB. Like Unmarshal, it upgrades the type from the formats of older
+// versions of CRDB and interns its locale and tuple labels. Enums may be given
+// either by name or by number.
+//
+// UnmarshalJSONPB is part of the jsonpb.JSONPBUnmarshaler interface.
+func (t *T) UnmarshalJSONPB(u *jsonpb.Unmarshaler, data []byte) error {
+	var it InternalType
+	if err := u.Unmarshal(bytes.NewReader(data), &it); err != nil {
+		return errors.Wrap(err, "error unmarshaling type from JSON")
+	}
+	t.InternalType = it
+	if err := t.upgradeType(); err != nil {
+		return err
+	}
+	if err := t.migrateType(); err != nil {
+		return err
+	}
+	DefaultInterner.InternType(t)
+	return nil
+}
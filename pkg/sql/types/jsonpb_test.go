@@ -0,0 +1,71 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gogo/protobuf/jsonpb"
+)
+
+func TestMarshalJSONPB(t *testing.T) {
+	typs := append(AllTypes(), []*T{
+		MakeCollatedString(MakeVarChar(20), "en_US"),
+		MakeArray(MakeCollatedString(String, "de")),
+		MakeLabeledTuple([]T{*Int, *MakeArray(MakeDecimal(10, 2))}, []string{"a", "b"}),
+		MakeTuple([]T{*MakeTuple([]T{*Float4, *Name}), *MakeArray(MakeBit(3))}),
+		MakeInterval(IntervalTypeMetadata{
+			Precision:      3,
+			PrecisionIsSet: true,
+			DurationField:  IntervalDurationField{FromDurationType: IntervalDurationType_DAY, DurationType: IntervalDurationType_SECOND},
+		}),
+	}...)
+	marshalers := []jsonpb.Marshaler{{}, {EnumsAsInts: true, OrigName: true}}
+	for _, typ := range typs {
+		if temp := *typ; temp.downgradeType() != nil {
+			// Nested arrays cannot be marshaled.
+			continue
+		}
+		for _, m := range marshalers {
+			js, err := m.MarshalToString(typ)
+			if err != nil {
+				t.Fatalf("%s: %v", typ.DebugString(), err)
+			}
+			var roundTripped T
+			if err := jsonpb.UnmarshalString(js, &roundTripped); err != nil {
+				t.Fatalf("%s: %s: %v", typ.DebugString(), js, err)
+			}
+			if !roundTripped.Identical(typ) {
+				t.Errorf("expected %s, but got %s from %s",
+					typ.DebugString(), roundTripped.DebugString(), js)
+			}
+		}
+	}
+
+	// The enums are marshaled by name, and the elements of arrays and tuples
+	// are marshaled like the types themselves.
+	js, err := (&jsonpb.Marshaler{}).MarshalToString(MakeArray(MakeTuple([]T{*Int2})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const expected = `{"family":"ArrayFamily","arrayElemType":"TupleFamily","oid":2287,` +
+		`"arrayContents":{"family":"TupleFamily","tupleContents":[` +
+		`{"family":"IntFamily","width":16,"oid":21}],"oid":2249}}`
+	if js != expected {
+		t.Errorf("expected %s, but got %s", expected, js)
+	}
+
+	if _, err := (&jsonpb.Marshaler{}).MarshalToString(MakeArray(IntArray)); err == nil ||
+		!strings.Contains(err.Error(), "nested array should never be marshaled") {
+		t.Errorf("expected an error marshaling a nested array, but got %v", err)
+	}
+}
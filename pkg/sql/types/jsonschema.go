@@ -0,0 +1,145 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/lib/pq/oid"
+)
+
+// JSONSchema returns a JSON Schema (draft 7) fragment describing the JSON
+// representation of values of the given type, as produced by the SQL to_json
+// function and by changefeeds that use the JSON format. The fragment can be
+// serialized with encoding/json, for example to validate row payloads sent to
+// a webhook. If nullable is true, the fragment also accepts null.
+//
+// The fragment includes the constraints implied by the type where JSON Schema
+// can express them: the bounds of INT values for their width, the maximum
+// length of VARCHAR(n) values, and the range of DECIMAL(p,s) values. Values with a
+// standard format are annotated accordingly: TIMESTAMPTZ values use the
+// "date-time" format, DATE values the "date" format and UUID values the
+// "uuid" format. TIMESTAMP and TIME values have no time zone offset, so they
+// do not conform to any standard format.
+func JSONSchema(t *T, nullable bool) (map[string]interface{}, error) {
+	schema := map[string]interface{}{}
+
+	var jsonType string
+	switch t.Family() {
+	case BoolFamily:
+		jsonType = "boolean"
+
+	case IntFamily:
+		jsonType = "integer"
+		min, max, err := IntBounds(t.Width())
+		if err != nil {
+			return nil, err
+		}
+		schema["minimum"] = min
+		schema["maximum"] = max
+
+	case FloatFamily:
+		jsonType = "number"
+
+	case DecimalFamily:
+		jsonType = "number"
+		if t.Precision() > 0 {
+			bound := math.Pow10(int(t.Precision() - t.Scale()))
+			schema["exclusiveMinimum"] = -bound
+			schema["exclusiveMaximum"] = bound
+		}
+
+	case StringFamily, CollatedStringFamily:
+		jsonType = "string"
+		if t.Width() > 0 {
+			schema["maxLength"] = t.Width()
+		}
+
+	case BitFamily:
+		jsonType = "string"
+		schema["pattern"] = "^[01]*$"
+		if t.Width() > 0 {
+			schema["maxLength"] = t.Width()
+			if t.Oid() != oid.T_varbit {
+				schema["minLength"] = t.Width()
+			}
+		}
+
+	case DateFamily:
+		jsonType = "string"
+		schema["format"] = "date"
+
+	case TimestampTZFamily:
+		jsonType = "string"
+		schema["format"] = "date-time"
+
+	case UuidFamily:
+		jsonType = "string"
+		schema["format"] = "uuid"
+
+	case BytesFamily, TimeFamily, TimestampFamily, IntervalFamily, INetFamily, OidFamily:
+		// These types are represented by their SQL text representation.
+		jsonType = "string"
+
+	case JsonFamily:
+		// Any JSON value is allowed, including null.
+		return schema, nil
+
+	case UnknownFamily:
+		jsonType = "null"
+		nullable = false
+
+	case ArrayFamily:
+		items, err := JSONSchema(t.ArrayContents(), true /* nullable */)
+		if err != nil {
+			return nil, err
+		}
+		jsonType = "array"
+		schema["items"] = items
+
+	case TupleFamily:
+		if IsWildcardTupleType(t) {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"type %s has no JSON Schema", t.SQLString())
+		}
+		// Tuple fields are named f1, f2, etc. in the JSON representation,
+		// regardless of any labels.
+		jsonType = "object"
+		props := make(map[string]interface{}, len(t.TupleContents()))
+		required := make([]string, len(t.TupleContents()))
+		for i := range t.TupleContents() {
+			field, err := JSONSchema(&t.TupleContents()[i], true /* nullable */)
+			if err != nil {
+				return nil, err
+			}
+			name := fmt.Sprintf("f%d", i+1)
+			props[name] = field
+			required[i] = name
+		}
+		schema["properties"] = props
+		schema["required"] = required
+		schema["additionalProperties"] = false
+
+	default:
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"type %s has no JSON Schema", t.SQLString())
+	}
+
+	if nullable {
+		schema["type"] = []string{jsonType, "null"}
+	} else {
+		schema["type"] = jsonType
+	}
+	return schema, nil
+}
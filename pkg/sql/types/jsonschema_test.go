@@ -0,0 +1,65 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestJSONSchema(t *testing.T) {
+	testCases := []struct {
+		typ      *T
+		nullable bool
+		expected string
+	}{
+		{Bool, false, `{"type":"boolean"}`},
+		{Int2, false, `{"maximum":32767,"minimum":-32768,"type":"integer"}`},
+		{Int, true, `{"maximum":9223372036854775807,"minimum":-9223372036854775808,"type":["integer","null"]}`},
+		{Float, false, `{"type":"number"}`},
+		{Decimal, false, `{"type":"number"}`},
+		{MakeDecimal(5, 2), false, `{"exclusiveMaximum":1000,"exclusiveMinimum":-1000,"type":"number"}`},
+		{String, false, `{"type":"string"}`},
+		{MakeVarChar(10), true, `{"maxLength":10,"type":["string","null"]}`},
+		{MakeBit(3), false, `{"maxLength":3,"minLength":3,"pattern":"^[01]*$","type":"string"}`},
+		{MakeVarBit(3), false, `{"maxLength":3,"pattern":"^[01]*$","type":"string"}`},
+		{Date, false, `{"format":"date","type":"string"}`},
+		{Timestamp, false, `{"type":"string"}`},
+		{TimestampTZ, false, `{"format":"date-time","type":"string"}`},
+		{Uuid, false, `{"format":"uuid","type":"string"}`},
+		{Jsonb, true, `{}`},
+		{Unknown, true, `{"type":"null"}`},
+		{IntArray, false, `{"items":{"maximum":9223372036854775807,"minimum":-9223372036854775808,` +
+			`"type":["integer","null"]},"type":"array"}`},
+		{MakeLabeledTuple([]T{*Bool, *String}, []string{"a", "b"}), false,
+			`{"additionalProperties":false,"properties":{"f1":{"type":["boolean","null"]},` +
+				`"f2":{"type":["string","null"]}},"required":["f1","f2"],"type":"object"}`},
+	}
+
+	for _, tc := range testCases {
+		schema, err := JSONSchema(tc.typ, tc.nullable)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.typ.SQLString(), err)
+			continue
+		}
+		j, err := json.Marshal(schema)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(j) != tc.expected {
+			t.Errorf("%s: expected %s, got %s", tc.typ.SQLString(), tc.expected, j)
+		}
+	}
+
+	if _, err := JSONSchema(Any, false /* nullable */); err == nil {
+		t.Error("expected error for wildcard type")
+	}
+}
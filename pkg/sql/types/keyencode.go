@@ -0,0 +1,61 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+// IsKeyEncodable returns whether the values of the type can appear in the keys
+// of indexes, which requires an encoding that preserves the order of values.
+// If they cannot, it also returns the reason, such as "JSONB values have no
+// key encoding". It is the single place that decides which types can be
+// indexed, for use by DDL validation and by random schema generators. JSONB
+// values are not key-encodable, but they can be indexed by inverted indexes.
+//
+// Collated strings are key-encoded using their collation key (see
+// CollationKey), so they are not key-encodable if their collation is
+// nondeterministic: if different strings have the same collation key, such as
+// under a case-insensitive locale, they cannot be told apart in the index.
+func (t *T) IsKeyEncodable() (ok bool, reason string) {
+	switch t.Family() {
+	case JsonFamily:
+		return false, "JSONB values have no key encoding"
+	case ArrayFamily:
+		if t.ArrayContents().Family() == TupleFamily {
+			return false, "arrays of tuples have no key encoding"
+		}
+		return false, "arrays have no key encoding"
+	case TupleFamily:
+		return false, "tuples have no key encoding"
+	case CollatedStringFamily:
+		if t.IsNondeterministicCollation() {
+			return false, "collated strings with a nondeterministic collation have no key encoding"
+		}
+	}
+	return true, ""
+}
+
+// IsNondeterministicCollation returns true if the type is a collated string
+// type whose locale compares some different strings as equal, because it sets
+// the collation strength ("ks" keyword) to level1 or level2, which ignore
+// differences of case and, with level1, of accents. For example, the locale
+// "en-u-ks-level2" is case-insensitive.
+func (t *T) IsNondeterministicCollation() bool {
+	if t.Family() != CollatedStringFamily || t.Locale() == "" {
+		return false
+	}
+	tag, err := t.LocaleTag()
+	if err != nil {
+		return false
+	}
+	switch tag.TypeForKey("ks") {
+	case "level1", "level2":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"testing"
+)
+
+func TestIsKeyEncodable(t *testing.T) {
+	testCases := []struct {
+		typ    *T
+		reason string
+	}{
+		{Int, ""},
+		{MakeDecimal(10, 2), ""},
+		{MakeCollatedString(String, "en"), ""},
+		{MakeCollatedString(String, "en-u-ks-level3"), ""},
+		{MakeCollatedString(MakeVarChar(10), "en-u-ks-level2"),
+			"collated strings with a nondeterministic collation have no key encoding"},
+		{MakeCollatedString(String, "de-u-ks-level1"),
+			"collated strings with a nondeterministic collation have no key encoding"},
+		{Jsonb, "JSONB values have no key encoding"},
+		{IntArray, "arrays have no key encoding"},
+		{MakeArray(MakeTuple([]T{*Int})), "arrays of tuples have no key encoding"},
+		{MakeTuple([]T{*Int}), "tuples have no key encoding"},
+	}
+	for _, tc := range testCases {
+		ok, reason := tc.typ.IsKeyEncodable()
+		if ok != (tc.reason == "") || reason != tc.reason {
+			t.Errorf("%s: expected %q, got %t, %q", tc.typ.SQLString(), tc.reason, ok, reason)
+		}
+	}
+}
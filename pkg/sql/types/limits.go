@@ -0,0 +1,111 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// These are the limits on the serialized types accepted by Unmarshal. They
+// protect against descriptors that are corrupted or crafted to exhaust the
+// stack with deeply nested ARRAY and TUPLE types, or the memory with huge
+// types. Types created by CockroachDB are far below these limits.
+const (
+	// MaxTypeNestingDepth is the maximum number of ARRAY and TUPLE types that
+	// enclose the innermost element of a type. For example, INT[] has a depth
+	// of 1, and a TUPLE of a TUPLE of INT[] has a depth of 3.
+	MaxTypeNestingDepth = 64
+	// MaxTypeSize is the maximum size in bytes of a serialized type.
+	MaxTypeSize = 4 << 20
+)
+
+// UnmarshalLimitError is the error returned by Unmarshal when a serialized type
+// exceeds MaxTypeNestingDepth or MaxTypeSize. Callers can detect it with
+// errors.As.
+type UnmarshalLimitError struct {
+	// Limit describes the exceeded limit, such as "nesting depth".
+	Limit string
+	// Max is the value of the exceeded limit.
+	Max int
+}
+
+// Error is part of the error interface.
+func (e *UnmarshalLimitError) Error() string {
+	return fmt.Sprintf("serialized type exceeds the maximum %s of %d", e.Limit, e.Max)
+}
+
+// These are the field numbers of the InternalType fields that contain nested
+// types, as declared in types.proto.
+const (
+	tupleContentsFieldNumber = 8
+	arrayContentsFieldNumber = 11
+)
+
+// checkUnmarshalLimits returns an UnmarshalLimitError if the given serialized
+// type exceeds MaxTypeSize or MaxTypeNestingDepth. It scans the protobuf wire
+// format without decoding it, and stops scanning at the first malformed field,
+// since malformed data is reported by the decoder.
+func checkUnmarshalLimits(data []byte) error {
+	if len(data) > MaxTypeSize {
+		return &UnmarshalLimitError{Limit: "size in bytes", Max: MaxTypeSize}
+	}
+	if !checkTypeNestingDepth(data, 0) {
+		return &UnmarshalLimitError{Limit: "nesting depth", Max: MaxTypeNestingDepth}
+	}
+	return nil
+}
+
+// checkTypeNestingDepth returns false if the given serialized type, which is
+// nested in depth ARRAY and TUPLE types, contains types that are nested
+// deeper than MaxTypeNestingDepth. The recursion is bounded by the limit.
+func checkTypeNestingDepth(data []byte, depth int) bool {
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return true
+		}
+		data = data[n:]
+		switch key & 0x7 {
+		case 0: // varint
+			if _, n = binary.Uvarint(data); n <= 0 {
+				return true
+			}
+			data = data[n:]
+		case 1: // fixed64
+			if len(data) < 8 {
+				return true
+			}
+			data = data[8:]
+		case 5: // fixed32
+			if len(data) < 4 {
+				return true
+			}
+			data = data[4:]
+		case 2: // length-delimited
+			length, n := binary.Uvarint(data)
+			if n <= 0 || length > uint64(len(data)-n) {
+				return true
+			}
+			field := data[n : n+int(length)]
+			data = data[n+int(length):]
+			switch key >> 3 {
+			case tupleContentsFieldNumber, arrayContentsFieldNumber:
+				if depth+1 > MaxTypeNestingDepth || !checkTypeNestingDepth(field, depth+1) {
+					return false
+				}
+			}
+		default:
+			return true
+		}
+	}
+	return true
+}
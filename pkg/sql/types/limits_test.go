@@ -0,0 +1,63 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/errors"
+)
+
+func TestUnmarshalLimits(t *testing.T) {
+	// nested returns a type in which depth ARRAY and TUPLE types alternately
+	// enclose an INT.
+	nested := func(depth int) *T {
+		typ := Int
+		for i := 0; i < depth; i++ {
+			if i%2 == 0 {
+				typ = MakeArray(typ)
+			} else {
+				typ = MakeTuple([]T{*typ})
+			}
+		}
+		return typ
+	}
+
+	for _, tc := range []struct {
+		data  []byte
+		limit string
+	}{
+		{data: marshalOrFatal(t, nested(MaxTypeNestingDepth))},
+		{data: marshalOrFatal(t, nested(MaxTypeNestingDepth+1)), limit: "nesting depth"},
+		{data: marshalOrFatal(t, nested(10*MaxTypeNestingDepth)), limit: "nesting depth"},
+		{data: make([]byte, MaxTypeSize+1), limit: "size in bytes"},
+	} {
+		var typ T
+		err := protoutil.Unmarshal(tc.data, &typ)
+		var limitErr *UnmarshalLimitError
+		if tc.limit == "" {
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		} else if !errors.As(err, &limitErr) || limitErr.Limit != tc.limit {
+			t.Errorf("expected %s limit error, got %v", tc.limit, err)
+		}
+	}
+}
+
+func marshalOrFatal(t *testing.T, typ *T) []byte {
+	data, err := protoutil.Marshal(typ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
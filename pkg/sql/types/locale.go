@@ -0,0 +1,55 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/errors"
+	"golang.org/x/text/language"
+)
+
+// ParseLocale parses and validates a collation locale, such as "en_US" or
+// "de-u-co-phonebk", returning it as a BCP 47 language tag. It is the single
+// place where locales given by users are validated, and returns an error with
+// the InvalidParameterValue code if the locale is not well-formed.
+//
+// The tag exposes the components of the locale: its language (Tag.Base),
+// script (Tag.Script), region (Tag.Region) and variants (Tag.Variants).
+func ParseLocale(locale string) (language.Tag, error) {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return language.Und, pgerror.Wrapf(err, pgcode.InvalidParameterValue, "invalid locale %s", locale)
+	}
+	return tag, nil
+}
+
+// MakeCollatedStringFromTag is like MakeCollatedString, but takes a language
+// tag rather than a locale string. The locale of the new type is the canonical
+// string form of the tag, such as "en-US".
+func MakeCollatedStringFromTag(strType *T, tag language.Tag) *T {
+	return MakeCollatedString(strType, tag.String())
+}
+
+// LocaleTag returns the locale of a type in the CollatedStringFamily as a
+// language tag. The locale is persisted as a string in the Locale field, and is
+// parsed on each call. LocaleTag returns an error if the locale of the wildcard
+// collated string type (which has no locale) is requested, or if the type is
+// not in the CollatedStringFamily.
+func (t *T) LocaleTag() (language.Tag, error) {
+	if t.Family() != CollatedStringFamily {
+		return language.Und, errors.AssertionFailedf("type %s has no locale", t.SQLString())
+	}
+	if t.Locale() == "" {
+		return language.Und, errors.AssertionFailedf("wildcard collated string type has no locale")
+	}
+	return ParseLocale(t.Locale())
+}
@@ -0,0 +1,70 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/lib/pq/oid"
+	"golang.org/x/text/language"
+)
+
+func TestLocaleTag(t *testing.T) {
+	testCases := []struct {
+		locale string
+		lang   string
+		region string
+	}{
+		{"en", "en", "US"},
+		{"en_US", "en", "US"},
+		{"en-GB", "en", "GB"},
+		{"de-u-co-phonebk", "de", "DE"},
+		{"fr_CA", "fr", "CA"},
+	}
+
+	for _, tc := range testCases {
+		typ := MakeCollatedString(String, tc.locale)
+		tag, err := typ.LocaleTag()
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.locale, err)
+			continue
+		}
+		if base, _ := tag.Base(); base.String() != tc.lang {
+			t.Errorf("%s: expected language %s, got %s", tc.locale, tc.lang, base)
+		}
+		if region, _ := tag.Region(); region.String() != tc.region {
+			t.Errorf("%s: expected region %s, got %s", tc.locale, tc.region, region)
+		}
+
+		// A type constructed from the tag has the canonical form of the locale.
+		fromTag := MakeCollatedStringFromTag(MakeVarChar(10), tag)
+		if fromTag.Locale() != tag.String() || fromTag.Oid() != oid.T_varchar {
+			t.Errorf("%s: unexpected type %s", tc.locale, fromTag.DebugString())
+		}
+	}
+
+	if _, err := ParseLocale("e"); err == nil {
+		t.Error("expected error for malformed locale")
+	} else if code := pgerror.GetPGCode(err); code != pgcode.InvalidParameterValue {
+		t.Errorf("expected code %s, got %s", pgcode.InvalidParameterValue, code)
+	}
+	if tag, err := ParseLocale("sv"); err != nil || tag != language.Swedish {
+		t.Errorf("expected %s, got %s (%v)", language.Swedish, tag, err)
+	}
+	if _, err := String.LocaleTag(); err == nil {
+		t.Error("expected error for non-collated type")
+	}
+	if _, err := AnyCollatedString.LocaleTag(); err == nil {
+		t.Error("expected error for wildcard collated string type")
+	}
+}
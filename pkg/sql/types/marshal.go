@@ -0,0 +1,138 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import "github.com/cockroachdb/errors"
+
+// MarshalAppend appends the serialized form of the type, which is the same as
+// the one returned by Marshal, to b and returns the extended slice. It does not
+// allocate if b has sufficient capacity for the Size of the type, so callers
+// that marshal many types can reuse a single buffer.
+//
+// The generated marshaling code computes the size of every nested type again
+// for each of the types that enclose it, which makes marshaling deeply nested
+// tuples quadratic. Instead, the type is encoded from the end of the buffer to
+// its start, so that the size of each nested type is known once it has been
+// encoded, and the whole type is encoded in a single pass.
+func (t *T) MarshalAppend(b []byte) ([]byte, error) {
+	size := t.Size()
+	n := len(b)
+	if cap(b)-n < size {
+		grown := make([]byte, n, n+size)
+		copy(grown, b)
+		b = grown
+	}
+	b = b[:n+size]
+	start, err := t.marshalBackward(b[n:], size)
+	if err != nil {
+		return nil, err
+	}
+	if start != 0 {
+		return nil, errors.AssertionFailedf(
+			"marshaled %d bytes, but the size of the type is %d", size-start, size)
+	}
+	return b, nil
+}
+
+// marshalBackward encodes the type so that it ends at offset i of data, and
+// returns the offset at which it starts. The fields are encoded in the reverse of
+// the order used by the generated InternalType.MarshalTo, so that the result is
+// identical. This must be kept in sync with the fields declared in types.proto;
+// TestMarshalAppend compares the result with the one of the generated code.
+func (t *T) marshalBackward(data []byte, i int) (int, error) {
+	// Downgrade the type like Marshal does.
+	temp := *t
+	if err := temp.downgradeType(); err != nil {
+		return 0, err
+	}
+	m := &temp.InternalType
+	var err error
+
+	i -= len(m.XXX_unrecognized)
+	copy(data[i:], m.XXX_unrecognized)
+	if m.WidthUnit != nil {
+		i = putVarintFieldBackward(data, i, 0x78, uint64(*m.WidthUnit))
+	}
+	if m.Version != nil {
+		i = putVarintFieldBackward(data, i, 0x70, uint64(*m.Version))
+	}
+	if f := m.IntervalDurationField; f != nil {
+		end := i
+		i = putVarintFieldBackward(data, i, 0x10, uint64(f.FromDurationType))
+		i = putVarintFieldBackward(data, i, 0x8, uint64(f.DurationType))
+		i = putVarintFieldBackward(data, i, 0x6a, uint64(end-i))
+	}
+	var timePrecisionIsSet uint64
+	if m.TimePrecisionIsSet {
+		timePrecisionIsSet = 1
+	}
+	i = putVarintFieldBackward(data, i, 0x60, timePrecisionIsSet)
+	if m.ArrayContents != nil {
+		end := i
+		if i, err = m.ArrayContents.marshalBackward(data, i); err != nil {
+			return 0, err
+		}
+		i = putVarintFieldBackward(data, i, 0x5a, uint64(end-i))
+	}
+	i = putVarintFieldBackward(data, i, 0x50, uint64(m.Oid))
+	for j := len(m.TupleLabels) - 1; j >= 0; j-- {
+		label := m.TupleLabels[j]
+		i -= len(label)
+		copy(data[i:], label)
+		i = putVarintFieldBackward(data, i, 0x4a, uint64(len(label)))
+	}
+	for j := len(m.TupleContents) - 1; j >= 0; j-- {
+		end := i
+		if i, err = m.TupleContents[j].marshalBackward(data, i); err != nil {
+			return 0, err
+		}
+		i = putVarintFieldBackward(data, i, 0x42, uint64(end-i))
+	}
+	if m.ArrayElemType != nil {
+		i = putVarintFieldBackward(data, i, 0x38, uint64(*m.ArrayElemType))
+	}
+	i = putVarintFieldBackward(data, i, 0x30, uint64(m.VisibleType))
+	if m.Locale != nil {
+		i -= len(*m.Locale)
+		copy(data[i:], *m.Locale)
+		i = putVarintFieldBackward(data, i, 0x2a, uint64(len(*m.Locale)))
+	}
+	if len(m.ArrayDimensions) > 0 {
+		// The dimensions are packed.
+		end := i
+		for j := len(m.ArrayDimensions) - 1; j >= 0; j-- {
+			i = putVarintBackward(data, i, uint64(m.ArrayDimensions[j]))
+		}
+		i = putVarintFieldBackward(data, i, 0x22, uint64(end-i))
+	}
+	i = putVarintFieldBackward(data, i, 0x18, uint64(m.Precision))
+	i = putVarintFieldBackward(data, i, 0x10, uint64(m.Width))
+	i = putVarintFieldBackward(data, i, 0x8, uint64(m.Family))
+	return i, nil
+}
+
+// putVarintBackward encodes v as a varint that ends at offset i of data, and
+// returns the offset at which it starts.
+func putVarintBackward(data []byte, i int, v uint64) int {
+	i -= sovTypes(v)
+	encodeVarintTypes(data, i, v)
+	return i
+}
+
+// putVarintFieldBackward encodes the given key, followed by v as a varint, so
+// that they end at offset i of data, and returns the offset at which the key
+// starts. All the keys of InternalType fit in a single byte.
+func putVarintFieldBackward(data []byte, i int, key byte, v uint64) int {
+	i = putVarintBackward(data, i, v)
+	i--
+	data[i] = key
+	return i
+}
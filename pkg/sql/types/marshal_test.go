@@ -0,0 +1,150 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+)
+
+func TestMarshalAppend(t *testing.T) {
+	// InternalType has a field per field of types.proto, and XXX_unrecognized.
+	// marshalBackward must be updated when fields are added.
+	if n := reflect.TypeOf(InternalType{}).NumField(); n != 16 {
+		t.Fatalf("InternalType has %d fields, but marshalBackward encodes 16", n)
+	}
+
+	var unrecognized T
+	if err := protoutil.Unmarshal([]byte{0x08, 0x0c, 0xa0, 0x06, 0x2a}, &unrecognized); err != nil {
+		t.Fatal(err)
+	}
+	deep := MakeLabeledTuple([]T{*Int, *MakeArray(MakeCollatedString(String, "de"))}, []string{"a", "b"})
+	for i := 0; i < 10; i++ {
+		deep = MakeTuple([]T{*deep, *MakeArray(MakeDecimal(10, 2)), *deep})
+	}
+	typs := []*T{
+		MakeCollatedString(MakeVarChar(20), "en_US"),
+		MakeStringWithWidthUnit(MakeVarChar(20), StringWidthUnit_BYTES),
+		MakeInterval(IntervalTypeMetadata{
+			Precision:      3,
+			PrecisionIsSet: true,
+			DurationField:  IntervalDurationField{FromDurationType: IntervalDurationType_DAY, DurationType: IntervalDurationType_SECOND},
+		}),
+		MakeTuple([]T{unrecognized, *MakeArray(&unrecognized)}),
+		deep,
+	}
+	for _, typ := range OidToType {
+		typs = append(typs, typ)
+	}
+	rng, _ := randutil.NewPseudoRand()
+	for i := 0; i < 1000; i++ {
+		typs = append(typs, randMarshalType(rng, 3 /* depth */))
+	}
+
+	// The encoding of each type must be identical to the one of the generated
+	// code, which is used by Marshal and MarshalTo.
+	prefix := []byte("prefix")
+	for _, typ := range typs {
+		temp := *typ
+		if err := temp.downgradeType(); err != nil {
+			// Nested arrays cannot be marshaled.
+			continue
+		}
+		expected, err := temp.InternalType.Marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual, err := typ.MarshalAppend(prefix[:len(prefix):len(prefix)])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(actual, append(prefix, expected...)) {
+			t.Fatalf("%s: expected %x, got %x", typ.DebugString(), expected, actual[len(prefix):])
+		}
+	}
+
+	// Marshaling to a buffer that is large enough does not allocate.
+	buf := make([]byte, 0, deep.Size())
+	if allocs := testing.AllocsPerRun(10, func() {
+		if deep.Size() != cap(buf) {
+			t.Fatal("unexpected size")
+		}
+		if _, err := deep.MarshalAppend(buf); err != nil {
+			t.Fatal(err)
+		}
+	}); allocs != 0 {
+		t.Errorf("expected no allocations, got %.1f", allocs)
+	}
+}
+
+// randMarshalType returns a random type for TestMarshalAppend, which sets the
+// optional fields of InternalType: arrays, labeled and unlabeled tuples of
+// types nested up to the given depth, collated strings, and intervals with
+// qualifiers.
+func randMarshalType(rng *rand.Rand, depth int) *T {
+	n := 5
+	if depth > 0 {
+		n = 7
+	}
+	switch rng.Intn(n) {
+	case 0:
+		return Scalar[rng.Intn(len(Scalar))]
+	case 1:
+		return MakeVarChar(int32(rng.Intn(100)))
+	case 2:
+		locales := []string{"en", "en_US", "de", "fr_CA"}
+		return MakeCollatedString(MakeVarChar(int32(rng.Intn(10))), locales[rng.Intn(len(locales))])
+	case 3:
+		precision := int32(1 + rng.Intn(20))
+		return MakeDecimal(precision, int32(rng.Intn(int(precision))))
+	case 4:
+		qualifiers := []IntervalDurationField{
+			{},
+			{DurationType: IntervalDurationType_YEAR},
+			{DurationType: IntervalDurationType_SECOND},
+			{FromDurationType: IntervalDurationType_YEAR, DurationType: IntervalDurationType_MONTH},
+			{FromDurationType: IntervalDurationType_DAY, DurationType: IntervalDurationType_HOUR},
+			{FromDurationType: IntervalDurationType_DAY, DurationType: IntervalDurationType_SECOND},
+			{FromDurationType: IntervalDurationType_MINUTE, DurationType: IntervalDurationType_SECOND},
+		}
+		itm := IntervalTypeMetadata{DurationField: qualifiers[rng.Intn(len(qualifiers))]}
+		switch itm.DurationField.DurationType {
+		case IntervalDurationType_UNSET, IntervalDurationType_SECOND:
+			if rng.Intn(2) == 0 {
+				itm.Precision, itm.PrecisionIsSet = int32(rng.Intn(MaxTimePrecision+1)), true
+			}
+		}
+		return MakeInterval(itm)
+	case 5:
+		elem := randMarshalType(rng, depth-1)
+		for elem.Family() == ArrayFamily {
+			elem = randMarshalType(rng, depth-1)
+		}
+		return MakeArray(elem)
+	default:
+		contents := make([]T, rng.Intn(4))
+		labels := make([]string, len(contents))
+		for i := range contents {
+			contents[i] = *randMarshalType(rng, depth-1)
+			labels[i] = fmt.Sprintf("f%d", i)
+		}
+		if rng.Intn(2) == 0 {
+			return MakeTuple(contents)
+		}
+		return MakeLabeledTuple(contents, labels)
+	}
+}
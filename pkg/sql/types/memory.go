@@ -0,0 +1,73 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import "unsafe"
+
+// These are the sizes of the values that are referenced by the fields of
+// InternalType.
+const (
+	sizeOfT                     = unsafe.Sizeof(T{})
+	sizeOfString                = unsafe.Sizeof("")
+	sizeOfInt32                 = unsafe.Sizeof(int32(0))
+	sizeOfFamily                = unsafe.Sizeof(Family(0))
+	sizeOfIntervalDurationField = unsafe.Sizeof(IntervalDurationField{})
+	sizeOfWidthUnit             = unsafe.Sizeof(StringWidthUnit(0))
+)
+
+// MemoryUsage returns the approximate number of bytes of memory used by the
+// type, including the memory referenced by it: its nested types, tuple labels,
+// locale, etc. It is meant for charging the types stored by catalog and plan
+// caches to memory monitors. The memory of the slices is their capacity.
+//
+// Memory that may be shared with other types, such as interned locales and
+// nested types, is counted as if it were not shared, so the result is an upper
+// bound on the memory retained by the type. The only exception is the empty
+// locale that is shared by most types, which is not counted.
+func (t *T) MemoryUsage() uintptr {
+	return sizeOfT + t.referencedMemoryUsage()
+}
+
+// referencedMemoryUsage returns the part of MemoryUsage that is referenced by
+// the fields of the type, as opposed to the memory of the struct itself.
+func (t *T) referencedMemoryUsage() uintptr {
+	it := &t.InternalType
+	var n uintptr
+	if it.Locale != nil && it.Locale != &emptyLocale {
+		n += sizeOfString + uintptr(len(*it.Locale))
+	}
+	n += uintptr(cap(it.ArrayDimensions)) * sizeOfInt32
+	if it.ArrayElemType != nil {
+		n += sizeOfFamily
+	}
+	n += uintptr(cap(it.TupleContents)) * sizeOfT
+	for i := range it.TupleContents {
+		n += it.TupleContents[i].referencedMemoryUsage()
+	}
+	n += uintptr(cap(it.TupleLabels)) * sizeOfString
+	for _, label := range it.TupleLabels {
+		n += uintptr(len(label))
+	}
+	if it.ArrayContents != nil {
+		n += it.ArrayContents.MemoryUsage()
+	}
+	if it.IntervalDurationField != nil {
+		n += sizeOfIntervalDurationField
+	}
+	if it.Version != nil {
+		n += sizeOfInt32
+	}
+	if it.WidthUnit != nil {
+		n += sizeOfWidthUnit
+	}
+	n += uintptr(cap(it.XXX_unrecognized))
+	return n
+}
@@ -0,0 +1,56 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+)
+
+func TestMemoryUsage(t *testing.T) {
+	sizeOfT := unsafe.Sizeof(T{})
+	sizeOfString := unsafe.Sizeof("")
+
+	locale := "de"
+	labels := []string{"a", "bc"}
+	tuple := MakeLabeledTuple([]T{*Int, *MakeCollatedString(String, locale)}, labels)
+	testCases := []struct {
+		typ      *T
+		expected uintptr
+	}{
+		{Int, sizeOfT},
+		{MakeCollatedString(String, locale), sizeOfT + sizeOfString + 2},
+		{MakeArray(Int), 2 * sizeOfT},
+		{tuple, sizeOfT + 2*sizeOfT + (sizeOfString + 2) + 2*sizeOfString + 3},
+		{MakeArray(tuple), sizeOfT + tuple.MemoryUsage()},
+	}
+	for _, tc := range testCases {
+		if actual := tc.typ.MemoryUsage(); actual != tc.expected {
+			t.Errorf("%s: expected %d bytes, got %d", tc.typ.SQLString(), tc.expected, actual)
+		}
+	}
+
+	// Unmarshaled types also account for the fields set by Unmarshal.
+	for _, typ := range []*T{Int, MakeArray(Int), tuple, MakeInterval(IntervalTypeMetadata{
+		DurationField: IntervalDurationField{DurationType: IntervalDurationType_DAY},
+	})} {
+		var unmarshaled T
+		if err := protoutil.Unmarshal(marshalOrFatal(t, typ), &unmarshaled); err != nil {
+			t.Fatal(err)
+		}
+		if unmarshaled.MemoryUsage() < typ.MemoryUsage() {
+			t.Errorf("%s: expected at least %d bytes, got %d",
+				typ.SQLString(), typ.MemoryUsage(), unmarshaled.MemoryUsage())
+		}
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
+)
+
+// ColumnTypeMetadataVersion is the version of the format of ColumnTypeMetadata.
+// It must be bumped when a change to the format would cause consumers that know
+// the previous version to misinterpret it, such as a change in the meaning of a
+// field. Adding a field does not require a new version, since consumers ignore
+// unknown fields.
+const ColumnTypeMetadataVersion = 1
+
+// ColumnTypeMetadata is a compact JSON descriptor of the type of a column,
+// meant to be embedded in the schema envelopes of changefeeds, so that
+// downstream consumers can interpret the values of the column without parsing
+// SQL type names. For example, a DECIMAL(10,2) column is described as:
+//
+//   {"v":1,"name":"DECIMAL(10,2)","family":"decimal","oid":1700,"typmod":655366}
+//
+// The type modifier is encoded like the atttypmod column of pg_attribute (see
+// Typmod), so that it has the same meaning as in Postgres drivers. Go consumers
+// can use DecodeColumnTypeMetadata to get the type back.
+type ColumnTypeMetadata struct {
+	// Version is the version of the format. It is only set at the top level.
+	Version int `json:"v,omitempty"`
+	// Name is the SQL name of the type, such as VARCHAR(10). See SQLString.
+	Name string `json:"name"`
+	// Family is the name of the family of the type, such as "int" or "array".
+	Family string `json:"family"`
+	// Oid is the OID of the type. Collated strings have the OID of their string
+	// type.
+	Oid oid.Oid `json:"oid"`
+	// Typmod is the type modifier of the type, if it has one.
+	Typmod *int32 `json:"typmod,omitempty"`
+	// Locale is the locale of collated strings.
+	Locale string `json:"locale,omitempty"`
+	// WidthUnit is "bytes" for the string types whose width is measured in
+	// bytes, and is omitted for the ones measured in characters. See
+	// T.WidthUnit.
+	WidthUnit string `json:"width_unit,omitempty"`
+	// Elem is the element type of arrays.
+	Elem *ColumnTypeMetadata `json:"elem,omitempty"`
+	// Fields are the types of the fields of tuples, and Labels their labels,
+	// if they have any.
+	Fields []ColumnTypeMetadata `json:"fields,omitempty"`
+	Labels []string             `json:"labels,omitempty"`
+}
+
+// MakeColumnTypeMetadata returns the JSON descriptor of the given type. See
+// ColumnTypeMetadata.
+func MakeColumnTypeMetadata(t *T) ColumnTypeMetadata {
+	m := makeColumnTypeMetadata(t)
+	m.Version = ColumnTypeMetadataVersion
+	return m
+}
+
+func makeColumnTypeMetadata(t *T) ColumnTypeMetadata {
+	m := ColumnTypeMetadata{Name: t.SQLString(), Family: familyName(t.Family()), Oid: t.Oid()}
+	switch t.Family() {
+	case ArrayFamily:
+		elem := makeColumnTypeMetadata(t.ArrayContents())
+		m.Elem = &elem
+	case TupleFamily:
+		m.Fields = make([]ColumnTypeMetadata, len(t.TupleContents()))
+		for i := range t.TupleContents() {
+			m.Fields[i] = makeColumnTypeMetadata(&t.TupleContents()[i])
+		}
+		m.Labels = t.TupleLabels()
+	case CollatedStringFamily:
+		m.Locale = t.Locale()
+	}
+	if t.WidthUnit() != StringWidthUnit_CHARACTERS {
+		m.WidthUnit = strings.ToLower(t.WidthUnit().String())
+	}
+	if typmod := t.Typmod(); typmod >= 0 {
+		m.Typmod = &typmod
+	}
+	return m
+}
+
+// DecodeColumnTypeMetadata decodes the JSON descriptor of a type, which was
+// encoded from a ColumnTypeMetadata, and returns the type. It returns an error
+// if the descriptor has a newer version than ColumnTypeMetadataVersion.
+func DecodeColumnTypeMetadata(data []byte) (*T, error) {
+	var m ColumnTypeMetadata
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, errors.Wrap(err, "invalid column type metadata")
+	}
+	if m.Version > ColumnTypeMetadataVersion {
+		return nil, errors.Errorf(
+			"column type metadata version %d is newer than the latest version %d",
+			m.Version, ColumnTypeMetadataVersion)
+	}
+	return m.Type()
+}
+
+// Type returns the type described by the metadata.
+func (m *ColumnTypeMetadata) Type() (*T, error) {
+	var typ *T
+	switch m.Family {
+	case familyName(ArrayFamily):
+		if m.Oid == oid.T_int2vector || m.Oid == oid.T_oidvector {
+			typ = OidToType[m.Oid]
+			break
+		}
+		if m.Elem == nil {
+			return nil, errors.Errorf("array type %s has no element type", m.Name)
+		}
+		elem, err := m.Elem.Type()
+		if err != nil {
+			return nil, err
+		}
+		typ = MakeArray(elem)
+
+	case familyName(TupleFamily):
+		contents := make([]T, len(m.Fields))
+		for i := range m.Fields {
+			field, err := m.Fields[i].Type()
+			if err != nil {
+				return nil, err
+			}
+			contents[i] = *field
+		}
+		if err := ValidateTupleLabels(len(contents), m.Labels); err != nil {
+			return nil, err
+		}
+		typ = MakeLabeledTuple(contents, m.Labels)
+
+	default:
+		typmod := int32(-1)
+		if m.Typmod != nil {
+			typmod = *m.Typmod
+		}
+		var err error
+		if typ, err = MakeTypeFromTypmod(m.Oid, typmod); err != nil {
+			return nil, err
+		}
+		if m.Family == familyName(CollatedStringFamily) {
+			if typ.Family() != StringFamily {
+				return nil, errors.Errorf("collated string type %s has OID %d", m.Name, m.Oid)
+			}
+			typ = MakeCollatedString(typ, m.Locale)
+		}
+		if m.WidthUnit != "" {
+			unit, ok := StringWidthUnit_value[strings.ToUpper(m.WidthUnit)]
+			if !ok || (typ.Family() != StringFamily && typ.Family() != CollatedStringFamily) {
+				return nil, errors.Errorf("type %s has invalid width unit %q", m.Name, m.WidthUnit)
+			}
+			typ = MakeStringWithWidthUnit(typ, StringWidthUnit(unit))
+		}
+	}
+
+	if name := familyName(typ.Family()); name != m.Family {
+		return nil, errors.Errorf("type %s has family %s, not %s", m.Name, name, m.Family)
+	}
+	return typ, nil
+}
@@ -0,0 +1,58 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestColumnTypeMetadata(t *testing.T) {
+	data, err := json.Marshal(MakeColumnTypeMetadata(MakeDecimal(10, 2)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"v":1,"name":"DECIMAL(10,2)","family":"decimal","oid":1700,"typmod":655366}`
+	if string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+
+	for _, typ := range wireCompatCorpus() {
+		data, err := json.Marshal(MakeColumnTypeMetadata(typ))
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, err := DecodeColumnTypeMetadata(data)
+		if err != nil {
+			t.Errorf("%s: %v", data, err)
+			continue
+		}
+		if !decoded.Identical(typ) {
+			t.Errorf("%s: expected %s, got %s", data, typ.DebugString(), decoded.DebugString())
+		}
+	}
+
+	for _, tc := range []struct {
+		data string
+		err  string
+	}{
+		{`{"v":2,"name":"INT8","family":"int","oid":20}`,
+			"column type metadata version 2 is newer than the latest version 1"},
+		{`{"v":1,"name":"INT8","family":"string","oid":20}`,
+			"type INT8 has family int, not string"},
+		{`{"v":1,"name":"INT8[]","family":"array","oid":1016}`,
+			"array type INT8[] has no element type"},
+	} {
+		if _, err := DecodeColumnTypeMetadata([]byte(tc.data)); err == nil || err.Error() != tc.err {
+			t.Errorf("%s: expected error %q, got %v", tc.data, tc.err, err)
+		}
+	}
+}
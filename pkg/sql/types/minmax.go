@@ -0,0 +1,265 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"net"
+	"reflect"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/apd"
+	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
+	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/cockroach/pkg/util/uint128"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/lib/pq/oid"
+)
+
+// maxUTF8Runes are the largest characters whose UTF-8 encodings have 1, 2, 3
+// and 4 bytes, by the number of bytes.
+var maxUTF8Runes = [...]rune{1: utf8.RuneSelf - 1, 2: 0x7FF, 3: 0xFFFF, 4: utf8.MaxRune}
+
+// MinValue returns the smallest value of this type, in the canonical Go
+// representation of its values (see GoType), according to the order in which
+// the values are sorted and key-encoded. It returns false if the type has no
+// smallest value, or if it has no Go representation. The smallest values are:
+//
+//   BOOL                : false
+//   INT2, INT4, INT8    : the smallest integer of the width
+//   FLOAT, DECIMAL      : NaN, which sorts before all the numbers
+//   string types        : the empty string, except for PG_LSN
+//                         (00000000/00000000) and MACADDR (00:00:00:00:00:00)
+//   BYTES               : no bytes
+//   BIT(n), VARBIT      : n zero bits, and no bits
+//   DATE, TIMESTAMP     : the first day of the finite dates, 4714-11-24 BC
+//   TIME                : 00:00:00
+//   INTERVAL            : the interval with the smallest months, days and
+//                         nanoseconds
+//   UUID                : 00000000-0000-0000-0000-000000000000
+//   INET                : 0.0.0.0/0
+//   OID                 : 0
+//   JSONB               : null
+//   T[]                 : the empty array
+//
+// The infinite DATE -infinity, which has no Go representation, sorts before
+// the smallest finite DATE.
+//
+// The optimizer uses MinValue and MaxValue to bound the buckets of histograms
+// and the spans of index constraints.
+func (t *T) MinValue() (interface{}, bool) {
+	switch t.Family() {
+	case BoolFamily:
+		return false, true
+	case IntFamily:
+		min, _, err := IntBounds(t.Width())
+		if err != nil {
+			return nil, false
+		}
+		return intOfWidth(t.Width(), min), true
+	case FloatFamily:
+		if t.IsFloat4() {
+			return float32(math.NaN()), true
+		}
+		return math.NaN(), true
+	case DecimalFamily:
+		return apd.Decimal{Form: apd.NaN}, true
+	case StringFamily, CollatedStringFamily:
+		switch t.StringKind() {
+		case JSONPathKind:
+			// JSONPATH values are ordered by their text, but the empty string
+			// is not a valid path.
+			return nil, false
+		case PGLSNKind:
+			return FormatLSN(0), true
+		case MACAddrKind, MACAddr8Kind:
+			return net.HardwareAddr(make([]byte, t.macAddrSize())).String(), true
+		}
+		return "", true
+	case BytesFamily:
+		return []byte{}, true
+	case BitFamily:
+		if t.Oid() == oid.T_bit && t.Width() > 0 {
+			return bitarray.MakeZeroBitArray(uint(t.Width())), true
+		}
+		return bitarray.BitArray{}, true
+	case DateFamily, TimestampFamily, TimestampTZFamily:
+		min, err := pgdate.LowDate.ToTime()
+		if err != nil {
+			return nil, false
+		}
+		return min, true
+	case TimeFamily:
+		return timeofday.Min, true
+	case IntervalFamily:
+		return duration.MakeDuration(math.MinInt64, math.MinInt64, math.MinInt64), true
+	case UuidFamily:
+		return uuid.UUID{}, true
+	case INetFamily:
+		return ipaddr.IPAddr{Family: ipaddr.IPv4family,
+			Addr: ipaddr.Addr(uint128.FromBytes(net.ParseIP("0.0.0.0"))), Mask: 0}, true
+	case OidFamily:
+		return oid.Oid(0), true
+	case JsonFamily:
+		return json.RawMessage("null"), true
+	case ArrayFamily:
+		goType, err := GoType(t)
+		if err != nil {
+			return nil, false
+		}
+		return reflect.MakeSlice(goType, 0, 0).Interface(), true
+	}
+	return nil, false
+}
+
+// MaxValue returns the largest value of this type, in the canonical Go
+// representation of its values (see GoType), according to the order in which
+// the values are sorted and key-encoded. It returns false if the type has no
+// largest value, which is the case of the types whose values can be
+// arbitrarily long, or if it has no Go representation. The largest values
+// are:
+//
+//   BOOL                : true
+//   INT2, INT4, INT8    : the largest integer of the width
+//   FLOAT, DECIMAL      : +Inf
+//   string types        : the longest string of U+10FFFF characters that fits
+//                         the width, except for PG_LSN (FFFFFFFF/FFFFFFFF)
+//                         and MACADDR (ff:ff:ff:ff:ff:ff); the strings of
+//                         NAME and of types whose widths are measured in
+//                         bytes end with the largest character that fits
+//   BYTES(n)            : n 0xFF bytes
+//   BIT(n), VARBIT(n)   : n one bits
+//   DATE                : the last day of the finite dates, 5874897-12-31
+//   TIMESTAMP           : the last microsecond of the last finite date that
+//                         the precision of the type allows
+//   TIME                : 23:59:59.999999, rounded down to the precision
+//   INTERVAL            : the interval with the largest months, days and
+//                         nanoseconds
+//   UUID                : ffffffff-ffff-ffff-ffff-ffffffffffff
+//   INET                : ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff/128
+//   OID                 : 4294967295
+//
+// Strings without a width, collated strings, whose order depends on their
+// locale, and JSONB and array values have no largest value. The
+// infinite DATE infinity, which has no Go representation, sorts after the
+// largest finite DATE.
+func (t *T) MaxValue() (interface{}, bool) {
+	switch t.Family() {
+	case BoolFamily:
+		return true, true
+	case IntFamily:
+		_, max, err := IntBounds(t.Width())
+		if err != nil {
+			return nil, false
+		}
+		return intOfWidth(t.Width(), max), true
+	case FloatFamily:
+		if t.IsFloat4() {
+			return float32(math.Inf(1)), true
+		}
+		return math.Inf(1), true
+	case DecimalFamily:
+		return apd.Decimal{Form: apd.Infinite}, true
+	case StringFamily:
+		switch t.StringKind() {
+		case JSONPathKind:
+			return nil, false
+		case PGLSNKind:
+			return FormatLSN(math.MaxUint64), true
+		case MACAddrKind, MACAddr8Kind:
+			return net.HardwareAddr(bytes.Repeat([]byte{0xFF}, t.macAddrSize())).String(), true
+		case NameKind:
+			return maxStringOfBytes(MaxNameLength), true
+		case QCharKind:
+			if t.Width() == 0 {
+				return string(utf8.MaxRune), true
+			}
+		}
+		if t.Width() == 0 {
+			return nil, false
+		}
+		if t.WidthUnit() == StringWidthUnit_BYTES {
+			return maxStringOfBytes(int(t.Width())), true
+		}
+		return strings.Repeat(string(utf8.MaxRune), int(t.Width())), true
+	case BytesFamily:
+		if t.Width() == 0 {
+			return nil, false
+		}
+		return bytes.Repeat([]byte{0xFF}, int(t.Width())), true
+	case BitFamily:
+		if t.Width() == 0 {
+			return nil, false
+		}
+		return bitarray.Not(bitarray.MakeZeroBitArray(uint(t.Width()))), true
+	case DateFamily, TimestampFamily, TimestampTZFamily:
+		max, err := pgdate.HighDate.ToTime()
+		if err != nil {
+			return nil, false
+		}
+		if t.Family() == DateFamily {
+			return max, true
+		}
+		precision := t.Precision()
+		if precision < 0 || precision > MaxTimePrecision {
+			precision = DefaultTimePrecision
+		}
+		unit := time.Duration(pow10(9 - precision))
+		return max.Add(24*time.Hour - unit), true
+	case TimeFamily:
+		unit := timeofday.TimeOfDay(pow10(6 - t.timePrecision()))
+		return timeofday.Max / unit * unit, true
+	case IntervalFamily:
+		return duration.MakeDuration(math.MaxInt64, math.MaxInt64, math.MaxInt64), true
+	case UuidFamily:
+		var max uuid.UUID
+		for i := range max {
+			max[i] = 0xFF
+		}
+		return max, true
+	case INetFamily:
+		return ipaddr.IPAddr{Family: ipaddr.IPv6family,
+			Addr: ipaddr.Addr(uint128.FromInts(math.MaxUint64, math.MaxUint64)), Mask: 128}, true
+	case OidFamily:
+		return oid.Oid(math.MaxUint32), true
+	}
+	return nil, false
+}
+
+// intOfWidth returns the given integer as the Go type of the INT type of the
+// given width.
+func intOfWidth(width int32, v int64) interface{} {
+	switch width {
+	case 16:
+		return int16(v)
+	case 32:
+		return int32(v)
+	}
+	return v
+}
+
+// maxStringOfBytes returns the largest valid UTF-8 string of at most n bytes,
+// which consists of U+10FFFF characters followed by the largest character that
+// fits the remaining bytes.
+func maxStringOfBytes(n int) string {
+	s := strings.Repeat(string(utf8.MaxRune), n/utf8.UTFMax)
+	if rest := n % utf8.UTFMax; rest > 0 {
+		s += string(maxUTF8Runes[rest])
+	}
+	return s
+}
@@ -0,0 +1,69 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
+	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
+	"github.com/cockroachdb/cockroach/pkg/util/uint128"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/lib/pq/oid"
+)
+
+func TestMinMaxValue(t *testing.T) {
+	testCases := []struct {
+		typ      *T
+		min, max interface{}
+	}{
+		{Bool, false, true},
+		{Int2, int16(math.MinInt16), int16(math.MaxInt16)},
+		{Int, int64(math.MinInt64), int64(math.MaxInt64)},
+		{Float4, nil, float32(math.Inf(1))},
+		{String, "", nil},
+		{MakeVarChar(2), "", "\U0010FFFF\U0010FFFF"},
+		{MakeStringWithWidthUnit(MakeVarChar(6), StringWidthUnit_BYTES), "", "\U0010FFFF\u07FF"},
+		{MakeCollatedString(MakeVarChar(2), "de"), "", nil},
+		{PGLSN, "00000000/00000000", "FFFFFFFF/FFFFFFFF"},
+		{MACAddr, "00:00:00:00:00:00", "ff:ff:ff:ff:ff:ff"},
+		{MakeBytes(2), []byte{}, []byte{0xFF, 0xFF}},
+		{MakeBit(3), bitarray.MakeZeroBitArray(3), bitarray.Not(bitarray.MakeZeroBitArray(3))},
+		{VarBit, bitarray.BitArray{}, nil},
+		{Date, time.Date(-4713, time.November, 24, 0, 0, 0, 0, time.UTC),
+			time.Date(5874897, time.December, 31, 0, 0, 0, 0, time.UTC)},
+		{MakeTimestamp(0), time.Date(-4713, time.November, 24, 0, 0, 0, 0, time.UTC),
+			time.Date(5874897, time.December, 31, 23, 59, 59, 0, time.UTC)},
+		{MakeTime(2), timeofday.Min, timeofday.New(23, 59, 59, 990000)},
+		{Uuid, uuid.UUID{}, uuid.FromUint128(uint128.FromInts(math.MaxUint64, math.MaxUint64))},
+		{Oid, oid.Oid(0), oid.Oid(math.MaxUint32)},
+		{Jsonb, json.RawMessage("null"), nil},
+		{IntArray, []int64{}, nil},
+		{MakeTuple([]T{*Int}), nil, nil},
+	}
+	for _, tc := range testCases {
+		min, ok := tc.typ.MinValue()
+		if tc.typ.Family() == FloatFamily {
+			if f, isFloat := min.(float32); !ok || !isFloat || !math.IsNaN(float64(f)) {
+				t.Errorf("%s: expected the minimum NaN, got %v", tc.typ.SQLString(), min)
+			}
+		} else if ok != (tc.min != nil) || !reflect.DeepEqual(min, tc.min) {
+			t.Errorf("%s: expected the minimum %v, got %v (%t)", tc.typ.SQLString(), tc.min, min, ok)
+		}
+		if max, ok := tc.typ.MaxValue(); ok != (tc.max != nil) || !reflect.DeepEqual(max, tc.max) {
+			t.Errorf("%s: expected the maximum %v, got %v (%t)", tc.typ.SQLString(), tc.max, max, ok)
+		}
+	}
+}
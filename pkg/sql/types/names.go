@@ -0,0 +1,331 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/lib/pq/oid"
+)
+
+// typeNameAlias is an entry in the typeNameAliases table.
+type typeNameAlias struct {
+	// name is the alias, in lower case and with words separated by a single
+	// space.
+	name string
+	// typ is the type denoted by the alias, as it is returned by the parser.
+	typ *T
+	// serial is true if the alias names a SERIAL pseudo-type, which the parser
+	// distinguishes from the INT type that it denotes.
+	serial bool
+}
+
+// typeNameAliases is the table of accepted names of types that have no type
+// modifiers, such as bigint or character varying. It is used by ParseType to
+// resolve type names, and by SQLString to format types: the first alias of
+// each type OID is its canonical name, which is the name that SQLString
+// returns for it (in upper case, unless it is quoted).
+//
+// INT and INTEGER are listed with the default INT8 type, although the parser
+// resolves them according to the default_int_size session setting.
+var typeNameAliases = []typeNameAlias{
+	{name: "bool", typ: Bool},
+	{name: "boolean", typ: Bool},
+
+	{name: "int8", typ: Int},
+	{name: "bigint", typ: Int},
+	{name: "int64", typ: Int},
+	{name: "int", typ: Int},
+	{name: "integer", typ: Int},
+	{name: "serial", typ: Int, serial: true},
+	{name: "serial8", typ: Int, serial: true},
+	{name: "bigserial", typ: Int, serial: true},
+	{name: "int4", typ: Int4},
+	{name: "serial4", typ: Int4, serial: true},
+	{name: "int2", typ: Int2},
+	{name: "smallint", typ: Int2},
+	{name: "serial2", typ: Int2, serial: true},
+	{name: "smallserial", typ: Int2, serial: true},
+
+	{name: "float8", typ: Float},
+	{name: "float", typ: Float},
+	{name: "double precision", typ: Float},
+	{name: "float4", typ: Float4},
+	{name: "real", typ: Float4},
+
+	{name: "decimal", typ: Decimal},
+	{name: "dec", typ: Decimal},
+	{name: "numeric", typ: Decimal},
+
+	{name: "string", typ: String},
+	{name: "text", typ: String},
+	{name: "varchar", typ: VarChar},
+	{name: "character varying", typ: VarChar},
+	{name: "char varying", typ: VarChar},
+	{name: "char", typ: MakeChar(1)},
+	{name: "character", typ: MakeChar(1)},
+	{name: `"char"`, typ: typeQChar},
+	{name: "name", typ: Name},
+	{name: "refcursor", typ: RefCursor},
+
+	{name: "bytes", typ: Bytes},
+	{name: "bytea", typ: Bytes},
+	{name: "blob", typ: Bytes},
+
+	{name: "bit", typ: MakeBit(1)},
+	{name: "varbit", typ: VarBit},
+	{name: "bit varying", typ: VarBit},
+
+	{name: "date", typ: Date},
+	{name: "time", typ: Time},
+	{name: "time without time zone", typ: Time},
+	{name: "timestamp", typ: Timestamp},
+	{name: "timestamp without time zone", typ: Timestamp},
+	{name: "timestamptz", typ: TimestampTZ},
+	{name: "timestamp with time zone", typ: TimestampTZ},
+	{name: "interval", typ: Interval},
+
+	{name: "jsonb", typ: Jsonb},
+	{name: "json", typ: Jsonb},
+	{name: "jsonpath", typ: Jsonpath},
+	{name: "uuid", typ: Uuid},
+	{name: "inet", typ: INet},
+	{name: "macaddr", typ: MACAddr},
+	{name: "macaddr8", typ: MACAddr8},
+	{name: "pg_lsn", typ: PGLSN},
+
+	{name: "oid", typ: Oid},
+	{name: "regproc", typ: RegProc},
+	{name: "regprocedure", typ: RegProcedure},
+	{name: "regclass", typ: RegClass},
+	{name: "regtype", typ: RegType},
+	{name: "regnamespace", typ: RegNamespace},
+	{name: "oidvector", typ: OidVector},
+	{name: "int2vector", typ: Int2Vector},
+}
+
+var (
+	// typeNameAliasesByName indexes typeNameAliases by alias.
+	typeNameAliasesByName map[string]*typeNameAlias
+	// canonicalTypeNames maps type OIDs to their canonical names in
+	// typeNameAliases, in upper case unless they are quoted.
+	canonicalTypeNames map[oid.Oid]string
+)
+
+func init() {
+	typeNameAliasesByName = make(map[string]*typeNameAlias, len(typeNameAliases))
+	canonicalTypeNames = make(map[oid.Oid]string)
+	for i := range typeNameAliases {
+		alias := &typeNameAliases[i]
+		typeNameAliasesByName[alias.name] = alias
+		if _, ok := canonicalTypeNames[alias.typ.Oid()]; !ok {
+			name := alias.name
+			if !strings.Contains(name, `"`) {
+				name = strings.ToUpper(name)
+			}
+			canonicalTypeNames[alias.typ.Oid()] = name
+		}
+	}
+}
+
+// normalizeTypeName converts a type name to the form used by typeNameAliases.
+// Quoted names are case-sensitive, so they are not converted to lower case.
+func normalizeTypeName(name string) string {
+	name = strings.Join(strings.Fields(name), " ")
+	if !strings.Contains(name, `"`) {
+		name = strings.ToLower(name)
+	}
+	return name
+}
+
+// LookupTypeName returns the type denoted by the given name of a type without
+// type modifiers, such as "BIGINT" or "character varying". The name is
+// case-insensitive, unless it is quoted. It returns false if the name is not
+// one of the aliases in typeNameAliases; in particular, names that include
+// type modifiers (e.g. VARCHAR(10)) or array bounds are not resolved.
+func LookupTypeName(name string) (*T, bool) {
+	alias, ok := typeNameAliasesByName[normalizeTypeName(name)]
+	if !ok {
+		return nil, false
+	}
+	return alias.typ, true
+}
+
+// IsSerialTypeName returns true if the given type name (as accepted by
+// LookupTypeName) names one of the SERIAL pseudo-types, such as BIGSERIAL.
+func IsSerialTypeName(name string) bool {
+	alias, ok := typeNameAliasesByName[normalizeTypeName(name)]
+	return ok && alias.serial
+}
+
+// TypeNameAliases returns all the type names accepted by LookupTypeName, in
+// sorted order.
+func TypeNameAliases() []string {
+	res := make([]string, len(typeNameAliases))
+	for i := range typeNameAliases {
+		res[i] = typeNameAliases[i].name
+	}
+	sort.Strings(res)
+	return res
+}
+
+// pgTypeNames are the names of types that are spelled differently by the
+// format_type function of Postgres (and therefore by pg_dump and psql) than by
+// CockroachDB. In Postgres, INTEGER is INT4, and BPCHAR is the blank-padded
+// character type without a length.
+var pgTypeNames = map[string]*T{
+	"int":     Int4,
+	"integer": Int4,
+	"bpchar":  typeBpChar,
+}
+
+// ParseSQLStandardName parses a type name in the form returned by
+// SQLStandardNameWithTypmod, which is the form in which Postgres formats types
+// with format_type, pg_dump and the \d commands of psql, such as:
+//
+//   character varying(255)[]
+//   numeric(12,4)
+//   timestamp(6) without time zone
+//   interval day to second(3)
+//
+// Names are resolved like in Postgres, so "integer" denotes INT4. The type
+// modifiers are validated by MakeTypeFromTypmod. Array bounds are accepted and
+// ignored, and a multidimensional array results in a one-dimensional ARRAY
+// type (see ParseDimensionedSQLStandardName). It returns an error with the
+// UndefinedObject code if the name is not known, or with the
+// InvalidParameterValue code if the modifiers are not valid for the type.
+func ParseSQLStandardName(s string) (*T, error) {
+	typ, bounds, err := parseSQLStandardName(s)
+	if err != nil {
+		return nil, err
+	}
+	if bounds != nil {
+		typ = MakeArray(typ)
+	}
+	return typ, nil
+}
+
+// parseSQLStandardName parses a type name like ParseSQLStandardName. If the
+// name has array bounds, it returns the element type, and the bound of each
+// dimension, which is -1 for the dimensions written without a bound.
+func parseSQLStandardName(s string) (*T, []int32, error) {
+	name := strings.TrimSpace(s)
+	var bounds []int32
+	for strings.HasSuffix(name, "]") {
+		i := strings.LastIndexByte(name, '[')
+		if i < 0 || strings.Trim(name[i+1:len(name)-1], "0123456789") != "" {
+			return nil, nil, pgerror.Newf(pgcode.Syntax, "invalid array bounds in type %q", s)
+		}
+		bound := int64(-1)
+		if digits := name[i+1 : len(name)-1]; digits != "" {
+			var err error
+			if bound, err = strconv.ParseInt(digits, 10, 32); err != nil {
+				return nil, nil, pgerror.Newf(pgcode.Syntax, "invalid array bounds in type %q", s)
+			}
+		}
+		bounds = append([]int32{int32(bound)}, bounds...)
+		name = strings.TrimSpace(name[:i])
+	}
+
+	// The modifiers may be followed by the rest of the name, as in
+	// "time(3) without time zone".
+	var args []int32
+	if i := strings.IndexByte(name, '('); i >= 0 {
+		j := strings.IndexByte(name, ')')
+		if j < i {
+			return nil, nil, pgerror.Newf(pgcode.Syntax, "invalid type modifiers in type %q", s)
+		}
+		for _, arg := range strings.Split(name[i+1:j], ",") {
+			n, err := strconv.ParseInt(strings.TrimSpace(arg), 10, 16)
+			if err != nil || n < 0 {
+				return nil, nil, pgerror.Newf(pgcode.InvalidParameterValue,
+					"invalid type modifiers in type %q", s)
+			}
+			args = append(args, int32(n))
+		}
+		name = name[:i] + " " + name[j+1:]
+	}
+	name = normalizeTypeName(name)
+
+	var typ *T
+	var typmod int32 = -1
+	if rest := strings.TrimPrefix(name, "interval "); rest != name {
+		var err error
+		if typ, typmod, err = parseIntervalQualifier(rest, args); err != nil {
+			return nil, nil, pgerror.Wrapf(err, pgcode.InvalidParameterValue, "invalid type %q", s)
+		}
+	} else {
+		var ok bool
+		if typ, ok = pgTypeNames[name]; !ok {
+			if typ, ok = LookupTypeName(name); !ok {
+				return nil, nil, pgerror.Newf(pgcode.UndefinedObject, "type %q does not exist", s)
+			}
+		}
+		switch {
+		case len(args) == 0:
+		case len(args) == 2 && typ.Family() == DecimalFamily:
+			typmod = ((args[0] << 16) | args[1]) + varHeaderSize
+		case len(args) == 1 && typ.Family() == DecimalFamily:
+			typmod = (args[0] << 16) + varHeaderSize
+		case len(args) == 1 && (typ.Family() == StringFamily || typ.Family() == BytesFamily):
+			typmod = args[0] + varHeaderSize
+		case len(args) == 1 && typ.Family() == IntervalFamily:
+			typmod = (intervalFullRange << 16) | args[0]
+		case len(args) == 1:
+			typmod = args[0]
+		default:
+			return nil, nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"invalid type modifiers in type %q", s)
+		}
+	}
+
+	if typmod >= 0 {
+		var err error
+		if typ, err = MakeTypeFromTypmod(typ.Oid(), typmod); err != nil {
+			return nil, nil, err
+		}
+	}
+	return typ, bounds, nil
+}
+
+// parseIntervalQualifier returns the INTERVAL type with the given qualifier,
+// such as "day to second", and the typmod that results from the qualifier and
+// the precision given by args, if any.
+func parseIntervalQualifier(qualifier string, args []int32) (*T, int32, error) {
+	units := strings.Fields(qualifier)
+	var df IntervalDurationField
+	switch {
+	case len(units) == 1:
+		df.DurationType = IntervalDurationType(IntervalDurationType_value[strings.ToUpper(units[0])])
+	case len(units) == 3 && units[1] == "to":
+		df.FromDurationType = IntervalDurationType(IntervalDurationType_value[strings.ToUpper(units[0])])
+		df.DurationType = IntervalDurationType(IntervalDurationType_value[strings.ToUpper(units[2])])
+		if df.FromDurationType == IntervalDurationType_UNSET {
+			return nil, 0, pgerror.Newf(pgcode.InvalidParameterValue, "unknown interval unit %s", units[0])
+		}
+	}
+	if df.DurationType == IntervalDurationType_UNSET || !df.isValid() {
+		return nil, 0, pgerror.Newf(pgcode.InvalidParameterValue, "invalid interval qualifier %s", qualifier)
+	}
+	precision := int32(intervalFullPrecision)
+	switch len(args) {
+	case 0:
+	case 1:
+		precision = args[0]
+	default:
+		return nil, 0, pgerror.New(pgcode.InvalidParameterValue, "invalid interval precision")
+	}
+	return Interval, (df.rangeMask() << 16) | precision, nil
+}
@@ -0,0 +1,184 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/lib/pq/oid"
+)
+
+func TestTypeNameAliases(t *testing.T) {
+	// The aliases of the types in Table 8.1 of the Postgres documentation
+	// (https://www.postgresql.org/docs/current/datatype.html) that are
+	// supported by CockroachDB. SERIAL is an INT8 by default in CockroachDB,
+	// rather than an INT4 as in Postgres.
+	pgAliases := map[string]oid.Oid{
+		"bigint":                      oid.T_int8,
+		"int8":                        oid.T_int8,
+		"bigserial":                   oid.T_int8,
+		"serial8":                     oid.T_int8,
+		"bit":                         oid.T_bit,
+		"bit varying":                 oid.T_varbit,
+		"varbit":                      oid.T_varbit,
+		"boolean":                     oid.T_bool,
+		"bool":                        oid.T_bool,
+		"bytea":                       oid.T_bytea,
+		"character":                   oid.T_bpchar,
+		"char":                        oid.T_bpchar,
+		"character varying":           oid.T_varchar,
+		"varchar":                     oid.T_varchar,
+		"date":                        oid.T_date,
+		"double precision":            oid.T_float8,
+		"float8":                      oid.T_float8,
+		"inet":                        oid.T_inet,
+		"integer":                     oid.T_int8,
+		"int":                         oid.T_int8,
+		"int4":                        oid.T_int4,
+		"interval":                    oid.T_interval,
+		"json":                        oid.T_jsonb,
+		"jsonb":                       oid.T_jsonb,
+		"numeric":                     oid.T_numeric,
+		"decimal":                     oid.T_numeric,
+		"real":                        oid.T_float4,
+		"float4":                      oid.T_float4,
+		"smallint":                    oid.T_int2,
+		"int2":                        oid.T_int2,
+		"smallserial":                 oid.T_int2,
+		"serial2":                     oid.T_int2,
+		"serial":                      oid.T_int8,
+		"serial4":                     oid.T_int4,
+		"text":                        oid.T_text,
+		"time":                        oid.T_time,
+		"time without time zone":      oid.T_time,
+		"timestamp":                   oid.T_timestamp,
+		"timestamp without time zone": oid.T_timestamp,
+		"timestamp with time zone":    oid.T_timestamptz,
+		"timestamptz":                 oid.T_timestamptz,
+		"uuid":                        oid.T_uuid,
+	}
+	for name, o := range pgAliases {
+		for _, s := range []string{name, strings.ToUpper(name), " " + strings.Replace(name, " ", "  ", -1)} {
+			typ, ok := LookupTypeName(s)
+			if !ok {
+				t.Errorf("%q: alias not found", s)
+				continue
+			}
+			if typ.Oid() != o {
+				t.Errorf("%q: expected OID %d, got %s", s, o, typ.DebugString())
+			}
+		}
+	}
+
+	if _, ok := LookupTypeName("varchar(10)"); ok {
+		t.Error("expected type name with modifiers not to be resolved")
+	}
+	if _, ok := LookupTypeName(`"CHAR"`); ok {
+		t.Error("expected quoted type name to be case-sensitive")
+	}
+	if typ, ok := LookupTypeName(`"char"`); !ok || typ.Oid() != oid.T_char {
+		t.Errorf(`expected "char" to be resolved, got %v`, typ)
+	}
+	if !IsSerialTypeName("BIGSERIAL") || IsSerialTypeName("bigint") {
+		t.Error("unexpected result of IsSerialTypeName")
+	}
+
+	// The canonical name of each type is the name used by SQLString.
+	seen := make(map[oid.Oid]bool)
+	for _, alias := range typeNameAliases {
+		if seen[alias.typ.Oid()] {
+			continue
+		}
+		seen[alias.typ.Oid()] = true
+		expected := alias.name
+		if !strings.Contains(expected, `"`) {
+			expected = strings.ToUpper(expected)
+		}
+		if actual := alias.typ.SQLString(); actual != expected {
+			t.Errorf("%s: expected SQLString %s, got %s", alias.name, expected, actual)
+		}
+	}
+}
+
+func TestParseSQLStandardName(t *testing.T) {
+	testCases := []struct {
+		name string
+		typ  *T
+	}{
+		{"boolean", Bool},
+		{"integer", Int4},
+		{"bigint", Int},
+		{"double precision", Float},
+		{"numeric", Decimal},
+		{"numeric(12,4)", MakeDecimal(12, 4)},
+		{"numeric(10)", MakeDecimal(10, 0)},
+		{"text", String},
+		{"character varying", VarChar},
+		{"character varying(255)", MakeVarChar(255)},
+		{"character varying(255)[]", MakeArray(MakeVarChar(255))},
+		{"character(3)", MakeChar(3)},
+		{"bpchar", typeBpChar},
+		{`"char"`, typeQChar},
+		{`"char"[]`, MakeArray(typeQChar)},
+		{"bit(4)", MakeBit(4)},
+		{"bit varying(8)", MakeVarBit(8)},
+		{"time(3) without time zone", MakeTime(3)},
+		{"timestamp(6) without time zone", MakeTimestamp(6)},
+		{"timestamp with time zone", TimestampTZ},
+		{"interval", Interval},
+		{"interval(3)", MakeInterval(IntervalTypeMetadata{Precision: 3, PrecisionIsSet: true})},
+		{"interval day to second(3)", MakeInterval(IntervalTypeMetadata{
+			DurationField: IntervalDurationField{
+				FromDurationType: IntervalDurationType_DAY, DurationType: IntervalDurationType_SECOND},
+			Precision: 3, PrecisionIsSet: true})},
+		{"interval year", MakeInterval(IntervalTypeMetadata{
+			DurationField: IntervalDurationField{DurationType: IntervalDurationType_YEAR}})},
+		{"integer[][]", MakeArray(Int4)},
+		{"integer[3]", MakeArray(Int4)},
+		{" Character Varying ( 10 ) ", MakeVarChar(10)},
+	}
+
+	for _, tc := range testCases {
+		typ, err := ParseSQLStandardName(tc.name)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.name, err)
+		} else if !typ.Identical(tc.typ) {
+			t.Errorf("%q: expected %s, got %s", tc.name, tc.typ.DebugString(), typ.DebugString())
+		}
+	}
+
+	errCases := []struct {
+		name string
+		code string
+	}{
+		{"foo", pgcode.UndefinedObject},
+		{"integer(3)", pgcode.InvalidParameterValue},
+		{"numeric(3,5)", pgcode.InvalidParameterValue},
+		{"numeric(1,2,3)", pgcode.InvalidParameterValue},
+		{"character varying(x)", pgcode.InvalidParameterValue},
+		{"timestamp(3) without time zone", pgcode.InvalidParameterValue},
+		{"interval hour(3)", pgcode.InvalidParameterValue},
+		{"interval second to day", pgcode.InvalidParameterValue},
+		{"integer[", pgcode.UndefinedObject},
+		{"integer[x]", pgcode.Syntax},
+	}
+	for _, tc := range errCases {
+		typ, err := ParseSQLStandardName(tc.name)
+		if err == nil {
+			t.Errorf("%q: expected error, got %s", tc.name, typ.SQLString())
+		} else if code := pgerror.GetPGCode(err); code != tc.code {
+			t.Errorf("%q: expected code %s, got %s (%v)", tc.name, tc.code, code, err)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// numericPromotions is the numeric promotion lattice, which is a chain: a
+// value of each family can be promoted to any of the families that follow it,
+// but not to the ones that precede it.
+var numericPromotions = [...]Family{IntFamily, DecimalFamily, FloatFamily}
+
+// numericCanonicalTypes are the types in which arithmetic on the corresponding
+// families of numericPromotions is performed.
+var numericCanonicalTypes = [...]*T{Int, Decimal, Float}
+
+// NumericPromotions returns the numeric type families in promotion order:
+// INT, DECIMAL, FLOAT. A value of each family can be promoted to any family
+// that follows it in the list.
+func NumericPromotions() []Family {
+	res := make([]Family, len(numericPromotions))
+	copy(res, numericPromotions[:])
+	return res
+}
+
+// numericRank returns the position of the given family in numericPromotions,
+// or false if it is not a numeric family.
+func numericRank(f Family) (int, bool) {
+	for i := range numericPromotions {
+		if numericPromotions[i] == f {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// IsNumeric returns true if this type is in one of the numeric families that
+// take part in numeric promotion.
+func (t *T) IsNumeric() bool {
+	_, ok := numericRank(t.Family())
+	return ok
+}
+
+// CanPromote returns true if values of the "from" numeric family can be
+// promoted to the "to" numeric family. Every numeric family can be promoted to
+// itself. CanPromote returns false if either family is not numeric.
+func CanPromote(from, to Family) bool {
+	fromRank, ok := numericRank(from)
+	if !ok {
+		return false
+	}
+	toRank, ok := numericRank(to)
+	return ok && fromRank <= toRank
+}
+
+// PromoteBinary returns the type to which both operands of a binary arithmetic
+// operation are converted before it is evaluated: the canonical type (INT8,
+// DECIMAL or FLOAT8) of the highest-ranked family of the two operands. For
+// example, INT and DECIMAL promote to DECIMAL, while INT2 and INT4 promote to
+// INT8, since arithmetic is performed at full width. A NULL (UNKNOWN) operand
+// takes the type of the other operand.
+//
+// The result is only the type of the operands; the result type of the
+// operation itself is specific to each operator (e.g. INT / INT is DECIMAL).
+// PromoteBinary returns an error with the DatatypeMismatch code if the
+// operands are not numeric, or if both of them are NULL.
+func PromoteBinary(left, right *T) (*T, error) {
+	if left.Family() == UnknownFamily {
+		left = right
+	} else if right.Family() == UnknownFamily {
+		right = left
+	}
+	leftRank, leftOk := numericRank(left.Family())
+	rightRank, rightOk := numericRank(right.Family())
+	if !leftOk || !rightOk {
+		return nil, pgerror.Newf(pgcode.DatatypeMismatch,
+			"cannot promote %s and %s to a common numeric type", left.SQLString(), right.SQLString())
+	}
+	if rightRank > leftRank {
+		leftRank = rightRank
+	}
+	return numericCanonicalTypes[leftRank], nil
+}
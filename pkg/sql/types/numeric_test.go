@@ -0,0 +1,75 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+func TestPromoteBinary(t *testing.T) {
+	testCases := []struct {
+		left, right *T
+		expected    *T
+	}{
+		{Int, Int, Int},
+		{Int2, Int4, Int},
+		{Int, Decimal, Decimal},
+		{MakeDecimal(10, 2), Int4, Decimal},
+		{Decimal, Float4, Float},
+		{Float4, Float4, Float},
+		{Int, Float, Float},
+		{Float, Int2, Float},
+		{Unknown, Int4, Int},
+		{Decimal, Unknown, Decimal},
+		{Int, String, nil},
+		{Interval, Int, nil},
+		{Unknown, Unknown, nil},
+	}
+
+	for _, tc := range testCases {
+		typ, err := PromoteBinary(tc.left, tc.right)
+		if tc.expected == nil {
+			if err == nil {
+				t.Errorf("%s, %s: expected error, got %s", tc.left.SQLString(), tc.right.SQLString(), typ.SQLString())
+			} else if code := pgerror.GetPGCode(err); code != pgcode.DatatypeMismatch {
+				t.Errorf("%s, %s: expected code %s, got %s", tc.left.SQLString(), tc.right.SQLString(),
+					pgcode.DatatypeMismatch, code)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s, %s: unexpected error: %v", tc.left.SQLString(), tc.right.SQLString(), err)
+		} else if typ != tc.expected {
+			t.Errorf("%s, %s: expected %s, got %s",
+				tc.left.SQLString(), tc.right.SQLString(), tc.expected.SQLString(), typ.SQLString())
+		}
+	}
+
+	// The promotion lattice is consistent with the implicit casts, so that
+	// every operand can be implicitly cast to the promoted type.
+	families := NumericPromotions()
+	for i, from := range families {
+		for j, to := range families {
+			if CanPromote(from, to) != (i <= j) {
+				t.Errorf("%s -> %s: unexpected CanPromote %t", from, to, CanPromote(from, to))
+			}
+			if CanPromote(from, to) && validCasts[to][from].ctx != CastImplicit {
+				t.Errorf("%s -> %s: promotion is not an implicit cast", from, to)
+			}
+		}
+	}
+	if CanPromote(IntFamily, StringFamily) || CanPromote(StringFamily, StringFamily) {
+		t.Error("unexpected promotion of non-numeric family")
+	}
+}
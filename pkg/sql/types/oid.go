@@ -11,6 +11,10 @@
 package types
 
 import (
+	"math"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/errors"
 	"github.com/lib/pq/oid"
 )
@@ -54,40 +58,66 @@ var (
 // instead of a method so that other packages can iterate over the map directly.
 // Note that additional elements for the array Oid types are added in init().
 var OidToType = map[oid.Oid]*T{
-	oid.T_anyelement:   Any,
-	oid.T_bit:          typeBit,
-	oid.T_bool:         Bool,
-	oid.T_bpchar:       typeBpChar,
-	oid.T_bytea:        Bytes,
-	oid.T_char:         typeQChar,
-	oid.T_date:         Date,
-	oid.T_float4:       Float4,
-	oid.T_float8:       Float,
-	oid.T_int2:         Int2,
-	oid.T_int2vector:   Int2Vector,
-	oid.T_int4:         Int4,
-	oid.T_int8:         Int,
-	oid.T_inet:         INet,
-	oid.T_interval:     Interval,
-	oid.T_jsonb:        Jsonb,
-	oid.T_name:         Name,
-	oid.T_numeric:      Decimal,
-	oid.T_oid:          Oid,
-	oid.T_oidvector:    OidVector,
-	oid.T_record:       AnyTuple,
-	oid.T_regclass:     RegClass,
-	oid.T_regnamespace: RegNamespace,
-	oid.T_regproc:      RegProc,
-	oid.T_regprocedure: RegProcedure,
-	oid.T_regtype:      RegType,
-	oid.T_text:         String,
-	oid.T_time:         Time,
-	oid.T_timestamp:    Timestamp,
-	oid.T_timestamptz:  TimestampTZ,
-	oid.T_unknown:      Unknown,
-	oid.T_uuid:         Uuid,
-	oid.T_varbit:       VarBit,
-	oid.T_varchar:      VarChar,
+	oid.T_anyelement:    Any,
+	oid.T_bit:           typeBit,
+	oid.T_bool:          Bool,
+	oid.T_bpchar:        typeBpChar,
+	oid.T_bytea:         Bytes,
+	oid.T_char:          typeQChar,
+	oid.T_date:          Date,
+	oid.T_event_trigger: EventTrigger,
+	oid.T_float4:        Float4,
+	oid.T_float8:        Float,
+	oid.T_int2:          Int2,
+	oid.T_int2vector:    Int2Vector,
+	oid.T_int4:          Int4,
+	oid.T_int8:          Int,
+	oid.T_inet:          INet,
+	oid.T_interval:      Interval,
+	oid.T_jsonb:         Jsonb,
+	T_jsonpath:          Jsonpath,
+	oid.T_macaddr:       MACAddr,
+	T_macaddr8:          MACAddr8,
+	oid.T_name:          Name,
+	oid.T_numeric:       Decimal,
+	oid.T_oid:           Oid,
+	oid.T_oidvector:     OidVector,
+	oid.T_pg_lsn:        PGLSN,
+	oid.T_record:        AnyTuple,
+	oid.T_refcursor:     RefCursor,
+	oid.T_regclass:      RegClass,
+	oid.T_regnamespace:  RegNamespace,
+	oid.T_regproc:       RegProc,
+	oid.T_regprocedure:  RegProcedure,
+	oid.T_regtype:       RegType,
+	oid.T_text:          String,
+	oid.T_time:          Time,
+	oid.T_timestamp:     Timestamp,
+	oid.T_timestamptz:   TimestampTZ,
+	oid.T_trigger:       Trigger,
+	oid.T_unknown:       Unknown,
+	oid.T_uuid:          Uuid,
+	oid.T_varbit:        VarBit,
+	oid.T_varchar:       VarChar,
+	oid.T_void:          Void,
+}
+
+// These are the OIDs of builtin Postgres types (and of their array types) that
+// are not listed in the oid package.
+const (
+	T_jsonpath  = oid.Oid(4072)
+	T__jsonpath = oid.Oid(4073)
+	T_macaddr8  = oid.Oid(774)
+	T__macaddr8 = oid.Oid(775)
+)
+
+// extraTypeNames contains the Postgres names of the types whose OIDs are not
+// listed in oid.TypeName.
+var extraTypeNames = map[oid.Oid]string{
+	T_jsonpath:  "jsonpath",
+	T__jsonpath: "_jsonpath",
+	T_macaddr8:  "macaddr8",
+	T__macaddr8: "_macaddr8",
 }
 
 // oidToArrayOid maps scalar type Oids to their corresponding array type Oid.
@@ -108,11 +138,16 @@ var oidToArrayOid = map[oid.Oid]oid.Oid{
 	oid.T_int8:         oid.T__int8,
 	oid.T_interval:     oid.T__interval,
 	oid.T_jsonb:        oid.T__jsonb,
+	T_jsonpath:         T__jsonpath,
+	oid.T_macaddr:      oid.T__macaddr,
+	T_macaddr8:         T__macaddr8,
 	oid.T_name:         oid.T__name,
 	oid.T_numeric:      oid.T__numeric,
 	oid.T_oid:          oid.T__oid,
 	oid.T_oidvector:    oid.T__oidvector,
+	oid.T_pg_lsn:       oid.T__pg_lsn,
 	oid.T_record:       oid.T__record,
+	oid.T_refcursor:    oid.T__refcursor,
 	oid.T_regclass:     oid.T__regclass,
 	oid.T_regnamespace: oid.T__regnamespace,
 	oid.T_regproc:      oid.T__regproc,
@@ -151,19 +186,146 @@ var familyToOid = map[Family]oid.Oid{
 	JsonFamily:           oid.T_jsonb,
 	TupleFamily:          oid.T_record,
 	BitFamily:            oid.T_bit,
+	VoidFamily:           oid.T_void,
+	TriggerFamily:        oid.T_trigger,
 	AnyFamily:            oid.T_anyelement,
 }
 
+// arrayOidToOid is the reverse of oidToArrayOid: it maps array type Oids to
+// the Oid of their element type. It also contains the vector types, which are
+// arrays with their own Oid. It is populated in init().
+var arrayOidToOid = map[oid.Oid]oid.Oid{
+	oid.T_int2vector: oid.T_int2,
+	oid.T_oidvector:  oid.T_oid,
+}
+
 // ArrayOids is a set of all oids which correspond to an array type.
 var ArrayOids = map[oid.Oid]struct{}{}
 
 func init() {
 	for o, ao := range oidToArrayOid {
 		ArrayOids[ao] = struct{}{}
+		arrayOidToOid[ao] = o
 		OidToType[ao] = MakeArray(OidToType[o])
 	}
 }
 
+// UserDefinedTypeOIDOffset is the smallest OID that can be assigned to a
+// user-defined type, such as an enum or composite type. User-defined types are
+// identified by the stable ID of their type descriptor, and the OID of such a
+// type is its ID plus this offset. OIDs below the offset are reserved for the
+// builtin types; the largest of those is far below the offset (checked in
+// init), so the two ranges never collide.
+const UserDefinedTypeOIDOffset = 100000
+
+// maxUserDefinedTypeID is the largest type descriptor ID that can be mapped
+// to an OID without overflowing it.
+const maxUserDefinedTypeID = math.MaxUint32 - UserDefinedTypeOIDOffset
+
+func init() {
+	for o := range OidToType {
+		if o >= UserDefinedTypeOIDOffset {
+			panic(errors.AssertionFailedf(
+				"builtin type OID %d collides with the user-defined type OID range", o))
+		}
+	}
+}
+
+// TypeIDToOID returns the OID of the user-defined type with the given type
+// descriptor ID. It returns an error if the ID is too large to be mapped to an
+// OID.
+func TypeIDToOID(id uint32) (oid.Oid, error) {
+	if id > maxUserDefinedTypeID {
+		return 0, pgerror.Newf(pgcode.ProgramLimitExceeded,
+			"type ID %d is too large to be assigned an OID", id)
+	}
+	return oid.Oid(id + UserDefinedTypeOIDOffset), nil
+}
+
+// UserDefinedTypeOIDToID returns the type descriptor ID of the user-defined
+// type with the given OID. It returns false if the OID is not in the range
+// reserved for user-defined types, as is the case for the builtin types.
+func UserDefinedTypeOIDToID(o oid.Oid) (uint32, bool) {
+	if !IsOIDUserDefinedType(o) {
+		return 0, false
+	}
+	return uint32(o) - UserDefinedTypeOIDOffset, true
+}
+
+// IsOIDUserDefinedType returns true if the given OID is in the range reserved
+// for user-defined types.
+func IsOIDUserDefinedType(o oid.Oid) bool {
+	return o >= UserDefinedTypeOIDOffset
+}
+
+// ArrayOid returns the Oid of the array type having elements with the given
+// Oid, such as T__int8 for T_int8. Collated strings use the Oid of the
+// corresponding string type, so an array of collated strings has the Oid of the
+// array of that string type. ArrayOid returns false if there is no such array
+// type, which is the case for T_unknown (see unknownArrayOid).
+func ArrayOid(elem oid.Oid) (oid.Oid, bool) {
+	o, ok := oidToArrayOid[elem]
+	return o, ok
+}
+
+// ElemOid returns the Oid of the elements of the array type with the given
+// Oid, such as T_int8 for T__int8. It also handles the vector types, so that
+// ElemOid(T_int2vector) is T_int2. It returns false if the given Oid is not
+// that of an array type.
+func ElemOid(array oid.Oid) (oid.Oid, bool) {
+	o, ok := arrayOidToOid[array]
+	return o, ok
+}
+
+// TypeForOid returns the type that has the given OID and no type modifiers,
+// such as VARCHAR for T_varchar. It returns false if the OID is unknown.
+func TypeForOid(o oid.Oid) (*T, bool) {
+	t, ok := OidToType[o]
+	return t, ok
+}
+
+// PGTypeName returns the name of the type with the given OID in the typname
+// column of pg_type, such as "int8" or "_varchar". These are also the names
+// under which Go clients such as the pgx/pgtype package register the types.
+// It returns false if the OID is unknown.
+func PGTypeName(o oid.Oid) (string, bool) {
+	t, ok := TypeForOid(o)
+	if !ok {
+		return "", false
+	}
+	return t.PGName(), true
+}
+
+// pgTypeNameToOid maps the pg_type names of the types to their OIDs.
+var pgTypeNameToOid map[string]oid.Oid
+
+func init() {
+	pgTypeNameToOid = make(map[string]oid.Oid, len(OidToType))
+	for o, t := range OidToType {
+		pgTypeNameToOid[t.PGName()] = o
+	}
+}
+
+// OidForPGTypeName returns the OID of the type with the given pg_type name,
+// such as T_int8 for "int8". It is the inverse of PGTypeName, and returns
+// false if the name is unknown. Unlike LookupTypeName, it does not accept SQL
+// aliases such as "bigint".
+func OidForPGTypeName(name string) (oid.Oid, bool) {
+	o, ok := pgTypeNameToOid[name]
+	return o, ok
+}
+
+// TypeForPGTypeName returns the type with the given pg_type name and no type
+// modifiers, such as INT8 for "int8" or STRING[] for "_text". It returns false
+// if the name is unknown.
+func TypeForPGTypeName(name string) (*T, bool) {
+	o, ok := OidForPGTypeName(name)
+	if !ok {
+		return nil, false
+	}
+	return TypeForOid(o)
+}
+
 // calcArrayOid returns the OID of the array type having elements of the given
 // type.
 func calcArrayOid(elemTyp *T) oid.Oid {
@@ -191,9 +353,9 @@ func calcArrayOid(elemTyp *T) oid.Oid {
 	// Map the OID of the array element type to the corresponding array OID.
 	// This should always be possible for all other OIDs (checked in oid.go
 	// init method).
-	o = oidToArrayOid[o]
-	if o == 0 {
+	ao, ok := ArrayOid(o)
+	if !ok {
 		panic(errors.AssertionFailedf("oid %d couldn't be mapped to array oid", o))
 	}
-	return o
+	return ao
 }
@@ -0,0 +1,159 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lib/pq/oid"
+)
+
+func TestArrayOids(t *testing.T) {
+	for o, typ := range OidToType {
+		if typ.Family() == ArrayFamily {
+			// Every array type maps back to the Oid of its element type.
+			elem, ok := ElemOid(o)
+			if !ok {
+				t.Errorf("%s: no element oid for array oid %d", typ.SQLString(), o)
+			} else if elem != typ.ArrayContents().Oid() {
+				t.Errorf("%s: expected element oid %d, got %d",
+					typ.SQLString(), typ.ArrayContents().Oid(), elem)
+			}
+			if _, ok := ArrayOids[o]; !ok && o != oid.T_int2vector && o != oid.T_oidvector {
+				t.Errorf("%s: array oid %d missing from ArrayOids", typ.SQLString(), o)
+			}
+			continue
+		}
+		if _, ok := ElemOid(o); ok {
+			t.Errorf("%s: unexpected element oid for scalar oid %d", typ.SQLString(), o)
+		}
+
+		// Every scalar type other than UNKNOWN and the signature-only types has
+		// an array type.
+		ao, ok := ArrayOid(o)
+		if typ.Family() == UnknownFamily || typ.IsSignatureOnly() {
+			if ok {
+				t.Errorf("unexpected array oid %d for %s", ao, typ.SQLString())
+			}
+			continue
+		}
+		if !ok || ao == 0 {
+			t.Errorf("%s: no array oid for oid %d", typ.SQLString(), o)
+			continue
+		}
+		if arr := MakeArray(typ); arr.Oid() != ao {
+			t.Errorf("%s: expected array oid %d, got %d", typ.SQLString(), ao, arr.Oid())
+		}
+		if elem, _ := ElemOid(ao); elem != o {
+			t.Errorf("%s: oid %d does not round trip, got %d", typ.SQLString(), o, elem)
+		}
+	}
+
+	// Parameterized types have the same array Oid as the unparameterized type.
+	testCases := []struct {
+		elem     *T
+		expected oid.Oid
+	}{
+		{MakeCollatedString(String, "en"), oid.T__text},
+		{MakeCollatedString(MakeVarChar(10), "de"), oid.T__varchar},
+		{MakeVarChar(10), oid.T__varchar},
+		{MakeDecimal(10, 2), oid.T__numeric},
+		{MakeBit(3), oid.T__bit},
+		{MakeTime(3), oid.T__time},
+		{INet, oid.T__inet},
+		{Jsonb, oid.T__jsonb},
+		{RegClass, oid.T__regclass},
+		{MakeTuple([]T{*Int}), oid.T__record},
+	}
+	for _, tc := range testCases {
+		if arr := MakeArray(tc.elem); arr.Oid() != tc.expected {
+			t.Errorf("%s: expected array oid %d, got %d", tc.elem.DebugString(), tc.expected, arr.Oid())
+		}
+	}
+}
+
+func TestUserDefinedTypeOIDs(t *testing.T) {
+	for _, id := range []uint32{0, 1, 52, 1 << 20, math.MaxUint32 - UserDefinedTypeOIDOffset} {
+		o, err := TypeIDToOID(id)
+		if err != nil {
+			t.Errorf("%d: unexpected error: %v", id, err)
+			continue
+		}
+		if !IsOIDUserDefinedType(o) {
+			t.Errorf("%d: OID %d is not in the user-defined type range", id, o)
+		}
+		if _, ok := OidToType[o]; ok {
+			t.Errorf("%d: OID %d collides with a builtin type", id, o)
+		}
+		if actual, ok := UserDefinedTypeOIDToID(o); !ok || actual != id {
+			t.Errorf("%d: OID %d maps back to ID %d", id, o, actual)
+		}
+	}
+
+	if _, err := TypeIDToOID(math.MaxUint32 - UserDefinedTypeOIDOffset + 1); err == nil {
+		t.Error("expected error for out of range type ID")
+	}
+	for o := range OidToType {
+		if IsOIDUserDefinedType(o) {
+			t.Errorf("builtin OID %d is in the user-defined type range", o)
+		}
+		if _, ok := UserDefinedTypeOIDToID(o); ok {
+			t.Errorf("builtin OID %d maps to a type ID", o)
+		}
+	}
+}
+
+func TestPGTypeName(t *testing.T) {
+	testCases := []struct {
+		oid  oid.Oid
+		name string
+		typ  *T
+	}{
+		{oid.T_int8, "int8", Int},
+		{oid.T_int4, "int4", Int4},
+		{oid.T__text, "_text", StringArray},
+		{oid.T_varchar, "varchar", VarChar},
+		{oid.T_bpchar, "bpchar", typeBpChar},
+		{oid.T_char, "char", typeQChar},
+		{oid.T_timestamptz, "timestamptz", TimestampTZ},
+		{oid.T_jsonb, "jsonb", Jsonb},
+		{T_jsonpath, "jsonpath", Jsonpath},
+		{T_macaddr8, "macaddr8", MACAddr8},
+	}
+
+	for _, tc := range testCases {
+		if name, ok := PGTypeName(tc.oid); !ok || name != tc.name {
+			t.Errorf("%d: expected name %q, got %q", tc.oid, tc.name, name)
+		}
+		if o, ok := OidForPGTypeName(tc.name); !ok || o != tc.oid {
+			t.Errorf("%s: expected OID %d, got %d", tc.name, tc.oid, o)
+		}
+		if typ, ok := TypeForPGTypeName(tc.name); !ok || !typ.Identical(tc.typ) {
+			t.Errorf("%s: expected %s, got %v", tc.name, tc.typ.DebugString(), typ)
+		}
+	}
+
+	// The names of all the types are distinct.
+	ForEachType(func(typ *T) {
+		name, _ := PGTypeName(typ.Oid())
+		if o, ok := OidForPGTypeName(name); !ok || o != typ.Oid() {
+			t.Errorf("%s: expected OID %d, got %d", name, typ.Oid(), o)
+		}
+	})
+
+	if _, ok := PGTypeName(oid.Oid(123456)); ok {
+		t.Error("expected unknown OID")
+	}
+	if _, ok := TypeForPGTypeName("bigint"); ok {
+		t.Error("expected SQL alias not to be a pg_type name")
+	}
+}
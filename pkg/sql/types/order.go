@@ -0,0 +1,140 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import "sort"
+
+// Compare returns -1, 0 or 1 depending on whether the type sorts before, the
+// same as, or after the other type in the canonical ordering of types. Types
+// are ordered by family and OID, then by their modifiers (width, precision,
+// width unit, interval qualifier and locale), and then by their nested types
+// and tuple labels. Compare returns 0 if and only if the types are Identical.
+//
+// The ordering has no meaning other than being deterministic, so that sets of
+// types, such as the candidates of an overload or the types of the branches of
+// a UNION, can be sorted into a stable order for plans and error messages.
+func (t *T) Compare(other *T) int {
+	a, b := &t.InternalType, &other.InternalType
+	if c := compareInt64(int64(a.Family), int64(b.Family)); c != 0 {
+		return c
+	}
+	if c := compareInt64(int64(a.Oid), int64(b.Oid)); c != 0 {
+		return c
+	}
+	if c := compareInt64(int64(a.Width), int64(b.Width)); c != 0 {
+		return c
+	}
+	if c := compareInt64(int64(a.Precision), int64(b.Precision)); c != 0 {
+		return c
+	}
+	if a.TimePrecisionIsSet != b.TimePrecisionIsSet {
+		if !a.TimePrecisionIsSet {
+			return -1
+		}
+		return 1
+	}
+	if c := compareInt64(int64(a.widthUnit()), int64(b.widthUnit())); c != 0 {
+		return c
+	}
+	if c := compareIntervalDurationFields(a.IntervalDurationField, b.IntervalDurationField); c != 0 {
+		return c
+	}
+	if c := compareLocales(a.Locale, b.Locale); c != 0 {
+		return c
+	}
+
+	switch {
+	case a.ArrayContents == nil && b.ArrayContents != nil:
+		return -1
+	case a.ArrayContents != nil && b.ArrayContents == nil:
+		return 1
+	case a.ArrayContents != nil:
+		if c := a.ArrayContents.Compare(b.ArrayContents); c != 0 {
+			return c
+		}
+	}
+
+	if c := compareInt64(int64(len(a.TupleContents)), int64(len(b.TupleContents))); c != 0 {
+		return c
+	}
+	for i := range a.TupleContents {
+		if c := a.TupleContents[i].Compare(&b.TupleContents[i]); c != 0 {
+			return c
+		}
+	}
+	if c := compareInt64(int64(len(a.TupleLabels)), int64(len(b.TupleLabels))); c != 0 {
+		return c
+	}
+	for i := range a.TupleLabels {
+		if a.TupleLabels[i] < b.TupleLabels[i] {
+			return -1
+		} else if a.TupleLabels[i] > b.TupleLabels[i] {
+			return 1
+		}
+	}
+	return 0
+}
+
+// Less returns true if the type sorts before the other type in the canonical
+// ordering of types. See Compare.
+func (t *T) Less(other *T) bool {
+	return t.Compare(other) < 0
+}
+
+// SortTypes sorts the given types in the canonical ordering of types. See
+// Compare.
+func SortTypes(typs []*T) {
+	sort.SliceStable(typs, func(i, j int) bool { return typs[i].Less(typs[j]) })
+}
+
+func compareInt64(a, b int64) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+// compareIntervalDurationFields orders a missing interval qualifier before any
+// other qualifier.
+func compareIntervalDurationFields(a, b *IntervalDurationField) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	if c := compareInt64(int64(a.DurationType), int64(b.DurationType)); c != 0 {
+		return c
+	}
+	return compareInt64(int64(a.FromDurationType), int64(b.FromDurationType))
+}
+
+// compareLocales orders a missing locale before any other locale, like
+// InternalType.Identical distinguishes them.
+func compareLocales(a, b *string) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	case *a < *b:
+		return -1
+	case *a > *b:
+		return 1
+	}
+	return 0
+}
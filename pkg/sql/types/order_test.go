@@ -0,0 +1,71 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	typs := []*T{
+		Bool, Int, Int4, Int2, Float, Float4, Decimal, MakeDecimal(10, 2), MakeDecimal(10, 3),
+		String, MakeVarChar(10), MakeVarChar(20), MakeChar(10),
+		MakeCollatedString(String, "de"), MakeCollatedString(String, "en"),
+		Time, MakeTime(0), MakeTime(3), Interval,
+		MakeArray(Int), MakeArray(String), MakeArray(MakeArray(Int)),
+		EmptyTuple, MakeTuple([]T{*Int}), MakeTuple([]T{*Int, *String}),
+		MakeLabeledTuple([]T{*Int, *String}, []string{"a", "b"}),
+		MakeLabeledTuple([]T{*Int, *String}, []string{"a", "c"}),
+	}
+	for _, a := range typs {
+		for _, b := range typs {
+			c := a.Compare(b)
+			if c != -b.Compare(a) {
+				t.Errorf("%s, %s: Compare is not antisymmetric", a.DebugString(), b.DebugString())
+			}
+			if (c == 0) != a.Identical(b) {
+				t.Errorf("%s, %s: Compare returned %d, but Identical returned %t",
+					a.DebugString(), b.DebugString(), c, a.Identical(b))
+			}
+		}
+	}
+
+	// Sorting is deterministic regardless of the initial order.
+	sorted := append([]*T(nil), typs...)
+	SortTypes(sorted)
+	for i := 1; i < len(sorted); i++ {
+		if !sorted[i-1].Less(sorted[i]) {
+			t.Errorf("expected %s to sort before %s", sorted[i-1].DebugString(), sorted[i].DebugString())
+		}
+	}
+	reversed := make([]*T, len(typs))
+	for i := range typs {
+		reversed[len(typs)-1-i] = typs[i]
+	}
+	SortTypes(reversed)
+	for i := range sorted {
+		if sorted[i] != reversed[i] {
+			t.Errorf("%d: expected %s, got %s", i, sorted[i].DebugString(), reversed[i].DebugString())
+		}
+	}
+
+	// Types are ordered by family first.
+	typs = []*T{String, MakeArray(Int), Int4, Bool, Int}
+	SortTypes(typs)
+	var names []string
+	for _, typ := range typs {
+		names = append(names, typ.SQLString())
+	}
+	if expected := []string{"BOOL", "INT8", "INT4", "STRING", "INT8[]"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+}
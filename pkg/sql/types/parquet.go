@@ -0,0 +1,243 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"math"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/errors"
+)
+
+// ParquetPhysicalType is one of the primitive types that Parquet uses to store
+// values on disk.
+type ParquetPhysicalType int
+
+// These constants mirror the Type enum of the Parquet format specification
+// (parquet.thrift).
+const (
+	ParquetBoolean ParquetPhysicalType = iota
+	ParquetInt32
+	ParquetInt64
+	ParquetInt96
+	ParquetFloat
+	ParquetDouble
+	ParquetByteArray
+	ParquetFixedLenByteArray
+)
+
+var parquetPhysicalTypeNames = [...]string{
+	ParquetBoolean:           "BOOLEAN",
+	ParquetInt32:             "INT32",
+	ParquetInt64:             "INT64",
+	ParquetInt96:             "INT96",
+	ParquetFloat:             "FLOAT",
+	ParquetDouble:            "DOUBLE",
+	ParquetByteArray:         "BYTE_ARRAY",
+	ParquetFixedLenByteArray: "FIXED_LEN_BYTE_ARRAY",
+}
+
+// String returns the name of the physical type, as used by the Parquet
+// specification.
+func (p ParquetPhysicalType) String() string {
+	if p < 0 || int(p) >= len(parquetPhysicalTypeNames) {
+		return "UNKNOWN"
+	}
+	return parquetPhysicalTypeNames[p]
+}
+
+// ParquetLogicalType is a Parquet logical type annotation, which describes how
+// to interpret the values stored using a physical type.
+type ParquetLogicalType int
+
+// These constants mirror the members of the LogicalType union of the Parquet
+// format specification (parquet.thrift) that are used by CockroachDB.
+const (
+	// ParquetNoLogicalType indicates that the values are interpreted directly
+	// according to their physical type.
+	ParquetNoLogicalType ParquetLogicalType = iota
+	ParquetString
+	ParquetIntType
+	ParquetDecimal
+	ParquetDate
+	ParquetTime
+	ParquetTimestamp
+	ParquetUUID
+	ParquetJSON
+	ParquetList
+)
+
+var parquetLogicalTypeNames = [...]string{
+	ParquetNoLogicalType: "NONE",
+	ParquetString:        "STRING",
+	ParquetIntType:       "INT",
+	ParquetDecimal:       "DECIMAL",
+	ParquetDate:          "DATE",
+	ParquetTime:          "TIME",
+	ParquetTimestamp:     "TIMESTAMP",
+	ParquetUUID:          "UUID",
+	ParquetJSON:          "JSON",
+	ParquetList:          "LIST",
+}
+
+// String returns the name of the logical type, as used by the Parquet
+// specification.
+func (l ParquetLogicalType) String() string {
+	if l < 0 || int(l) >= len(parquetLogicalTypeNames) {
+		return "UNKNOWN"
+	}
+	return parquetLogicalTypeNames[l]
+}
+
+// ParquetType describes how the values of a SQL type are represented in a
+// Parquet file: the physical type used to store them, along with the logical
+// type annotation and its parameters.
+type ParquetType struct {
+	// Physical is the physical type of the values. It is not used for a
+	// ParquetList, which is stored as a nested group rather than a primitive.
+	Physical ParquetPhysicalType
+	Logical  ParquetLogicalType
+
+	// TypeLength is the length in bytes of a ParquetFixedLenByteArray value.
+	TypeLength int32
+	// Precision and Scale are the parameters of a ParquetDecimal value.
+	Precision int32
+	Scale     int32
+	// BitWidth and IsSigned are the parameters of a ParquetIntType value.
+	BitWidth int32
+	IsSigned bool
+	// IsAdjustedToUTC is a parameter of ParquetTime and ParquetTimestamp
+	// values. It is true if the values are instants in UTC, and false if they
+	// are local times.
+	IsAdjustedToUTC bool
+	// Element is the type of the elements of a ParquetList, which are always
+	// optional (nullable).
+	Element *ParquetType
+}
+
+// Precisions of decimals that can be stored in INT32 and INT64 columns,
+// according to the Parquet specification.
+const (
+	parquetMaxInt32DecimalPrecision = 9
+	parquetMaxInt64DecimalPrecision = 18
+)
+
+// ToParquetType returns the Parquet representation of values of the given
+// type, for use by tools that export data in the Parquet format:
+//
+//   - DECIMAL(p,s) uses INT32, INT64 or FIXED_LEN_BYTE_ARRAY storage depending
+//     on p. A DECIMAL with no precision is stored as a STRING, since Parquet
+//     requires a precision.
+//   - TIME, TIMESTAMP and TIMESTAMPTZ use microsecond INT64 storage. Only
+//     TIMESTAMPTZ is adjusted to UTC.
+//   - UUID uses a FIXED_LEN_BYTE_ARRAY of 16 bytes.
+//   - Arrays use the LIST logical type.
+//   - INTERVAL, BIT, INET and collated strings are stored as STRING values,
+//     using their SQL text representation.
+//
+// It returns an error for types that cannot be stored in Parquet, such as
+// tuples.
+func ToParquetType(t *T) (ParquetType, error) {
+	stringType := ParquetType{Physical: ParquetByteArray, Logical: ParquetString}
+
+	switch t.Family() {
+	case BoolFamily:
+		return ParquetType{Physical: ParquetBoolean}, nil
+
+	case IntFamily:
+		switch t.Width() {
+		case 16, 32:
+			return ParquetType{
+				Physical: ParquetInt32, Logical: ParquetIntType, BitWidth: t.Width(), IsSigned: true,
+			}, nil
+		case 64:
+			return ParquetType{
+				Physical: ParquetInt64, Logical: ParquetIntType, BitWidth: 64, IsSigned: true,
+			}, nil
+		}
+		return ParquetType{}, errors.AssertionFailedf("unknown int width: %d", t.Width())
+
+	case OidFamily:
+		return ParquetType{
+			Physical: ParquetInt32, Logical: ParquetIntType, BitWidth: 32, IsSigned: false,
+		}, nil
+
+	case FloatFamily:
+		if t.IsFloat4() {
+			return ParquetType{Physical: ParquetFloat}, nil
+		}
+		return ParquetType{Physical: ParquetDouble}, nil
+
+	case DecimalFamily:
+		precision := t.Precision()
+		if precision == 0 {
+			return stringType, nil
+		}
+		pt := ParquetType{Logical: ParquetDecimal, Precision: precision, Scale: t.Scale()}
+		switch {
+		case precision <= parquetMaxInt32DecimalPrecision:
+			pt.Physical = ParquetInt32
+		case precision <= parquetMaxInt64DecimalPrecision:
+			pt.Physical = ParquetInt64
+		default:
+			pt.Physical = ParquetFixedLenByteArray
+			pt.TypeLength = parquetDecimalByteLength(precision)
+		}
+		return pt, nil
+
+	case DateFamily:
+		return ParquetType{Physical: ParquetInt32, Logical: ParquetDate}, nil
+
+	case TimeFamily:
+		return ParquetType{Physical: ParquetInt64, Logical: ParquetTime}, nil
+
+	case TimestampFamily:
+		return ParquetType{Physical: ParquetInt64, Logical: ParquetTimestamp}, nil
+
+	case TimestampTZFamily:
+		return ParquetType{
+			Physical: ParquetInt64, Logical: ParquetTimestamp, IsAdjustedToUTC: true,
+		}, nil
+
+	case StringFamily, CollatedStringFamily, IntervalFamily, BitFamily, INetFamily:
+		return stringType, nil
+
+	case BytesFamily:
+		return ParquetType{Physical: ParquetByteArray}, nil
+
+	case UuidFamily:
+		return ParquetType{
+			Physical: ParquetFixedLenByteArray, Logical: ParquetUUID, TypeLength: 16,
+		}, nil
+
+	case JsonFamily:
+		return ParquetType{Physical: ParquetByteArray, Logical: ParquetJSON}, nil
+
+	case ArrayFamily:
+		elem, err := ToParquetType(t.ArrayContents())
+		if err != nil {
+			return ParquetType{}, err
+		}
+		return ParquetType{Logical: ParquetList, Element: &elem}, nil
+	}
+
+	return ParquetType{}, pgerror.Newf(pgcode.FeatureNotSupported,
+		"type %s cannot be stored in Parquet", t.SQLString())
+}
+
+// parquetDecimalByteLength returns the minimum number of bytes needed to store
+// the two's complement representation of any unscaled decimal value having the
+// given precision.
+func parquetDecimalByteLength(precision int32) int32 {
+	bits := float64(precision)*math.Log2(10) + 1
+	return int32(math.Ceil(bits / 8))
+}
@@ -0,0 +1,62 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+func TestToParquetType(t *testing.T) {
+	testCases := []struct {
+		typ      *T
+		expected ParquetType
+	}{
+		{Bool, ParquetType{Physical: ParquetBoolean}},
+		{Int2, ParquetType{Physical: ParquetInt32, Logical: ParquetIntType, BitWidth: 16, IsSigned: true}},
+		{Int, ParquetType{Physical: ParquetInt64, Logical: ParquetIntType, BitWidth: 64, IsSigned: true}},
+		{Float4, ParquetType{Physical: ParquetFloat}},
+		{Float, ParquetType{Physical: ParquetDouble}},
+		{MakeDecimal(9, 2), ParquetType{Physical: ParquetInt32, Logical: ParquetDecimal, Precision: 9, Scale: 2}},
+		{MakeDecimal(18, 0), ParquetType{Physical: ParquetInt64, Logical: ParquetDecimal, Precision: 18}},
+		{MakeDecimal(20, 4), ParquetType{
+			Physical: ParquetFixedLenByteArray, Logical: ParquetDecimal, Precision: 20, Scale: 4, TypeLength: 9}},
+		{Decimal, ParquetType{Physical: ParquetByteArray, Logical: ParquetString}},
+		{Date, ParquetType{Physical: ParquetInt32, Logical: ParquetDate}},
+		{Timestamp, ParquetType{Physical: ParquetInt64, Logical: ParquetTimestamp}},
+		{TimestampTZ, ParquetType{Physical: ParquetInt64, Logical: ParquetTimestamp, IsAdjustedToUTC: true}},
+		{Uuid, ParquetType{Physical: ParquetFixedLenByteArray, Logical: ParquetUUID, TypeLength: 16}},
+		{Jsonb, ParquetType{Physical: ParquetByteArray, Logical: ParquetJSON}},
+		{Interval, ParquetType{Physical: ParquetByteArray, Logical: ParquetString}},
+		{IntArray, ParquetType{Logical: ParquetList, Element: &ParquetType{
+			Physical: ParquetInt64, Logical: ParquetIntType, BitWidth: 64, IsSigned: true}}},
+	}
+
+	for _, tc := range testCases {
+		actual, err := ToParquetType(tc.typ)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.typ.SQLString(), err)
+			continue
+		}
+		if !reflect.DeepEqual(actual, tc.expected) {
+			t.Errorf("%s: expected %+v, got %+v", tc.typ.SQLString(), tc.expected, actual)
+		}
+	}
+
+	if _, err := ToParquetType(MakeTuple([]T{*Int})); err == nil {
+		t.Error("expected error for tuple type")
+	} else if code := pgerror.GetPGCode(err); code != pgcode.FeatureNotSupported {
+		t.Errorf("expected code %s, got %s", pgcode.FeatureNotSupported, code)
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import "github.com/lib/pq/oid"
+
+// These are the kinds of types used in the typtype column of pg_type.
+const (
+	PGTypeTypeBase      byte = 'b'
+	PGTypeTypeComposite byte = 'c'
+	PGTypeTypeDomain    byte = 'd'
+	PGTypeTypeEnum      byte = 'e'
+	PGTypeTypePseudo    byte = 'p'
+	PGTypeTypeRange     byte = 'r'
+)
+
+// These are the type categories used in the typcategory column of pg_type. See
+// https://www.postgresql.org/docs/9.6/static/catalog-pg-type.html#CATALOG-TYPCATEGORY-TABLE.
+const (
+	PGTypeCategoryArray       byte = 'A'
+	PGTypeCategoryBoolean     byte = 'B'
+	PGTypeCategoryComposite   byte = 'C'
+	PGTypeCategoryDateTime    byte = 'D'
+	PGTypeCategoryEnum        byte = 'E'
+	PGTypeCategoryGeometric   byte = 'G'
+	PGTypeCategoryNetworkAddr byte = 'I'
+	PGTypeCategoryNumeric     byte = 'N'
+	PGTypeCategoryPseudo      byte = 'P'
+	PGTypeCategoryRange       byte = 'R'
+	PGTypeCategoryString      byte = 'S'
+	PGTypeCategoryTimespan    byte = 'T'
+	PGTypeCategoryUserDefined byte = 'U'
+	PGTypeCategoryBitString   byte = 'V'
+	PGTypeCategoryUnknown     byte = 'X'
+)
+
+// PGTypeInfo contains the values that describe a type in the pg_type catalog
+// table, and in the columns of pg_attribute that are copied from it. The
+// values match the ones reported by Postgres for the analogous types.
+type PGTypeInfo struct {
+	// Name is the typname column. See T.PGName.
+	Name string
+	// Len is the typlen column: the size in bytes of the internal
+	// representation of fixed-size types, -1 for variable-length types, and -2
+	// for null-terminated types.
+	Len int16
+	// ByVal is the typbyval column, which is true if values are passed by value
+	// rather than by reference. Only fixed-size types of at most 8 bytes are
+	// passed by value.
+	ByVal bool
+	// Type is the typtype column. It is one of the PGTypeType constants.
+	Type byte
+	// Category is the typcategory column. It is one of the PGTypeCategory
+	// constants.
+	Category byte
+	// IsPreferred is the typispreferred column, which is true if the type is
+	// the preferred type of its category.
+	IsPreferred bool
+	// Delim is the typdelim column: the character that separates values of the
+	// type in the text representation of arrays.
+	Delim byte
+	// Elem is the typelem column: the OID of the element type of array types,
+	// or 0 if there is none.
+	Elem oid.Oid
+	// Array is the typarray column: the OID of the array type having elements
+	// of this type, or 0 if there is none.
+	Array oid.Oid
+}
+
+// pgTypeCategories maps each type family to its pg_type category. This mapping
+// should be kept in sync with the Postgres categorization.
+var pgTypeCategories = map[Family]byte{
+	AnyFamily:            PGTypeCategoryPseudo,
+	BitFamily:            PGTypeCategoryBitString,
+	BoolFamily:           PGTypeCategoryBoolean,
+	BytesFamily:          PGTypeCategoryUserDefined,
+	CollatedStringFamily: PGTypeCategoryString,
+	DateFamily:           PGTypeCategoryDateTime,
+	TimeFamily:           PGTypeCategoryDateTime,
+	FloatFamily:          PGTypeCategoryNumeric,
+	IntFamily:            PGTypeCategoryNumeric,
+	IntervalFamily:       PGTypeCategoryTimespan,
+	JsonFamily:           PGTypeCategoryUserDefined,
+	DecimalFamily:        PGTypeCategoryNumeric,
+	StringFamily:         PGTypeCategoryString,
+	TimestampFamily:      PGTypeCategoryDateTime,
+	TimestampTZFamily:    PGTypeCategoryDateTime,
+	ArrayFamily:          PGTypeCategoryArray,
+	TupleFamily:          PGTypeCategoryPseudo,
+	OidFamily:            PGTypeCategoryNumeric,
+	UuidFamily:           PGTypeCategoryUserDefined,
+	INetFamily:           PGTypeCategoryNetworkAddr,
+	UnknownFamily:        PGTypeCategoryUnknown,
+	VoidFamily:           PGTypeCategoryPseudo,
+	TriggerFamily:        PGTypeCategoryPseudo,
+}
+
+// PGTypeInfo returns the values that describe this type in the pg_type
+// catalog table.
+func (t *T) PGTypeInfo() PGTypeInfo {
+	info := PGTypeInfo{
+		Name:     t.PGName(),
+		Len:      t.pgTypeLen(),
+		Type:     PGTypeTypeBase,
+		Category: pgTypeCategories[t.Family()],
+		// Like Postgres, TEXT is the preferred type of the string category.
+		IsPreferred: t.StringKind() == TextKind,
+		Delim:       t.ArrayDelimiter(),
+	}
+	// Like Postgres, MAC addresses are passed by reference despite their size.
+	info.ByVal = info.Len > 0 && info.Len <= 8 && t.StringKind() != MACAddrKind &&
+		t.StringKind() != MACAddr8Kind
+
+	// The types that are represented as strings but are not character string
+	// types in Postgres are not in the string category.
+	switch t.StringKind() {
+	case JSONPathKind, RefCursorKind, PGLSNKind, MACAddrKind, MACAddr8Kind:
+		info.Category = PGTypeCategoryUserDefined
+	}
+
+	// Special case ARRAY of ANY.
+	if t.Family() == ArrayFamily && t.ArrayContents().Family() == AnyFamily {
+		info.Category = PGTypeCategoryPseudo
+	}
+	if info.Category == PGTypeCategoryPseudo {
+		info.Type = PGTypeTypePseudo
+	}
+
+	// AnyArray does not have an element type.
+	if elem, ok := ElemOid(t.Oid()); ok && t.Oid() != oid.T_anyarray {
+		info.Elem = elem
+	}
+	// Array types do not have array types of their own, with the exception of
+	// the vector types.
+	info.Array, _ = ArrayOid(t.Oid())
+	return info
+}
+
+// ArrayDelimiter returns the character that separates values of this type in
+// the text representation of arrays, as reported by the typdelim column of
+// pg_type. Postgres uses ';' for the box type and ',' for all the other builtin
+// types, which include all the types supported by CockroachDB.
+func (t *T) ArrayDelimiter() byte {
+	return ','
+}
+
+// pgTypeLen returns the typlen of the type: the size in bytes of its internal
+// representation in Postgres, or -1 if it has a variable length.
+func (t *T) pgTypeLen() int16 {
+	switch t.Family() {
+	case BoolFamily:
+		return 1
+	case IntFamily, FloatFamily:
+		return int16(t.Width() / 8)
+	case DateFamily, OidFamily:
+		return 4
+	case TimeFamily, TimestampFamily, TimestampTZFamily:
+		return 8
+	case IntervalFamily, UuidFamily:
+		return 16
+	case StringFamily, CollatedStringFamily:
+		switch t.StringKind() {
+		case QCharKind:
+			return 1
+		case NameKind:
+			// The values are null-terminated.
+			return MaxNameLength + 1
+		case PGLSNKind:
+			return 8
+		case MACAddrKind:
+			return MACAddrSize
+		case MACAddr8Kind:
+			return MACAddr8Size
+		}
+	case AnyFamily, VoidFamily, TriggerFamily:
+		// These pseudo-types have the same representation as oid.
+		return 4
+	case UnknownFamily:
+		// unknown is represented by a null-terminated string.
+		return -2
+	}
+	return -1
+}
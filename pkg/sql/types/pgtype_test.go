@@ -0,0 +1,148 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/lib/pq/oid"
+)
+
+func TestPGTypeInfo(t *testing.T) {
+	testCases := []struct {
+		typ      *T
+		expected PGTypeInfo
+	}{
+		{Bool, PGTypeInfo{Name: "bool", Len: 1, ByVal: true, Type: 'b', Category: 'B', Delim: ',',
+			Array: oid.T__bool}},
+		{Int2, PGTypeInfo{Name: "int2", Len: 2, ByVal: true, Type: 'b', Category: 'N', Delim: ',',
+			Array: oid.T__int2}},
+		{Float4, PGTypeInfo{Name: "float4", Len: 4, ByVal: true, Type: 'b', Category: 'N', Delim: ',',
+			Array: oid.T__float4}},
+		{String, PGTypeInfo{Name: "text", Len: -1, Type: 'b', Category: 'S', IsPreferred: true,
+			Delim: ',', Array: oid.T__text}},
+		{MakeCollatedString(MakeVarChar(10), "en"), PGTypeInfo{Name: "varchar", Len: -1, Type: 'b',
+			Category: 'S', Delim: ',', Array: oid.T__varchar}},
+		{Name, PGTypeInfo{Name: "name", Len: 64, Type: 'b', Category: 'S', Delim: ',',
+			Array: oid.T__name}},
+		{Jsonpath, PGTypeInfo{Name: "jsonpath", Len: -1, Type: 'b', Category: 'U', Delim: ',',
+			Array: T__jsonpath}},
+		{RefCursor, PGTypeInfo{Name: "refcursor", Len: -1, Type: 'b', Category: 'U', Delim: ',',
+			Array: oid.T__refcursor}},
+		{PGLSN, PGTypeInfo{Name: "pg_lsn", Len: 8, ByVal: true, Type: 'b', Category: 'U', Delim: ',',
+			Array: oid.T__pg_lsn}},
+		{MACAddr, PGTypeInfo{Name: "macaddr", Len: 6, Type: 'b', Category: 'U', Delim: ',',
+			Array: oid.T__macaddr}},
+		{MACAddr8, PGTypeInfo{Name: "macaddr8", Len: 8, Type: 'b', Category: 'U', Delim: ',',
+			Array: T__macaddr8}},
+		{Interval, PGTypeInfo{Name: "interval", Len: 16, Type: 'b', Category: 'T', Delim: ',',
+			Array: oid.T__interval}},
+		{Uuid, PGTypeInfo{Name: "uuid", Len: 16, Type: 'b', Category: 'U', Delim: ',',
+			Array: oid.T__uuid}},
+		{Unknown, PGTypeInfo{Name: "unknown", Len: -2, Type: 'b', Category: 'X', Delim: ','}},
+		{Void, PGTypeInfo{Name: "void", Len: 4, ByVal: true, Type: 'p', Category: 'P', Delim: ','}},
+		{Trigger, PGTypeInfo{Name: "trigger", Len: 4, ByVal: true, Type: 'p', Category: 'P', Delim: ','}},
+		{EventTrigger, PGTypeInfo{Name: "event_trigger", Len: 4, ByVal: true, Type: 'p', Category: 'P',
+			Delim: ','}},
+		{IntArray, PGTypeInfo{Name: "_int8", Len: -1, Type: 'b', Category: 'A', Delim: ',',
+			Elem: oid.T_int8}},
+		{Int2Vector, PGTypeInfo{Name: "int2vector", Len: -1, Type: 'b', Category: 'A', Delim: ',',
+			Elem: oid.T_int2, Array: oid.T__int2vector}},
+		{Any, PGTypeInfo{Name: "anyelement", Len: 4, ByVal: true, Type: 'p', Category: 'P',
+			Delim: ',', Array: oid.T_anyarray}},
+		{AnyArray, PGTypeInfo{Name: "anyarray", Len: -1, Type: 'p', Category: 'P', Delim: ','}},
+		{AnyTuple, PGTypeInfo{Name: "record", Len: -1, Type: 'p', Category: 'P', Delim: ',',
+			Array: oid.T__record}},
+	}
+
+	for _, tc := range testCases {
+		if actual := tc.typ.PGTypeInfo(); actual != tc.expected {
+			t.Errorf("%s: expected %+v, got %+v", tc.typ.DebugString(), tc.expected, actual)
+		}
+	}
+
+	// Every type with an OID has a complete description.
+	for o, typ := range OidToType {
+		info := typ.PGTypeInfo()
+		if info.Category == 0 || info.Len == 0 || info.Name == "" {
+			t.Errorf("%d: incomplete type info %+v", o, info)
+		}
+		if info.ByVal && (info.Len < 1 || info.Len > 8) {
+			t.Errorf("%d: unexpected by-value type with length %d", o, info.Len)
+		}
+		if info.Delim != typ.ArrayDelimiter() || info.Delim != ',' {
+			t.Errorf("%d: unexpected array delimiter %q", o, info.Delim)
+		}
+	}
+}
+
+// pgTypeSnapshotFile is a snapshot of pg_type in Postgres. See
+// TestPGTypeSnapshot.
+const pgTypeSnapshotFile = "testdata/pg_type"
+
+// maxBuiltinPGOid is the largest OID of the built-in Postgres types. The OIDs of
+// the objects created by Postgres at bootstrap, and by users, are larger.
+const maxBuiltinPGOid = 9999
+
+// TestPGTypeSnapshot checks that the OIDs of the types match the snapshot of
+// pg_type in testdata/pg_type, so that a refactor cannot silently change an OID
+// that drivers hard-code.
+func TestPGTypeSnapshot(t *testing.T) {
+	f, err := os.Open(pgTypeSnapshotFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	snapshot := make(map[oid.Oid]bool)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var o, typArray oid.Oid
+		var typName string
+		if _, err := fmt.Sscanf(line, "%d\t%s\t%d", &o, &typName, &typArray); err != nil {
+			t.Fatalf("invalid line %q: %v", line, err)
+		}
+		snapshot[o] = true
+
+		typ, ok := TypeForOid(o)
+		if !ok {
+			t.Errorf("%s: no type with OID %d", typName, o)
+			continue
+		}
+		if name := typ.PGName(); name != typName {
+			t.Errorf("%d: expected type %s, got %s", o, typName, name)
+		}
+		if typArray != 0 {
+			if arrayOid, ok := ArrayOid(o); !ok || arrayOid != typArray {
+				t.Errorf("%s: expected array OID %d, got %d", typName, typArray, arrayOid)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Types that use the OIDs of built-in Postgres types must use them for the
+	// same types as Postgres, so they must be added to the snapshot.
+	for o, typ := range OidToType {
+		if o <= maxBuiltinPGOid && !snapshot[o] {
+			t.Errorf("%s: OID %d is missing from %s", typ.PGName(), o, pgTypeSnapshotFile)
+		}
+	}
+}
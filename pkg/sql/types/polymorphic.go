@@ -0,0 +1,183 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import "github.com/lib/pq/oid"
+
+// PolymorphicBindings records the concrete types that polymorphic pseudo-types
+// have been bound to while matching a list of argument types against a list of
+// parameter types. It follows the Postgres rules for polymorphic functions:
+// every occurrence of anyelement, anyarray and anynonarray in a signature must
+// resolve to the same element type. For example, matching (anyarray,
+// anyelement) against (INT[], INT) succeeds, while matching it against (INT[],
+// STRING) does not.
+//
+// The element type is stored under oid.T_anyelement. Use Resolve to compute
+// the concrete type corresponding to a polymorphic type.
+//
+// Only Any, AnyArray and AnyNonArray are bound. AnyTuple and AnyCollatedString
+// remain wildcards that match independently at each occurrence.
+type PolymorphicBindings map[oid.Oid]*T
+
+// MatchPolymorphic matches the given argument types against the given
+// parameter types, which may contain wildcard and polymorphic types. If every
+// argument matches, it returns the resulting bindings and true. NULL arguments
+// (UnknownFamily) match any parameter type without binding it.
+func MatchPolymorphic(params, args []*T) (PolymorphicBindings, bool) {
+	if len(params) != len(args) {
+		return nil, false
+	}
+	b := make(PolymorphicBindings)
+	for i := range params {
+		if !b.Match(params[i], args[i]) {
+			return nil, false
+		}
+	}
+	return b, true
+}
+
+// Match returns true if the actual type matches the pattern type, given the
+// types already bound in b. New bindings made by the match are added to b,
+// which must not be nil. If the match fails, b may contain partial bindings
+// and should be discarded. A NULL actual type (UnknownFamily) matches any
+// pattern without binding it.
+func (b PolymorphicBindings) Match(pattern, actual *T) bool {
+	if actual.Family() == UnknownFamily {
+		return true
+	}
+	return matchPattern(pattern, actual, b)
+}
+
+// Resolve returns the concrete type that the given type resolves to under the
+// bindings in b: anyelement and anynonarray resolve to the bound element type,
+// and anyarray resolves to an array of it. Arrays and tuples containing
+// polymorphic types are resolved recursively. Any polymorphic type that is not
+// yet bound is returned unchanged.
+func (b PolymorphicBindings) Resolve(typ *T) *T {
+	switch typ.Family() {
+	case AnyFamily:
+		if elem, ok := b[oid.T_anyelement]; ok {
+			return elem
+		}
+	case ArrayFamily:
+		if typ.Oid() == oid.T_anyarray {
+			if elem, ok := b[oid.T_anyelement]; ok {
+				return MakeArray(elem)
+			}
+			return typ
+		}
+		if contents := typ.ArrayContents(); contents != nil {
+			if resolved := b.Resolve(contents); resolved != contents {
+				return MakeArray(resolved)
+			}
+		}
+	case TupleFamily:
+		if IsWildcardTupleType(typ) {
+			return typ
+		}
+		var contents []T
+		for i := range typ.TupleContents() {
+			orig := &typ.TupleContents()[i]
+			if resolved := b.Resolve(orig); resolved != orig {
+				if contents == nil {
+					contents = make([]T, len(typ.TupleContents()))
+					copy(contents, typ.TupleContents())
+				}
+				contents[i] = *resolved
+			}
+		}
+		if contents != nil {
+			return MakeLabeledTuple(contents, typ.TupleLabels())
+		}
+	}
+	return typ
+}
+
+// bind unifies the given element type with the element type already bound in
+// b, or binds it if there is none. It returns false if the two conflict. If b
+// is nil, the element type is not recorded and always matches.
+func (b PolymorphicBindings) bind(elem *T) bool {
+	if b == nil || elem.Family() == UnknownFamily {
+		return true
+	}
+	if bound, ok := b[oid.T_anyelement]; ok {
+		if bound.Family() == UnknownFamily {
+			b[oid.T_anyelement] = elem
+			return true
+		}
+		return bound.Equivalent(elem)
+	}
+	b[oid.T_anyelement] = elem
+	return true
+}
+
+// Matches returns true if the actual type matches this type, which may be or
+// contain wildcard types (Any, AnyArray, AnyNonArray, AnyTuple and
+// AnyCollatedString). Each wildcard is matched independently, so a signature
+// such as (anyelement, anyelement) matches (INT, STRING). Use
+// PolymorphicBindings to enforce that polymorphic types bind consistently.
+//
+// Unlike Equivalent, a NULL actual type (UnknownFamily) matches any pattern,
+// and AnyNonArray does not match an array type.
+func (t *T) Matches(actual *T) bool {
+	if actual.Family() == UnknownFamily {
+		return true
+	}
+	return matchPattern(t, actual, nil /* b */)
+}
+
+// matchPattern implements Matches and PolymorphicBindings.Match. If b is nil,
+// polymorphic types are treated as unbound wildcards.
+func matchPattern(pattern, actual *T, b PolymorphicBindings) bool {
+	if actual.Family() == AnyFamily {
+		// A wildcard actual type matches any pattern. This mirrors the symmetry
+		// of Equivalent.
+		return true
+	}
+
+	switch pattern.Family() {
+	case AnyFamily:
+		if pattern.Oid() == oid.T_anynonarray && actual.Family() == ArrayFamily {
+			return false
+		}
+		return b.bind(actual)
+
+	case ArrayFamily:
+		if actual.Family() != ArrayFamily {
+			return false
+		}
+		if pattern.Oid() == oid.T_anyarray {
+			return b.bind(actual.ArrayContents())
+		}
+		return matchPattern(pattern.ArrayContents(), actual.ArrayContents(), b)
+
+	case TupleFamily:
+		if actual.Family() != TupleFamily {
+			return false
+		}
+		if IsWildcardTupleType(pattern) || IsWildcardTupleType(actual) {
+			return true
+		}
+		if len(pattern.TupleContents()) != len(actual.TupleContents()) {
+			return false
+		}
+		for i := range pattern.TupleContents() {
+			if !matchPattern(&pattern.TupleContents()[i], &actual.TupleContents()[i], b) {
+				return false
+			}
+		}
+		return true
+	}
+
+	// Equivalent handles the remaining scalar types, including the wildcard
+	// collation of AnyCollatedString.
+	return pattern.Equivalent(actual)
+}
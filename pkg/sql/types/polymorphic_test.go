@@ -0,0 +1,102 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"testing"
+
+	"github.com/lib/pq/oid"
+)
+
+func TestMatches(t *testing.T) {
+	testCases := []struct {
+		pattern *T
+		actual  *T
+		match   bool
+	}{
+		{Any, Int, true},
+		{Any, IntArray, true},
+		{Any, Unknown, true},
+		{AnyNonArray, Int, true},
+		{AnyNonArray, IntArray, false},
+		{AnyArray, IntArray, true},
+		{AnyArray, MakeArray(MakeArray(String)), true},
+		{AnyArray, Int, false},
+		{AnyArray, Unknown, true},
+		{AnyTuple, MakeTuple([]T{*Int, *String}), true},
+		{AnyTuple, EmptyTuple, true},
+		{AnyTuple, Int, false},
+		{AnyCollatedString, MakeCollatedString(String, "en"), true},
+		{AnyCollatedString, String, false},
+		{MakeTuple([]T{*Any, *Int}), MakeTuple([]T{*String, *Int4}), true},
+		{MakeTuple([]T{*Any, *Int}), MakeTuple([]T{*String, *String}), false},
+		{Int, Int4, true},
+		{Int, Any, true},
+		{Int, String, false},
+	}
+
+	for _, tc := range testCases {
+		if actual := tc.pattern.Matches(tc.actual); actual != tc.match {
+			t.Errorf("expected <%v>.Matches(<%v>) to be %t",
+				tc.pattern.DebugString(), tc.actual.DebugString(), tc.match)
+		}
+	}
+}
+
+func TestMatchPolymorphic(t *testing.T) {
+	testCases := []struct {
+		params []*T
+		args   []*T
+		match  bool
+		elem   *T
+	}{
+		{[]*T{Any, Any}, []*T{Int, Int4}, true, Int},
+		{[]*T{Any, Any}, []*T{Int, String}, false, nil},
+		{[]*T{Any, Any}, []*T{Unknown, String}, true, String},
+		{[]*T{AnyArray, Any}, []*T{IntArray, Int}, true, Int},
+		{[]*T{AnyArray, Any}, []*T{IntArray, String}, false, nil},
+		{[]*T{Any, AnyArray}, []*T{Unknown, StringArray}, true, String},
+		{[]*T{AnyArray, AnyNonArray}, []*T{IntArray, Int}, true, Int},
+		{[]*T{AnyNonArray}, []*T{IntArray}, false, nil},
+		{[]*T{AnyTuple, AnyTuple}, []*T{MakeTuple([]T{*Int}), EmptyTuple}, true, nil},
+		{[]*T{MakeTuple([]T{*Any, *Any})}, []*T{MakeTuple([]T{*Int, *String})}, false, nil},
+		{[]*T{Any}, []*T{Int, Int}, false, nil},
+	}
+
+	for i, tc := range testCases {
+		b, ok := MatchPolymorphic(tc.params, tc.args)
+		if ok != tc.match {
+			t.Errorf("%d: expected match=%t, got %t", i, tc.match, ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		elem, bound := b[oid.T_anyelement]
+		if tc.elem == nil {
+			if bound {
+				t.Errorf("%d: expected no binding, got %s", i, elem.DebugString())
+			}
+			continue
+		}
+		if !bound || !elem.Identical(tc.elem) {
+			t.Errorf("%d: expected binding %s, got %v", i, tc.elem.DebugString(), elem)
+			continue
+		}
+		if res := b.Resolve(AnyArray); !res.Identical(MakeArray(tc.elem)) {
+			t.Errorf("%d: expected anyarray to resolve to %s, got %s",
+				i, MakeArray(tc.elem).DebugString(), res.DebugString())
+		}
+		if res := b.Resolve(MakeTuple([]T{*Any, *Bool})); !res.Identical(MakeTuple([]T{*tc.elem, *Bool})) {
+			t.Errorf("%d: unexpected tuple resolution %s", i, res.DebugString())
+		}
+	}
+}
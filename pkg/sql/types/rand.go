@@ -0,0 +1,329 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/cockroachdb/apd"
+	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
+	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
+)
+
+// These are the limits on the size of the values generated by RandDatumOfType
+// for the types that do not limit it themselves.
+const (
+	randMaxLength      = 10
+	randMaxBits        = 100
+	randMaxDigits      = 18
+	randMaxJSONDepth   = 3
+	randMinUnixSeconds = -62135596800 // 0001-01-01 00:00:00 UTC
+	randMaxUnixSeconds = 253402300799 // 9999-12-31 23:59:59 UTC
+)
+
+// RandDatumOfType returns a random value of the given type, in the canonical Go
+// representation of the non-NULL values of the type (see GoType). The value is
+// valid for the type metadata:
+//
+//   INT2, INT4, INT8    : within the bounds of the width
+//   DECIMAL(p,s)        : at most p digits, s of which are fractional
+//   string types        : no longer than the width, in the unit of the type;
+//                         collated strings contain arbitrary Unicode letters,
+//                         and the other string types ASCII characters, except
+//                         for the types whose values have a fixed format, such
+//                         as PG_LSN and MACADDR
+//   BIT(n), VARBIT(n)   : exactly n, or at most n, bits
+//   BYTES(n)            : at most n bytes
+//   TIME(p), TIMESTAMP  : rounded to the precision of the type
+//   INTERVAL            : only the fields allowed by the qualifier, rounded
+//                         to the precision of the type
+//   T[]                 : elements of T, and no more than the bound of the
+//                         dimension of the array, if it has one
+//
+// Fuzzers, workload generators and encoding tests use it so that they agree on
+// the values of each type. It returns an error for types that have no Go
+// representation, such as tuples and wildcard types.
+func RandDatumOfType(rng *rand.Rand, t *T) (interface{}, error) {
+	goType, err := GoType(t)
+	if err != nil {
+		return nil, err
+	}
+	switch t.Family() {
+	case BoolFamily:
+		return rng.Intn(2) == 1, nil
+	case IntFamily:
+		switch t.Width() {
+		case 16:
+			return int16(rng.Uint32()), nil
+		case 32:
+			return int32(rng.Uint32()), nil
+		}
+		// int64(rng.Uint64()) to get negative numbers, too.
+		return int64(rng.Uint64()), nil
+	case FloatFamily:
+		if t.IsFloat4() {
+			return float32(rng.NormFloat64()), nil
+		}
+		return rng.NormFloat64(), nil
+	case DecimalFamily:
+		return randDecimal(rng, t), nil
+	case StringFamily, CollatedStringFamily:
+		return randString(rng, t), nil
+	case BytesFamily:
+		n := randMaxLength
+		if t.Width() > 0 {
+			n = int(t.Width())
+		}
+		p := make([]byte, rng.Intn(n+1))
+		_, _ = rng.Read(p)
+		return p, nil
+	case DateFamily:
+		days := (randMaxUnixSeconds - randMinUnixSeconds) / (24 * 60 * 60)
+		return timeutil.Unix(randMinUnixSeconds, 0).AddDate(0, 0, rng.Intn(days+1)), nil
+	case TimestampFamily, TimestampTZFamily:
+		// The precision of the TIMESTAMP types is -1 by default.
+		precision := t.Precision()
+		if precision < 0 || precision > MaxTimePrecision {
+			precision = DefaultTimePrecision
+		}
+		sec := randMinUnixSeconds + rng.Int63n(randMaxUnixSeconds-randMinUnixSeconds+1)
+		nsec := rng.Int63n(int64(time.Second)) / pow10(9-precision) * pow10(9-precision)
+		return timeutil.Unix(sec, nsec), nil
+	case TimeFamily:
+		micros := int64(timeofday.Random(rng))
+		return timeofday.TimeOfDay(micros / pow10(6-t.timePrecision()) * pow10(6-t.timePrecision())), nil
+	case IntervalFamily:
+		return randInterval(rng, t), nil
+	case UuidFamily:
+		return uuid.Must(uuid.NewGenWithReader(rng).NewV4()), nil
+	case INetFamily:
+		return ipaddr.RandIPAddr(rng), nil
+	case BitFamily:
+		n := uint(t.Width())
+		if n == 0 {
+			n = uint(rng.Intn(randMaxBits + 1))
+		} else if t.Oid() == oid.T_varbit {
+			n = uint(rng.Intn(int(n) + 1))
+		}
+		return bitarray.Rand(rng, n), nil
+	case OidFamily:
+		return oid.Oid(rng.Uint32()), nil
+	case JsonFamily:
+		data, err := json.Marshal(randJSONValue(rng, randMaxJSONDepth))
+		if err != nil {
+			return nil, errors.NewAssertionErrorWithWrappedErrf(err, "failed to marshal random JSON")
+		}
+		return json.RawMessage(data), nil
+	case ArrayFamily:
+		n := rng.Intn(randMaxLength)
+		if dims := t.InternalType.ArrayDimensions; len(dims) == 1 && dims[0] >= 0 && int(dims[0]) < n {
+			n = int(dims[0])
+		}
+		arr := reflect.MakeSlice(goType, n, n)
+		for i := 0; i < n; i++ {
+			elem, err := RandDatumOfType(rng, t.ArrayContents())
+			if err != nil {
+				return nil, err
+			}
+			arr.Index(i).Set(reflect.ValueOf(elem))
+		}
+		return arr.Interface(), nil
+	}
+	return nil, errors.AssertionFailedf("no random values for type %s", t.SQLString())
+}
+
+// pow10 returns 10 to the power of the given non-negative exponent.
+func pow10(exp int32) int64 {
+	res := int64(1)
+	for i := int32(0); i < exp; i++ {
+		res *= 10
+	}
+	return res
+}
+
+// timePrecision returns the number of fractional second digits of a TIME or
+// INTERVAL type.
+func (t *T) timePrecision() int32 {
+	if t.TimePrecisionIsSet() {
+		return t.Precision()
+	}
+	return DefaultTimePrecision
+}
+
+// randDecimal returns a random value of a DECIMAL type. The values of a
+// DECIMAL type without a precision have up to randMaxDigits digits and an
+// arbitrary exponent.
+func randDecimal(rng *rand.Rand, t *T) apd.Decimal {
+	maxDigits, exponent := randMaxDigits, int32(rng.Intn(40)-20)
+	if t.Precision() > 0 {
+		maxDigits, exponent = int(t.Precision()), -t.Scale()
+	}
+	digits := make([]byte, 1+rng.Intn(maxDigits))
+	for i := range digits {
+		digits[i] = byte('0' + rng.Intn(10))
+	}
+	var d apd.Decimal
+	d.Coeff.SetString(string(digits), 10)
+	d.Exponent = exponent
+	d.Negative = d.Coeff.Sign() != 0 && rng.Intn(2) == 1
+	return d
+}
+
+// randString returns a random value of a type in the StringFamily or the
+// CollatedStringFamily.
+func randString(rng *rand.Rand, t *T) string {
+	switch t.StringKind() {
+	case PGLSNKind:
+		return FormatLSN(rng.Uint64())
+	case MACAddrKind, MACAddr8Kind:
+		addr := make(net.HardwareAddr, t.macAddrSize())
+		_, _ = rng.Read(addr)
+		return addr.String()
+	case JSONPathKind:
+		return "$." + randIdentifier(rng)
+	case RefCursorKind:
+		return randIdentifier(rng)
+	}
+
+	maxLength := randMaxLength
+	switch {
+	case t.Width() > 0:
+		maxLength = int(t.Width())
+	case t.StringKind() == QCharKind:
+		maxLength = 1
+	case t.StringKind() == NameKind:
+		maxLength = MaxNameLength
+	}
+	var b strings.Builder
+	for n := rng.Intn(maxLength + 1); n > 0; n-- {
+		var r rune
+		if t.Family() == CollatedStringFamily {
+			for {
+				r = rune(rng.Intn(unicode.MaxRune + 1))
+				if unicode.IsLetter(r) {
+					break
+				}
+			}
+		} else {
+			r = rune(' ' + rng.Intn('~'-' '+1))
+		}
+		// The widths of some types are measured in bytes, so a character may
+		// not fit even though there are characters left.
+		s := b.String() + string(r)
+		if !t.StringFitsWidth(s) || t.TruncateString(s) != s {
+			break
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// randIdentifier returns a random lowercase SQL identifier.
+func randIdentifier(rng *rand.Rand) string {
+	p := make([]byte, 1+rng.Intn(randMaxLength))
+	for i := range p {
+		p[i] = byte('a' + rng.Intn(26))
+	}
+	return string(p)
+}
+
+// randInterval returns a random value of an INTERVAL type, which only has the
+// fields allowed by the qualifier of the type. For example, the values of
+// INTERVAL YEAR are a whole number of years, and those of INTERVAL DAY TO HOUR
+// have days and hours, but no months, minutes or seconds.
+func randInterval(rng *rand.Rand, t *T) duration.Duration {
+	itm, err := t.IntervalTypeMetadata()
+	if err != nil {
+		panic(err)
+	}
+	df := itm.DurationField
+	from, to := df.FromDurationType, df.DurationType
+	if to == IntervalDurationType_UNSET {
+		from, to = IntervalDurationType_YEAR, IntervalDurationType_SECOND
+	} else if from == IntervalDurationType_UNSET {
+		from = to
+	}
+	sign := 1 - rng.Int63n(2)*2
+
+	var months, days, nanos int64
+	if from <= IntervalDurationType_MONTH {
+		months = rng.Int63n(1000)
+		if to == IntervalDurationType_YEAR {
+			months -= months % 12
+		}
+	}
+	if from <= IntervalDurationType_DAY && to >= IntervalDurationType_DAY {
+		days = rng.Int63n(1000)
+	}
+	if to >= IntervalDurationType_HOUR {
+		var unit int64
+		switch to {
+		case IntervalDurationType_HOUR:
+			unit = int64(time.Hour)
+		case IntervalDurationType_MINUTE:
+			unit = int64(time.Minute)
+		default:
+			unit = pow10(9 - t.timePrecision())
+		}
+		nanos = rng.Int63n(25*int64(time.Hour)) / unit * unit
+		if from >= IntervalDurationType_HOUR {
+			// The qualifiers that start at hours or minutes have no days, so
+			// the time can exceed a day.
+			nanos = rng.Int63n(1000*int64(time.Hour)) / unit * unit
+		}
+	}
+	return duration.MakeDuration(sign*nanos, sign*days, sign*months)
+}
+
+// randJSONValue returns a random value that can be marshaled as JSON, which
+// contains objects and arrays nested at most depth levels deep.
+func randJSONValue(rng *rand.Rand, depth int) interface{} {
+	n := 6
+	if depth == 0 {
+		n = 4
+	}
+	switch rng.Intn(n) {
+	case 0:
+		return nil
+	case 1:
+		return rng.Intn(2) == 1
+	case 2:
+		return math.Round(rng.NormFloat64()*1000) / 100
+	case 3:
+		return randIdentifier(rng)
+	case 4:
+		arr := make([]interface{}, rng.Intn(4))
+		for i := range arr {
+			arr[i] = randJSONValue(rng, depth-1)
+		}
+		return arr
+	default:
+		obj := make(map[string]interface{})
+		for i := rng.Intn(4); i > 0; i-- {
+			obj[randIdentifier(rng)+strconv.Itoa(i)] = randJSONValue(rng, depth-1)
+		}
+		return obj
+	}
+}
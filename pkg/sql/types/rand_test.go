@@ -0,0 +1,123 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/apd"
+	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
+)
+
+func TestRandDatumOfType(t *testing.T) {
+	rng, _ := randutil.NewPseudoRand()
+	typs := append(AllTypes(),
+		MakeVarChar(3),
+		MakeStringWithWidthUnit(MakeVarChar(3), StringWidthUnit_BYTES),
+		MakeCollatedString(MakeChar(2), "de"),
+		MakeBit(5),
+		MakeVarBit(4),
+		MakeDecimal(5, 2),
+		MakeTime(2),
+		MakeTimestamp(0),
+		MakeInterval(IntervalTypeMetadata{DurationField: IntervalDurationField{
+			DurationType: IntervalDurationType_YEAR}}),
+		MakeInterval(IntervalTypeMetadata{DurationField: IntervalDurationField{
+			FromDurationType: IntervalDurationType_DAY, DurationType: IntervalDurationType_HOUR}}),
+		MakeArray(MakeVarChar(2)),
+	)
+	for _, typ := range typs {
+		goType, err := GoType(typ)
+		if err != nil {
+			if _, err := RandDatumOfType(rng, typ); err == nil {
+				t.Errorf("%s: expected an error for a type without a Go representation", typ.SQLString())
+			}
+			continue
+		}
+		for i := 0; i < 100; i++ {
+			v, err := RandDatumOfType(rng, typ)
+			if err != nil {
+				t.Fatalf("%s: %v", typ.SQLString(), err)
+			}
+			if reflect.TypeOf(v) != goType {
+				t.Fatalf("%s: expected a value of type %s, got %T", typ.SQLString(), goType, v)
+			}
+			if err := checkRandDatum(typ, v); err != nil {
+				t.Fatalf("%s: invalid value %v: %v", typ.SQLString(), v, err)
+			}
+		}
+	}
+}
+
+// checkRandDatum returns an error if the given random value does not honor the
+// metadata of the given type.
+func checkRandDatum(typ *T, v interface{}) error {
+	switch v := v.(type) {
+	case string:
+		if !typ.StringFitsWidth(v) || typ.TruncateString(v) != v {
+			return errors.New("value exceeds the width")
+		}
+		if _, err := typ.CanonicalizeString(v); err != nil {
+			return err
+		}
+	case bitarray.BitArray:
+		if typ.Width() > 0 && (v.BitLen() > uint(typ.Width()) ||
+			typ.Oid() == oid.T_bit && v.BitLen() != uint(typ.Width())) {
+			return errors.Newf("unexpected bit length %d", v.BitLen())
+		}
+	case apd.Decimal:
+		if typ.Precision() > 0 && (v.Exponent != -typ.Scale() || v.NumDigits() > int64(typ.Precision())) {
+			return errors.New("value exceeds the precision or scale")
+		}
+	case timeofday.TimeOfDay:
+		if typ.TimePrecisionIsSet() && int64(v)%10000 != 0 {
+			return errors.New("value exceeds the precision")
+		}
+	case time.Time:
+		if typ.Precision() == 0 && v.Nanosecond() != 0 {
+			return errors.New("value exceeds the precision")
+		}
+	case duration.Duration:
+		if df := typ.InternalType.IntervalDurationField; df != nil {
+			switch df.DurationType {
+			case IntervalDurationType_YEAR:
+				if v.Months%12 != 0 || v.Days != 0 || v.Nanos() != 0 {
+					return errors.New("value has fields other than years")
+				}
+			case IntervalDurationType_HOUR:
+				if v.Months != 0 || v.Nanos()%int64(time.Hour) != 0 {
+					return errors.New("value has fields other than days and hours")
+				}
+			}
+		}
+	case json.RawMessage:
+		if !json.Valid(v) {
+			return errors.New("invalid JSON")
+		}
+	}
+	if typ.Family() == ArrayFamily {
+		arr := reflect.ValueOf(v)
+		for i := 0; i < arr.Len(); i++ {
+			if err := checkRandDatum(typ.ArrayContents(), arr.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import "github.com/lib/pq/oid"
+
+// ScalarType is a lightweight representation of a scalar type (i.e. not an
+// array or tuple type), intended for planning and execution hot paths. Unlike
+// T, it is a plain value that contains no pointers to other types, so it can be
+// copied, stored in slices and compared with == without allocating or chasing
+// pointers. Two ScalarType values are equal if they represent the same type.
+//
+// A ScalarType is obtained from a T with T.Scalar, and can be converted back
+// with ScalarType.T. Types that are persisted should be stored as a T, since
+// ScalarType does not preserve the fields of the wire format that are kept
+// only for backwards compatibility.
+type ScalarType struct {
+	family             Family
+	oid                oid.Oid
+	width              int32
+	widthUnit          StringWidthUnit
+	precision          int32
+	timePrecisionIsSet bool
+	durationField      IntervalDurationField
+	locale             string
+}
+
+// Scalar returns the ScalarType representation of this type. It returns false
+// if this is an array or tuple type, which has no such representation.
+func (t *T) Scalar() (ScalarType, bool) {
+	switch t.Family() {
+	case ArrayFamily, TupleFamily:
+		return ScalarType{}, false
+	}
+	s := ScalarType{
+		family:             t.Family(),
+		oid:                t.Oid(),
+		width:              t.Width(),
+		widthUnit:          t.WidthUnit(),
+		precision:          t.Precision(),
+		timePrecisionIsSet: t.TimePrecisionIsSet(),
+		locale:             t.Locale(),
+	}
+	if t.InternalType.IntervalDurationField != nil {
+		s.durationField = *t.InternalType.IntervalDurationField
+	}
+	return s, true
+}
+
+// T returns the type that this ScalarType represents. If it is identical to
+// one of the predefined types, such as Int or String, then that instance is
+// returned, and no allocation is needed.
+func (s ScalarType) T() *T {
+	if typ, ok := OidToType[s.oid]; ok {
+		if canonical, ok := typ.Scalar(); ok && canonical == s {
+			return typ
+		}
+	}
+	t := &T{InternalType: InternalType{
+		Family:             s.family,
+		Oid:                s.oid,
+		Width:              s.width,
+		Precision:          s.precision,
+		TimePrecisionIsSet: s.timePrecisionIsSet,
+	}}
+	if s.locale == "" {
+		t.InternalType.Locale = &emptyLocale
+	} else {
+		locale := s.locale
+		t.InternalType.Locale = &locale
+	}
+	if s.durationField != (IntervalDurationField{}) {
+		df := s.durationField
+		t.InternalType.IntervalDurationField = &df
+	}
+	if s.widthUnit != StringWidthUnit_CHARACTERS {
+		unit := s.widthUnit
+		t.InternalType.WidthUnit = &unit
+	}
+	return t
+}
+
+// Family returns the type family. See T.Family for more details.
+func (s ScalarType) Family() Family {
+	return s.family
+}
+
+// Oid returns the type's Postgres Object ID. See T.Oid for more details.
+func (s ScalarType) Oid() oid.Oid {
+	return s.oid
+}
+
+// Width returns the size or scale of the type. See T.Width for more details.
+func (s ScalarType) Width() int32 {
+	return s.width
+}
+
+// WidthUnit returns the unit in which the width of a string type is measured.
+// See T.WidthUnit for more details.
+func (s ScalarType) WidthUnit() StringWidthUnit {
+	return s.widthUnit
+}
+
+// Precision returns the accuracy of the type. See T.Precision for more
+// details.
+func (s ScalarType) Precision() int32 {
+	return s.precision
+}
+
+// Scale is an alias method for Width, used for clarity for types in
+// DecimalFamily.
+func (s ScalarType) Scale() int32 {
+	return s.width
+}
+
+// TimePrecisionIsSet returns true if this is a TIME or INTERVAL type that
+// explicitly specifies its precision. See T.TimePrecisionIsSet for more
+// details.
+func (s ScalarType) TimePrecisionIsSet() bool {
+	return s.timePrecisionIsSet
+}
+
+// Locale identifies the collation of a type in the CollatedStringFamily. It is
+// empty for all other types.
+func (s ScalarType) Locale() string {
+	return s.locale
+}
+
+// Equivalent returns true if this type is equivalent to the given type, with
+// the same semantics as T.Equivalent.
+func (s ScalarType) Equivalent(other ScalarType) bool {
+	if s.family == AnyFamily || other.family == AnyFamily {
+		return true
+	}
+	if s.family != other.family {
+		return false
+	}
+	if s.family == CollatedStringFamily {
+		return s.locale == "" || other.locale == "" || s.locale == other.locale
+	}
+	return true
+}
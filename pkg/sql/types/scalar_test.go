@@ -0,0 +1,66 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"testing"
+)
+
+func TestScalarType(t *testing.T) {
+	typs := []*T{
+		MakeBit(3), MakeVarChar(10), MakeChar(10), MakeCollatedString(String, "en"),
+		MakeCollatedString(MakeVarChar(10), "de"), MakeDecimal(10, 3), MakeTime(0), MakeTime(3),
+		MakeTimestampTZ(6),
+		MakeInterval(IntervalTypeMetadata{
+			DurationField: IntervalDurationField{DurationType: IntervalDurationType_HOUR},
+		}),
+	}
+	for _, typ := range OidToType {
+		typs = append(typs, typ)
+	}
+
+	for _, typ := range typs {
+		s, ok := typ.Scalar()
+		if typ.Family() == ArrayFamily || typ.Family() == TupleFamily {
+			if ok {
+				t.Errorf("%s: expected no scalar representation", typ.DebugString())
+			}
+			continue
+		}
+		if !ok {
+			t.Fatalf("%s: expected scalar representation", typ.DebugString())
+		}
+		if s.Family() != typ.Family() || s.Oid() != typ.Oid() || s.Width() != typ.Width() ||
+			s.Precision() != typ.Precision() || s.Locale() != typ.Locale() ||
+			s.TimePrecisionIsSet() != typ.TimePrecisionIsSet() {
+			t.Errorf("%s: attributes differ: %+v", typ.DebugString(), s)
+		}
+		if roundtrip := s.T(); !roundtrip.Identical(typ) {
+			t.Errorf("expected %s, got %s", typ.DebugString(), roundtrip.DebugString())
+		}
+	}
+
+	// Converting a predefined type back returns the same instance.
+	s, _ := Int4.Scalar()
+	if s.T() != Int4 {
+		t.Error("expected predefined INT4 instance")
+	}
+
+	enCollate, _ := MakeCollatedString(String, "en").Scalar()
+	deCollate, _ := MakeCollatedString(String, "de").Scalar()
+	int8Scalar, _ := Int.Scalar()
+	if s == int8Scalar || !s.Equivalent(int8Scalar) {
+		t.Error("expected INT4 and INT8 to be equivalent but not equal")
+	}
+	if enCollate.Equivalent(deCollate) {
+		t.Error("expected different collations not to be equivalent")
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+
+	"github.com/cockroachdb/errors"
+)
+
+// The self-describing encoding of a type is its protobuf encoding, preceded by
+// a header that identifies it as a type, for types that are stored on their
+// own rather than in a descriptor, such as in job payloads and in the metadata
+// files of exports. The header consists of:
+//
+//   selfDescribingMagic    : 4 bytes that identify the encoding
+//   the encoding version   : 1 byte, selfDescribingVersion
+//   the type version       : the serialization version of the type (see
+//                            typeMigrations), as an unsigned varint
+//
+// The magic starts with a zero byte, which never starts a protobuf encoding
+// (field number 0 is invalid), so the self-describing encoding of a type can be
+// told apart from its bare protobuf encoding. The type version is repeated in
+// the header so that tools can tell which migrations a stored type needs
+// without decoding it.
+var selfDescribingMagic = []byte("\x00CRT")
+
+// selfDescribingVersion is the version of the layout of the header of the
+// self-describing encoding. It changes if the header does, not if the type
+// does.
+const selfDescribingVersion = 1
+
+// EncodeSelfDescribing returns the self-describing encoding of the type. Like
+// Marshal, the type is encoded in a format that is backwards-compatible with
+// the previous version of CRDB. Use DecodeSelfDescribing to decode it.
+func (t *T) EncodeSelfDescribing() ([]byte, error) {
+	size := t.Size()
+	b := make([]byte, 0, len(selfDescribingMagic)+1+binary.MaxVarintLen32+size)
+	b = append(b, selfDescribingMagic...)
+	b = append(b, selfDescribingVersion)
+	b = appendUvarint(b, uint64(latestTypeVersion()))
+	return t.MarshalAppend(b)
+}
+
+// IsSelfDescribing returns true if the given data starts with the header of the
+// self-describing encoding of a type.
+func IsSelfDescribing(data []byte) bool {
+	return bytes.HasPrefix(data, selfDescribingMagic)
+}
+
+// SelfDescribingTypeVersion returns the serialization version of the type in
+// the given self-describing encoding, without decoding the type.
+func SelfDescribingTypeVersion(data []byte) (uint32, error) {
+	version, _, err := decodeSelfDescribingHeader(data)
+	return version, err
+}
+
+// DecodeSelfDescribing decodes a type from its self-describing encoding, which
+// was created by EncodeSelfDescribing. Like Unmarshal, it migrates the type to
+// the latest version. It returns an error if the data is not a self-describing
+// encoding, or if it was encoded with a version that is newer than the latest
+// one.
+func DecodeSelfDescribing(data []byte) (*T, error) {
+	version, rest, err := decodeSelfDescribingHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	if latest := latestTypeVersion(); version > latest {
+		return nil, errors.Errorf(
+			"type serialization version %d is newer than the latest version %d", version, latest)
+	}
+	var t T
+	if err := t.Unmarshal(rest); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// decodeSelfDescribingHeader decodes the header of the self-describing encoding
+// of a type, and returns the type version and the protobuf encoding that
+// follows the header.
+func decodeSelfDescribingHeader(data []byte) (uint32, []byte, error) {
+	if !IsSelfDescribing(data) {
+		return 0, nil, errors.New("not a self-describing type encoding")
+	}
+	data = data[len(selfDescribingMagic):]
+	if len(data) == 0 {
+		return 0, nil, errors.New("invalid self-describing type encoding: unexpected end of data")
+	}
+	if data[0] != selfDescribingVersion {
+		return 0, nil, errors.Errorf("unknown self-describing type encoding version %d", data[0])
+	}
+	version, n := binary.Uvarint(data[1:])
+	if n <= 0 || version > math.MaxUint32 {
+		return 0, nil, errors.New("invalid self-describing type encoding: invalid type version")
+	}
+	return uint32(version), data[1+n:], nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+)
+
+func TestSelfDescribing(t *testing.T) {
+	typs := append(AllTypes(), []*T{
+		MakeCollatedString(MakeVarChar(20), "en_US"),
+		MakeArray(MakeCollatedString(String, "de")),
+		MakeLabeledTuple([]T{*Int, *MakeArray(MakeDecimal(10, 2))}, []string{"a", "b"}),
+	}...)
+	for _, typ := range typs {
+		if temp := *typ; temp.downgradeType() != nil {
+			// Nested arrays cannot be marshaled.
+			continue
+		}
+		data, err := typ.EncodeSelfDescribing()
+		if err != nil {
+			t.Fatalf("%s: %v", typ.DebugString(), err)
+		}
+		if !IsSelfDescribing(data) {
+			t.Errorf("%s: expected a self-describing encoding, but got %x", typ.DebugString(), data)
+		}
+		if version, err := SelfDescribingTypeVersion(data); err != nil {
+			t.Errorf("%s: %v", typ.DebugString(), err)
+		} else if version != latestTypeVersion() {
+			t.Errorf("%s: expected version %d, but got %d", typ.DebugString(), latestTypeVersion(), version)
+		}
+		decoded, err := DecodeSelfDescribing(data)
+		if err != nil {
+			t.Fatalf("%s: %v", typ.DebugString(), err)
+		}
+		if !decoded.Identical(typ) {
+			t.Errorf("expected %s, but got %s", typ.DebugString(), decoded.DebugString())
+		}
+
+		// The bare protobuf encoding is not self-describing.
+		bare, err := protoutil.Marshal(typ)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if IsSelfDescribing(bare) {
+			t.Errorf("%s: expected the protobuf encoding %x not to be self-describing", typ.DebugString(), bare)
+		}
+		if !bytes.HasSuffix(data, bare) {
+			t.Errorf("%s: expected %x to end with the protobuf encoding %x", typ.DebugString(), data, bare)
+		}
+	}
+
+	data, err := Int.EncodeSelfDescribing()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bare, err := protoutil.Marshal(Int)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := append([]byte("\x00CRT\x01\x00"), bare...); !bytes.Equal(data, expected) {
+		t.Errorf("expected %x, but got %x", expected, data)
+	}
+
+	testCases := []struct {
+		data []byte
+		err  string
+	}{
+		{bare, "not a self-describing type encoding"},
+		{[]byte("\x00CRT"), "invalid self-describing type encoding: unexpected end of data"},
+		{[]byte("\x00CRT\x02\x00"), "unknown self-describing type encoding version 2"},
+		{[]byte("\x00CRT\x01\x80"), "invalid self-describing type encoding: invalid type version"},
+		{append([]byte("\x00CRT\x01\x01"), bare...), "type serialization version 1 is newer than the latest version 0"},
+	}
+	for _, tc := range testCases {
+		if _, err := DecodeSelfDescribing(tc.data); err == nil || err.Error() != tc.err {
+			t.Errorf("%x: expected error %q, but got %v", tc.data, tc.err, err)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import "github.com/cockroachdb/errors"
+
+// MakeSerialMetadata returns the metadata of a column that was declared with
+// the SERIAL pseudo-type denoting the given integer type, and was normalized in
+// the given way. It returns an error if the type is not in the IntFamily.
+func MakeSerialMetadata(typ *T, normalization SerialNormalization) (SerialMetadata, error) {
+	if typ.Family() != IntFamily {
+		return SerialMetadata{}, errors.AssertionFailedf(
+			"SERIAL pseudo-types denote integer types, not %s", typ.SQLString())
+	}
+	return SerialMetadata{Width: typ.Width(), Normalization: normalization}, nil
+}
+
+// SerialTypeName returns the canonical name of the SERIAL pseudo-type that
+// denotes the integer type of the given width: SERIAL2, SERIAL4 or SERIAL8.
+func SerialTypeName(width int32) string {
+	switch width {
+	case 16:
+		return "SERIAL2"
+	case 32:
+		return "SERIAL4"
+	}
+	return "SERIAL8"
+}
+
+// SQLString returns the name of the declared SERIAL pseudo-type, such as
+// SERIAL4.
+func (m *SerialMetadata) SQLString() string {
+	return SerialTypeName(m.Width)
+}
+
+// DeclaredType returns the integer type denoted by the declared SERIAL
+// pseudo-type, such as INT4 for SERIAL4.
+func (m *SerialMetadata) DeclaredType() *T {
+	switch m.Width {
+	case 16:
+		return Int2
+	case 32:
+		return Int4
+	}
+	return Int
+}
+
+// ColumnType returns the type of the column that results from the
+// normalization. The values of unique_rowid() and of virtual sequences do not
+// fit in narrower integers, so the type is INT8 unless the column uses a SQL
+// sequence, in which case it is the declared type.
+func (m *SerialMetadata) ColumnType() *T {
+	if m.Normalization == SerialNormalization_SQL_SEQUENCE {
+		return m.DeclaredType()
+	}
+	return Int
+}
@@ -0,0 +1,54 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+)
+
+func TestSerialMetadata(t *testing.T) {
+	testCases := []struct {
+		typ           *T
+		normalization SerialNormalization
+		name          string
+		columnType    *T
+	}{
+		{Int2, SerialNormalization_ROWID, "SERIAL2", Int},
+		{Int4, SerialNormalization_VIRTUAL_SEQUENCE, "SERIAL4", Int},
+		{Int4, SerialNormalization_SQL_SEQUENCE, "SERIAL4", Int4},
+		{Int2, SerialNormalization_SQL_SEQUENCE, "SERIAL2", Int2},
+		{Int, SerialNormalization_SQL_SEQUENCE, "SERIAL8", Int},
+	}
+	for _, tc := range testCases {
+		m, err := MakeSerialMetadata(tc.typ, tc.normalization)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m.SQLString() != tc.name || m.DeclaredType() != tc.typ || m.ColumnType() != tc.columnType {
+			t.Errorf("%s %s: expected %s, %s, got %s, %s, %s", tc.typ.SQLString(), tc.normalization,
+				tc.name, tc.columnType.SQLString(), m.SQLString(), m.DeclaredType().SQLString(),
+				m.ColumnType().SQLString())
+		}
+		data, err := protoutil.Marshal(&m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var unmarshaled SerialMetadata
+		if err := protoutil.Unmarshal(data, &unmarshaled); err != nil || unmarshaled != m {
+			t.Errorf("expected %v, got %v (%v)", m, unmarshaled, err)
+		}
+	}
+	if _, err := MakeSerialMetadata(String, SerialNormalization_ROWID); err == nil {
+		t.Error("expected error for non-integer type")
+	}
+}
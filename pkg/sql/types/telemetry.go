@@ -0,0 +1,137 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"fmt"
+
+	"github.com/lib/pq/oid"
+)
+
+// familyTelemetryNames are the names of the families in the telemetry names of
+// types. They are spelled out rather than derived from the names of the
+// families, so that renaming a family does not change the names of the
+// telemetry counters keyed by type. They must never change.
+var familyTelemetryNames = map[Family]string{
+	BoolFamily:           "bool",
+	IntFamily:            "int",
+	FloatFamily:          "float",
+	DecimalFamily:        "decimal",
+	DateFamily:           "date",
+	TimestampFamily:      "timestamp",
+	IntervalFamily:       "interval",
+	StringFamily:         "string",
+	BytesFamily:          "bytes",
+	TimestampTZFamily:    "timestamptz",
+	CollatedStringFamily: "collatedstring",
+	OidFamily:            "oid",
+	UnknownFamily:        "unknown",
+	UuidFamily:           "uuid",
+	ArrayFamily:          "array",
+	INetFamily:           "inet",
+	TimeFamily:           "time",
+	JsonFamily:           "jsonb",
+	TupleFamily:          "tuple",
+	BitFamily:            "bit",
+	VoidFamily:           "void",
+	TriggerFamily:        "trigger",
+	AnyFamily:            "any",
+}
+
+// stringKindTelemetryNames are the names of the string kinds in the telemetry
+// names of types. Like familyTelemetryNames, they must never change.
+var stringKindTelemetryNames = map[StringKind]string{
+	TextKind:      "text",
+	VarCharKind:   "varchar",
+	BpCharKind:    "char",
+	QCharKind:     "qchar",
+	NameKind:      "name",
+	JSONPathKind:  "jsonpath",
+	RefCursorKind: "refcursor",
+	PGLSNKind:     "pg_lsn",
+	MACAddrKind:   "macaddr",
+	MACAddr8Kind:  "macaddr8",
+}
+
+// TelemetryName returns the name of the type in the telemetry counters that are
+// keyed by type. It consists of the family of the type and of a coarse bucket
+// of its modifiers, such as whether it has a width, but not the value of the
+// modifiers, so that there are few distinct names:
+//
+//   INT2, INT8          : int.16, int.64
+//   FLOAT4              : float.4
+//   DECIMAL(10,2)       : decimal.precision
+//   VARCHAR(20)         : string.varchar.width
+//   STRING COLLATE de   : collatedstring.text
+//   BIT(3), VARBIT      : bit.fixed.width, bit.varying
+//   TIMESTAMP(3)        : timestamp.precision
+//   INTERVAL DAY        : interval.qualifier
+//   INT8[]              : array.int.64
+//   RECORD, (INT, BOOL) : tuple
+//
+// The locales of collated strings and the contents of tuples are omitted. The
+// telemetry names are stable across releases, and do not depend on the names
+// that are used for the types elsewhere.
+func (t *T) TelemetryName() string {
+	name, ok := familyTelemetryNames[t.Family()]
+	if !ok {
+		name = fmt.Sprintf("family%d", t.Family())
+	}
+	withWidth := func(name string) string {
+		if t.Width() > 0 {
+			name += ".width"
+		}
+		if t.WidthUnit() == StringWidthUnit_BYTES {
+			name += ".bytes"
+		}
+		return name
+	}
+	switch t.Family() {
+	case IntFamily:
+		return fmt.Sprintf("%s.%d", name, t.Width())
+	case FloatFamily:
+		if t.IsFloat4() {
+			return name + ".4"
+		}
+		return name + ".8"
+	case DecimalFamily:
+		if t.Precision() > 0 {
+			return name + ".precision"
+		}
+	case StringFamily, CollatedStringFamily:
+		return withWidth(name + "." + stringKindTelemetryNames[t.StringKind()])
+	case BytesFamily:
+		return withWidth(name)
+	case BitFamily:
+		if t.Oid() == oid.T_varbit {
+			return withWidth(name + ".varying")
+		}
+		return withWidth(name + ".fixed")
+	case TimestampFamily, TimestampTZFamily:
+		if t.Precision() >= 0 {
+			return name + ".precision"
+		}
+	case TimeFamily:
+		if t.TimePrecisionIsSet() {
+			return name + ".precision"
+		}
+	case IntervalFamily:
+		if t.InternalType.IntervalDurationField != nil {
+			name += ".qualifier"
+		}
+		if t.TimePrecisionIsSet() {
+			name += ".precision"
+		}
+	case ArrayFamily:
+		return name + "." + t.ArrayContents().TelemetryName()
+	}
+	return name
+}
@@ -0,0 +1,61 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"testing"
+)
+
+func TestTelemetryName(t *testing.T) {
+	testCases := []struct {
+		typ      *T
+		expected string
+	}{
+		{Bool, "bool"},
+		{Int2, "int.16"},
+		{Int, "int.64"},
+		{Float4, "float.4"},
+		{Decimal, "decimal"},
+		{MakeDecimal(10, 2), "decimal.precision"},
+		{String, "string.text"},
+		{MakeVarChar(20), "string.varchar.width"},
+		{MakeStringWithWidthUnit(MakeVarChar(20), StringWidthUnit_BYTES), "string.varchar.width.bytes"},
+		{MakeCollatedString(String, "de"), "collatedstring.text"},
+		{MakeCollatedString(MakeChar(3), "fr"), "collatedstring.char.width"},
+		{MACAddr, "string.macaddr"},
+		{MakeBytes(3), "bytes.width"},
+		{MakeBit(3), "bit.fixed.width"},
+		{VarBit, "bit.varying"},
+		{Timestamp, "timestamp"},
+		{MakeTimestampTZ(0), "timestamptz.precision"},
+		{Time, "time"},
+		{MakeTime(3), "time.precision"},
+		{MakeInterval(IntervalTypeMetadata{DurationField: IntervalDurationField{
+			DurationType: IntervalDurationType_DAY}}), "interval.qualifier"},
+		{MakeInterval(IntervalTypeMetadata{Precision: 3, PrecisionIsSet: true}), "interval.precision"},
+		{IntArray, "array.int.64"},
+		{MakeArray(MakeVarChar(2)), "array.string.varchar.width"},
+		{MakeTuple([]T{*Int, *Bool}), "tuple"},
+		{AnyTuple, "tuple"},
+		{Any, "any"},
+	}
+	for _, tc := range testCases {
+		if actual := tc.typ.TelemetryName(); actual != tc.expected {
+			t.Errorf("%s: expected %s, got %s", tc.typ.SQLString(), tc.expected, actual)
+		}
+	}
+	// All the families have a telemetry name.
+	for family := range Family_name {
+		if _, ok := familyTelemetryNames[Family(family)]; !ok {
+			t.Errorf("family %s has no telemetry name", Family(family))
+		}
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// MaxNameLength is the maximum length in bytes of the values of the NAME type.
+// Like in Postgres, where it is NAMEDATALEN-1, longer values are truncated when
+// they are converted to NAME.
+const MaxNameLength = 63
+
+// These are the sizes in bytes of the values of the MACADDR and MACADDR8
+// types, as reported by the typlen column of pg_type.
+const (
+	MACAddrSize  = 6
+	MACAddr8Size = 8
+)
+
+// CanonicalizeString validates a value of one of the string types that have a
+// fixed text format (PG_LSN, MACADDR and MACADDR8), and returns the value in
+// its canonical form:
+//
+//   PG_LSN  : 00000016/B374D848
+//   MACADDR : 08:00:2b:01:02:03
+//   MACADDR8: 08:00:2b:01:02:03:04:05
+//
+// MAC addresses are printed like Postgres prints them. Unlike Postgres, both
+// halves of log sequence numbers are zero-padded, so that the values stored
+// as strings sort in the order of the positions that they denote.
+//
+// It returns an error with the InvalidTextRepresentation code if the value is
+// not valid for the type. The values of all the other types are returned
+// unchanged.
+func (t *T) CanonicalizeString(s string) (string, error) {
+	switch t.StringKind() {
+	case PGLSNKind:
+		lsn, err := ParseLSN(s)
+		if err != nil {
+			return "", err
+		}
+		return FormatLSN(lsn), nil
+	case MACAddrKind, MACAddr8Kind:
+		addr, err := ParseMACAddr(s, t.macAddrSize())
+		if err != nil {
+			return "", err
+		}
+		return addr.String(), nil
+	}
+	return s, nil
+}
+
+// TruncateString truncates a string that is converted to this type to the
+// maximum length of values of the type:
+//
+//   NAME               : MaxNameLength bytes, without splitting a character
+//   "char"             : a single character, unless it has a width
+//   other string types : the width of the type in bytes, if any
+//
+// Like Postgres, "char" holds a single character even though its width is
+// unspecified. A width is only honored for compatibility with the "char" types
+// that have one (see MakeQChar).
+func (t *T) TruncateString(s string) string {
+	switch t.StringKind() {
+	case NameKind:
+		if len(s) > MaxNameLength {
+			n := MaxNameLength
+			for n > 0 && !utf8.RuneStart(s[n]) {
+				n--
+			}
+			s = s[:n]
+		}
+		return s
+	case QCharKind:
+		if t.Width() == 0 {
+			_, size := utf8.DecodeRuneInString(s)
+			return s[:size]
+		}
+	}
+	if t.Width() > 0 && int(t.Width()) < len(s) {
+		s = s[:t.Width()]
+	}
+	return s
+}
+
+// StringLength returns the length of a value of this string type in the unit in
+// which its width is measured: the number of bytes if its WidthUnit is BYTES,
+// and the number of characters otherwise.
+func (t *T) StringLength(s string) int {
+	if t.WidthUnit() == StringWidthUnit_BYTES {
+		return len(s)
+	}
+	return utf8.RuneCountInString(s)
+}
+
+// StringFitsWidth returns true if a value of this string type is no longer than
+// the width of the type, as measured by StringLength. Values always fit the
+// types that have no width. For example, "été" fits VARCHAR(3), but not a
+// VARCHAR(3) whose width is measured in bytes, since it has 5 bytes.
+func (t *T) StringFitsWidth(s string) bool {
+	return t.Width() == 0 || t.StringLength(s) <= int(t.Width())
+}
+
+// ParseLSN parses a Postgres log sequence number (a value of the PG_LSN type)
+// from its text form, which consists of two hexadecimal numbers of up to 32
+// bits separated by a slash, such as "16/B374D848".
+func ParseLSN(s string) (uint64, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) == 2 && parts[0] != "" && parts[1] != "" {
+		hi, errHi := strconv.ParseUint(parts[0], 16, 32)
+		lo, errLo := strconv.ParseUint(parts[1], 16, 32)
+		if errHi == nil && errLo == nil {
+			return hi<<32 | lo, nil
+		}
+	}
+	return 0, pgerror.Newf(pgcode.InvalidTextRepresentation,
+		"invalid input syntax for type pg_lsn: %q", s)
+}
+
+// FormatLSN returns the canonical text form of a Postgres log sequence number,
+// in which both halves are zero-padded to 8 digits. It is the inverse of
+// ParseLSN.
+func FormatLSN(lsn uint64) string {
+	return fmt.Sprintf("%08X/%08X", lsn>>32, uint32(lsn))
+}
+
+// ParseMACAddr parses a MAC address of the given size (MACAddrSize or
+// MACAddr8Size) from its text form. Like Postgres, it accepts hexadecimal
+// digits that are optionally grouped by ':', '-' or '.' separators, such as
+// "08:00:2b:01:02:03", "08002b-010203" or "0800.2b01.0203". A 6-byte address
+// is accepted for the 8-byte size, and is converted to the 8-byte form by
+// inserting FF:FE in its middle.
+func ParseMACAddr(s string, size int) (net.HardwareAddr, error) {
+	typName := "macaddr"
+	if size == MACAddr8Size {
+		typName = "macaddr8"
+	}
+	digits := strings.Map(func(r rune) rune {
+		switch r {
+		case ':', '-', '.':
+			return -1
+		}
+		return r
+	}, s)
+	addr, err := hex.DecodeString(digits)
+	if err == nil && size == MACAddr8Size && len(addr) == MACAddrSize {
+		addr = []byte{addr[0], addr[1], addr[2], 0xff, 0xfe, addr[3], addr[4], addr[5]}
+	}
+	if err != nil || len(addr) != size {
+		return nil, pgerror.Newf(pgcode.InvalidTextRepresentation,
+			"invalid input syntax for type %s: %q", typName, s)
+	}
+	return net.HardwareAddr(addr), nil
+}
+
+// macAddrSize returns the size in bytes of the values of the MACADDR or
+// MACADDR8 type.
+func (t *T) macAddrSize() int {
+	if t.StringKind() == MACAddr8Kind {
+		return MACAddr8Size
+	}
+	return MACAddrSize
+}
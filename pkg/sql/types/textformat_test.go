@@ -0,0 +1,116 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+func TestCanonicalizeString(t *testing.T) {
+	testCases := []struct {
+		typ      *T
+		s        string
+		expected string
+		err      string
+	}{
+		{PGLSN, "16/B374D848", "00000016/B374D848", ""},
+		{PGLSN, "0/0", "00000000/00000000", ""},
+		{PGLSN, "ffffffff/00000001", "FFFFFFFF/00000001", ""},
+		{PGLSN, "16B374D848", "", `invalid input syntax for type pg_lsn: "16B374D848"`},
+		{PGLSN, "1/100000000", "", `invalid input syntax for type pg_lsn: "1/100000000"`},
+		{PGLSN, "/1", "", `invalid input syntax for type pg_lsn: "/1"`},
+
+		{MACAddr, "08:00:2b:01:02:03", "08:00:2b:01:02:03", ""},
+		{MACAddr, "08-00-2B-01-02-03", "08:00:2b:01:02:03", ""},
+		{MACAddr, "08002b:010203", "08:00:2b:01:02:03", ""},
+		{MACAddr, "0800.2b01.0203", "08:00:2b:01:02:03", ""},
+		{MACAddr, "08002b010203", "08:00:2b:01:02:03", ""},
+		{MACAddr, "08:00:2b:01:02", "", `invalid input syntax for type macaddr: "08:00:2b:01:02"`},
+		{MACAddr, "08:00:2b:01:02:0g", "", `invalid input syntax for type macaddr: "08:00:2b:01:02:0g"`},
+
+		{MACAddr8, "08:00:2b:01:02:03:04:05", "08:00:2b:01:02:03:04:05", ""},
+		{MACAddr8, "08002b0102030405", "08:00:2b:01:02:03:04:05", ""},
+		{MACAddr8, "08:00:2b:01:02:03", "08:00:2b:ff:fe:01:02:03", ""},
+		{MACAddr8, "08:00:2b:01:02:03:04", "", `invalid input syntax for type macaddr8: "08:00:2b:01:02:03:04"`},
+
+		{String, "16B374D848", "16B374D848", ""},
+		{MakeCollatedString(String, "en"), "08:00", "08:00", ""},
+		{Int, "abc", "abc", ""},
+	}
+
+	for _, tc := range testCases {
+		actual, err := tc.typ.CanonicalizeString(tc.s)
+		if tc.err != "" {
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("%s %q: expected error %q, got %v", tc.typ.SQLString(), tc.s, tc.err, err)
+			} else if code := pgerror.GetPGCode(err); code != pgcode.InvalidTextRepresentation {
+				t.Errorf("%s %q: expected code %s, got %s", tc.typ.SQLString(), tc.s,
+					pgcode.InvalidTextRepresentation, code)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s %q: unexpected error: %v", tc.typ.SQLString(), tc.s, err)
+		} else if actual != tc.expected {
+			t.Errorf("%s %q: expected %q, got %q", tc.typ.SQLString(), tc.s, tc.expected, actual)
+		}
+	}
+
+	// ParseLSN and FormatLSN are inverses.
+	for _, lsn := range []uint64{0, 1, 1 << 32, 0x16B374D848, math.MaxUint64} {
+		if actual, err := ParseLSN(FormatLSN(lsn)); err != nil || actual != lsn {
+			t.Errorf("%d: expected round trip, got %d (%v)", lsn, actual, err)
+		}
+	}
+
+	// The canonical forms sort in the order of the log sequence numbers.
+	if !(FormatLSN(0x900000000) < FormatLSN(0x1000000000)) || !(FormatLSN(0xF) < FormatLSN(0x10)) {
+		t.Errorf("expected %s < %s", FormatLSN(0x900000000), FormatLSN(0x1000000000))
+	}
+}
+
+func TestTruncateString(t *testing.T) {
+	longName := strings.Repeat("a", MaxNameLength+10)
+	// The multi-byte character straddles the NAME length limit.
+	multiByteName := strings.Repeat("a", MaxNameLength-1) + "é"
+
+	testCases := []struct {
+		typ      *T
+		s        string
+		expected string
+	}{
+		{Name, "foo", "foo"},
+		{Name, longName, longName[:MaxNameLength]},
+		{Name, multiByteName, multiByteName[:MaxNameLength-1]},
+		{typeQChar, "abc", "a"},
+		{typeQChar, "éa", "é"},
+		{typeQChar, "", ""},
+		{MakeQChar(2), "abc", "ab"},
+		{MakeCollatedString(typeQChar, "en"), "abc", "a"},
+		{String, longName, longName},
+		{MakeString(2), "abc", "ab"},
+		{MakeVarChar(5), "abc", "abc"},
+		{MakeChar(2), "abc", "ab"},
+		{MakeCollatedString(MakeVarChar(1), "en"), "abc", "a"},
+		{RefCursor, longName, longName},
+	}
+
+	for _, tc := range testCases {
+		if actual := tc.typ.TruncateString(tc.s); actual != tc.expected {
+			t.Errorf("%s %q: expected %q, got %q", tc.typ.SQLString(), tc.s, tc.expected, actual)
+		}
+	}
+}
@@ -16,6 +16,8 @@ import (
 	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/lex"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/errors"
@@ -70,10 +72,14 @@ import (
 // | SQL type          | Family         | Oid           | Precision | Width |
 // |-------------------|----------------|---------------|-----------|-------|
 // | NULL (unknown)    | UNKNOWN        | T_unknown     | 0         | 0     |
+// | VOID              | VOID           | T_void        | 0         | 0     |
+// | TRIGGER           | TRIGGER        | T_trigger     | 0         | 0     |
+// | EVENT_TRIGGER     | TRIGGER        | T_event_tr... | 0         | 0     |
 // | BOOL              | BOOL           | T_bool        | 0         | 0     |
 // | DATE              | DATE           | T_date        | 0         | 0     |
 // | TIMESTAMP         | TIMESTAMP      | T_timestamp   | 0         | 0     |
 // | INTERVAL          | INTERVAL       | T_interval    | 0         | 0     |
+// | INTERVAL(N)       | INTERVAL       | T_interval    | N         | 0     |
 // | TIMESTAMPTZ       | TIMESTAMPTZ    | T_timestamptz | 0         | 0     |
 // | OID               | OID            | T_oid         | 0         | 0     |
 // | UUID              | UUID           | T_uuid        | 0         | 0     |
@@ -92,6 +98,11 @@ import (
 // | CHAR(N)           | STRING         | T_bpchar      | 0         | N     |
 // | "char"            | STRING         | T_char        | 0         | 0     |
 // | NAME              | STRING         | T_name        | 0         | 0     |
+// | JSONPATH          | STRING         | T_jsonpath    | 0         | 0     |
+// | REFCURSOR         | STRING         | T_refcursor   | 0         | 0     |
+// | PG_LSN            | STRING         | T_pg_lsn      | 0         | 0     |
+// | MACADDR           | STRING         | T_macaddr     | 0         | 0     |
+// | MACADDR8          | STRING         | T_macaddr8    | 0         | 0     |
 // |                   |                |               |           |       |
 // | STRING COLLATE en | COLLATEDSTRING | T_text        | 0         | 0     |
 // | STRING(N) COL...  | COLLATEDSTRING | T_text        | 0         | N     |
@@ -169,6 +180,21 @@ var (
 	Unknown = &T{InternalType: InternalType{
 		Family: UnknownFamily, Oid: oid.T_unknown, Locale: &emptyLocale}}
 
+	// Void is the type of the result of functions and procedures that return
+	// nothing. It cannot be used as the type of a table column.
+	Void = &T{InternalType: InternalType{
+		Family: VoidFamily, Oid: oid.T_void, Locale: &emptyLocale}}
+
+	// Trigger is the type of the result of trigger functions, which run on
+	// changes to tables. It cannot be used as the type of a table column.
+	Trigger = &T{InternalType: InternalType{
+		Family: TriggerFamily, Oid: oid.T_trigger, Locale: &emptyLocale}}
+
+	// EventTrigger is the type of the result of event trigger functions, which
+	// run on DDL events. It cannot be used as the type of a table column.
+	EventTrigger = &T{InternalType: InternalType{
+		Family: TriggerFamily, Oid: oid.T_event_trigger, Locale: &emptyLocale}}
+
 	// Bool is the type of a boolean true/false value.
 	Bool = &T{InternalType: InternalType{
 		Family: BoolFamily, Oid: oid.T_bool, Locale: &emptyLocale}}
@@ -220,12 +246,44 @@ var (
 	VarChar = &T{InternalType: InternalType{
 		Family: StringFamily, Oid: oid.T_varchar, Locale: &emptyLocale}}
 
-	// Name is a type-alias for String with a different OID (T_name). It is
+	// Name is a type-alias for String with a different OID (T_name). Its values
+	// are identifiers of at most MaxNameLength bytes (see TruncateString). It is
 	// reported as NAME in SHOW CREATE and "name" in introspection for
 	// compatibility with PostgreSQL.
 	Name = &T{InternalType: InternalType{
 		Family: StringFamily, Oid: oid.T_name, Locale: &emptyLocale}}
 
+	// RefCursor is a type-alias for String with a different OID (T_refcursor).
+	// Its values are the names of cursors, such as the ones returned by
+	// procedures that open cursors for their results. It is reported as
+	// REFCURSOR in SHOW CREATE and "refcursor" in introspection for
+	// compatibility with PostgreSQL.
+	RefCursor = &T{InternalType: InternalType{
+		Family: StringFamily, Oid: oid.T_refcursor, Locale: &emptyLocale}}
+
+	// PGLSN is the type of a Postgres log sequence number, which is a position
+	// in the write-ahead log, such as 16/B374D848. It is a type-alias for
+	// String with a different OID (T_pg_lsn), whose values are canonicalized by
+	// CanonicalizeString so that they sort in numeric order. It is reported as
+	// PG_LSN in SHOW CREATE and "pg_lsn" in introspection for compatibility
+	// with PostgreSQL.
+	PGLSN = &T{InternalType: InternalType{
+		Family: StringFamily, Oid: oid.T_pg_lsn, Locale: &emptyLocale}}
+
+	// MACAddr is the type of a 6-byte MAC address, such as 08:00:2b:01:02:03.
+	// It is a type-alias for String with a different OID (T_macaddr), whose
+	// values are canonicalized by CanonicalizeString. It is reported as MACADDR
+	// in SHOW CREATE and "macaddr" in introspection for compatibility with
+	// PostgreSQL.
+	MACAddr = &T{InternalType: InternalType{
+		Family: StringFamily, Oid: oid.T_macaddr, Locale: &emptyLocale}}
+
+	// MACAddr8 is the type of an 8-byte (EUI-64) MAC address. It is like
+	// MACAddr, but has the T_macaddr8 OID, and is reported as MACADDR8 in SHOW
+	// CREATE and "macaddr8" in introspection.
+	MACAddr8 = &T{InternalType: InternalType{
+		Family: StringFamily, Oid: T_macaddr8, Locale: &emptyLocale}}
+
 	// Bytes is the type of a list of raw byte values.
 	Bytes = &T{InternalType: InternalType{
 		Family: BytesFamily, Oid: oid.T_bytea, Locale: &emptyLocale}}
@@ -239,7 +297,8 @@ var (
 		Family: DateFamily, Oid: oid.T_date, Locale: &emptyLocale}}
 
 	// Time is the type of a value specifying hour, minute, second (with no date
-	// component). By default, it has microsecond precision. There is no timezone
+	// component). By default, it has microsecond precision; use MakeTime to
+	// construct a type with a different precision. There is no timezone
 	// associated with it. For example:
 	//
 	//   HH:MM:SS.ssssss
@@ -265,7 +324,8 @@ var (
 		Family: TimestampTZFamily, Precision: -1, Oid: oid.T_timestamptz, Locale: &emptyLocale}}
 
 	// Interval is the type of a value describing a duration of time. By default,
-	// it has microsecond precision.
+	// it has microsecond precision. Use MakeInterval to construct an INTERVAL
+	// type with a qualifier (e.g. INTERVAL DAY TO SECOND) or a precision.
 	Interval = &T{InternalType: InternalType{
 		Family: IntervalFamily, Oid: oid.T_interval, Locale: &emptyLocale}}
 
@@ -274,6 +334,16 @@ var (
 	Jsonb = &T{InternalType: InternalType{
 		Family: JsonFamily, Oid: oid.T_jsonb, Locale: &emptyLocale}}
 
+	// Jsonpath is the type of a SQL/JSON path expression, such as '$.a[*]',
+	// which selects items from a JSON value. It has the OID of the Postgres
+	// jsonpath type (T_jsonpath) so that clients can use it in function
+	// signatures and prepared statements. There is no jsonpath datum yet, so
+	// Jsonpath is a type-alias for String, and its values are not validated.
+	// It is reported as JSONPATH in SHOW CREATE and "jsonpath" in
+	// introspection.
+	Jsonpath = &T{InternalType: InternalType{
+		Family: StringFamily, Oid: T_jsonpath, Locale: &emptyLocale}}
+
 	// Uuid is the type of a universally unique identifier (UUID), which is a
 	// 128-bit quantity that is very unlikely to ever be generated again, and so
 	// can be relied on to be distinct from all other UUID values.
@@ -327,6 +397,13 @@ var (
 	AnyArray = &T{InternalType: InternalType{
 		Family: ArrayFamily, ArrayContents: Any, Oid: oid.T_anyarray, Locale: &emptyLocale}}
 
+	// AnyNonArray is a special type used only during static analysis as a
+	// wildcard type that matches any type other than an array type. It
+	// corresponds to the Postgres anynonarray pseudo-type. Execution-time values
+	// should never have this type.
+	AnyNonArray = &T{InternalType: InternalType{
+		Family: AnyFamily, Oid: oid.T_anynonarray, Locale: &emptyLocale}}
+
 	// AnyTuple is a special type used only during static analysis as a wildcard
 	// type that matches a tuple with any number of fields of any type (including
 	// tuple types). Execution-time values should never have this type.
@@ -383,9 +460,10 @@ var (
 		Family: StringFamily, Oid: oid.T_bpchar, Locale: &emptyLocale}}
 
 	// typeQChar is a special PostgreSQL-only type supported for compatibility.
-	// It behaves like VARCHAR, its maximum width cannot be modified, and has a
-	// peculiar name in the syntax and introspection. It is not exported to avoid
-	// confusion with typeBpChar, as well as confusion over its default width.
+	// It holds a single character (see TruncateString), its maximum width cannot
+	// be modified, and it has a peculiar name in the syntax and introspection.
+	// It is not exported to avoid confusion with typeBpChar, as well as
+	// confusion over its default width.
 	//
 	// It is reported as "char" (with double quotes included) in SHOW CREATE and
 	// "char" in introspection for compatibility with PostgreSQL.
@@ -439,6 +517,16 @@ const (
 	unknownArrayOid = 0
 )
 
+const (
+	// MaxTimePrecision is the maximum number of fractional second digits that
+	// can be specified for a TIME type (i.e. microsecond precision).
+	MaxTimePrecision = 6
+
+	// DefaultTimePrecision is the number of fractional second digits used by a
+	// TIME type that does not explicitly specify its precision.
+	DefaultTimePrecision = MaxTimePrecision
+)
+
 var (
 	emptyLocale = ""
 )
@@ -464,7 +552,11 @@ func MakeScalar(family Family, o oid.Oid, precision, width int32, locale string)
 		panic(errors.AssertionFailedf("negative precision is not allowed"))
 	}
 	switch family {
-	case DecimalFamily, TimeFamily, TimestampFamily, TimestampTZFamily:
+	case DecimalFamily, TimestampFamily, TimestampTZFamily:
+	case TimeFamily:
+		if precision > MaxTimePrecision {
+			panic(errors.AssertionFailedf("precision %d is not currently supported", precision))
+		}
 	default:
 		if precision != 0 {
 			panic(errors.AssertionFailedf("type %s cannot have precision", family))
@@ -506,6 +598,9 @@ func MakeScalar(family Family, o oid.Oid, precision, width int32, locale string)
 		Precision: precision,
 		Width:     width,
 		Locale:    &locale,
+		// A TIME type with zero precision is assumed to have default precision.
+		// Use MakeTime to construct a TIME(0) type.
+		TimePrecisionIsSet: family == TimeFamily && precision != 0,
 	}}
 }
 
@@ -595,11 +690,34 @@ func MakeCollatedString(strType *T, locale string) *T {
 	switch strType.Oid() {
 	case oid.T_text, oid.T_varchar, oid.T_bpchar, oid.T_char:
 		return &T{InternalType: InternalType{
-			Family: CollatedStringFamily, Oid: strType.Oid(), Width: strType.Width(), Locale: &locale}}
+			Family: CollatedStringFamily, Oid: strType.Oid(), Width: strType.Width(), Locale: &locale,
+			WidthUnit: strType.InternalType.WidthUnit}}
 	}
 	panic(errors.AssertionFailedf("cannot apply collation to non-string type: %s", strType))
 }
 
+// MakeStringWithWidthUnit constructs a new instance of the given string or
+// collated string type, having the same oid, width and locale values, whose
+// width is measured in the given unit. For example, a VARCHAR(10) column that
+// is migrated from a MySQL column that holds at most 10 bytes has the type:
+//
+//   MakeStringWithWidthUnit(MakeVarChar(10), StringWidthUnit_BYTES)
+//
+func MakeStringWithWidthUnit(strType *T, unit StringWidthUnit) *T {
+	switch strType.Family() {
+	case StringFamily, CollatedStringFamily:
+	default:
+		panic(errors.AssertionFailedf("cannot set width unit of non-string type: %s", strType))
+	}
+	t := *strType
+	if unit == StringWidthUnit_CHARACTERS {
+		t.InternalType.WidthUnit = nil
+	} else {
+		t.InternalType.WidthUnit = &unit
+	}
+	return &t
+}
+
 // MakeDecimal constructs a new instance of a DECIMAL type (oid = T_numeric)
 // that has at most "precision" # of decimal digits (0 = unspecified number of
 // digits) and at most "scale" # of decimal digits after the decimal point
@@ -628,16 +746,20 @@ func MakeDecimal(precision, scale int32) *T {
 }
 
 // MakeTime constructs a new instance of a TIME type (oid = T_time) that has at
-// most the given number of fractional second digits.
+// most the given number of fractional second digits (0 <= precision <= 6).
+// Unlike the Time type, the returned type always explicitly specifies its
+// precision, so MakeTime(0) is equivalent to TIME(0), not TIME.
 func MakeTime(precision int32) *T {
-	if precision == 0 {
-		return Time
-	}
-	if precision != 6 {
+	if precision < 0 || precision > MaxTimePrecision {
 		panic(errors.AssertionFailedf("precision %d is not currently supported", precision))
 	}
 	return &T{InternalType: InternalType{
-		Family: TimeFamily, Oid: oid.T_time, Precision: precision, Locale: &emptyLocale}}
+		Family:             TimeFamily,
+		Oid:                oid.T_time,
+		Precision:          precision,
+		TimePrecisionIsSet: true,
+		Locale:             &emptyLocale,
+	}}
 }
 
 // MakeTimestamp constructs a new instance of a TIMESTAMP type that has at most
@@ -761,19 +883,48 @@ func (t *T) Width() int32 {
 	return t.InternalType.Width
 }
 
+// WidthUnit is the unit in which the Width of a string type is measured. It is
+// BYTES for the types made with MakeStringWithWidthUnit to hold values up to a
+// number of bytes, and CHARACTERS for all other types. See StringFitsWidth.
+func (t *T) WidthUnit() StringWidthUnit {
+	return t.InternalType.widthUnit()
+}
+
+func (t *InternalType) widthUnit() StringWidthUnit {
+	if t.WidthUnit == nil {
+		return StringWidthUnit_CHARACTERS
+	}
+	return *t.WidthUnit
+}
+
 // Precision is the accuracy of the data type.
 //
 //   DECIMAL    : max # digits (must be >= Width/Scale)
 //   TIME       : max # fractional second digits
 //   TIMESTAMP  : max # fractional second digits
 //   TIMESTAMPTZ: max # fractional second digits
+//   INTERVAL   : max # fractional second digits
 //
 // For TIMESTAMP and TIMESTAMP TZ, the precision field is -1 for a default precision value of 6.
+// For TIME and INTERVAL, the precision is only meaningful if TimePrecisionIsSet
+// is true; otherwise the type has DefaultTimePrecision.
 // Precision is always 0 for other types.
 func (t *T) Precision() int32 {
 	return t.InternalType.Precision
 }
 
+// TimePrecisionIsSet returns true if this is a TIME or INTERVAL type that
+// explicitly specifies its precision, as in TIME(3) or INTERVAL SECOND(3). It
+// returns false for types with default precision, as well as for all other
+// type families.
+func (t *T) TimePrecisionIsSet() bool {
+	switch t.Family() {
+	case TimeFamily, IntervalFamily:
+		return t.InternalType.TimePrecisionIsSet
+	}
+	return false
+}
+
 // Scale is an alias method for Width, used for clarity for types in
 // DecimalFamily.
 func (t *T) Scale() int32 {
@@ -799,6 +950,102 @@ func (t *T) TupleLabels() []string {
 	return t.InternalType.TupleLabels
 }
 
+// TupleLabel returns the label of the tuple field at the given index, or the
+// empty string if the tuple type does not specify labels.
+func (t *T) TupleLabel(idx int) string {
+	if t.InternalType.TupleLabels == nil {
+		return ""
+	}
+	return t.InternalType.TupleLabels[idx]
+}
+
+// TupleLabelIndex returns the index of the first tuple field having the given
+// label, or -1 if there is no such field. It always returns -1 for types that
+// are not labeled tuple types.
+func (t *T) TupleLabelIndex(label string) int {
+	for i := range t.InternalType.TupleLabels {
+		if t.InternalType.TupleLabels[i] == label {
+			return i
+		}
+	}
+	return -1
+}
+
+// ForEachTupleField calls the given function once for each field of a tuple
+// type, in order, passing the field's index, label, and type. The label is the
+// empty string if the tuple type does not specify labels. The type points into
+// the TupleContents slice, and must not be modified.
+func (t *T) ForEachTupleField(fn func(idx int, label string, typ *T)) {
+	contents := t.TupleContents()
+	for i := range contents {
+		fn(i, t.TupleLabel(i), &contents[i])
+	}
+}
+
+// FieldByPath returns the type of the field of a nested tuple type that is
+// reached by following the given path of field indexes: the first index selects
+// a field of this type, the second a field of that field, and so on. An empty
+// path returns this type. The returned type points into the TupleContents of
+// the nested type, and must not be modified.
+func (t *T) FieldByPath(path []int) (*T, error) {
+	typ := t
+	for _, idx := range path {
+		if typ.Family() != TupleFamily {
+			return nil, pgerror.Newf(pgcode.WrongObjectType, "type %s is not composite", typ)
+		}
+		contents := typ.TupleContents()
+		if idx < 0 || idx >= len(contents) {
+			return nil, pgerror.Newf(pgcode.DatatypeMismatch,
+				"field index %d is out of range for type %s", idx, typ)
+		}
+		typ = &contents[idx]
+	}
+	return typ, nil
+}
+
+// FieldByLabelPath is like FieldByPath, but follows a path of field labels, as
+// in the record field access expression (r).a.b. Each label selects the first
+// field of the labeled tuple type that has it.
+func (t *T) FieldByLabelPath(labels []string) (*T, error) {
+	typ := t
+	for _, label := range labels {
+		if typ.Family() != TupleFamily || len(typ.TupleLabels()) == 0 {
+			return nil, pgerror.Newf(pgcode.WrongObjectType, "type %s is not composite", typ)
+		}
+		idx := typ.TupleLabelIndex(label)
+		if idx < 0 {
+			return nil, pgerror.Newf(pgcode.DatatypeMismatch,
+				"could not identify column %q in %s", label, typ)
+		}
+		typ = &typ.TupleContents()[idx]
+	}
+	return typ, nil
+}
+
+// ValidateTupleLabels checks that the given labels can be used to label a tuple
+// type having the given number of fields: the number of labels must match the
+// number of fields, and the labels must be unique. A nil or empty labels slice
+// is always valid, since it denotes an unlabeled tuple type.
+func ValidateTupleLabels(numFields int, labels []string) error {
+	if len(labels) == 0 {
+		return nil
+	}
+	if len(labels) != numFields {
+		return pgerror.Newf(pgcode.Syntax,
+			"mismatch in tuple definition: %d expressions, %d labels", numFields, len(labels))
+	}
+	for i := range labels {
+		for j := 0; j < i; j++ {
+			if labels[i] == labels[j] {
+				var buf bytes.Buffer
+				lex.EncodeRestrictedSQLIdent(&buf, labels[i], lex.EncNoFlags)
+				return pgerror.Newf(pgcode.Syntax, "found duplicate tuple label: %q", buf.String())
+			}
+		}
+	}
+	return nil
+}
+
 // Name returns a single word description of the type that describes it
 // succinctly, but without all the details, such as width, locale, etc. The name
 // is sometimes the same as the name returned by SQLStandardName, but is more
@@ -808,6 +1055,9 @@ func (t *T) TupleLabels() []string {
 func (t *T) Name() string {
 	switch t.Family() {
 	case AnyFamily:
+		if t.Oid() == oid.T_anynonarray {
+			return "anynonarray"
+		}
 		return "anyelement"
 	case ArrayFamily:
 		switch t.Oid() {
@@ -859,18 +1109,28 @@ func (t *T) Name() string {
 	case OidFamily:
 		return t.SQLStandardName()
 	case StringFamily, CollatedStringFamily:
-		switch t.Oid() {
-		case oid.T_text:
+		switch t.StringKind() {
+		case TextKind:
 			return "string"
-		case oid.T_bpchar:
+		case BpCharKind:
 			return "char"
-		case oid.T_char:
+		case QCharKind:
 			// Yes, that's the name. The ways of PostgreSQL are inscrutable.
 			return `"char"`
-		case oid.T_varchar:
+		case VarCharKind:
 			return "varchar"
-		case oid.T_name:
+		case NameKind:
 			return "name"
+		case JSONPathKind:
+			return "jsonpath"
+		case RefCursorKind:
+			return "refcursor"
+		case PGLSNKind:
+			return "pg_lsn"
+		case MACAddrKind:
+			return "macaddr"
+		case MACAddr8Kind:
+			return "macaddr8"
 		}
 		panic(errors.AssertionFailedf("unexpected OID: %d", t.Oid()))
 	case TimeFamily:
@@ -886,6 +1146,13 @@ func (t *T) Name() string {
 		return "unknown"
 	case UuidFamily:
 		return "uuid"
+	case VoidFamily:
+		return "void"
+	case TriggerFamily:
+		if t.Oid() == oid.T_event_trigger {
+			return "event_trigger"
+		}
+		return "trigger"
 	default:
 		panic(errors.AssertionFailedf("unexpected Family: %s", t.Family()))
 	}
@@ -907,6 +1174,9 @@ func (t *T) PGName() string {
 	if ok {
 		return strings.ToLower(name)
 	}
+	if name, ok := extraTypeNames[t.Oid()]; ok {
+		return name
+	}
 
 	// Postgres does not have an UNKNOWN[] type. However, CRDB does, so
 	// manufacture a name for it.
@@ -943,6 +1213,9 @@ func (t *T) SQLStandardNameWithTypmod(haveTypmod bool, typmod int) string {
 	var buf strings.Builder
 	switch t.Family() {
 	case AnyFamily:
+		if t.Oid() == oid.T_anynonarray {
+			return "anynonarray"
+		}
 		return "anyelement"
 	case ArrayFamily:
 		switch t.Oid() {
@@ -966,7 +1239,10 @@ func (t *T) SQLStandardNameWithTypmod(haveTypmod bool, typmod int) string {
 	case BoolFamily:
 		return "boolean"
 	case BytesFamily:
-		return "bytea"
+		if !haveTypmod || typmod <= varHeaderSize {
+			return "bytea"
+		}
+		return fmt.Sprintf("bytea(%d)", typmod-varHeaderSize)
 	case DateFamily:
 		return "date"
 	case DecimalFamily:
@@ -1007,8 +1283,15 @@ func (t *T) SQLStandardNameWithTypmod(haveTypmod bool, typmod int) string {
 			panic(errors.AssertionFailedf("programming error: unknown int width: %d", t.Width()))
 		}
 	case IntervalFamily:
-		// TODO(jordan): intervals can have typmods, but we don't support them yet.
-		return "interval"
+		if !haveTypmod || typmod < 0 {
+			return "interval"
+		}
+		typ, err := MakeIntervalFromTypmod(int32(typmod))
+		if err != nil {
+			return "interval"
+		}
+		itm, _ := typ.IntervalTypeMetadata()
+		return formatInterval("interval", itm, strings.ToLower)
 	case JsonFamily:
 		// Only binary JSON is currently supported.
 		return "jsonb"
@@ -1030,23 +1313,36 @@ func (t *T) SQLStandardNameWithTypmod(haveTypmod bool, typmod int) string {
 			panic(errors.AssertionFailedf("unexpected Oid: %v", errors.Safe(t.Oid())))
 		}
 	case StringFamily, CollatedStringFamily:
-		switch t.Oid() {
-		case oid.T_text:
+		kind := t.StringKind()
+		switch kind {
+		case TextKind:
 			buf.WriteString("text")
-		case oid.T_varchar:
+		case VarCharKind:
 			buf.WriteString("character varying")
-		case oid.T_bpchar:
+		case BpCharKind:
 			if haveTypmod && typmod < 0 {
 				// Special case. Run `select format_type('bpchar'::regtype, -1);` in pg.
 				return "bpchar"
 			}
 			buf.WriteString("character")
-		case oid.T_char:
+		case QCharKind:
 			// Type modifiers not allowed for "char".
 			return `"char"`
-		case oid.T_name:
+		case NameKind:
 			// Type modifiers not allowed for name.
 			return "name"
+		case JSONPathKind:
+			// Type modifiers not allowed for jsonpath.
+			return "jsonpath"
+		case RefCursorKind:
+			// Type modifiers not allowed for refcursor.
+			return "refcursor"
+		case PGLSNKind:
+			return "pg_lsn"
+		case MACAddrKind:
+			return "macaddr"
+		case MACAddr8Kind:
+			return "macaddr8"
 		default:
 			panic(errors.AssertionFailedf("unexpected OID: %d", t.Oid()))
 		}
@@ -1056,7 +1352,7 @@ func (t *T) SQLStandardNameWithTypmod(haveTypmod bool, typmod int) string {
 
 		// Typmod gets subtracted by 4 for all non-text string-like types to produce
 		// the length.
-		if t.Oid() != oid.T_text {
+		if kind != TextKind {
 			typmod -= 4
 		}
 		if typmod <= 0 {
@@ -1067,7 +1363,9 @@ func (t *T) SQLStandardNameWithTypmod(haveTypmod bool, typmod int) string {
 		return buf.String()
 
 	case TimeFamily:
-		if !haveTypmod || typmod <= 0 {
+		// Unlike other types, a typmod of 0 is meaningful for TIME, since it
+		// specifies a precision of whole seconds.
+		if !haveTypmod || typmod < 0 {
 			return "time without time zone"
 		}
 		return fmt.Sprintf("time(%d) without time zone", typmod)
@@ -1087,6 +1385,13 @@ func (t *T) SQLStandardNameWithTypmod(haveTypmod bool, typmod int) string {
 		return "unknown"
 	case UuidFamily:
 		return "uuid"
+	case VoidFamily:
+		return "void"
+	case TriggerFamily:
+		if t.Oid() == oid.T_event_trigger {
+			return "event_trigger"
+		}
+		return "trigger"
 	default:
 		panic(errors.AssertionFailedf("unexpected Family: %v", errors.Safe(t.Family())))
 	}
@@ -1122,14 +1427,14 @@ func (t *T) SQLString() string {
 			typName = fmt.Sprintf("%s(%d)", typName, t.Width())
 		}
 		return typName
+	case BytesFamily:
+		if t.Width() > 0 {
+			return fmt.Sprintf("BYTES(%d)", t.Width())
+		}
 	case IntFamily:
 		switch t.Width() {
-		case 16:
-			return "INT2"
-		case 32:
-			return "INT4"
-		case 64:
-			return "INT8"
+		case 16, 32, 64:
+			return canonicalTypeNames[t.Oid()]
 		default:
 			panic(errors.AssertionFailedf("programming error: unknown int width: %d", t.Width()))
 		}
@@ -1138,12 +1443,10 @@ func (t *T) SQLString() string {
 	case CollatedStringFamily:
 		return t.collatedStringTypeSQL(false /* isArray */)
 	case FloatFamily:
-		const realName = "FLOAT4"
-		const doubleName = "FLOAT8"
-		if t.Width() == 32 {
-			return realName
+		if t.IsFloat4() {
+			return canonicalTypeNames[oid.T_float4]
 		}
-		return doubleName
+		return canonicalTypeNames[oid.T_float8]
 	case DecimalFamily:
 		if t.Precision() > 0 {
 			if t.Width() > 0 {
@@ -1161,9 +1464,11 @@ func (t *T) SQLString() string {
 		// This is the timestamp with the default precision value
 		return strings.ToUpper(t.Name())
 	case TimeFamily:
-		if t.Precision() > 0 {
+		if t.TimePrecisionIsSet() {
 			return fmt.Sprintf("%s(%d)", strings.ToUpper(t.Name()), t.Precision())
 		}
+	case IntervalFamily:
+		return t.intervalTypeSQL()
 	case OidFamily:
 		if name, ok := oid.TypeName[t.Oid()]; ok {
 			return name
@@ -1180,9 +1485,47 @@ func (t *T) SQLString() string {
 		}
 		return t.ArrayContents().SQLString() + "[]"
 	}
+	if name, ok := canonicalTypeNames[t.Oid()]; ok {
+		return name
+	}
 	return strings.ToUpper(t.Name())
 }
 
+// ErrorFormat returns the name of the type that is used in the errors about
+// values that do not fit the modifiers of the type, such as:
+//
+//   value too long for type VARCHAR(10)
+//
+// It is the same as SQLString, except that the modifiers are always rendered in
+// full, even where SQLString omits the ones that are implied by the type name:
+// the width of CHAR(1) and BIT(1), and the scale of DECIMAL(10,0).
+func (t *T) ErrorFormat() string {
+	switch t.Family() {
+	case BitFamily:
+		if t.Oid() != oid.T_varbit && t.Width() == 1 {
+			return "BIT(1)"
+		}
+	case StringFamily, CollatedStringFamily:
+		if t.StringKind() == BpCharKind && t.Width() == 1 {
+			// Keep the COLLATE clause of collated strings.
+			return "CHAR(1)" + strings.TrimPrefix(t.SQLString(), "CHAR")
+		}
+	case DecimalFamily:
+		if t.Precision() > 0 {
+			return fmt.Sprintf("DECIMAL(%d,%d)", t.Precision(), t.Scale())
+		}
+	case ArrayFamily:
+		switch t.Oid() {
+		case oid.T_oidvector, oid.T_int2vector:
+		default:
+			if t.ArrayContents().Family() != CollatedStringFamily {
+				return t.ArrayContents().ErrorFormat() + "[]"
+			}
+		}
+	}
+	return t.SQLString()
+}
+
 // Equivalent returns true if this type is "equivalent" to the given type.
 // Equivalent types are compatible with one another: they can be compared,
 // assigned, and unioned. Equivalent types must always have the same type family
@@ -1243,6 +1586,127 @@ func (t *T) Identical(other *T) bool {
 	return t.InternalType.Identical(&other.InternalType)
 }
 
+// DeepCopy returns a copy of this type that shares no memory with it, so that
+// either one can be modified without affecting the other. It copies the
+// contents of arrays and tuples, as well as any other slice or pointer fields.
+// It is much cheaper than protoutil.Clone, which relies on reflection.
+func (t *T) DeepCopy() *T {
+	c := &T{InternalType: t.InternalType}
+	it := &c.InternalType
+	if it.Locale != nil {
+		locale := *it.Locale
+		it.Locale = &locale
+	}
+	if it.ArrayElemType != nil {
+		family := *it.ArrayElemType
+		it.ArrayElemType = &family
+	}
+	if it.IntervalDurationField != nil {
+		df := *it.IntervalDurationField
+		it.IntervalDurationField = &df
+	}
+	if it.Version != nil {
+		version := *it.Version
+		it.Version = &version
+	}
+	if it.WidthUnit != nil {
+		unit := *it.WidthUnit
+		it.WidthUnit = &unit
+	}
+	if it.ArrayContents != nil {
+		it.ArrayContents = it.ArrayContents.DeepCopy()
+	}
+	if it.TupleContents != nil {
+		contents := make([]T, len(it.TupleContents))
+		for i := range it.TupleContents {
+			contents[i] = *it.TupleContents[i].DeepCopy()
+		}
+		it.TupleContents = contents
+	}
+	if it.TupleLabels != nil {
+		it.TupleLabels = append([]string(nil), it.TupleLabels...)
+	}
+	if it.ArrayDimensions != nil {
+		it.ArrayDimensions = append([]int32(nil), it.ArrayDimensions...)
+	}
+	if it.XXX_unrecognized != nil {
+		it.XXX_unrecognized = append([]byte(nil), it.XXX_unrecognized...)
+	}
+	return c
+}
+
+// EquivalentIgnoringAlias returns true if this type and the given type describe
+// the same set of values, even if they are spelled using different aliases or
+// were serialized by different versions of CRDB. Unlike Equivalent, it does not
+// ignore attributes such as width and precision: INT4 and INT8 are different,
+// but INT4 and an INT with a width of 32 are the same, as are FLOAT4 and a
+// FLOAT with a (legacy) precision of 24. VARCHAR and STRING are also treated as
+// the same, as long as they have the same width.
+//
+// This is intended for tools such as schema diffs, where aliases should not
+// produce spurious differences.
+func (t *T) EquivalentIgnoringAlias(other *T) bool {
+	a, b := t.withoutAlias(), other.withoutAlias()
+	return a.Identical(b)
+}
+
+// withoutAlias returns a copy of this type in which the attributes that only
+// reflect how the type was spelled or serialized have been normalized. See
+// EquivalentIgnoringAlias.
+func (t *T) withoutAlias() *T {
+	n := *t
+	n.InternalType.VisibleType = visibleNONE
+	if n.InternalType.Locale != nil && *n.InternalType.Locale == "" {
+		n.InternalType.Locale = nil
+	}
+
+	switch n.Family() {
+	case IntFamily:
+		switch n.Width() {
+		case 16:
+			n.InternalType.Oid = oid.T_int2
+		case 32:
+			n.InternalType.Oid = oid.T_int4
+		default:
+			n.InternalType.Width = 64
+			n.InternalType.Oid = oid.T_int8
+		}
+
+	case FloatFamily:
+		width := n.Width()
+		if width == 0 {
+			width = FloatWidthFromPrecision(n.Precision())
+		}
+		if width == 32 {
+			n.InternalType.Oid = oid.T_float4
+		} else {
+			width = 64
+			n.InternalType.Oid = oid.T_float8
+		}
+		n.InternalType.Width = width
+		n.InternalType.Precision = 0
+
+	case StringFamily, CollatedStringFamily:
+		if n.Oid() == oid.T_varchar || n.Oid() == 0 {
+			n.InternalType.Oid = oid.T_text
+		}
+
+	case ArrayFamily:
+		n.InternalType.ArrayContents = n.ArrayContents().withoutAlias()
+		n.InternalType.Oid = calcArrayOid(n.ArrayContents())
+
+	case TupleFamily:
+		if len(n.TupleContents()) > 0 {
+			contents := make([]T, len(n.TupleContents()))
+			for i := range n.TupleContents() {
+				contents[i] = *n.TupleContents()[i].withoutAlias()
+			}
+			n.InternalType.TupleContents = contents
+		}
+	}
+	return &n
+}
+
 // Size returns the size, in bytes, of this type once it has been marshaled to
 // a byte buffer. This is typically called to determine the size of the buffer
 // that needs to be allocated before calling Marshal.
@@ -1281,6 +1745,21 @@ func (t *InternalType) Identical(other *InternalType) bool {
 	if t.Precision != other.Precision {
 		return false
 	}
+	if t.TimePrecisionIsSet != other.TimePrecisionIsSet {
+		return false
+	}
+	if t.widthUnit() != other.widthUnit() {
+		return false
+	}
+	if t.IntervalDurationField != nil && other.IntervalDurationField != nil {
+		if *t.IntervalDurationField != *other.IntervalDurationField {
+			return false
+		}
+	} else if t.IntervalDurationField != nil {
+		return false
+	} else if other.IntervalDurationField != nil {
+		return false
+	}
 	if t.Locale != nil && other.Locale != nil {
 		if *t.Locale != *other.Locale {
 			return false
@@ -1325,15 +1804,78 @@ func (t *InternalType) Identical(other *InternalType) bool {
 //   var t T
 //   err := protoutil.Unmarshal(data, &t)
 //
+// The locale and tuple labels of the type are interned by DefaultInterner.
+// Unmarshal returns an UnmarshalLimitError if the type is nested deeper than
+// MaxTypeNestingDepth or is larger than MaxTypeSize.
+//
 // Unmarshal is part of the protoutil.Message interface.
 func (t *T) Unmarshal(data []byte) error {
+	if err := checkUnmarshalLimits(data); err != nil {
+		return err
+	}
 	// Unmarshal the internal type, and then perform an upgrade step to convert
 	// to the latest format.
 	err := protoutil.Unmarshal(data, &t.InternalType)
 	if err != nil {
 		return err
 	}
-	return t.upgradeType()
+	if err := t.upgradeType(); err != nil {
+		return err
+	}
+	if err := t.migrateType(); err != nil {
+		return err
+	}
+	DefaultInterner.InternType(t)
+	return nil
+}
+
+// typeMigration upgrades a type from the serialization version for which it is
+// registered in typeMigrations to the next version.
+type typeMigration func(t *T) error
+
+// typeMigrations is the registry of migrations that are applied when a type is
+// unmarshaled. The migration at index i upgrades a type that was serialized
+// with version i to version i+1, so the latest version is the number of
+// registered migrations. Future changes to the representation of types (e.g.
+// restructuring the fields of array elements) must bump the version by
+// registering a migration here, rather than detecting old representations by
+// the presence or absence of fields.
+//
+// Types serialized before the version was introduced have version 0. They are
+// first converted from the formats of older versions of CRDB by upgradeType,
+// which also undoes the backwards-compatible changes made by downgradeType.
+var typeMigrations []typeMigration
+
+// latestTypeVersion returns the serialization version of types that are
+// marshaled by this version of CRDB.
+func latestTypeVersion() uint32 {
+	return uint32(len(typeMigrations))
+}
+
+// migrateType applies the registered migrations to a type that was just
+// unmarshaled and upgraded by upgradeType, bringing it from its serialization
+// version to the latest version. It returns an error if the type was
+// serialized with a version that is newer than the latest version.
+func (t *T) migrateType() error {
+	var version uint32
+	if t.InternalType.Version != nil {
+		version = *t.InternalType.Version
+	}
+	latest := latestTypeVersion()
+	if version > latest {
+		return errors.AssertionFailedf(
+			"type serialization version %d is newer than the latest version %d", version, latest)
+	}
+	for ; version < latest; version++ {
+		if err := typeMigrations[version](t); err != nil {
+			return errors.Wrapf(err, "error migrating type from version %d", version)
+		}
+	}
+
+	// Types in memory always use the latest representation, so the version is
+	// only set when they are marshaled.
+	t.InternalType.Version = nil
+	return nil
 }
 
 // upgradeType assumes its input was just unmarshaled from bytes that may have
@@ -1373,40 +1915,10 @@ func (t *T) upgradeType() error {
 		}
 
 	case FloatFamily:
-		// Map visible REAL type to 32-bit width.
-		switch t.InternalType.VisibleType {
-		case visibleREAL:
-			t.InternalType.Oid = oid.T_float4
-			t.InternalType.Width = 32
-		case visibleDOUBLE:
-			t.InternalType.Oid = oid.T_float8
-			t.InternalType.Width = 64
-		case visibleNONE:
-			switch t.Width() {
-			case 32:
-				t.InternalType.Oid = oid.T_float4
-			case 64:
-				t.InternalType.Oid = oid.T_float8
-			default:
-				// Pre-2.1 (before Width) there were 3 cases:
-				// - VisibleType = DOUBLE PRECISION, Width = 0 -> now clearly FLOAT8
-				// - VisibleType = NONE, Width = 0 -> now clearly FLOAT8
-				// - VisibleType = NONE, Precision > 0 -> we need to derive the width.
-				if t.Precision() >= 1 && t.Precision() <= 24 {
-					t.InternalType.Oid = oid.T_float4
-					t.InternalType.Width = 32
-				} else {
-					t.InternalType.Oid = oid.T_float8
-					t.InternalType.Width = 64
-				}
-			}
-		default:
-			return errors.AssertionFailedf("unexpected visible type: %d", t.InternalType.VisibleType)
+		if err := t.UpgradeFromPre21(); err != nil {
+			return err
 		}
 
-		// Precision should always be set to 0 going forward.
-		t.InternalType.Precision = 0
-
 	case StringFamily, CollatedStringFamily:
 		// Map string-related visible types to corresponding Oid values.
 		switch t.InternalType.VisibleType {
@@ -1417,7 +1929,13 @@ func (t *T) upgradeType() error {
 		case visibleQCHAR:
 			t.InternalType.Oid = oid.T_char
 		case visibleNONE:
-			t.InternalType.Oid = oid.T_text
+			// The string types introduced after the Oid field have no visible
+			// type, and are identified by their Oid.
+			switch t.InternalType.Oid {
+			case T_jsonpath, oid.T_refcursor, oid.T_pg_lsn, oid.T_macaddr, T_macaddr8:
+			default:
+				t.InternalType.Oid = oid.T_text
+			}
 		default:
 			return errors.AssertionFailedf("unexpected visible type: %d", t.InternalType.VisibleType)
 		}
@@ -1428,6 +1946,17 @@ func (t *T) upgradeType() error {
 			}
 		}
 
+	case TimeFamily:
+		// Previous versions of CRDB only supported TIME(6), and stored it without
+		// setting TimePrecisionIsSet. TIME(0) could not be created, so a non-zero
+		// precision is always an explicitly specified one.
+		if t.InternalType.Precision != 0 {
+			t.InternalType.TimePrecisionIsSet = true
+		}
+		if t.InternalType.Oid == 0 {
+			t.InternalType.Oid = oid.T_time
+		}
+
 	case BitFamily:
 		// Map visible VARBIT type to T_varbit OID value.
 		switch t.InternalType.VisibleType {
@@ -1450,6 +1979,9 @@ func (t *T) upgradeType() error {
 			if err := arrayContents.upgradeType(); err != nil {
 				return err
 			}
+			if err := arrayContents.migrateType(); err != nil {
+				return err
+			}
 			t.InternalType.ArrayContents = &arrayContents
 			t.InternalType.Oid = calcArrayOid(t.ArrayContents())
 		}
@@ -1567,6 +2099,9 @@ func (t *T) downgradeType() error {
 			t.InternalType.VisibleType = visibleQCHAR
 		case oid.T_name:
 			t.InternalType.Family = name
+		case T_jsonpath, oid.T_refcursor, oid.T_pg_lsn, oid.T_macaddr, T_macaddr8:
+			// Nothing to do, since the Oid field was already in use when these
+			// types were introduced.
 		default:
 			return errors.AssertionFailedf("unexpected Oid: %d", t.Oid())
 		}
@@ -1602,6 +2137,16 @@ func (t *T) downgradeType() error {
 		t.InternalType.Locale = nil
 	}
 
+	// Record the serialization version, so that the type can be migrated if
+	// its representation changes in a later version. Version 0 is left unset,
+	// which is how types were serialized before the version was introduced.
+	// The version is declared in the body of the if statement, so that it is
+	// only moved to the heap if it is set.
+	if latestTypeVersion() > 0 {
+		version := latestTypeVersion()
+		t.InternalType.Version = &version
+	}
+
 	return nil
 }
 
@@ -1650,11 +2195,95 @@ func (t *T) String() string {
 }
 
 // DebugString returns a detailed dump of the type protobuf struct, suitable for
-// debugging scenarios.
+// debugging scenarios such as descriptor inspection tools and test failure
+// messages. Every field of the type is shown, and the element types of arrays
+// and the field types of tuples are rendered as an indented tree:
+//
+//   family: TupleFamily oid: 2249 (RECORD) width: 0 precision: 0 locale: ""
+//     field 0 "a": family: IntFamily oid: 20 (INT8) width: 64 precision: 0 locale: ""
+//     field 1 "b": family: ArrayFamily oid: 1009 (_TEXT) width: 0 precision: 0 locale: ""
+//       elem: family: StringFamily oid: 25 (TEXT) width: 0 precision: 0 locale: ""
+//
+// Use CompactDebugString for a single-line dump.
 func (t *T) DebugString() string {
+	var buf bytes.Buffer
+	t.debugTree(&buf, 0 /* depth */)
+	return buf.String()
+}
+
+// CompactDebugString returns a single-line dump of the type protobuf struct.
+func (t *T) CompactDebugString() string {
 	return t.InternalType.String()
 }
 
+// debugTree writes the DebugString representation of this type, indented by
+// the given depth, to the buffer.
+func (t *T) debugTree(buf *bytes.Buffer, depth int) {
+	it := &t.InternalType
+	fmt.Fprintf(buf, "family: %s oid: %d (%s) width: %d precision: %d",
+		it.Family, it.Oid, oid.TypeName[it.Oid], it.Width, it.Precision)
+	if it.Locale != nil {
+		fmt.Fprintf(buf, " locale: %q", *it.Locale)
+	} else {
+		buf.WriteString(" locale: <nil>")
+	}
+	if it.TimePrecisionIsSet {
+		buf.WriteString(" time_precision_is_set: true")
+	}
+	if it.IntervalDurationField != nil {
+		fmt.Fprintf(buf, " interval_duration_field: {%s}", it.IntervalDurationField)
+	}
+	if it.WidthUnit != nil {
+		fmt.Fprintf(buf, " width_unit: %s", *it.WidthUnit)
+	}
+	if it.VisibleType != visibleNONE {
+		fmt.Fprintf(buf, " visible_type: %d", it.VisibleType)
+	}
+	if it.ArrayElemType != nil {
+		fmt.Fprintf(buf, " array_elem_type: %s", *it.ArrayElemType)
+	}
+	if it.ArrayDimensions != nil {
+		fmt.Fprintf(buf, " array_dimensions: %v", it.ArrayDimensions)
+	}
+	if len(it.XXX_unrecognized) > 0 {
+		fmt.Fprintf(buf, " unrecognized: %x", it.XXX_unrecognized)
+	}
+
+	indent := func() {
+		buf.WriteByte('\n')
+		for i := 0; i <= depth; i++ {
+			buf.WriteString("  ")
+		}
+	}
+	if it.ArrayContents != nil {
+		indent()
+		buf.WriteString("elem: ")
+		it.ArrayContents.debugTree(buf, depth+1)
+	}
+	for i := range it.TupleContents {
+		indent()
+		fmt.Fprintf(buf, "field %d", i)
+		if i < len(it.TupleLabels) {
+			fmt.Fprintf(buf, " %q", it.TupleLabels[i])
+		}
+		buf.WriteString(": ")
+		it.TupleContents[i].debugTree(buf, depth+1)
+	}
+}
+
+// IsSignatureOnly returns true if the type is a pseudo-type that can only appear
+// in the signatures of functions and procedures, such as the VOID result of a
+// procedure or the TRIGGER result of a trigger function. These types cannot be
+// used as the type of table columns, nor as the element type of arrays, which
+// they do not have.
+func (t *T) IsSignatureOnly() bool {
+	switch t.Family() {
+	case VoidFamily, TriggerFamily:
+		return true
+	}
+	return false
+}
+
 // IsAmbiguous returns true if this type is in UnknownFamily or AnyFamily.
 // Instances of ambiguous types can be NULL or be in one of several different
 // type families. This is important for parameterized types to determine whether
@@ -1681,6 +2310,146 @@ func (t *T) IsAmbiguous() bool {
 	return false
 }
 
+// StringKind identifies which of the character string types is described by a
+// type in the StringFamily or CollatedStringFamily. The kinds differ in their
+// names, in how their widths are interpreted, and in their treatment of
+// trailing spaces. See the T.StringKind method for more details.
+//
+// The kind is not stored separately: like for the other type aliases since
+// 19.1, the Oid of a type is the authoritative encoding of its kind, and it is
+// the Oid that is stored in descriptors and reported in pg_type. StringKind
+// decodes it in one place, so that callers can drive the comparison and
+// trailing space semantics of a type from its kind rather than from its Oid.
+type StringKind int
+
+const (
+	// NonStringKind is the StringKind of types that are not string types.
+	NonStringKind StringKind = iota
+	// TextKind is the kind of the STRING (TEXT) type, which has no width
+	// restriction unless one is specified, as in STRING(N).
+	TextKind
+	// VarCharKind is the kind of the VARCHAR(N) type, which holds at most N
+	// characters.
+	VarCharKind
+	// BpCharKind is the kind of the blank-padded CHAR(N) type, which holds
+	// exactly N characters. Trailing spaces are not significant for this kind.
+	BpCharKind
+	// QCharKind is the kind of the single-byte "char" type.
+	QCharKind
+	// NameKind is the kind of the NAME type, which is used for identifiers in
+	// the system catalogs.
+	NameKind
+	// JSONPathKind is the kind of the JSONPATH type, which holds SQL/JSON path
+	// expressions. It is not a character string type in Postgres, but its
+	// values are represented as strings.
+	JSONPathKind
+	// RefCursorKind is the kind of the REFCURSOR type, which holds the names of
+	// cursors.
+	RefCursorKind
+	// PGLSNKind is the kind of the PG_LSN type, which holds Postgres log
+	// sequence numbers.
+	PGLSNKind
+	// MACAddrKind is the kind of the MACADDR type, which holds 6-byte MAC
+	// addresses.
+	MACAddrKind
+	// MACAddr8Kind is the kind of the MACADDR8 type, which holds 8-byte MAC
+	// addresses.
+	MACAddr8Kind
+)
+
+var stringKindNames = [...]string{
+	NonStringKind: "NonStringKind",
+	TextKind:      "TextKind",
+	VarCharKind:   "VarCharKind",
+	BpCharKind:    "BpCharKind",
+	QCharKind:     "QCharKind",
+	NameKind:      "NameKind",
+	JSONPathKind:  "JSONPathKind",
+	RefCursorKind: "RefCursorKind",
+	PGLSNKind:     "PGLSNKind",
+	MACAddrKind:   "MACAddrKind",
+	MACAddr8Kind:  "MACAddr8Kind",
+}
+
+// String implements the fmt.Stringer interface.
+func (k StringKind) String() string {
+	if k < 0 || int(k) >= len(stringKindNames) {
+		return fmt.Sprintf("StringKind(%d)", int(k))
+	}
+	return stringKindNames[k]
+}
+
+// StringKind returns the kind of character string described by this type:
+//
+//   STRING, TEXT: TextKind
+//   VARCHAR     : VarCharKind
+//   CHAR        : BpCharKind
+//   "char"      : QCharKind
+//   NAME        : NameKind
+//   JSONPATH    : JSONPathKind
+//   REFCURSOR   : RefCursorKind
+//   PG_LSN      : PGLSNKind
+//   MACADDR     : MACAddrKind
+//   MACADDR8    : MACAddr8Kind
+//
+// The kind is determined by the Oid of the type, which is its authoritative
+// encoding. The kind of a COLLATEDSTRING type is the kind of the string type
+// that was collated. StringKind returns NonStringKind for all other types.
+func (t *T) StringKind() StringKind {
+	switch t.Family() {
+	case StringFamily, CollatedStringFamily:
+	default:
+		return NonStringKind
+	}
+	switch t.Oid() {
+	case oid.T_text:
+		return TextKind
+	case oid.T_varchar:
+		return VarCharKind
+	case oid.T_bpchar:
+		return BpCharKind
+	case oid.T_char:
+		return QCharKind
+	case oid.T_name:
+		return NameKind
+	case T_jsonpath:
+		return JSONPathKind
+	case oid.T_refcursor:
+		return RefCursorKind
+	case oid.T_pg_lsn:
+		return PGLSNKind
+	case oid.T_macaddr:
+		return MACAddrKind
+	case T_macaddr8:
+		return MACAddr8Kind
+	}
+	panic(errors.AssertionFailedf("unexpected OID: %d", t.Oid()))
+}
+
+// IsBlankPadded returns true if this is a blank-padded CHAR type (possibly
+// collated). Values of such types are conceptually padded with spaces to the
+// width of the type, so trailing spaces are not significant when values are
+// compared, hashed, or measured (see TrimBlankPadding). Trailing spaces are
+// significant for all other string types.
+func (t *T) IsBlankPadded() bool {
+	return t.StringKind() == BpCharKind
+}
+
+// TrimBlankPadding removes the trailing spaces of a value that is converted to
+// this type if it is blank-padded. The values of blank-padded types are kept
+// without their padding, so that they compare, hash and encode the same way
+// regardless of how many trailing spaces they were given:
+//
+//   'a  '::CHAR(3) = 'a'::CHAR(3)
+//
+// The values of all other types are returned unchanged.
+func (t *T) TrimBlankPadding(s string) string {
+	if !t.IsBlankPadded() {
+		return s
+	}
+	return strings.TrimRight(s, " ")
+}
+
 // IsStringType returns true iff the given type is String or a collated string
 // type.
 func IsStringType(t *T) bool {
@@ -1776,24 +2545,34 @@ func (t *T) collatedStringTypeSQL(isArray bool) string {
 // STRING/COLLATEDSTRING type.
 func (t *T) stringTypeSQL() string {
 	typName := "STRING"
-	switch t.Oid() {
-	case oid.T_varchar:
+	kind := t.StringKind()
+	switch kind {
+	case VarCharKind:
 		typName = "VARCHAR"
-	case oid.T_bpchar:
+	case BpCharKind:
 		typName = "CHAR"
-	case oid.T_char:
+	case QCharKind:
 		// Yes, that's the name. The ways of PostgreSQL are inscrutable.
 		typName = `"char"`
-	case oid.T_name:
+	case NameKind:
 		typName = "NAME"
+	case JSONPathKind:
+		typName = "JSONPATH"
+	case RefCursorKind:
+		typName = "REFCURSOR"
+	case PGLSNKind:
+		typName = "PG_LSN"
+	case MACAddrKind:
+		typName = "MACADDR"
+	case MACAddr8Kind:
+		typName = "MACADDR8"
 	}
 
 	// In general, if there is a specified width we want to print it next to the
 	// type. However, in the specific case of CHAR and "char", the default is 1
 	// and the width should be omitted in that case.
 	if t.Width() > 0 {
-		o := t.Oid()
-		if t.Width() != 1 || (o != oid.T_bpchar && o != oid.T_char) {
+		if t.Width() != 1 || (kind != BpCharKind && kind != QCharKind) {
 			typName = fmt.Sprintf("%s(%d)", typName, t.Width())
 		}
 	}
@@ -1805,8 +2584,8 @@ var typNameLiterals map[string]*T
 
 func init() {
 	typNameLiterals = make(map[string]*T)
-	for o, t := range OidToType {
-		name := strings.ToLower(oid.TypeName[o])
+	for _, t := range OidToType {
+		name := t.PGName()
 		if _, ok := typNameLiterals[name]; !ok {
 			typNameLiterals[name] = t
 		}
@@ -1837,11 +2616,8 @@ var postgresPredefinedTypeIssues = map[string]int{
 	"circle":        21286,
 	"line":          21286,
 	"lseg":          21286,
-	"macaddr":       -1,
-	"macaddr8":      -1,
 	"money":         -1,
 	"path":          21286,
-	"pg_lsn":        -1,
 	"point":         21286,
 	"polygon":       21286,
 	"tsquery":       7821,
@@ -11,11 +11,28 @@
 package types
 
 import (
+	"bufio"
 	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
+	"sort"
+	"strings"
 	"testing"
 
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/errors"
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
 	"github.com/lib/pq/oid"
 )
 
@@ -155,6 +172,16 @@ func TestTypes(t *testing.T) {
 		{Interval, &T{InternalType: InternalType{
 			Family: IntervalFamily, Oid: oid.T_interval, Locale: &emptyLocale}}},
 		{Interval, MakeScalar(IntervalFamily, oid.T_interval, 0, 0, emptyLocale)},
+		{Interval, MakeInterval(IntervalTypeMetadata{})},
+		{MakeInterval(IntervalTypeMetadata{Precision: 3, PrecisionIsSet: true}), &T{InternalType: InternalType{
+			Family: IntervalFamily, Oid: oid.T_interval, Precision: 3, TimePrecisionIsSet: true, Locale: &emptyLocale}}},
+		{MakeInterval(IntervalTypeMetadata{
+			DurationField: IntervalDurationField{
+				DurationType: IntervalDurationType_SECOND, FromDurationType: IntervalDurationType_DAY},
+		}), &T{InternalType: InternalType{
+			Family: IntervalFamily, Oid: oid.T_interval, Locale: &emptyLocale,
+			IntervalDurationField: &IntervalDurationField{
+				DurationType: IntervalDurationType_SECOND, FromDurationType: IntervalDurationType_DAY}}}},
 
 		// JSON
 		{Jsonb, &T{InternalType: InternalType{
@@ -210,12 +237,33 @@ func TestTypes(t *testing.T) {
 			Family: StringFamily, Oid: oid.T_name, Locale: &emptyLocale}}},
 		{Name, MakeScalar(StringFamily, oid.T_name, 0, 0, emptyLocale)},
 
+		// JSONPATH
+		{Jsonpath, &T{InternalType: InternalType{
+			Family: StringFamily, Oid: T_jsonpath, Locale: &emptyLocale}}},
+		{Jsonpath, MakeScalar(StringFamily, T_jsonpath, 0, 0, emptyLocale)},
+
+		// REFCURSOR
+		{RefCursor, &T{InternalType: InternalType{
+			Family: StringFamily, Oid: oid.T_refcursor, Locale: &emptyLocale}}},
+		{RefCursor, MakeScalar(StringFamily, oid.T_refcursor, 0, 0, emptyLocale)},
+
+		// PG_LSN, MACADDR, MACADDR8
+		{PGLSN, &T{InternalType: InternalType{
+			Family: StringFamily, Oid: oid.T_pg_lsn, Locale: &emptyLocale}}},
+		{MACAddr, &T{InternalType: InternalType{
+			Family: StringFamily, Oid: oid.T_macaddr, Locale: &emptyLocale}}},
+		{MACAddr8, MakeScalar(StringFamily, T_macaddr8, 0, 0, emptyLocale)},
+
 		// TIME
-		{MakeTime(0), Time},
-		{MakeTime(0), &T{InternalType: InternalType{
+		{Time, &T{InternalType: InternalType{
 			Family: TimeFamily, Oid: oid.T_time, Locale: &emptyLocale}}},
+		{Time, MakeScalar(TimeFamily, oid.T_time, 0, 0, emptyLocale)},
+		{MakeTime(0), &T{InternalType: InternalType{
+			Family: TimeFamily, Oid: oid.T_time, TimePrecisionIsSet: true, Locale: &emptyLocale}}},
+		{MakeTime(3), &T{InternalType: InternalType{
+			Family: TimeFamily, Oid: oid.T_time, Precision: 3, TimePrecisionIsSet: true, Locale: &emptyLocale}}},
 		{MakeTime(6), &T{InternalType: InternalType{
-			Family: TimeFamily, Oid: oid.T_time, Precision: 6, Locale: &emptyLocale}}},
+			Family: TimeFamily, Oid: oid.T_time, Precision: 6, TimePrecisionIsSet: true, Locale: &emptyLocale}}},
 		{MakeTime(6), MakeScalar(TimeFamily, oid.T_time, 6, 0, emptyLocale)},
 
 		// TIMESTAMP
@@ -253,6 +301,19 @@ func TestTypes(t *testing.T) {
 		{Uuid, &T{InternalType: InternalType{
 			Family: UuidFamily, Oid: oid.T_uuid, Locale: &emptyLocale}}},
 		{Uuid, MakeScalar(UuidFamily, oid.T_uuid, 0, 0, emptyLocale)},
+
+		// VOID
+		{Void, &T{InternalType: InternalType{
+			Family: VoidFamily, Oid: oid.T_void, Locale: &emptyLocale}}},
+		{Void, MakeScalar(VoidFamily, oid.T_void, 0, 0, emptyLocale)},
+
+		// TRIGGER
+		{Trigger, &T{InternalType: InternalType{
+			Family: TriggerFamily, Oid: oid.T_trigger, Locale: &emptyLocale}}},
+		{Trigger, MakeScalar(TriggerFamily, oid.T_trigger, 0, 0, emptyLocale)},
+		{EventTrigger, &T{InternalType: InternalType{
+			Family: TriggerFamily, Oid: oid.T_event_trigger, Locale: &emptyLocale}}},
+		{EventTrigger, MakeScalar(TriggerFamily, oid.T_event_trigger, 0, 0, emptyLocale)},
 	}
 
 	for _, tc := range testCases {
@@ -422,6 +483,10 @@ func TestMarshalCompat(t *testing.T) {
 		{MakeChar(10), InternalType{Family: StringFamily, Oid: oid.T_bpchar, Width: 10, VisibleType: visibleCHAR}},
 		{MakeQChar(1), InternalType{Family: StringFamily, Oid: oid.T_char, Width: 1, VisibleType: visibleQCHAR}},
 		{Name, InternalType{Family: name, Oid: oid.T_name}},
+		{Jsonpath, InternalType{Family: StringFamily, Oid: T_jsonpath}},
+		{RefCursor, InternalType{Family: StringFamily, Oid: oid.T_refcursor}},
+		{PGLSN, InternalType{Family: StringFamily, Oid: oid.T_pg_lsn}},
+		{MACAddr8, InternalType{Family: StringFamily, Oid: T_macaddr8}},
 	}
 
 	for _, tc := range testCases {
@@ -479,6 +544,10 @@ func TestUnmarshalCompat(t *testing.T) {
 		{InternalType{Family: IntFamily, Width: 20}, Int},
 		{InternalType{Family: IntFamily}, Int},
 
+		// TIME
+		{InternalType{Family: TimeFamily}, Time},
+		{InternalType{Family: TimeFamily, Oid: oid.T_time, Precision: 6}, MakeTime(6)},
+
 		// STRING
 		{InternalType{Family: StringFamily}, String},
 		{InternalType{Family: StringFamily, VisibleType: visibleVARCHAR}, VarChar},
@@ -505,6 +574,121 @@ func TestUnmarshalCompat(t *testing.T) {
 	}
 }
 
+// TestUnrecognizedFields checks that fields which were added by a newer
+// version are preserved when a type is unmarshaled and marshaled again.
+func TestUnrecognizedFields(t *testing.T) {
+	// unknown is field number 100, with a varint value of 42.
+	unknown := []byte{0xa0, 0x06, 0x2a}
+	withUnknown := func(typ *T) []byte {
+		data, err := protoutil.Marshal(typ)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return append(data, unknown...)
+	}
+
+	// Add the unknown field to the element type of an array, as well as to
+	// the array itself.
+	var elem T
+	if err := protoutil.Unmarshal(withUnknown(Int4), &elem); err != nil {
+		t.Fatal(err)
+	}
+	for _, data := range [][]byte{
+		withUnknown(Int4),
+		withUnknown(MakeArray(&elem)),
+		withUnknown(MakeTuple([]T{elem, *String})),
+		withUnknown(MakeInterval(IntervalTypeMetadata{Precision: 3, PrecisionIsSet: true})),
+	} {
+		var typ T
+		if err := protoutil.Unmarshal(data, &typ); err != nil {
+			t.Fatal(err)
+		}
+		roundtrip, err := protoutil.Marshal(&typ)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(data, roundtrip) {
+			t.Errorf("%s: unrecognized fields were not preserved\nexpected: %x\nactual:   %x",
+				typ.DebugString(), data, roundtrip)
+		}
+	}
+}
+
+// TestFileDescriptorRegistration checks that the file descriptor of types.proto
+// is registered at init without being decompressed or copied: the registry
+// holds the generated gzipped bytes, which are only decompressed when the
+// descriptor is requested through reflection. Registration is therefore
+// already lazy, and adds no decompression cost to binary startup.
+func TestFileDescriptorRegistration(t *testing.T) {
+	gz, indexes := (&InternalType{}).Descriptor()
+	registered := proto.FileDescriptor("sql/types/types.proto")
+	if len(registered) == 0 || &registered[0] != &gz[0] {
+		t.Fatal("expected the generated descriptor bytes to be registered as is")
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(gz))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fd descriptor.FileDescriptorProto
+	if err := proto.Unmarshal(b, &fd); err != nil {
+		t.Fatal(err)
+	}
+	if name := fd.MessageType[indexes[0]].GetName(); name != "InternalType" {
+		t.Errorf("expected InternalType descriptor, got %s", name)
+	}
+}
+
+func TestArrayDimensionsEncoding(t *testing.T) {
+	dims := []int32{-1, 3, 300}
+	withDims := InternalType{Family: ArrayFamily, ArrayDimensions: dims}
+	packed, err := protoutil.Marshal(&withDims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	base, err := protoutil.Marshal(&InternalType{Family: ArrayFamily})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The unpacked form has a key (field 4, wire type 0) per dimension, while
+	// the packed form has a single key (field 4, wire type 2).
+	var unpacked []byte
+	var buf [binary.MaxVarintLen64]byte
+	unpacked = append(unpacked, base...)
+	for _, d := range dims {
+		unpacked = append(unpacked, 0x20)
+		unpacked = append(unpacked, buf[:binary.PutUvarint(buf[:], uint64(d))]...)
+	}
+	if bytes.Count(packed, []byte{0x22}) == 0 || len(packed) >= len(unpacked) {
+		t.Errorf("expected packed encoding, got %x", packed)
+	}
+
+	// Add a field after the packed dimensions that contains many bytes that
+	// look like the last bytes of varints, to check that only the bytes of the
+	// packed field are used to size the decoded slice.
+	locale := strings.Repeat("a", 100)
+	trailing := append(append([]byte(nil), packed...), 0x2a, byte(len(locale)))
+	trailing = append(trailing, locale...)
+
+	for _, data := range [][]byte{packed, unpacked, trailing} {
+		var it InternalType
+		if err := protoutil.Unmarshal(data, &it); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(it.ArrayDimensions, dims) {
+			t.Errorf("expected dimensions %v, got %v", dims, it.ArrayDimensions)
+		}
+		if cap(it.ArrayDimensions) > 2*len(dims) {
+			t.Errorf("expected capacity at most %d, got %d", 2*len(dims), cap(it.ArrayDimensions))
+		}
+	}
+}
+
 func TestOids(t *testing.T) {
 	for o, typ := range OidToType {
 		if typ.Oid() != o {
@@ -519,3 +703,1045 @@ func TestOids(t *testing.T) {
 		}
 	}
 }
+
+func TestTimePrecision(t *testing.T) {
+	testCases := []struct {
+		typ       *T
+		sqlString string
+		pgName    string
+	}{
+		{Time, "TIME", "time without time zone"},
+		{MakeTime(0), "TIME(0)", "time(0) without time zone"},
+		{MakeTime(3), "TIME(3)", "time(3) without time zone"},
+		{MakeTime(6), "TIME(6)", "time(6) without time zone"},
+	}
+
+	for _, tc := range testCases {
+		if actual := tc.typ.SQLString(); actual != tc.sqlString {
+			t.Errorf("expected SQL string %q, got %q", tc.sqlString, actual)
+		}
+		typmod := -1
+		if tc.typ.TimePrecisionIsSet() {
+			typmod = int(tc.typ.Precision())
+		}
+		if actual := tc.typ.SQLStandardNameWithTypmod(true, typmod); actual != tc.pgName {
+			t.Errorf("expected standard name %q, got %q", tc.pgName, actual)
+		}
+	}
+
+	if Time.Equivalent(MakeTime(0)) != true {
+		t.Errorf("expected TIME to be equivalent to TIME(0)")
+	}
+	if Time.Identical(MakeTime(0)) {
+		t.Errorf("expected TIME to not be identical to TIME(0)")
+	}
+}
+
+func TestStringKind(t *testing.T) {
+	testCases := []struct {
+		typ         *T
+		kind        StringKind
+		blankPadded bool
+	}{
+		{String, TextKind, false},
+		{MakeString(10), TextKind, false},
+		{VarChar, VarCharKind, false},
+		{MakeVarChar(10), VarCharKind, false},
+		{typeBpChar, BpCharKind, true},
+		{MakeChar(10), BpCharKind, true},
+		{MakeCollatedString(MakeChar(10), "en"), BpCharKind, true},
+		{MakeCollatedString(VarChar, "en"), VarCharKind, false},
+		{typeQChar, QCharKind, false},
+		{Name, NameKind, false},
+		{Jsonpath, JSONPathKind, false},
+		{RefCursor, RefCursorKind, false},
+		{PGLSN, PGLSNKind, false},
+		{MACAddr, MACAddrKind, false},
+		{MACAddr8, MACAddr8Kind, false},
+		{Int, NonStringKind, false},
+		{Bytes, NonStringKind, false},
+		{MakeArray(MakeChar(10)), NonStringKind, false},
+	}
+
+	for _, tc := range testCases {
+		if actual := tc.typ.StringKind(); actual != tc.kind {
+			t.Errorf("%s: expected %s, got %s", tc.typ.SQLString(), tc.kind, actual)
+		}
+		if actual := tc.typ.IsBlankPadded(); actual != tc.blankPadded {
+			t.Errorf("%s: expected IsBlankPadded=%t, got %t", tc.typ.SQLString(), tc.blankPadded, actual)
+		}
+		trimmed := "a "
+		if tc.blankPadded {
+			trimmed = "a"
+		}
+		if actual := tc.typ.TrimBlankPadding("a "); actual != trimmed {
+			t.Errorf("%s: expected TrimBlankPadding=%q, got %q", tc.typ.SQLString(), trimmed, actual)
+		}
+	}
+}
+
+func TestTupleLabels(t *testing.T) {
+	labeled := MakeLabeledTuple([]T{*Int, *String, *Bool}, []string{"a", "b", "c"})
+	unlabeled := MakeTuple([]T{*Int, *String})
+
+	if idx := labeled.TupleLabelIndex("b"); idx != 1 {
+		t.Errorf("expected index 1, got %d", idx)
+	}
+	if idx := labeled.TupleLabelIndex("d"); idx != -1 {
+		t.Errorf("expected index -1, got %d", idx)
+	}
+	if idx := unlabeled.TupleLabelIndex(""); idx != -1 {
+		t.Errorf("expected index -1, got %d", idx)
+	}
+	if idx := Int.TupleLabelIndex("a"); idx != -1 {
+		t.Errorf("expected index -1, got %d", idx)
+	}
+
+	var labels []string
+	var fieldTypes []*T
+	labeled.ForEachTupleField(func(i int, label string, typ *T) {
+		if label != labeled.TupleLabel(i) {
+			t.Errorf("expected label %q, got %q", labeled.TupleLabel(i), label)
+		}
+		labels = append(labels, label)
+		fieldTypes = append(fieldTypes, typ)
+	})
+	if !reflect.DeepEqual(labels, []string{"a", "b", "c"}) {
+		t.Errorf("unexpected labels %v", labels)
+	}
+	if len(fieldTypes) != 3 || fieldTypes[2] != &labeled.TupleContents()[2] {
+		t.Errorf("unexpected field types %v", fieldTypes)
+	}
+	unlabeled.ForEachTupleField(func(i int, label string, typ *T) {
+		if label != "" {
+			t.Errorf("expected empty label, got %q", label)
+		}
+		if !typ.Identical(&unlabeled.TupleContents()[i]) {
+			t.Errorf("expected %s, got %s", unlabeled.TupleContents()[i].SQLString(), typ.SQLString())
+		}
+	})
+
+	testCases := []struct {
+		numFields int
+		labels    []string
+		err       string
+	}{
+		{2, nil, ""},
+		{2, []string{"a", "b"}, ""},
+		{2, []string{"a"}, "mismatch in tuple definition: 2 expressions, 1 labels"},
+		{1, []string{"a", "b"}, "mismatch in tuple definition: 1 expressions, 2 labels"},
+		{3, []string{"a", "b", "a"}, `found duplicate tuple label: "a"`},
+		{2, []string{"A b", "A b"}, `found duplicate tuple label: "\"A b\""`},
+	}
+	for _, tc := range testCases {
+		err := ValidateTupleLabels(tc.numFields, tc.labels)
+		if tc.err == "" {
+			if err != nil {
+				t.Errorf("%v: unexpected error: %v", tc.labels, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != tc.err {
+			t.Errorf("%v: expected error %q, got %v", tc.labels, tc.err, err)
+		} else if code := pgerror.GetPGCode(err); code != pgcode.Syntax {
+			t.Errorf("%v: expected code %s, got %s", tc.labels, pgcode.Syntax, code)
+		}
+	}
+}
+
+func TestFieldByPath(t *testing.T) {
+	inner := MakeLabeledTuple([]T{*Int, *MakeArray(String)}, []string{"x", "y"})
+	outer := MakeLabeledTuple([]T{*Bool, *inner}, []string{"a", "b"})
+	unlabeled := MakeTuple([]T{*outer, *Date})
+
+	indexCases := []struct {
+		typ      *T
+		path     []int
+		expected *T
+		err      string
+	}{
+		{outer, nil, outer, ""},
+		{outer, []int{0}, Bool, ""},
+		{outer, []int{1, 1}, MakeArray(String), ""},
+		{unlabeled, []int{0, 1, 0}, Int, ""},
+		{unlabeled, []int{1}, Date, ""},
+		{outer, []int{2}, nil, "field index 2 is out of range for type tuple{bool AS a, " +
+			"tuple{int AS x, string[] AS y} AS b}"},
+		{outer, []int{-1}, nil, "field index -1 is out of range for type tuple{bool AS a, " +
+			"tuple{int AS x, string[] AS y} AS b}"},
+		{outer, []int{0, 0}, nil, "type bool is not composite"},
+		{Int, []int{0}, nil, "type int is not composite"},
+	}
+	for _, tc := range indexCases {
+		actual, err := tc.typ.FieldByPath(tc.path)
+		if tc.err != "" {
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("%v: expected error %q, got %v", tc.path, tc.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", tc.path, err)
+		} else if !actual.Identical(tc.expected) {
+			t.Errorf("%v: expected %s, got %s", tc.path, tc.expected.DebugString(), actual.DebugString())
+		}
+	}
+
+	labelCases := []struct {
+		typ      *T
+		labels   []string
+		expected *T
+		err      string
+	}{
+		{outer, nil, outer, ""},
+		{outer, []string{"a"}, Bool, ""},
+		{outer, []string{"b", "y"}, MakeArray(String), ""},
+		{outer, []string{"b", "z"}, nil, `could not identify column "z" in tuple{int AS x, string[] AS y}`},
+		{outer, []string{"a", "x"}, nil, "type bool is not composite"},
+		{unlabeled, []string{"a"}, nil, "type tuple{tuple{bool AS a, tuple{int AS x, string[] AS y} AS b}, " +
+			"date} is not composite"},
+	}
+	for _, tc := range labelCases {
+		actual, err := tc.typ.FieldByLabelPath(tc.labels)
+		if tc.err != "" {
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("%v: expected error %q, got %v", tc.labels, tc.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", tc.labels, err)
+		} else if !actual.Identical(tc.expected) {
+			t.Errorf("%v: expected %s, got %s", tc.labels, tc.expected.DebugString(), actual.DebugString())
+		}
+	}
+}
+
+func TestEquivalentIgnoringAlias(t *testing.T) {
+	// Types as they may be unmarshaled from a descriptor written by a previous
+	// version, before they are upgraded.
+	legacyInt4 := &T{InternalType: InternalType{Family: IntFamily, Width: 32}}
+	legacyFloat4 := &T{InternalType: InternalType{Family: FloatFamily, Precision: 20}}
+	legacyFloat8 := &T{InternalType: InternalType{Family: FloatFamily, Precision: 40}}
+	legacyInt4Array := &T{InternalType: InternalType{
+		Family: ArrayFamily, Oid: oid.T__int4, ArrayContents: legacyInt4}}
+
+	testCases := []struct {
+		a, b     *T
+		expected bool
+	}{
+		{Int4, Int4, true},
+		{Int4, legacyInt4, true},
+		{Int4, Int, false},
+		{Float4, legacyFloat4, true},
+		{Float, legacyFloat8, true},
+		{Float4, Float, false},
+		{VarChar, String, true},
+		{MakeVarChar(10), MakeString(10), true},
+		{MakeVarChar(10), String, false},
+		{MakeChar(10), MakeString(10), false},
+		{legacyInt4Array, MakeArray(Int4), true},
+		{MakeArray(VarChar), StringArray, true},
+		{MakeArray(Int4), IntArray, false},
+		{MakeTuple([]T{*legacyInt4, *VarChar}), MakeTuple([]T{*Int4, *String}), true},
+		{MakeTuple([]T{*Int4}), MakeTuple([]T{*Int}), false},
+		{MakeLabeledTuple([]T{*Int}, []string{"a"}), MakeTuple([]T{*Int}), false},
+		{MakeDecimal(10, 2), MakeDecimal(10, 3), false},
+		{MakeCollatedString(VarChar, "en"), MakeCollatedString(String, "en"), true},
+		{MakeCollatedString(String, "en"), MakeCollatedString(String, "de"), false},
+	}
+
+	for _, tc := range testCases {
+		if actual := tc.a.EquivalentIgnoringAlias(tc.b); actual != tc.expected {
+			t.Errorf("%s vs %s: expected %v, got %v",
+				tc.a.DebugString(), tc.b.DebugString(), tc.expected, actual)
+		}
+		if actual := tc.b.EquivalentIgnoringAlias(tc.a); actual != tc.expected {
+			t.Errorf("%s vs %s: expected %v, got %v",
+				tc.b.DebugString(), tc.a.DebugString(), tc.expected, actual)
+		}
+	}
+}
+
+var rewriteWireCorpus = flag.Bool(
+	"rewrite-wire-corpus", false,
+	"rewrite testdata/wire_compat with the current encoding of each type. This must "+
+		"only be done for intentional, backwards-compatible changes to the wire format; "+
+		"please verify the diff carefully!",
+)
+
+const wireCompatFile = "testdata/wire_compat"
+
+// wireCompatCorpus returns the types that are checked by TestWireCompat. It
+// covers every type family and OID, as well as type attributes and nesting.
+// New types may be added to the corpus, but existing entries in the testdata
+// file must never be removed, since they may be found in descriptors written by
+// previous versions.
+func wireCompatCorpus() []*T {
+	var oids []oid.Oid
+	for o := range OidToType {
+		oids = append(oids, o)
+	}
+	sort.Slice(oids, func(i, j int) bool { return oids[i] < oids[j] })
+
+	// OidToType includes the array type of each scalar type. Arrays of the
+	// vector types are skipped, since nested arrays cannot be marshaled.
+	var corpus []*T
+	for _, o := range oids {
+		typ := OidToType[o]
+		if typ.Family() == ArrayFamily && typ.ArrayContents().Family() == ArrayFamily {
+			continue
+		}
+		corpus = append(corpus, typ)
+	}
+
+	enCollate := MakeCollatedString(String, "en")
+	corpus = append(corpus,
+		MakeBit(3), MakeVarBit(3),
+		MakeString(10), MakeVarChar(10), MakeChar(10),
+		enCollate, MakeCollatedString(MakeVarChar(10), "de"),
+		MakeCollatedString(MakeChar(10), "fr"), MakeCollatedString(typeQChar, "en"),
+		MakeDecimal(10, 0), MakeDecimal(10, 3),
+		MakeTime(0), MakeTime(3), MakeTimestamp(0), MakeTimestampTZ(6),
+		MakeInterval(IntervalTypeMetadata{Precision: 3, PrecisionIsSet: true}),
+		MakeInterval(IntervalTypeMetadata{
+			DurationField: IntervalDurationField{DurationType: IntervalDurationType_HOUR},
+		}),
+		MakeInterval(IntervalTypeMetadata{
+			DurationField: IntervalDurationField{
+				DurationType: IntervalDurationType_SECOND, FromDurationType: IntervalDurationType_DAY,
+			},
+			Precision:      3,
+			PrecisionIsSet: true,
+		}),
+		MakeArray(enCollate), MakeArray(MakeVarChar(10)), MakeArray(MakeDecimal(10, 3)),
+		EmptyTuple,
+		MakeTuple([]T{*Int, *String}),
+		MakeLabeledTuple([]T{*Int, *String}, []string{"a", "b"}),
+		MakeTuple([]T{*MakeTuple([]T{*Bool, *enCollate}), *MakeArray(Float4)}),
+		MakeArray(MakeTuple([]T{*Int})),
+	)
+	return corpus
+}
+
+// wireCompatName returns the name of the given type in testdata/wire_compat.
+func wireCompatName(typ *T) string {
+	if IsWildcardTupleType(typ) {
+		return "RECORD"
+	}
+	// Tuples, and arrays of tuples, have no SQL name.
+	if name := typ.SQLString(); name != "" && name != "[]" {
+		return name
+	}
+	return typ.String()
+}
+
+// TestWireCompat checks that the wire encoding of types is stable, by
+// comparing it with the encoding recorded in testdata/wire_compat, and that
+// every recorded encoding can still be decoded. Run with -rewrite-wire-corpus
+// to record the encoding of types that are added to the corpus.
+func TestWireCompat(t *testing.T) {
+	golden := make(map[string][]byte)
+	var goldenNames []string
+	if f, err := os.Open(wireCompatFile); err == nil {
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			parts := strings.Split(line, "\t")
+			if len(parts) != 2 {
+				t.Fatalf("malformed line in %s: %q", wireCompatFile, line)
+			}
+			data, err := hex.DecodeString(parts[1])
+			if err != nil {
+				t.Fatalf("malformed line in %s: %q: %v", wireCompatFile, line, err)
+			}
+			golden[parts[0]] = data
+			goldenNames = append(goldenNames, parts[0])
+		}
+		if err := scanner.Err(); err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+	} else if !*rewriteWireCorpus {
+		t.Fatal(err)
+	}
+
+	// Every recorded encoding must remain decodable.
+	decoded := make(map[string]*T, len(golden))
+	for name, data := range golden {
+		var typ T
+		if err := protoutil.Unmarshal(data, &typ); err != nil {
+			t.Errorf("%s: error decoding recorded encoding: %v", name, err)
+			continue
+		}
+		decoded[name] = &typ
+	}
+
+	seen := make(map[string]bool)
+	for _, typ := range wireCompatCorpus() {
+		name := wireCompatName(typ)
+		if seen[name] {
+			t.Fatalf("duplicate corpus entry: %s", name)
+		}
+		seen[name] = true
+
+		data, err := protoutil.Marshal(typ)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		expected, ok := golden[name]
+		if !ok {
+			if !*rewriteWireCorpus {
+				t.Errorf("%s: missing from %s; run with -rewrite-wire-corpus", name, wireCompatFile)
+			}
+			golden[name] = data
+			goldenNames = append(goldenNames, name)
+			continue
+		}
+		if !bytes.Equal(data, expected) {
+			t.Errorf("%s: wire encoding changed\nexpected: %x\nactual:   %x", name, expected, data)
+		}
+		if typ2, ok := decoded[name]; ok && !typ2.Identical(typ) {
+			t.Errorf("%s: decoded type differs\nexpected: %s\nactual:   %s",
+				name, typ.DebugString(), typ2.DebugString())
+		}
+	}
+
+	if *rewriteWireCorpus {
+		var buf bytes.Buffer
+		buf.WriteString("# Generated by TestWireCompat with -rewrite-wire-corpus. Entries must\n")
+		buf.WriteString("# never be removed or changed. See wireCompatCorpus.\n")
+		for _, name := range goldenNames {
+			fmt.Fprintf(&buf, "%s\t%x\n", name, golden[name])
+		}
+		if err := os.MkdirAll(filepath.Dir(wireCompatFile), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(wireCompatFile, buf.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDebugString(t *testing.T) {
+	typ := MakeLabeledTuple([]T{*Int, *StringArray}, []string{"a", "b"})
+	expected := `family: TupleFamily oid: 2249 (RECORD) width: 0 precision: 0 locale: ""
+  field 0 "a": family: IntFamily oid: 20 (INT8) width: 64 precision: 0 locale: ""
+  field 1 "b": family: ArrayFamily oid: 1009 (_TEXT) width: 0 precision: 0 locale: ""
+    elem: family: StringFamily oid: 25 (TEXT) width: 0 precision: 0 locale: ""`
+	if actual := typ.DebugString(); actual != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, actual)
+	}
+
+	interval := MakeInterval(IntervalTypeMetadata{
+		DurationField: IntervalDurationField{
+			DurationType: IntervalDurationType_SECOND, FromDurationType: IntervalDurationType_DAY,
+		},
+		Precision:      3,
+		PrecisionIsSet: true,
+	})
+	expected = `family: IntervalFamily oid: 1186 (INTERVAL) width: 0 precision: 3 locale: "" ` +
+		`time_precision_is_set: true interval_duration_field: {duration_type:SECOND from_duration_type:DAY }`
+	if actual := interval.DebugString(); actual != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, actual)
+	}
+
+	if actual := Int.CompactDebugString(); strings.Contains(actual, "\n") {
+		t.Errorf("expected single line, got %s", actual)
+	}
+}
+
+func TestDeepCopy(t *testing.T) {
+	orig := MakeLabeledTuple(
+		[]T{*MakeCollatedString(String, "en"), *MakeArray(MakeDecimal(10, 2)), *MakeTuple([]T{*Int})},
+		[]string{"a", "b", "c"},
+	)
+	orig.InternalType.XXX_unrecognized = []byte{0xa0, 0x06, 0x2a}
+	before := orig.DebugString()
+
+	c := orig.DeepCopy()
+	if !c.Identical(orig) || !bytes.Equal(c.InternalType.XXX_unrecognized, orig.InternalType.XXX_unrecognized) {
+		t.Fatalf("expected identical copy, got %s", c.DebugString())
+	}
+
+	// Modifying the copy must not affect the original.
+	*c.TupleContents()[0].InternalType.Locale = "de"
+	c.TupleContents()[1].ArrayContents().InternalType.Precision = 20
+	c.TupleContents()[2].TupleContents()[0] = *String
+	c.InternalType.TupleLabels[0] = "z"
+	c.InternalType.XXX_unrecognized[0] = 0
+	if after := orig.DebugString(); after != before {
+		t.Errorf("original was modified:\n%s", after)
+	}
+}
+
+func TestTypeMigrations(t *testing.T) {
+	defer func(migrations []typeMigration) { typeMigrations = migrations }(typeMigrations)
+	typeMigrations = nil
+
+	typs := []*T{Int, MakeVarChar(10), MakeArray(MakeDecimal(10, 3)), MakeTuple([]T{*Int, *Bool})}
+	v0 := make([][]byte, len(typs))
+	for i, typ := range typs {
+		data, err := protoutil.Marshal(typ)
+		if err != nil {
+			t.Fatal(err)
+		}
+		v0[i] = data
+	}
+
+	// Register a migration to version 1, which records the families of the
+	// types that it is applied to.
+	var migrated []Family
+	typeMigrations = []typeMigration{func(t *T) error {
+		migrated = append(migrated, t.Family())
+		return nil
+	}}
+
+	v1 := make([][]byte, len(typs))
+	for i, typ := range typs {
+		migrated = nil
+		var typ2 T
+		if err := protoutil.Unmarshal(v0[i], &typ2); err != nil {
+			t.Fatalf("%s: %v", typ.DebugString(), err)
+		}
+		if len(migrated) == 0 || migrated[len(migrated)-1] != typ.Family() {
+			t.Errorf("%s: migration was not applied: %v", typ.DebugString(), migrated)
+		}
+		if typ2.InternalType.Version != nil {
+			t.Errorf("%s: version is set after unmarshaling", typ.DebugString())
+		}
+		if !typ2.Identical(typ) {
+			t.Errorf("%s: expected %s after migration", typ2.DebugString(), typ.DebugString())
+		}
+
+		// Types marshaled with version 1 are not migrated again.
+		data, err := protoutil.Marshal(typ)
+		if err != nil {
+			t.Fatal(err)
+		}
+		v1[i] = data
+		migrated = nil
+		var typ3 T
+		if err := protoutil.Unmarshal(data, &typ3); err != nil {
+			t.Fatalf("%s: %v", typ.DebugString(), err)
+		}
+		if len(migrated) != 0 {
+			t.Errorf("%s: migration was applied to latest version: %v", typ.DebugString(), migrated)
+		}
+		if !typ3.Identical(typ) {
+			t.Errorf("%s: expected %s after round trip", typ3.DebugString(), typ.DebugString())
+		}
+	}
+
+	// Migration errors are returned.
+	typeMigrations = []typeMigration{func(t *T) error {
+		return errors.New("boom")
+	}}
+	var typ T
+	if err := protoutil.Unmarshal(v0[0], &typ); err == nil || err.Error() != "error migrating type from version 0: boom" {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// Types serialized with a newer version cannot be unmarshaled.
+	typeMigrations = nil
+	if err := protoutil.Unmarshal(v1[0], &typ); err == nil || !strings.Contains(err.Error(), "newer than the latest version 0") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// These are the numbers of the fields of InternalType that are used by the
+// version skew tests.
+const (
+	tupleContentsField         = 8
+	arrayContentsField         = 11
+	timePrecisionIsSetField    = 12
+	intervalDurationFieldField = 13
+	versionField               = 14
+)
+
+// typeField is a field of the protobuf encoding of a type, including its key.
+type typeField []byte
+
+// number returns the field number of the field.
+func (f typeField) number() uint64 {
+	key, _ := binary.Uvarint(f)
+	return key >> 3
+}
+
+// encodeTypeField returns the encoding of a varint or length-delimited field.
+func encodeTypeField(number uint64, value interface{}) typeField {
+	switch v := value.(type) {
+	case uint64:
+		return appendUvarint(appendUvarint(nil, number<<3), v)
+	case []byte:
+		f := appendUvarint(appendUvarint(nil, number<<3|2), uint64(len(v)))
+		return append(f, v...)
+	}
+	panic(fmt.Sprintf("unsupported field value %T", value))
+}
+
+// rewriteTypeFields decodes the fields of the protobuf encoding of a type, and
+// re-encodes the fields returned by rewrite. The nested types are rewritten
+// first, so that rewrite is applied at every level of nesting, the way a node
+// that runs another version would encode or decode every nested type.
+func rewriteTypeFields(t *testing.T, data []byte, rewrite func([]typeField) []typeField) []byte {
+	var fields []typeField
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			t.Fatalf("invalid field key in %x", data)
+		}
+		end := n
+		switch key & 7 {
+		case 0:
+			_, m := binary.Uvarint(data[n:])
+			if m <= 0 {
+				t.Fatalf("invalid varint in %x", data)
+			}
+			end += m
+		case 2:
+			l, m := binary.Uvarint(data[n:])
+			if m <= 0 || uint64(len(data)) < uint64(n+m)+l {
+				t.Fatalf("invalid length in %x", data)
+			}
+			end += m + int(l)
+			if number := key >> 3; number == tupleContentsField || number == arrayContentsField {
+				nested := rewriteTypeFields(t, data[n+m:end], rewrite)
+				fields = append(fields, encodeTypeField(number, nested))
+				data = data[end:]
+				continue
+			}
+		default:
+			t.Fatalf("unexpected wire type %d in %x", key&7, data)
+		}
+		fields = append(fields, typeField(data[:end]))
+		data = data[end:]
+	}
+
+	var res []byte
+	for _, f := range rewrite(fields) {
+		res = append(res, f...)
+	}
+	return res
+}
+
+// withoutTypeFields returns a rewrite function that removes the given fields.
+func withoutTypeFields(numbers ...uint64) func([]typeField) []typeField {
+	return func(fields []typeField) []typeField {
+		var res []typeField
+		for _, f := range fields {
+			keep := true
+			for _, number := range numbers {
+				if f.number() == number {
+					keep = false
+				}
+			}
+			if keep {
+				res = append(res, f)
+			}
+		}
+		return res
+	}
+}
+
+// TestVersionSkew simulates the encodings of types that are exchanged between
+// nodes that run different versions in a mixed-version cluster, by rewriting
+// the encoding of every type in the wire compatibility corpus the way an older
+// or newer version would, and checks how the rewritten encoding is decoded.
+// When a field is added to InternalType, add a case for the versions that
+// predate it.
+func TestVersionSkew(t *testing.T) {
+	testCases := []struct {
+		name    string
+		rewrite func([]typeField) []typeField
+		// check verifies the result of unmarshaling the rewritten encoding of
+		// the given type, which was written as expected by the current version.
+		check func(typ *T, expected []byte, decoded *T, err error) string
+	}{
+		{
+			// Older versions do not set the serialization version. Their types
+			// must be upgraded to the representation of the current version.
+			name:    "older writer without version",
+			rewrite: withoutTypeFields(versionField),
+			check: func(typ *T, expected []byte, decoded *T, err error) string {
+				if err != nil {
+					return err.Error()
+				}
+				data, err := protoutil.Marshal(decoded)
+				if err != nil {
+					return err.Error()
+				}
+				if !bytes.Equal(data, expected) {
+					return fmt.Sprintf("upgraded type is encoded as %x, expected %x", data, expected)
+				}
+				return ""
+			},
+		},
+		{
+			// Older versions did not know about the explicit precision of TIME
+			// and the qualifiers of INTERVAL. A node that runs such a version
+			// ignores these fields, and must still see a type of the same family
+			// and OID.
+			name: "older reader",
+			rewrite: withoutTypeFields(
+				timePrecisionIsSetField, intervalDurationFieldField, versionField),
+			check: func(typ *T, expected []byte, decoded *T, err error) string {
+				if err != nil {
+					return err.Error()
+				}
+				if !decoded.Equivalent(typ) || decoded.Oid() != typ.Oid() {
+					return fmt.Sprintf("decoded as %s", decoded.DebugString())
+				}
+				return ""
+			},
+		},
+		{
+			// Newer versions may add fields. They must be ignored, and preserved
+			// when the type is encoded again.
+			name: "newer writer with an unknown field",
+			rewrite: func(fields []typeField) []typeField {
+				return append(fields, encodeTypeField(100, uint64(42)))
+			},
+			check: func(typ *T, expected []byte, decoded *T, err error) string {
+				if err != nil {
+					return err.Error()
+				}
+				if !decoded.Identical(typ) {
+					return fmt.Sprintf("decoded as %s", decoded.DebugString())
+				}
+				return ""
+			},
+		},
+		{
+			// Types serialized with a newer version cannot be decoded, since
+			// their representation may have changed in ways that are unknown to
+			// this version.
+			name: "newer writer with the next version",
+			rewrite: func(fields []typeField) []typeField {
+				fields = withoutTypeFields(versionField)(fields)
+				return append(fields, encodeTypeField(versionField, uint64(latestTypeVersion()+1)))
+			},
+			check: func(typ *T, expected []byte, decoded *T, err error) string {
+				if err == nil {
+					return fmt.Sprintf("expected an error, decoded as %s", decoded.DebugString())
+				}
+				if !strings.Contains(err.Error(), "is newer than the latest version") {
+					return fmt.Sprintf("unexpected error: %v", err)
+				}
+				return ""
+			},
+		},
+	}
+
+	// Rewriting the fields as they are must not change the encoding.
+	for _, typ := range wireCompatCorpus() {
+		data, err := protoutil.Marshal(typ)
+		if err != nil {
+			t.Fatal(err)
+		}
+		identity := func(fields []typeField) []typeField { return fields }
+		if rewritten := rewriteTypeFields(t, data, identity); !bytes.Equal(rewritten, data) {
+			t.Fatalf("%s: rewriting changed %x to %x", typ.DebugString(), data, rewritten)
+		}
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, typ := range wireCompatCorpus() {
+				expected, err := protoutil.Marshal(typ)
+				if err != nil {
+					t.Fatal(err)
+				}
+				data := rewriteTypeFields(t, expected, tc.rewrite)
+				var decoded T
+				err = protoutil.Unmarshal(data, &decoded)
+				if msg := tc.check(typ, expected, &decoded, err); msg != "" {
+					t.Errorf("%s: %s", typ.DebugString(), msg)
+				}
+			}
+		})
+	}
+}
+
+func TestSignatureOnlyTypes(t *testing.T) {
+	testCases := []struct {
+		typ      *T
+		sql      string
+		standard string
+	}{
+		{Void, "VOID", "void"},
+		{Trigger, "TRIGGER", "trigger"},
+		{EventTrigger, "EVENT_TRIGGER", "event_trigger"},
+	}
+	for _, tc := range testCases {
+		if typ, ok := TypeForOid(tc.typ.Oid()); !ok || typ != tc.typ {
+			t.Errorf("%s: expected the type for OID %d", tc.sql, tc.typ.Oid())
+		}
+		if actual := tc.typ.SQLString(); actual != tc.sql {
+			t.Errorf("expected SQLString %s, got %s", tc.sql, actual)
+		}
+		if actual := tc.typ.SQLStandardName(); actual != tc.standard {
+			t.Errorf("%s: expected SQLStandardName %s, got %s", tc.sql, tc.standard, actual)
+		}
+		if actual := tc.typ.Typmod(); actual != -1 {
+			t.Errorf("%s: expected typmod -1, got %d", tc.sql, actual)
+		}
+		if !tc.typ.IsSignatureOnly() {
+			t.Errorf("%s: expected a signature-only type", tc.sql)
+		}
+		if tc.typ.IsAmbiguous() {
+			t.Errorf("%s: expected the type not to be ambiguous", tc.sql)
+		}
+		if _, ok := ArrayOid(tc.typ.Oid()); ok {
+			t.Errorf("%s: expected the type not to have an array type", tc.sql)
+		}
+	}
+
+	for _, typ := range []*T{Unknown, Any, Int, MakeArray(String), EmptyTuple} {
+		if typ.IsSignatureOnly() {
+			t.Errorf("%s: expected a type that is not signature-only", typ.SQLString())
+		}
+	}
+}
+
+func TestErrorFormat(t *testing.T) {
+	testCases := []struct {
+		typ      *T
+		expected string
+	}{
+		{MakeVarChar(10), "VARCHAR(10)"},
+		{MakeString(10), "STRING(10)"},
+		{typeBpChar, "CHAR"},
+		{MakeChar(1), "CHAR(1)"},
+		{MakeChar(3), "CHAR(3)"},
+		{MakeCollatedString(MakeChar(1), "en"), "CHAR(1) COLLATE en"},
+		{MakeCollatedString(MakeVarChar(3), "en"), "VARCHAR(3) COLLATE en"},
+		{typeQChar, `"char"`},
+		{MakeBit(1), "BIT(1)"},
+		{MakeBit(3), "BIT(3)"},
+		{VarBit, "VARBIT"},
+		{MakeVarBit(3), "VARBIT(3)"},
+		{Decimal, "DECIMAL"},
+		{MakeDecimal(10, 0), "DECIMAL(10,0)"},
+		{MakeDecimal(10, 2), "DECIMAL(10,2)"},
+		{MakeTime(3), "TIME(3)"},
+		{MakeArray(MakeChar(1)), "CHAR(1)[]"},
+		{MakeArray(MakeDecimal(10, 0)), "DECIMAL(10,0)[]"},
+		{Int2Vector, "INT2VECTOR"},
+		{Int4, "INT4"},
+	}
+	for _, tc := range testCases {
+		if actual := tc.typ.ErrorFormat(); actual != tc.expected {
+			t.Errorf("%s: expected %s, got %s", tc.typ.DebugString(), tc.expected, actual)
+		}
+	}
+}
+
+func TestStringWidthUnit(t *testing.T) {
+	chars := MakeVarChar(3)
+	byteWidth := MakeStringWithWidthUnit(chars, StringWidthUnit_BYTES)
+	if chars.WidthUnit() != StringWidthUnit_CHARACTERS || Int.WidthUnit() != StringWidthUnit_CHARACTERS {
+		t.Errorf("expected widths to be measured in characters by default")
+	}
+	if byteWidth.WidthUnit() != StringWidthUnit_BYTES || chars.WidthUnit() != StringWidthUnit_CHARACTERS {
+		t.Errorf("expected only the new type to be measured in bytes")
+	}
+	if byteWidth.Oid() != oid.T_varchar || byteWidth.Width() != 3 {
+		t.Errorf("expected VARCHAR(3), got %s", byteWidth.DebugString())
+	}
+	if byteWidth.Identical(chars) || byteWidth.Compare(chars) == 0 || !byteWidth.Equivalent(chars) {
+		t.Errorf("expected %s to be equivalent but not identical to %s",
+			byteWidth.DebugString(), chars.DebugString())
+	}
+	if typ := MakeStringWithWidthUnit(byteWidth, StringWidthUnit_CHARACTERS); !typ.Identical(chars) {
+		t.Errorf("expected %s, got %s", chars.DebugString(), typ.DebugString())
+	}
+	collated := MakeCollatedString(byteWidth, "en")
+	if collated.WidthUnit() != StringWidthUnit_BYTES {
+		t.Errorf("expected the collated string to keep the width unit: %s", collated.DebugString())
+	}
+
+	fitsTestData := []struct {
+		typ      *T
+		s        string
+		expected bool
+	}{
+		{chars, "abc", true},
+		{chars, "été", true},
+		{chars, "abcd", false},
+		{byteWidth, "abc", true},
+		{byteWidth, "été", false},
+		{byteWidth, "ét", true},
+		{collated, "été", false},
+		{MakeStringWithWidthUnit(String, StringWidthUnit_BYTES), "été", true},
+	}
+	for _, tc := range fitsTestData {
+		if actual := tc.typ.StringFitsWidth(tc.s); actual != tc.expected {
+			t.Errorf("%s: expected StringFitsWidth(%q) = %t", tc.typ.DebugString(), tc.s, tc.expected)
+		}
+	}
+
+	// The width unit survives the encodings and copies of the type.
+	for _, typ := range []*T{byteWidth, collated, MakeArray(byteWidth)} {
+		data, err := protoutil.Marshal(typ)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var unmarshaled T
+		if err := protoutil.Unmarshal(data, &unmarshaled); err != nil || !unmarshaled.Identical(typ) {
+			t.Errorf("%s: expected identical type after roundtrip, got %s (%v)",
+				typ.DebugString(), unmarshaled.DebugString(), err)
+		}
+		if c := typ.DeepCopy(); !c.Identical(typ) {
+			t.Errorf("%s: expected identical copy, got %s", typ.DebugString(), c.DebugString())
+		}
+		data, err = json.Marshal(MakeColumnTypeMetadata(typ))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if decoded, err := DecodeColumnTypeMetadata(data); err != nil || !decoded.Identical(typ) {
+			t.Errorf("%s: expected identical type after metadata roundtrip of %s, got %v (%v)",
+				typ.DebugString(), data, decoded, err)
+		}
+	}
+	if s, ok := byteWidth.Scalar(); !ok || !s.T().Identical(byteWidth) || s.WidthUnit() != StringWidthUnit_BYTES {
+		t.Errorf("expected scalar roundtrip of %s", byteWidth.DebugString())
+	}
+}
+
+// benchmarkTypes returns the types used by the benchmarks of the
+// representation of types: a scalar type, types with modifiers, a tuple nested
+// 16 levels deep, and a labeled tuple of 1000 elements and an array of it.
+func benchmarkTypes() []struct {
+	name string
+	typ  *T
+} {
+	deep := Int
+	for i := 0; i < 16; i++ {
+		deep = MakeTuple([]T{*deep, *String})
+	}
+	contents := make([]T, 1000)
+	labels := make([]string, len(contents))
+	for i := range contents {
+		contents[i] = *[]*T{Int, MakeVarChar(20), MakeDecimal(10, 2), TimestampTZ}[i%4]
+		labels[i] = fmt.Sprintf("col%d", i)
+	}
+	wide := MakeLabeledTuple(contents, labels)
+
+	return []struct {
+		name string
+		typ  *T
+	}{
+		{"int", Int},
+		{"collated-string", MakeCollatedString(MakeVarChar(20), "en_US")},
+		{"decimal-array", MakeArray(MakeDecimal(10, 2))},
+		{"deep-tuple", deep},
+		{"wide-tuple", wide},
+		{"wide-tuple-array", MakeArray(wide)},
+	}
+}
+
+func BenchmarkMarshal(b *testing.B) {
+	for _, bc := range benchmarkTypes() {
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := protoutil.Marshal(bc.typ); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(bc.name+"/append", func(b *testing.B) {
+			buf := make([]byte, 0, bc.typ.Size())
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := bc.typ.MarshalAppend(buf); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(bc.name+"/compact", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := bc.typ.MarshalCompact(nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkUnmarshal(b *testing.B) {
+	for _, bc := range benchmarkTypes() {
+		data, err := protoutil.Marshal(bc.typ)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(bc.name, func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				var typ T
+				if err := protoutil.Unmarshal(data, &typ); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		compact, err := bc.typ.MarshalCompact(nil)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(bc.name+"/compact", func(b *testing.B) {
+			b.SetBytes(int64(len(compact)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := UnmarshalCompact(compact); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkSize(b *testing.B) {
+	for _, bc := range benchmarkTypes() {
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = bc.typ.Size()
+			}
+		})
+	}
+}
+
+func BenchmarkEquivalent(b *testing.B) {
+	for _, bc := range benchmarkTypes() {
+		// Compare with a copy, so that the nested types are compared as well.
+		other := bc.typ.DeepCopy()
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if !bc.typ.Equivalent(other) {
+					b.Fatal("expected types to be equivalent")
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkTypeForOid(b *testing.B) {
+	var oids []oid.Oid
+	for _, typ := range AllTypes() {
+		oids = append(oids, typ.Oid())
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, ok := TypeForOid(oids[i%len(oids)]); !ok {
+			b.Fatal("expected type")
+		}
+	}
+}
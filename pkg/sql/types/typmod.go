@@ -0,0 +1,163 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/lib/pq/oid"
+)
+
+// varHeaderSize is the size of the header of variable-length values in
+// Postgres, which is included in the typmod of bounded string and numeric
+// types.
+const varHeaderSize = 4
+
+// Typmod returns the Postgres type modifier (typmod) of the type, as reported
+// by the atttypmod column of pg_attribute. Postgres clients combine it with the
+// type OID to reconstruct the lengths and precisions of the type:
+//
+//   VARCHAR(n), CHAR(n), STRING(n)   n + 4
+//   BYTES(n)                         n + 4
+//   BIT(n), VARBIT(n)                n
+//   DECIMAL(p,s)                     ((p << 16) | s) + 4
+//   TIME(p), TIMESTAMP(p)            p
+//   INTERVAL                         see IntervalTypmod
+//
+// Typmod returns -1 if the type has no modifier. MakeTypeFromTypmod performs
+// the reverse conversion.
+func (t *T) Typmod() int32 {
+	switch t.Family() {
+	case StringFamily, CollatedStringFamily:
+		switch t.StringKind() {
+		case QCharKind, NameKind, JSONPathKind, RefCursorKind, PGLSNKind, MACAddrKind, MACAddr8Kind:
+			// Type modifiers are only allowed for the character string types
+			// other than "char" and name.
+			return -1
+		}
+		if t.Width() > 0 {
+			return t.Width() + varHeaderSize
+		}
+
+	case BytesFamily:
+		if t.Width() > 0 {
+			return t.Width() + varHeaderSize
+		}
+
+	case BitFamily:
+		if t.Width() > 0 {
+			return t.Width()
+		}
+
+	case DecimalFamily:
+		if t.Precision() > 0 {
+			return ((t.Precision() << 16) | t.Scale()) + varHeaderSize
+		}
+
+	case TimeFamily:
+		if t.TimePrecisionIsSet() {
+			// Unlike other types, a typmod of 0 is meaningful for TIME.
+			return t.Precision()
+		}
+
+	case TimestampFamily, TimestampTZFamily:
+		// The default precision is -1, so that TIMESTAMP(0) has a typmod.
+		if t.Precision() >= 0 {
+			return t.Precision()
+		}
+
+	case IntervalFamily:
+		return t.IntervalTypmod()
+	}
+	return -1
+}
+
+// MakeTypeFromTypmod constructs the type identified by the given type OID and
+// Postgres type modifier (typmod), such as the values of the atttypid and
+// atttypmod columns of pg_attribute. A negative typmod results in the type
+// without a modifier. It returns an error if the OID is unknown, or if the
+// typmod is not valid for the type.
+func MakeTypeFromTypmod(o oid.Oid, typmod int32) (*T, error) {
+	typ, ok := TypeForOid(o)
+	if !ok {
+		return nil, pgerror.Newf(pgcode.UndefinedObject, "type with OID %d does not exist", o)
+	}
+	if typmod < 0 {
+		return typ, nil
+	}
+	invalid := func() error {
+		return pgerror.Newf(pgcode.InvalidParameterValue,
+			"invalid type modifier %d for type %s", typmod, typ.SQLStandardName())
+	}
+
+	switch typ.Family() {
+	case StringFamily:
+		switch typ.StringKind() {
+		case TextKind, VarCharKind, BpCharKind:
+			if typmod <= varHeaderSize {
+				return nil, invalid()
+			}
+			res := *typ
+			res.InternalType.Width = typmod - varHeaderSize
+			return &res, nil
+		}
+
+	case BytesFamily:
+		// Postgres does not allow type modifiers for bytea. The modifier is the
+		// maximum length of BYTES(n) types, plus the header size like VARCHAR(n).
+		if ValidateBytesWidth(typmod-varHeaderSize) != nil {
+			return nil, invalid()
+		}
+		return MakeBytes(typmod - varHeaderSize), nil
+
+	case BitFamily:
+		if typmod == 0 {
+			return nil, invalid()
+		}
+		if typ.Oid() == oid.T_varbit {
+			return MakeVarBit(typmod), nil
+		}
+		return MakeBit(typmod), nil
+
+	case DecimalFamily:
+		typmod -= varHeaderSize
+		precision, scale := (typmod>>16)&0xFFFF, typmod&0xFFFF
+		if typmod < 0 || precision == 0 || scale > precision {
+			return nil, invalid()
+		}
+		return MakeDecimal(precision, scale), nil
+
+	case TimeFamily:
+		if typmod > MaxTimePrecision {
+			return nil, invalid()
+		}
+		return MakeTime(typmod), nil
+
+	case TimestampFamily, TimestampTZFamily:
+		// Only the default precisions are currently supported.
+		if typmod != 0 && typmod != 6 {
+			return nil, invalid()
+		}
+		if typ.Family() == TimestampFamily {
+			return MakeTimestamp(typmod), nil
+		}
+		return MakeTimestampTZ(typmod), nil
+
+	case IntervalFamily:
+		res, err := MakeIntervalFromTypmod(typmod)
+		if err != nil {
+			return nil, pgerror.Wrapf(err, pgcode.InvalidParameterValue, "invalid type modifier")
+		}
+		return res, nil
+	}
+	return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+		"type modifier is not allowed for type %s", typ.SQLStandardName())
+}
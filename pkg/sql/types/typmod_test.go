@@ -0,0 +1,89 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"testing"
+
+	"github.com/lib/pq/oid"
+)
+
+func TestTypmod(t *testing.T) {
+	testCases := []struct {
+		typ    *T
+		typmod int32
+	}{
+		{Int, -1},
+		{String, -1},
+		{MakeString(10), 14},
+		{MakeVarChar(20), 24},
+		{MakeChar(3), 7},
+		{MakeCollatedString(MakeVarChar(5), "en"), 9},
+		{typeQChar, -1},
+		{Name, -1},
+		{Jsonpath, -1},
+		{RefCursor, -1},
+		{MACAddr, -1},
+		{MakeBit(5), 5},
+		{MakeVarBit(8), 8},
+		{VarBit, -1},
+		{Decimal, -1},
+		{MakeDecimal(10, 0), 655364},
+		{MakeDecimal(10, 7), 655371},
+		{Time, -1},
+		{MakeTime(0), 0},
+		{MakeTime(3), 3},
+		{Timestamp, -1},
+		{MakeTimestamp(0), 0},
+		{MakeTimestamp(6), 6},
+		{MakeTimestampTZ(6), 6},
+		{Interval, -1},
+		{MakeInterval(IntervalTypeMetadata{Precision: 3, PrecisionIsSet: true}), 2147418115},
+	}
+
+	for _, tc := range testCases {
+		typmod := tc.typ.Typmod()
+		if typmod != tc.typmod {
+			t.Errorf("%s: expected typmod %d, got %d", tc.typ.SQLString(), tc.typmod, typmod)
+			continue
+		}
+		if tc.typ.Family() == CollatedStringFamily {
+			continue
+		}
+		typ, err := MakeTypeFromTypmod(tc.typ.Oid(), typmod)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.typ.SQLString(), err)
+		} else if !typ.Identical(tc.typ) {
+			t.Errorf("%s: expected %s, got %s", tc.typ.SQLString(), tc.typ.DebugString(), typ.DebugString())
+		}
+	}
+
+	errCases := []struct {
+		oid    oid.Oid
+		typmod int32
+	}{
+		{oid.T_int8, 10},
+		{oid.T_varchar, 4},
+		{oid.T_name, 10},
+		{oid.T_bit, 0},
+		{oid.T_numeric, 3},
+		{oid.T_numeric, (5<<16 | 7) + 4},
+		{oid.T_time, 7},
+		{oid.T_timestamp, 3},
+		{oid.T_interval, 0x7FFF<<16 | 10},
+		{oid.Oid(123456), -1},
+	}
+	for _, tc := range errCases {
+		if typ, err := MakeTypeFromTypmod(tc.oid, tc.typmod); err == nil {
+			t.Errorf("%d, %d: expected error, got %s", tc.oid, tc.typmod, typ.SQLString())
+		}
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// UnifyError is the structured error returned by Unify and Constrain when a
+// single type cannot be chosen for a set of candidate types. Callers can
+// recover it from the returned error with errors.UnwrapAll in order to produce
+// a more specific message, e.g. one that names the placeholder involved.
+type UnifyError struct {
+	// Candidates are the non-NULL candidate types that were considered. It is
+	// empty if there were no such candidates.
+	Candidates []*T
+	// Ambiguous is true if the types could not be unified because there was no
+	// candidate, or because several inequivalent candidates were equally good.
+	// It is false if the candidates conflict with one another.
+	Ambiguous bool
+}
+
+// Error implements the error interface.
+func (e *UnifyError) Error() string {
+	if len(e.Candidates) == 0 {
+		return "could not determine data type"
+	}
+	var buf bytes.Buffer
+	for i, typ := range e.Candidates {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(typ.SQLString())
+	}
+	if e.Ambiguous {
+		return fmt.Sprintf("ambiguous data type: could be any of %s", buf.String())
+	}
+	return fmt.Sprintf("conflicting data types: %s", buf.String())
+}
+
+// newUnifyError returns a UnifyError annotated with the matching pgcode.
+func newUnifyError(candidates []*T, ambiguous bool) error {
+	code := pgcode.DatatypeMismatch
+	if ambiguous {
+		code = pgcode.IndeterminateDatatype
+	}
+	return pgerror.WithCandidateCode(&UnifyError{Candidates: candidates, Ambiguous: ambiguous}, code)
+}
+
+// Unify returns the least common supertype of the given candidate types, such
+// as the types observed in the different contexts in which a placeholder is
+// used. The supertype is the candidate to which every other candidate can be
+// implicitly cast, as defined by CastContext. For example, the supertype of
+// INT and DECIMAL is DECIMAL, and the supertype of DATE and TIMESTAMPTZ is
+// TIMESTAMPTZ. When several equivalent candidates qualify, such as INT2 and
+// INT8, the widest one is chosen.
+//
+// NULL and wildcard candidates (UnknownFamily and AnyFamily) are ignored, as
+// they do not constrain the result. If no candidate remains, if the
+// candidates have no common supertype, or if several inequivalent candidates
+// qualify, Unify returns an error wrapping a *UnifyError.
+func Unify(candidates []*T) (*T, error) {
+	known := make([]*T, 0, len(candidates))
+	for _, typ := range candidates {
+		if !typ.IsAmbiguous() {
+			known = append(known, typ)
+		}
+	}
+	if len(known) == 0 {
+		return nil, newUnifyError(nil, true /* ambiguous */)
+	}
+
+	var best *T
+	for _, super := range known {
+		if !isSupertypeOf(super, known) {
+			continue
+		}
+		switch {
+		case best == nil:
+			best = super
+		case best.Equivalent(super):
+			if isWider(super, best) {
+				best = super
+			}
+		default:
+			return nil, newUnifyError(known, true /* ambiguous */)
+		}
+	}
+	if best == nil {
+		return nil, newUnifyError(known, false /* ambiguous */)
+	}
+	return best, nil
+}
+
+// LUB returns the least upper bound of the given types: the common supertype
+// to which the results of the branches of a CASE expression, the columns of the
+// inputs of a UNION, or the rows of a VALUES clause are converted. The rules
+// are applied in the following order of precedence:
+//
+//   1. NULL absorption: UNKNOWN types are ignored, since NULL can be converted
+//      to any type. If every type is UNKNOWN, or there are no types, the result
+//      is UNKNOWN.
+//   2. Supertype selection: the remaining types are unified by Unify, which
+//      implements numeric promotion along INT -> DECIMAL -> FLOAT (INT2 ->
+//      INT4 -> INT8 and FLOAT4 -> FLOAT8 within a family), as well as
+//      DATE -> TIMESTAMP -> TIMESTAMPTZ.
+//   3. Widening: the result is widened so that it can hold the values of every
+//      type. DECIMAL types with different precisions or scales widen to the
+//      unconstrained DECIMAL. String types of different kinds, such as CHAR(3)
+//      and VARCHAR(5), widen to STRING, keeping the locale of a collated
+//      string. Array types are widened element by element.
+//
+// LUB returns an error wrapping a *UnifyError if the types have no common
+// supertype. Unlike Unify, it does not return an error when all types are
+// UNKNOWN.
+func LUB(typs ...*T) (*T, error) {
+	known := make([]*T, 0, len(typs))
+	for _, typ := range typs {
+		if typ.Family() != UnknownFamily {
+			known = append(known, typ)
+		}
+	}
+	if len(known) == 0 {
+		return Unknown, nil
+	}
+
+	res, err := Unify(known)
+	if err != nil {
+		return nil, err
+	}
+
+	switch res.Family() {
+	case DecimalFamily:
+		for _, typ := range known {
+			if typ.Family() == DecimalFamily &&
+				(typ.Precision() != res.Precision() || typ.Scale() != res.Scale()) {
+				return Decimal, nil
+			}
+		}
+
+	case StringFamily, CollatedStringFamily:
+		for _, typ := range known {
+			if typ.StringKind() != NonStringKind && typ.StringKind() != res.StringKind() {
+				if res.Family() == CollatedStringFamily {
+					return MakeCollatedString(String, res.Locale()), nil
+				}
+				return String, nil
+			}
+		}
+
+	case ArrayFamily:
+		contents := make([]*T, 0, len(known))
+		for _, typ := range known {
+			if typ.Family() == ArrayFamily {
+				contents = append(contents, typ.ArrayContents())
+			}
+		}
+		elem, err := LUB(contents...)
+		if err != nil {
+			return nil, err
+		}
+		if !elem.Identical(res.ArrayContents()) {
+			return MakeArray(elem), nil
+		}
+	}
+	return res, nil
+}
+
+// Constrain narrows the type previously inferred for an expression, such as a
+// placeholder, with an additional type requirement. If prev is nil, there is
+// no previous inference and typ is returned. Otherwise the two types must be
+// equivalent, in which case prev is kept; if they are not, Constrain returns
+// an error wrapping a *UnifyError.
+func Constrain(prev, typ *T) (*T, error) {
+	if prev == nil {
+		return typ, nil
+	}
+	if !prev.Equivalent(typ) {
+		return nil, newUnifyError([]*T{prev, typ}, false /* ambiguous */)
+	}
+	return prev, nil
+}
+
+// isSupertypeOf returns true if every one of the given types can be implicitly
+// cast to super.
+func isSupertypeOf(super *T, types []*T) bool {
+	for _, typ := range types {
+		if typ != super && !super.Equivalent(typ) && !CanCastImplicit(typ, super) {
+			return false
+		}
+	}
+	return true
+}
+
+// isWider returns true if a can hold more values than the equivalent type b,
+// based on their widths. A width of zero means that there is no limit.
+func isWider(a, b *T) bool {
+	if a.Width() == 0 || b.Width() == 0 {
+		return a.Width() == 0 && b.Width() != 0
+	}
+	return a.Width() > b.Width()
+}
@@ -0,0 +1,155 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/errors"
+)
+
+func TestUnify(t *testing.T) {
+	testCases := []struct {
+		candidates []*T
+		expected   *T
+		// ambiguous is only checked if expected is nil.
+		ambiguous bool
+	}{
+		{[]*T{Int}, Int, false},
+		{[]*T{Unknown, Int, Unknown}, Int, false},
+		{[]*T{Any, String}, String, false},
+		{[]*T{Int2, Int, Int4}, Int, false},
+		{[]*T{Int, Decimal}, Decimal, false},
+		{[]*T{Int, Decimal, Float}, Float, false},
+		{[]*T{Date, TimestampTZ, Timestamp}, TimestampTZ, false},
+		{[]*T{MakeVarChar(10), String}, String, false},
+		{[]*T{IntArray, MakeArray(Decimal)}, MakeArray(Decimal), false},
+		{nil, nil, true},
+		{[]*T{Unknown}, nil, true},
+		{[]*T{String, MakeCollatedString(String, "en")}, nil, true},
+		{[]*T{Int, String}, nil, false},
+		{[]*T{Uuid, Bytes}, nil, false},
+	}
+
+	for i, tc := range testCases {
+		typ, err := Unify(tc.candidates)
+		if tc.expected != nil {
+			if err != nil {
+				t.Errorf("%d: unexpected error: %v", i, err)
+			} else if !typ.Identical(tc.expected) {
+				t.Errorf("%d: expected %s, got %s", i, tc.expected.SQLString(), typ.SQLString())
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("%d: expected error, got %s", i, typ.SQLString())
+			continue
+		}
+		ue, ok := errors.UnwrapAll(err).(*UnifyError)
+		if !ok {
+			t.Errorf("%d: expected *UnifyError, got %T", i, errors.UnwrapAll(err))
+			continue
+		}
+		if ue.Ambiguous != tc.ambiguous {
+			t.Errorf("%d: expected ambiguous=%t, got %t (%v)", i, tc.ambiguous, ue.Ambiguous, err)
+		}
+		code := pgcode.DatatypeMismatch
+		if tc.ambiguous {
+			code = pgcode.IndeterminateDatatype
+		}
+		if actual := pgerror.GetPGCode(err); actual != code {
+			t.Errorf("%d: expected code %s, got %s", i, code, actual)
+		}
+	}
+}
+
+func TestConstrain(t *testing.T) {
+	if typ, err := Constrain(nil, Int); err != nil || typ != Int {
+		t.Errorf("expected INT8, got %v, %v", typ, err)
+	}
+	if typ, err := Constrain(Int, Int4); err != nil || typ != Int {
+		t.Errorf("expected INT8, got %v, %v", typ, err)
+	}
+	_, err := Constrain(Int, String)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if expected := "conflicting data types: INT8, STRING"; err.Error() != expected {
+		t.Errorf("expected error %q, got %q", expected, err)
+	}
+}
+
+func TestLUB(t *testing.T) {
+	testCases := []struct {
+		typs     []*T
+		expected *T
+	}{
+		// NULL absorption.
+		{nil, Unknown},
+		{[]*T{Unknown, Unknown}, Unknown},
+		{[]*T{Unknown, Int, Unknown}, Int},
+
+		// Numeric promotion.
+		{[]*T{Int2, Int4}, Int4},
+		{[]*T{Int, Decimal}, Decimal},
+		{[]*T{Decimal, Float4}, Float4},
+		{[]*T{Float4, Float}, Float},
+		{[]*T{Int, Decimal, Float}, Float},
+		{[]*T{MakeDecimal(10, 2), MakeDecimal(10, 2)}, MakeDecimal(10, 2)},
+		{[]*T{MakeDecimal(5, 2), MakeDecimal(10, 0)}, Decimal},
+		{[]*T{Int, MakeDecimal(5, 2)}, MakeDecimal(5, 2)},
+
+		// String widening.
+		{[]*T{MakeVarChar(3), MakeVarChar(10)}, MakeVarChar(10)},
+		{[]*T{MakeVarChar(3), MakeVarChar(0)}, MakeVarChar(0)},
+		{[]*T{MakeVarChar(3), String}, String},
+		{[]*T{MakeChar(3), MakeVarChar(5)}, String},
+		{[]*T{Name, MakeVarChar(5)}, String},
+		{[]*T{MakeChar(3), MakeChar(5)}, MakeChar(5)},
+		{[]*T{
+			MakeCollatedString(MakeVarChar(3), "en"), MakeCollatedString(MakeChar(5), "en"),
+		}, MakeCollatedString(String, "en")},
+
+		// Arrays.
+		{[]*T{IntArray, MakeArray(Decimal)}, MakeArray(Decimal)},
+		{[]*T{MakeArray(MakeChar(3)), MakeArray(MakeVarChar(5))}, MakeArray(String)},
+		{[]*T{MakeArray(Unknown), IntArray}, IntArray},
+
+		// Other families.
+		{[]*T{Date, Timestamp}, Timestamp},
+		{[]*T{Unknown, Uuid}, Uuid},
+	}
+
+	for i, tc := range testCases {
+		typ, err := LUB(tc.typs...)
+		if err != nil {
+			t.Errorf("%d: unexpected error: %v", i, err)
+		} else if !typ.Identical(tc.expected) {
+			t.Errorf("%d: expected %s, got %s", i, tc.expected.DebugString(), typ.DebugString())
+		}
+	}
+
+	errCases := [][]*T{
+		{Int, String},
+		{Uuid, Unknown, Bytes},
+		{IntArray, String},
+		{MakeCollatedString(String, "en"), MakeCollatedString(String, "de")},
+	}
+	for i, typs := range errCases {
+		if typ, err := LUB(typs...); err == nil {
+			t.Errorf("%d: expected error, got %s", i, typ.SQLString())
+		} else if _, ok := errors.UnwrapAll(err).(*UnifyError); !ok {
+			t.Errorf("%d: expected *UnifyError, got %T", i, errors.UnwrapAll(err))
+		}
+	}
+}
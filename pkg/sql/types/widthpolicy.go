@@ -0,0 +1,289 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"unicode/utf8"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
+)
+
+// WidthPolicy describes how a value that does not fit the width of a type is
+// converted to the type, such as a string that is longer than the width of a
+// VARCHAR(n), or a decimal with more fractional digits than the scale of a
+// DECIMAL(p,s).
+type WidthPolicy int
+
+const (
+	// WidthPolicyError rejects the value with an error.
+	WidthPolicyError WidthPolicy = iota
+	// WidthPolicyTruncate drops the end of the value, such as the characters
+	// of a string or the bits of a bit string that exceed the width.
+	WidthPolicyTruncate
+	// WidthPolicyRound rounds the value to the precision of the type, such as
+	// the fractional digits of a decimal to the scale of the type.
+	WidthPolicyRound
+)
+
+// String returns the name of the width policy.
+func (p WidthPolicy) String() string {
+	switch p {
+	case WidthPolicyError:
+		return "error"
+	case WidthPolicyTruncate:
+		return "truncate"
+	case WidthPolicyRound:
+		return "round"
+	}
+	return "unknown"
+}
+
+// CompatibilityMode is the SQL dialect whose handling of over-width values a
+// session emulates. Sessions map their compatibility setting to a mode, and
+// pass it to WidthPolicy.
+type CompatibilityMode int
+
+const (
+	// PostgresCompatibilityMode follows Postgres: values that are assigned to
+	// a column must fit its width, but explicit casts truncate them.
+	PostgresCompatibilityMode CompatibilityMode = iota
+	// LenientCompatibilityMode also truncates the values that are assigned to
+	// a column, like the non-strict modes of other databases.
+	LenientCompatibilityMode
+
+	numCompatibilityModes
+)
+
+// widthModifier is a modifier of types that a value may not fit.
+type widthModifier int
+
+const (
+	// noWidthModifier is the modifier of the types whose values always fit.
+	noWidthModifier widthModifier = iota
+	// stringWidth is the width of VARCHAR(n), CHAR(n), and of the other
+	// string types that have a width.
+	stringWidth
+	// implicitStringWidth is the implicit width of NAME and of "char" without
+	// a width.
+	implicitStringWidth
+	// bytesWidth is the width of BYTES(n).
+	bytesWidth
+	// bitWidth is the width of BIT(n) and VARBIT(n).
+	bitWidth
+	// intWidth is the width of the INT types.
+	intWidth
+	// decimalScale is the scale of DECIMAL(p,s). The precision is not a
+	// policy: values with more than p-s integer digits are always rejected.
+	decimalScale
+	// timePrecision is the precision of TIME(p), TIMESTAMP(p) and INTERVAL(p).
+	timePrecision
+)
+
+// widthContextPolicies are the policies of a width modifier in assignments, and
+// in explicit casts.
+type widthContextPolicies struct {
+	assignment, explicit WidthPolicy
+}
+
+// widthPolicies declares the policies of each width modifier, by compatibility
+// mode. The paths that convert values to types (INSERT, UPDATE, and casts)
+// should consult WidthPolicy rather than decide for themselves, so that they
+// agree on the handling of each type.
+var widthPolicies = map[widthModifier][numCompatibilityModes]widthContextPolicies{
+	noWidthModifier: {
+		PostgresCompatibilityMode: {WidthPolicyError, WidthPolicyError},
+		LenientCompatibilityMode:  {WidthPolicyError, WidthPolicyError},
+	},
+	stringWidth: {
+		PostgresCompatibilityMode: {WidthPolicyError, WidthPolicyTruncate},
+		LenientCompatibilityMode:  {WidthPolicyTruncate, WidthPolicyTruncate},
+	},
+	implicitStringWidth: {
+		PostgresCompatibilityMode: {WidthPolicyTruncate, WidthPolicyTruncate},
+		LenientCompatibilityMode:  {WidthPolicyTruncate, WidthPolicyTruncate},
+	},
+	bytesWidth: {
+		PostgresCompatibilityMode: {WidthPolicyError, WidthPolicyTruncate},
+		LenientCompatibilityMode:  {WidthPolicyTruncate, WidthPolicyTruncate},
+	},
+	bitWidth: {
+		PostgresCompatibilityMode: {WidthPolicyError, WidthPolicyTruncate},
+		LenientCompatibilityMode:  {WidthPolicyTruncate, WidthPolicyTruncate},
+	},
+	intWidth: {
+		PostgresCompatibilityMode: {WidthPolicyError, WidthPolicyError},
+		LenientCompatibilityMode:  {WidthPolicyError, WidthPolicyError},
+	},
+	decimalScale: {
+		PostgresCompatibilityMode: {WidthPolicyRound, WidthPolicyRound},
+		LenientCompatibilityMode:  {WidthPolicyRound, WidthPolicyRound},
+	},
+	timePrecision: {
+		PostgresCompatibilityMode: {WidthPolicyRound, WidthPolicyRound},
+		LenientCompatibilityMode:  {WidthPolicyRound, WidthPolicyRound},
+	},
+}
+
+// widthModifier returns the modifier of the type that values may not fit.
+func (t *T) widthModifier() widthModifier {
+	switch t.Family() {
+	case StringFamily, CollatedStringFamily:
+		switch {
+		case t.StringKind() == NameKind:
+			return implicitStringWidth
+		case t.StringKind() == QCharKind && t.Width() == 0:
+			return implicitStringWidth
+		case t.Width() > 0:
+			return stringWidth
+		}
+	case BytesFamily:
+		if t.Width() > 0 {
+			return bytesWidth
+		}
+	case BitFamily:
+		if t.Width() > 0 {
+			return bitWidth
+		}
+	case IntFamily:
+		return intWidth
+	case DecimalFamily:
+		if t.Precision() > 0 {
+			return decimalScale
+		}
+	case TimeFamily, TimestampFamily, TimestampTZFamily, IntervalFamily:
+		return timePrecision
+	case ArrayFamily:
+		return t.ArrayContents().widthModifier()
+	}
+	return noWidthModifier
+}
+
+// WidthPolicy returns the policy for the values that do not fit the width of
+// this type, in the given cast context and compatibility mode. Values that are
+// converted to a type by an explicit cast follow the policy of explicit casts,
+// and all the other values, including those assigned to a column by INSERT or
+// UPDATE, follow the policy of assignments. In the Postgres mode:
+//
+//   VARCHAR(n), CHAR(n), BYTES(n), BIT(n), VARBIT(n) : error, truncate on cast
+//   NAME, "char"                                    : truncate
+//   DECIMAL(p,s)                                    : round to the scale
+//   TIME(p), TIMESTAMP(p), INTERVAL(p)              : round to the precision
+//   INT2, INT4, INT8                                : error
+//
+// The policy of an array type is the policy of its element type. The values of
+// the types without a width always fit, and their policy is WidthPolicyError.
+func (t *T) WidthPolicy(ctx CastContextKind, mode CompatibilityMode) WidthPolicy {
+	if mode < 0 || mode >= numCompatibilityModes {
+		mode = PostgresCompatibilityMode
+	}
+	policies := widthPolicies[t.widthModifier()][mode]
+	if ctx == CastExplicit {
+		return policies.explicit
+	}
+	return policies.assignment
+}
+
+// LimitStringWidth converts a string to this string type, according to the
+// width policy of the type in the given cast context and compatibility mode.
+// It returns the string unchanged if it fits the width of the type, the
+// string truncated to the width if the policy is to truncate, and an error
+// with the StringDataRightTruncation code otherwise. A truncated string never
+// ends with part of a character, even if the width is measured in bytes.
+func (t *T) LimitStringWidth(
+	s string, ctx CastContextKind, mode CompatibilityMode,
+) (string, error) {
+	if t.Family() != StringFamily && t.Family() != CollatedStringFamily {
+		return "", errors.AssertionFailedf("cannot limit the width of strings of type %s", t.SQLString())
+	}
+	if t.widthModifier() == implicitStringWidth {
+		return t.TruncateString(s), nil
+	}
+	if t.StringFitsWidth(s) {
+		return s, nil
+	}
+	if t.WidthPolicy(ctx, mode) != WidthPolicyTruncate {
+		return "", pgerror.Newf(pgcode.StringDataRightTruncation,
+			"value too long for type %s", t.ErrorFormat())
+	}
+	if t.WidthUnit() == StringWidthUnit_BYTES {
+		n := int(t.Width())
+		for n > 0 && !utf8.RuneStart(s[n]) {
+			n--
+		}
+		return s[:n], nil
+	}
+	n := 0
+	for i := range s {
+		if n == int(t.Width()) {
+			return s[:i], nil
+		}
+		n++
+	}
+	return s, nil
+}
+
+// LimitBytesWidth is like LimitStringWidth, but for the values of BYTES types.
+// It does not copy the truncated values.
+func (t *T) LimitBytesWidth(
+	b []byte, ctx CastContextKind, mode CompatibilityMode,
+) ([]byte, error) {
+	if t.Family() != BytesFamily {
+		return nil, errors.AssertionFailedf("cannot limit the width of bytes of type %s", t.SQLString())
+	}
+	if t.Width() == 0 || len(b) <= int(t.Width()) {
+		return b, nil
+	}
+	if t.WidthPolicy(ctx, mode) != WidthPolicyTruncate {
+		return nil, pgerror.Newf(pgcode.StringDataRightTruncation,
+			"value too long for type %s", t.ErrorFormat())
+	}
+	return b[:t.Width()], nil
+}
+
+// LimitBitWidth is like LimitStringWidth, but for the values of BIT types. The
+// values of BIT(n) must have exactly n bits, so if the policy is to truncate,
+// the shorter values are also padded with zero bits, and otherwise they are
+// rejected with an error with the StringDataLengthMismatch code.
+func (t *T) LimitBitWidth(
+	b bitarray.BitArray, ctx CastContextKind, mode CompatibilityMode,
+) (bitarray.BitArray, error) {
+	if t.Family() != BitFamily {
+		return bitarray.BitArray{}, errors.AssertionFailedf(
+			"cannot limit the width of bit strings of type %s", t.SQLString())
+	}
+	width := uint(t.Width())
+	if width == 0 {
+		return b, nil
+	}
+	truncate := t.WidthPolicy(ctx, mode) == WidthPolicyTruncate
+	if t.Oid() == oid.T_varbit {
+		if b.BitLen() <= width {
+			return b, nil
+		}
+		if !truncate {
+			return bitarray.BitArray{}, pgerror.Newf(pgcode.StringDataRightTruncation,
+				"bit string length %d too large for type %s", b.BitLen(), t.ErrorFormat())
+		}
+		return b.ToWidth(width), nil
+	}
+	if b.BitLen() == width {
+		return b, nil
+	}
+	if !truncate {
+		return bitarray.BitArray{}, pgerror.Newf(pgcode.StringDataLengthMismatch,
+			"bit string length %d does not match type %s", b.BitLen(), t.ErrorFormat())
+	}
+	return b.ToWidth(width), nil
+}
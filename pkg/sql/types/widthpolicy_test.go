@@ -0,0 +1,147 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
+)
+
+func TestWidthPolicy(t *testing.T) {
+	testCases := []struct {
+		typ                  *T
+		assignment, explicit WidthPolicy
+		lenient              WidthPolicy
+	}{
+		{MakeVarChar(3), WidthPolicyError, WidthPolicyTruncate, WidthPolicyTruncate},
+		{MakeChar(3), WidthPolicyError, WidthPolicyTruncate, WidthPolicyTruncate},
+		{MakeCollatedString(MakeVarChar(3), "de"), WidthPolicyError, WidthPolicyTruncate, WidthPolicyTruncate},
+		{String, WidthPolicyError, WidthPolicyError, WidthPolicyError},
+		{Name, WidthPolicyTruncate, WidthPolicyTruncate, WidthPolicyTruncate},
+		{MakeQChar(0), WidthPolicyTruncate, WidthPolicyTruncate, WidthPolicyTruncate},
+		{MakeBytes(3), WidthPolicyError, WidthPolicyTruncate, WidthPolicyTruncate},
+		{MakeBit(3), WidthPolicyError, WidthPolicyTruncate, WidthPolicyTruncate},
+		{MakeVarBit(3), WidthPolicyError, WidthPolicyTruncate, WidthPolicyTruncate},
+		{Int2, WidthPolicyError, WidthPolicyError, WidthPolicyError},
+		{MakeDecimal(10, 2), WidthPolicyRound, WidthPolicyRound, WidthPolicyRound},
+		{Decimal, WidthPolicyError, WidthPolicyError, WidthPolicyError},
+		{MakeTimestamp(0), WidthPolicyRound, WidthPolicyRound, WidthPolicyRound},
+		{MakeArray(MakeVarChar(3)), WidthPolicyError, WidthPolicyTruncate, WidthPolicyTruncate},
+		{Bool, WidthPolicyError, WidthPolicyError, WidthPolicyError},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.typ.SQLString(), func(t *testing.T) {
+			if p := tc.typ.WidthPolicy(CastAssignment, PostgresCompatibilityMode); p != tc.assignment {
+				t.Errorf("expected assignment policy %s, but got %s", tc.assignment, p)
+			}
+			if p := tc.typ.WidthPolicy(CastImplicit, PostgresCompatibilityMode); p != tc.assignment {
+				t.Errorf("expected implicit policy %s, but got %s", tc.assignment, p)
+			}
+			if p := tc.typ.WidthPolicy(CastExplicit, PostgresCompatibilityMode); p != tc.explicit {
+				t.Errorf("expected explicit policy %s, but got %s", tc.explicit, p)
+			}
+			if p := tc.typ.WidthPolicy(CastAssignment, LenientCompatibilityMode); p != tc.lenient {
+				t.Errorf("expected lenient assignment policy %s, but got %s", tc.lenient, p)
+			}
+		})
+	}
+
+	bytesVarChar := MakeStringWithWidthUnit(MakeVarChar(4), StringWidthUnit_BYTES)
+	stringCases := []struct {
+		typ      *T
+		in       string
+		ctx      CastContextKind
+		mode     CompatibilityMode
+		expected string
+		err      string
+	}{
+		{MakeVarChar(3), "abc", CastAssignment, PostgresCompatibilityMode, "abc", ""},
+		{MakeVarChar(3), "abcd", CastAssignment, PostgresCompatibilityMode, "", "value too long for type VARCHAR(3)"},
+		{MakeVarChar(3), "abcd", CastExplicit, PostgresCompatibilityMode, "abc", ""},
+		{MakeVarChar(3), "étés", CastAssignment, LenientCompatibilityMode, "été", ""},
+		{MakeChar(1), "ab", CastAssignment, PostgresCompatibilityMode, "", "value too long for type CHAR(1)"},
+		{bytesVarChar, "ééé", CastExplicit, PostgresCompatibilityMode, "éé", ""},
+		{bytesVarChar, "aééé", CastExplicit, PostgresCompatibilityMode, "aé", ""},
+		{Name, strings.Repeat("a", MaxNameLength+1), CastAssignment, PostgresCompatibilityMode,
+			strings.Repeat("a", MaxNameLength), ""},
+		{MakeQChar(0), "ab", CastAssignment, PostgresCompatibilityMode, "a", ""},
+		{String, "abcd", CastAssignment, PostgresCompatibilityMode, "abcd", ""},
+	}
+	for _, tc := range stringCases {
+		s, err := tc.typ.LimitStringWidth(tc.in, tc.ctx, tc.mode)
+		if tc.err != "" {
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("%s %q: expected error %q, but got %v", tc.typ.SQLString(), tc.in, tc.err, err)
+			} else if code := pgerror.GetPGCode(err); code != pgcode.StringDataRightTruncation {
+				t.Errorf("%s %q: expected code %s, but got %s", tc.typ.SQLString(), tc.in,
+					pgcode.StringDataRightTruncation, code)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s %q: %v", tc.typ.SQLString(), tc.in, err)
+		} else if s != tc.expected {
+			t.Errorf("%s %q: expected %q, but got %q", tc.typ.SQLString(), tc.in, tc.expected, s)
+		}
+	}
+	if _, err := Int.LimitStringWidth("a", CastAssignment, PostgresCompatibilityMode); err == nil {
+		t.Error("expected an error limiting the width of strings of type INT8")
+	}
+
+	if b, err := MakeBytes(2).LimitBytesWidth([]byte("abc"), CastExplicit, PostgresCompatibilityMode); err != nil {
+		t.Error(err)
+	} else if string(b) != "ab" {
+		t.Errorf("expected ab, but got %q", b)
+	}
+	if _, err := MakeBytes(2).LimitBytesWidth([]byte("abc"), CastAssignment, PostgresCompatibilityMode); err == nil ||
+		err.Error() != "value too long for type BYTES(2)" {
+		t.Errorf("expected an error, but got %v", err)
+	}
+
+	bitCases := []struct {
+		typ      *T
+		in       string
+		ctx      CastContextKind
+		expected string
+		err      string
+	}{
+		{MakeBit(3), "101", CastAssignment, "101", ""},
+		{MakeBit(3), "10", CastAssignment, "", "bit string length 2 does not match type BIT(3)"},
+		{MakeBit(3), "10", CastExplicit, "100", ""},
+		{MakeBit(3), "1011", CastExplicit, "101", ""},
+		{MakeVarBit(3), "10", CastAssignment, "10", ""},
+		{MakeVarBit(3), "1011", CastAssignment, "", "bit string length 4 too large for type VARBIT(3)"},
+		{MakeVarBit(3), "1011", CastExplicit, "101", ""},
+		{VarBit, "1011", CastAssignment, "1011", ""},
+	}
+	for _, tc := range bitCases {
+		in, err := bitarray.Parse(tc.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := tc.typ.LimitBitWidth(in, tc.ctx, PostgresCompatibilityMode)
+		if tc.err != "" {
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("%s %s: expected error %q, but got %v", tc.typ.SQLString(), tc.in, tc.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s %s: %v", tc.typ.SQLString(), tc.in, err)
+		} else if b.String() != tc.expected {
+			t.Errorf("%s %s: expected %s, but got %s", tc.typ.SQLString(), tc.in, tc.expected, b.String())
+		}
+	}
+}
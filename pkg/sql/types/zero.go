@@ -0,0 +1,123 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/lex"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
+)
+
+// ZeroValueLiteral returns the canonical SQL literal of the "zero" value of the
+// given type, which backfills use as the default value of new columns that are
+// NOT NULL, and tools use to generate placeholder rows:
+//
+//   BOOL                  : false
+//   INT, FLOAT, DECIMAL   : 0
+//   string types, BYTES   : ''
+//   collated strings      : '' COLLATE <locale>
+//   BIT(n)                : B'<n zeros>'
+//   DATE                  : '1970-01-01'
+//   TIMESTAMP             : '1970-01-01 00:00:00'
+//   TIMESTAMPTZ           : '1970-01-01 00:00:00+00:00'
+//   TIME, INTERVAL        : '00:00:00'
+//   UUID                  : '00000000-0000-0000-0000-000000000000'
+//   INET                  : '0.0.0.0'
+//   OID                   : 0
+//   JSONB                 : 'null'
+//   T[]                   : '{}'
+//
+// The string types whose values have a fixed format have the zero value of
+// their format instead, such as '00000000/00000000' for PG_LSN, and JSONPATH
+// has '$'. The literals are not annotated with the type, so that they are
+// interpreted as values of the column that they are assigned to. The value is
+// validated against the constraints of the type, such as its width.
+// ZeroValueLiteral returns an error with the FeatureNotSupported code for the
+// types that have no values, such as the tuple and wildcard types.
+func ZeroValueLiteral(t *T) (string, error) {
+	var lit string
+	switch t.Family() {
+	case BoolFamily:
+		lit = "false"
+	case IntFamily:
+		if err := t.CheckBounds(0); err != nil {
+			return "", err
+		}
+		lit = "0"
+	case FloatFamily, DecimalFamily, OidFamily:
+		lit = "0"
+	case StringFamily, CollatedStringFamily:
+		var s string
+		switch t.StringKind() {
+		case PGLSNKind:
+			s = FormatLSN(0)
+		case MACAddrKind, MACAddr8Kind:
+			s = strings.TrimSuffix(strings.Repeat("00:", t.macAddrSize()), ":")
+		case JSONPathKind:
+			s = "$"
+		}
+		if _, err := t.CanonicalizeString(s); err != nil {
+			return "", errors.NewAssertionErrorWithWrappedErrf(err,
+				"invalid zero value of type %s", t.SQLString())
+		}
+		if !t.StringFitsWidth(s) {
+			return "", errors.AssertionFailedf(
+				"zero value %q does not fit type %s", s, t.SQLString())
+		}
+		var buf bytes.Buffer
+		lex.EncodeSQLString(&buf, s)
+		if t.Family() == CollatedStringFamily {
+			buf.WriteString(" COLLATE ")
+			lex.EncodeLocaleName(&buf, t.Locale())
+		}
+		lit = buf.String()
+	case BytesFamily:
+		lit = "''"
+	case BitFamily:
+		// The values of BIT(n) have exactly n bits, and those of the other BIT
+		// types may have none.
+		var zeros string
+		if t.Oid() == oid.T_bit {
+			zeros = strings.Repeat("0", int(t.Width()))
+		}
+		lit = "B'" + zeros + "'"
+	case DateFamily:
+		lit = "'1970-01-01'"
+	case TimestampFamily:
+		lit = "'1970-01-01 00:00:00'"
+	case TimestampTZFamily:
+		lit = "'1970-01-01 00:00:00+00:00'"
+	case TimeFamily, IntervalFamily:
+		lit = "'00:00:00'"
+	case UuidFamily:
+		lit = "'00000000-0000-0000-0000-000000000000'"
+	case INetFamily:
+		lit = "'0.0.0.0'"
+	case JsonFamily:
+		lit = "'null'"
+	case ArrayFamily:
+		// The element type must have values for the array to be valid, even
+		// though the empty array has no elements.
+		if _, err := ZeroValueLiteral(t.ArrayContents()); err != nil {
+			return "", err
+		}
+		lit = "'{}'"
+	default:
+		return "", pgerror.Newf(pgcode.FeatureNotSupported,
+			"type %s has no zero value", t.SQLString())
+	}
+	return lit, nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+func TestZeroValueLiteral(t *testing.T) {
+	testCases := []struct {
+		typ      *T
+		expected string
+	}{
+		{Bool, "false"},
+		{Int2, "0"},
+		{MakeDecimal(3, 2), "0"},
+		{MakeVarChar(2), "''"},
+		{MakeCollatedString(String, "en-US"), "'' COLLATE en_US"},
+		{PGLSN, "'00000000/00000000'"},
+		{MACAddr8, "'00:00:00:00:00:00:00:00'"},
+		{Jsonpath, "'$'"},
+		{Bytes, "''"},
+		{MakeBit(3), "B'000'"},
+		{VarBit, "B''"},
+		{Date, "'1970-01-01'"},
+		{TimestampTZ, "'1970-01-01 00:00:00+00:00'"},
+		{MakeInterval(IntervalTypeMetadata{DurationField: IntervalDurationField{
+			DurationType: IntervalDurationType_YEAR}}), "'00:00:00'"},
+		{Uuid, "'00000000-0000-0000-0000-000000000000'"},
+		{Jsonb, "'null'"},
+		{StringArray, "'{}'"},
+		{MakeTuple([]T{*Int}), "error"},
+		{MakeArray(AnyTuple), "error"},
+		{Unknown, "error"},
+	}
+	for _, tc := range testCases {
+		lit, err := ZeroValueLiteral(tc.typ)
+		if err != nil {
+			if tc.expected != "error" || pgerror.GetPGCode(err) != pgcode.FeatureNotSupported {
+				t.Errorf("%s: unexpected error: %v", tc.typ.SQLString(), err)
+			}
+			continue
+		}
+		if lit != tc.expected {
+			t.Errorf("%s: expected %s, got %s", tc.typ.SQLString(), tc.expected, lit)
+		}
+	}
+	// All the types that have a Go representation have a zero value.
+	for _, typ := range AllTypes() {
+		if _, err := GoType(typ); err != nil {
+			continue
+		}
+		if _, err := ZeroValueLiteral(typ); err != nil {
+			t.Errorf("%s: %v", typ.SQLString(), err)
+		}
+	}
+}
@@ -107,6 +107,24 @@ func Difference(t1 TimeOfDay, t2 TimeOfDay) duration.Duration {
 	return duration.MakeDuration(int64(t1-t2)*nanosPerMicro, 0, 0)
 }
 
+// Round rounds t to the given number of fractional second digits, between 0
+// and 6, as for the values of a TIME(precision) type. Like in Postgres, a time
+// that rounds up to midnight becomes 24:00:00 rather than 00:00:00.
+func (t TimeOfDay) Round(precision int32) TimeOfDay {
+	if precision < 0 || precision >= 6 {
+		return t
+	}
+	unit := int64(1)
+	for i := precision; i < 6; i++ {
+		unit *= 10
+	}
+	rounded := (int64(t) + unit/2) / unit * unit
+	if rounded > int64(Max) {
+		return Time2400
+	}
+	return TimeOfDay(rounded)
+}
+
 // Hour returns the hour specified by t, in the range [0, 24].
 func (t TimeOfDay) Hour() int {
 	if t == Time2400 {
@@ -109,3 +109,27 @@ func TestDifference(t *testing.T) {
 		})
 	}
 }
+
+func TestRound(t *testing.T) {
+	testData := []struct {
+		t         TimeOfDay
+		precision int32
+		exp       TimeOfDay
+	}{
+		{New(12, 0, 0, 123456), 6, New(12, 0, 0, 123456)},
+		{New(12, 0, 0, 123456), 3, New(12, 0, 0, 123000)},
+		{New(12, 0, 0, 123500), 3, New(12, 0, 0, 124000)},
+		{New(12, 0, 0, 499999), 0, New(12, 0, 0, 0)},
+		{New(12, 0, 0, 500000), 0, New(12, 0, 1, 0)},
+		{New(23, 59, 59, 999999), 3, Time2400},
+		{Time2400, 0, Time2400},
+	}
+	for _, td := range testData {
+		t.Run(fmt.Sprintf("%s,%d", td.t, td.precision), func(t *testing.T) {
+			actual := td.t.Round(td.precision)
+			if actual != td.exp {
+				t.Errorf("expected %s, got %s", td.exp, actual)
+			}
+		})
+	}
+}